@@ -0,0 +1,132 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package address derives the blockchain addresses of TSS keys from their public keys or save data. The functions
+// that take save data first check that the save data of every party given agrees on the key, and that the public
+// shares of each interpolate to its public key, so that an address is never derived from a corrupt share.
+package address
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// ECDSAKey returns the public key of ECDSA save data, after checking that the save data of every party given is of
+// the same key and consistent with it
+func ECDSAKey(saves ...keygen.LocalPartySaveData) (*crypto.ECPoint, error) {
+	pubs := make([]*crypto.ECPoint, len(saves))
+	ks, bigXj := make([][]*big.Int, len(saves)), make([][]*crypto.ECPoint, len(saves))
+	structured := make([]bool, len(saves))
+	for i, save := range saves {
+		pubs[i], ks[i], bigXj[i], structured[i] = save.ECDSAPub, save.Ks, save.BigXj, save.AccessStructure != nil
+	}
+	return consistentKey(tss.S256(), pubs, ks, bigXj, structured)
+}
+
+// EdDSAKey returns the public key of EdDSA save data, after checking that the save data of every party given is of
+// the same key and consistent with it
+func EdDSAKey(saves ...eddsakeygen.LocalPartySaveData) (*crypto.ECPoint, error) {
+	pubs := make([]*crypto.ECPoint, len(saves))
+	ks, bigXj := make([][]*big.Int, len(saves)), make([][]*crypto.ECPoint, len(saves))
+	structured := make([]bool, len(saves))
+	for i, save := range saves {
+		pubs[i], ks[i], bigXj[i], structured[i] = save.EDDSAPub, save.Ks, save.BigXj, save.AccessStructure != nil
+	}
+	// ed25519 or BabyJubJub
+	ec := tss.Edwards()
+	if len(pubs) > 0 && pubs[0] != nil {
+		ec = pubs[0].Curve()
+	}
+	return consistentKey(ec, pubs, ks, bigXj, structured)
+}
+
+// consistentKey returns the public key of the save data of parties, given as their public keys, the keys of the
+// parties of each and their public shares, after checking that they agree on the key and that the public shares of
+// each interpolate to it. The public shares of a key shared along an access structure do not interpolate to it, and
+// are only compared.
+func consistentKey(ec elliptic.Curve, pubs []*crypto.ECPoint, ks [][]*big.Int, bigXj [][]*crypto.ECPoint, structured []bool) (*crypto.ECPoint, error) {
+	if len(pubs) == 0 {
+		return nil, errors.New("address: no save data")
+	}
+	for i, pub := range pubs {
+		if pub == nil {
+			return nil, fmt.Errorf("address: the save data %d has no public key", i)
+		}
+		if !pub.Equals(pubs[0]) {
+			return nil, fmt.Errorf("address: the save data %d is of another key than the save data 0", i)
+		}
+		if len(ks[i]) != len(bigXj[i]) || len(ks[i]) == 0 {
+			return nil, fmt.Errorf("address: the save data %d has not as many public shares as parties", i)
+		}
+		if structured[i] {
+			continue
+		}
+		interpolated, err := interpolate(ec, ks[i], bigXj[i])
+		if err != nil {
+			return nil, fmt.Errorf("address: the save data %d: %w", i, err)
+		}
+		if !interpolated.Equals(pub) {
+			return nil, fmt.Errorf("address: the public shares of the save data %d do not interpolate to its public key", i)
+		}
+	}
+	return pubs[0], nil
+}
+
+// interpolate returns the point at 0 of the polynomial through the public shares. With the shares of all of the
+// parties it is the public key, whatever the threshold.
+func interpolate(ec elliptic.Curve, ks []*big.Int, bigXj []*crypto.ECPoint) (*crypto.ECPoint, error) {
+	for _, k := range ks {
+		if k == nil {
+			return nil, errors.New("the key of a party is missing")
+		}
+	}
+	if _, err := vss.CheckIndexes(ec, ks); err != nil {
+		return nil, err
+	}
+	modN := common.ModInt(ec.Params().N)
+	var sum *crypto.ECPoint
+	for i, ki := range ks {
+		if bigXj[i] == nil {
+			return nil, errors.New("a public share is missing")
+		}
+		coef := big.NewInt(1)
+		for j, kj := range ks {
+			if j != i {
+				coef = modN.Mul(coef, modN.Mul(kj, modN.ModInverse(modN.Sub(kj, ki))))
+			}
+		}
+		term := bigXj[i].ScalarMult(coef)
+		if sum == nil {
+			sum = term
+			continue
+		}
+		var err error
+		if sum, err = sum.Add(term); err != nil {
+			return nil, err
+		}
+	}
+	return sum, nil
+}
+
+// checkCurve checks that `pub` is on the curve `expected`
+func checkCurve(pub *crypto.ECPoint, expected tss.CurveName) error {
+	if pub == nil {
+		return errors.New("address: no public key")
+	}
+	if name, ok := tss.GetCurveName(pub.Curve()); !ok || name != expected {
+		return fmt.Errorf("address: the key is not on %s", expected)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package address
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestEthereum(t *testing.T) {
+	// the addresses of the private keys 1 and 2
+	for k, expected := range map[int64]string{
+		1: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		2: "0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF",
+	} {
+		addr, err := Ethereum(crypto.ScalarBaseMult(tss.S256(), big.NewInt(k)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, addr)
+	}
+	_, err := Ethereum(crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(1)))
+	assert.Error(t, err)
+
+	// the examples of EIP-55
+	for _, expected := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		raw, err := hex.DecodeString(expected[2:])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, checksumHex(raw))
+	}
+}
+
+func TestEthereumOf(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(test.TestParticipants)
+	assert.NoError(t, err)
+	addr, err := EthereumOf(keys...)
+	assert.NoError(t, err)
+	expected, err := Ethereum(keys[0].ECDSAPub)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, addr)
+
+	// a party whose public shares were corrupted
+	corrupt := keys[1]
+	corrupt.BigXj = append([]*crypto.ECPoint(nil), keys[1].BigXj...)
+	corrupt.BigXj[0] = keys[1].BigXj[1]
+	_, err = EthereumOf(keys[0], corrupt)
+	assert.Error(t, err)
+
+	// the save data of another key
+	other := keys[1]
+	other.ECDSAPub = crypto.ScalarBaseMult(tss.S256(), big.NewInt(1))
+	_, err = EthereumOf(keys[0], other)
+	assert.Error(t, err)
+
+	_, err = EthereumOf()
+	assert.Error(t, err)
+}
+
+func TestBitcoin(t *testing.T) {
+	// the example of BIP 173
+	addr, err := BitcoinP2WPKH(crypto.ScalarBaseMult(tss.S256(), big.NewInt(1)), &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", addr)
+
+	// the wallet test vectors of BIP 341
+	for _, v := range []struct {
+		internalKey, merkleRoot, tweak, outputKey, address string
+	}{{
+		internalKey: "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+		tweak:       "b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70",
+		outputKey:   "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+		address:     "bc1p2wsldez5mud2yam29q22wgfh9439spgduvct83k3pm50fcxa5dps59h4z5",
+	}, {
+		internalKey: "187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+		merkleRoot:  "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21",
+		tweak:       "cbd8679ba636c1110ea247542cfbd964131a6be84f873f7f3b62a777528ed001",
+		outputKey:   "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+		address:     "bc1pz37fc4cn9ah8anwm4xqqhvxygjf9rjf2resrw8h8w4tmvcs0863sa2e586",
+	}} {
+		x, _ := new(big.Int).SetString(v.internalKey, 16)
+		// either point with the X of the internal key: the key of the shares may have an odd Y
+		for _, pub := range liftX(t, x) {
+			var merkleRoot []byte
+			if v.merkleRoot != "" {
+				merkleRoot, _ = hex.DecodeString(v.merkleRoot)
+			}
+			key, err := Taproot(pub, merkleRoot)
+			assert.NoError(t, err)
+			assert.Equal(t, v.tweak, hex.EncodeToString(key.Tweak.FillBytes(make([]byte, 32))))
+			assert.Equal(t, v.outputKey, hex.EncodeToString(key.OutputKey.X().FillBytes(make([]byte, 32))))
+			assert.Equal(t, pub.Y().Bit(0) == 1, key.NegateSecret)
+			assert.Zero(t, key.InternalKey.Y().Bit(0))
+			addr, err := BitcoinP2TR(pub, merkleRoot, &chaincfg.MainNetParams)
+			assert.NoError(t, err)
+			assert.Equal(t, v.address, addr)
+		}
+	}
+
+	// the tweaked secret is that of the output key
+	x := big.NewInt(12345)
+	pub := crypto.ScalarBaseMult(tss.S256(), x)
+	key, err := Taproot(pub, nil)
+	assert.NoError(t, err)
+	if key.NegateSecret {
+		x.Neg(x)
+	}
+	secret := new(big.Int).Mod(new(big.Int).Add(x, key.Tweak), tss.S256().Params().N)
+	assert.True(t, crypto.ScalarBaseMult(tss.S256(), secret).Equals(key.OutputKey))
+
+	_, err = Taproot(pub, []byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+// liftX returns the two points with the X `x`
+func liftX(t *testing.T, x *big.Int) []*crypto.ECPoint {
+	ec := tss.S256()
+	p := ec.Params().P
+	y2 := new(big.Int).Exp(x, big.NewInt(3), p)
+	y2.Add(y2, big.NewInt(7))
+	y := new(big.Int).ModSqrt(y2.Mod(y2, p), p)
+	even, err := crypto.NewECPoint(ec, x, y)
+	assert.NoError(t, err)
+	odd, err := crypto.NewECPoint(ec, x, new(big.Int).Sub(p, y))
+	assert.NoError(t, err)
+	return []*crypto.ECPoint{even, odd}
+}
+
+func TestEdDSA(t *testing.T) {
+	keys, _, err := eddsakeygen.LoadKeygenTestFixtures(test.TestParticipants)
+	assert.NoError(t, err)
+	pub, err := EdDSAKey(keys...)
+	assert.NoError(t, err)
+	assert.True(t, keys[0].EDDSAPub.Equals(pub))
+	corrupt := keys[1]
+	corrupt.BigXj = append([]*crypto.ECPoint(nil), keys[1].BigXj...)
+	corrupt.BigXj[0] = keys[1].BigXj[1]
+	_, err = EdDSAKey(keys[0], corrupt)
+	assert.Error(t, err)
+
+	// the public key of the first test vector of RFC 8032
+	rfc8032, _ := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	edPub, err := edwards.ParsePubKey(rfc8032)
+	assert.NoError(t, err)
+	pub, err = crypto.NewECPoint(tss.Edwards(), edPub.X, edPub.Y)
+	assert.NoError(t, err)
+
+	addr, err := Solana(pub)
+	assert.NoError(t, err)
+	assert.Equal(t, "FVen3X669xLzsi6N2V91DoiyzHzg1uAgqiT8jZ9nS96Z", addr)
+	addr, err = Cosmos(pub, "cosmos")
+	assert.NoError(t, err)
+	assert.Equal(t, "cosmos1y8lrrhap2j3xzcntlp2qgm7jyudhhm2tc7hkue", addr)
+	addr, err = Polkadot(pub, SS58Polkadot)
+	assert.NoError(t, err)
+	assert.Equal(t, "15sND1xy2556eoAx6eGV6zkURiPJ9T9qJ8XMDHsYTuZezp7f", addr)
+
+	_, err = Solana(crypto.ScalarBaseMult(tss.S256(), big.NewInt(1)))
+	assert.Error(t, err)
+}
+
+func TestSS58(t *testing.T) {
+	// the account of Alice in the Substrate docs
+	alice, _ := hex.DecodeString("d43593c715fdd31c61141abd04a99fd6822c8558854ccde39a5684e7a56da27d")
+	for prefix, expected := range map[uint16]string{
+		SS58Substrate: "5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY",
+		SS58Polkadot:  "15oF4uVJwmo4TdGW7VfQxNLavjCXviqxT9S1MgbjMNHr6Sp5",
+		SS58Kusama:    "HNZata7iMYWmk5RvZRTiAsSDhV8366zq2YGb3tLH5Upf74F",
+		255:           "yGHXkYLYqxijLKKfd9Q2CB9shRVu8rPNBS53wvwGTutYg4zTg",
+	} {
+		addr, err := ss58(prefix, alice)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, addr)
+	}
+	_, err := ss58(16384, alice)
+	assert.Error(t, err)
+}
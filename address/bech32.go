@@ -0,0 +1,94 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// the constants of the checksums of BIP 173 (bech32) and BIP 350 (bech32m)
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// segwitAddress returns the segwit address of a witness program: bech32 for version 0, and bech32m for the versions
+// after it, as BIP 350 requires
+func segwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if hrp == "" {
+		return "", errors.New("address: the network has no segwit prefix")
+	}
+	data := append([]byte{version}, convertBits(program, 8, 5)...)
+	checksumConst := uint32(bech32Const)
+	if version > 0 {
+		checksumConst = bech32mConst
+	}
+	return bech32Encode(hrp, data, checksumConst), nil
+}
+
+func bech32Encode(hrp string, data []byte, checksumConst uint32) string {
+	values := append(hrpExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ checksumConst
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range generator {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups bytes of `from` bits into bytes of `to` bits, padding the last with zeros
+func convertBits(data []byte, from, to uint) []byte {
+	var acc, bits uint
+	out := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, b := range data {
+		acc = acc<<from | uint(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits)&(1<<to-1))
+		}
+	}
+	if bits > 0 {
+		out = append(out, byte(acc<<(to-bits))&(1<<to-1))
+	}
+	return out
+}
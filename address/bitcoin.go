@@ -0,0 +1,111 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// TaprootKey is a key as BIP 341 commits to it in a P2TR output: the TSS key is the internal key P, and the output
+// key Q = P + tG, where P has its Y made even and t is the tweak of P and the Merkle root of the script tree. A
+// signing of the key path signs for Q with the secret ±x + t, where x is the TSS secret.
+type TaprootKey struct {
+	// the internal key, with an even Y
+	InternalKey *crypto.ECPoint
+	// whether the TSS key has an odd Y, so that its secret is negated to be that of the internal key
+	NegateSecret bool
+	Tweak        *big.Int
+	OutputKey    *crypto.ECPoint
+}
+
+// BitcoinP2WPKH returns the native segwit version 0 address of a secp256k1 public key on the network `net`, e.g.
+// &chaincfg.MainNetParams: the bech32 encoding of the HASH160 of the compressed key
+func BitcoinP2WPKH(pub *crypto.ECPoint, net *chaincfg.Params) (string, error) {
+	if err := checkCurve(pub, tss.Secp256k1); err != nil {
+		return "", err
+	}
+	return segwitAddress(net.Bech32HRPSegwit, 0, hash160(compressed(pub)))
+}
+
+// BitcoinP2TR returns the taproot address of a secp256k1 public key as the internal key on the network `net`: the
+// bech32m encoding of the output key of Taproot. `merkleRoot` is the root of the script tree, or nil for an output that
+// can only be spent by the key path.
+func BitcoinP2TR(pub *crypto.ECPoint, merkleRoot []byte, net *chaincfg.Params) (string, error) {
+	key, err := Taproot(pub, merkleRoot)
+	if err != nil {
+		return "", err
+	}
+	return segwitAddress(net.Bech32HRPSegwit, 1, key.OutputKey.X().FillBytes(make([]byte, 32)))
+}
+
+// Taproot returns the taproot output key of a secp256k1 public key as the internal key, with the tweak and the
+// negation that a signing of the key path applies to the shares
+func Taproot(pub *crypto.ECPoint, merkleRoot []byte) (*TaprootKey, error) {
+	if err := checkCurve(pub, tss.Secp256k1); err != nil {
+		return nil, err
+	}
+	if merkleRoot != nil && len(merkleRoot) != sha256.Size {
+		return nil, fmt.Errorf("address: the Merkle root has %d bytes, not %d", len(merkleRoot), sha256.Size)
+	}
+	ec := tss.S256()
+	key := &TaprootKey{InternalKey: pub, NegateSecret: pub.Y().Bit(0) == 1}
+	if key.NegateSecret {
+		negY := new(big.Int).Sub(ec.Params().P, pub.Y())
+		internal, err := crypto.NewECPoint(ec, pub.X(), negY)
+		if err != nil {
+			return nil, err
+		}
+		key.InternalKey = internal
+	}
+	x := pub.X().FillBytes(make([]byte, 32))
+	key.Tweak = new(big.Int).SetBytes(taggedHash("TapTweak", x, merkleRoot))
+	if key.Tweak.Cmp(ec.Params().N) >= 0 {
+		return nil, errors.New("address: the taproot tweak is out of range")
+	}
+	var err error
+	if key.OutputKey, err = key.InternalKey.Add(crypto.ScalarBaseMult(ec, key.Tweak)); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// taggedHash is the tagged hash of BIP 340: SHA-256(SHA-256(tag) || SHA-256(tag) || msg)
+func taggedHash(tag string, msg ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msg {
+		h.Write(m)
+	}
+	return h.Sum(nil)
+}
+
+// hash160 is the HASH160 of Bitcoin: RIPEMD-160(SHA-256(data))
+func hash160(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	h := ripemd160.New()
+	h.Write(sum[:])
+	return h.Sum(nil)
+}
+
+// compressed returns the compressed SEC 1 encoding of a secp256k1 point
+func compressed(pub *crypto.ECPoint) []byte {
+	out := make([]byte, 33)
+	out[0] = 2 + byte(pub.Y().Bit(0))
+	pub.X().FillBytes(out[1:])
+	return out
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package address
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/btcsuite/btcutil/base58"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/pubkey"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// the SS58 prefixes of the main networks of Polkadot and Kusama, and of Substrate chains in general
+const (
+	SS58Polkadot  = 0
+	SS58Kusama    = 2
+	SS58Substrate = 42
+
+	maxSS58Prefix = 16383
+)
+
+// Solana returns the Solana address of an ed25519 public key: the base58 encoding of the key
+func Solana(pub *crypto.ECPoint) (string, error) {
+	if err := checkCurve(pub, tss.Ed25519); err != nil {
+		return "", err
+	}
+	return base58.Encode(pubkey.Ed25519Bytes(pub)), nil
+}
+
+// Cosmos returns the address of an ed25519 public key on a Cosmos chain with the bech32 prefix `hrp`, e.g. "cosmos":
+// the first 20 bytes of the SHA-256 of the key, as Tendermint derives it
+func Cosmos(pub *crypto.ECPoint, hrp string) (string, error) {
+	if err := checkCurve(pub, tss.Ed25519); err != nil {
+		return "", err
+	}
+	if hrp == "" {
+		return "", errors.New("address: no bech32 prefix")
+	}
+	sum := sha256.Sum256(pubkey.Ed25519Bytes(pub))
+	return bech32Encode(hrp, convertBits(sum[:20], 8, 5), bech32Const), nil
+}
+
+// Polkadot returns the SS58 address of an ed25519 public key on the network with the prefix `prefix`, e.g.
+// SS58Polkadot
+func Polkadot(pub *crypto.ECPoint, prefix uint16) (string, error) {
+	if err := checkCurve(pub, tss.Ed25519); err != nil {
+		return "", err
+	}
+	return ss58(prefix, pubkey.Ed25519Bytes(pub))
+}
+
+// ss58 encodes an account ID in SS58: the prefix, in one or two bytes, the account ID and the first 2 bytes of the
+// BLAKE2b-512 of "SS58PRE" and both, in base58
+func ss58(prefix uint16, accountID []byte) (string, error) {
+	var out []byte
+	switch {
+	case prefix < 64:
+		out = []byte{byte(prefix)}
+	case prefix <= maxSS58Prefix:
+		out = []byte{byte((prefix&0xfc)>>2) | 0x40, byte(prefix>>8) | byte(prefix&0x03)<<6}
+	default:
+		return "", errors.New("address: the SS58 prefix is out of range")
+	}
+	out = append(out, accountID...)
+	h, err := blake2b.New512(nil)
+	if err != nil {
+		return "", err
+	}
+	h.Write([]byte("SS58PRE"))
+	h.Write(out)
+	return base58.Encode(append(out, h.Sum(nil)[:2]...)), nil
+}
@@ -0,0 +1,63 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package address
+
+import (
+	"encoding/hex"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Ethereum returns the Ethereum address of a secp256k1 public key: the last 20 bytes of the Keccak-256 of its
+// uncompressed coordinates, in hex with the mixed-case checksum of EIP-55 and the 0x prefix. It is also the address of
+// the key on the other EVM chains.
+func Ethereum(pub *crypto.ECPoint) (string, error) {
+	if err := checkCurve(pub, tss.Secp256k1); err != nil {
+		return "", err
+	}
+	coords := make([]byte, 64)
+	pub.X().FillBytes(coords[:32])
+	pub.Y().FillBytes(coords[32:])
+	return checksumHex(keccak256(coords)[12:]), nil
+}
+
+// EthereumOf returns the Ethereum address of the key of ECDSA save data (see ECDSAKey)
+func EthereumOf(saves ...keygen.LocalPartySaveData) (string, error) {
+	pub, err := ECDSAKey(saves...)
+	if err != nil {
+		return "", err
+	}
+	return Ethereum(pub)
+}
+
+// checksumHex encodes an address in hex with the checksum of EIP-55: a letter is upper case where the matching nibble
+// of the Keccak-256 of the lower-case hex is 8 or more
+func checksumHex(addr []byte) string {
+	lower := hex.EncodeToString(addr)
+	hash := keccak256([]byte(lower))
+	out := []byte(lower)
+	for i, c := range out {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -5,6 +5,8 @@ import (
 	"math/big"
 
 	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
 )
 
 // CurveParams contains the parameters for the Baby JubJub curve.
@@ -40,8 +42,8 @@ type CurveParams struct {
 }
 
 var BabyJubJubParams = &CurveParams{
-	// P is the prime used in the field.
-	P: NewIntFromString("21888242871839275222246405745257275088548364400416034343698204186575808495617"),
+	// P is the prime used in the field, which is also the field of Poseidon.
+	P: common.PoseidonFieldModulus(),
 	// N is the order of the curve group generated by the base point.
 	N: NewIntFromString("2736030358979909402780800718157159386076813972158567259200215660948447373041"),
 	// N:        NewIntFromString("21888242871839275222246405745257275088614511777268538073601725287587578984328"),
@@ -0,0 +1,16 @@
+package babyjubjub
+
+import (
+	"testing"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestFieldModulusMatchesPoseidon(t *testing.T) {
+	assert.Equal(t, common.PoseidonFieldModulus(), BabyJubJubParams.P)
+	assert.Equal(t, common.PoseidonFieldModulus(), BabyJubJub().Params().P)
+	assert.Equal(t, iden3bjj.SubOrder, BabyJubJubParams.N)
+}
@@ -0,0 +1,86 @@
+package babyjubjub
+
+import (
+	"errors"
+	"math/big"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+)
+
+// CircomSignatureLen is the length of a packed CircomSignature: compressed R8, S and compressed A of 32 bytes each.
+const CircomSignatureLen = 96
+
+// CircomSignature is an EdDSA-Poseidon signature (R8, S) with the public key A that verifies it, laid out for the
+// EdDSAPoseidonVerifier of circomlib and the iden3 circuits.
+type CircomSignature struct {
+	R8x, R8y *big.Int
+	S        *big.Int
+	Ax, Ay   *big.Int
+}
+
+// Marshal packs the signature as circomlib expects it: the 64-byte compressed signature (compressed R8 then S in
+// little-endian), followed by the 32-byte compressed public key. Points are compressed as in iden3 babyjub, with the
+// y coordinate in little-endian and the sign of x in the most significant bit.
+func (sig *CircomSignature) Marshal() ([]byte, error) {
+	if !sig.ValidateBasic() {
+		return nil, errors.New("CircomSignature: invalid signature")
+	}
+	bz := make([]byte, CircomSignatureLen)
+	copy(bz, Compress(sig.R8x, sig.R8y))
+	sBz := sig.S.FillBytes(make([]byte, 32))
+	for i, b := range sBz {
+		bz[64-1-i] = b
+	}
+	copy(bz[64:], Compress(sig.Ax, sig.Ay))
+	return bz, nil
+}
+
+// Compress encodes the point in 32 bytes as iden3 babyjub does
+func Compress(x, y *big.Int) []byte {
+	comp := (&iden3bjj.Point{X: x, Y: y}).Compress()
+	return comp[:]
+}
+
+// UnmarshalCircomSignature parses a signature packed by Marshal, checking that its points are on the curve
+func UnmarshalCircomSignature(bz []byte) (*CircomSignature, error) {
+	if len(bz) != CircomSignatureLen {
+		return nil, errors.New("UnmarshalCircomSignature: wrong length")
+	}
+	var r8Comp, aComp [32]byte
+	copy(r8Comp[:], bz[:32])
+	copy(aComp[:], bz[64:])
+	r8, err := iden3bjj.NewPoint().Decompress(r8Comp)
+	if err != nil {
+		return nil, err
+	}
+	a, err := iden3bjj.NewPoint().Decompress(aComp)
+	if err != nil {
+		return nil, err
+	}
+	sBz := make([]byte, 32)
+	for i := range sBz {
+		sBz[i] = bz[64-1-i]
+	}
+	sig := &CircomSignature{
+		R8x: r8.X,
+		R8y: r8.Y,
+		S:   new(big.Int).SetBytes(sBz),
+		Ax:  a.X,
+		Ay:  a.Y,
+	}
+	if !sig.ValidateBasic() {
+		return nil, errors.New("UnmarshalCircomSignature: invalid signature")
+	}
+	return sig, nil
+}
+
+// ValidateBasic checks that the points are on the curve and that S is reduced modulo the subgroup order
+func (sig *CircomSignature) ValidateBasic() bool {
+	if sig == nil || sig.R8x == nil || sig.R8y == nil || sig.S == nil || sig.Ax == nil || sig.Ay == nil {
+		return false
+	}
+	if sig.S.Sign() < 0 || sig.S.Cmp(BabyJubJubParams.N) >= 0 {
+		return false
+	}
+	return babyjubjub.IsOnCurve(sig.R8x, sig.R8y) && babyjubjub.IsOnCurve(sig.Ax, sig.Ay)
+}
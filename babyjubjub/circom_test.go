@@ -0,0 +1,33 @@
+package babyjubjub
+
+import (
+	"math/big"
+	"testing"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircomSignatureMatchesIden3(t *testing.T) {
+	k := iden3bjj.NewRandPrivKey()
+	pub := k.Public()
+	sig := k.SignPoseidon(big.NewInt(42))
+
+	circomSig := &CircomSignature{R8x: sig.R8.X, R8y: sig.R8.Y, S: sig.S, Ax: pub.X, Ay: pub.Y}
+	bz, err := circomSig.Marshal()
+	assert.NoError(t, err)
+	sigComp := sig.Compress()
+	pubComp := pub.Compress()
+	assert.Equal(t, sigComp[:], bz[:64], "signature should be packed as iden3 compresses it")
+	assert.Equal(t, pubComp[:], bz[64:], "public key should be packed as iden3 compresses it")
+
+	parsed, err := UnmarshalCircomSignature(bz)
+	assert.NoError(t, err)
+	assert.Equal(t, circomSig, parsed)
+
+	_, err = UnmarshalCircomSignature(bz[:64])
+	assert.Error(t, err, "short input should fail")
+	circomSig.S = BabyJubJubParams.N
+	_, err = circomSig.Marshal()
+	assert.Error(t, err, "unreduced S should fail")
+}
@@ -0,0 +1,46 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Command coordinator serves the REST API of package transport/coordinator, which relays the messages of the parties
+// of keygen, signing and resharing sessions running on separate machines and tracks their progress.
+//
+//	coordinator -addr :8080 -tls-cert cert.pem -tls-key key.pem
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/transport/coordinator"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "the address to listen on")
+	certFile := flag.String("tls-cert", "", "the TLS certificate; the server speaks plain HTTP without one")
+	keyFile := flag.String("tls-key", "", "the key of the TLS certificate")
+	maxMessages := flag.Int("max-messages", 100000, "the most messages a session may hold")
+	flag.Parse()
+
+	handler := coordinator.NewServer()
+	handler.SetMaxMessages(*maxMessages)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	common.Logger.Infof("coordinator listening on %s", *addr)
+	var err error
+	if *certFile != "" {
+		err = server.ListenAndServeTLS(*certFile, *keyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	common.Logger.Errorf("coordinator stopped: %v", err)
+	os.Exit(1)
+}
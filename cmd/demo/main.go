@@ -0,0 +1,222 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Command demo runs a key through its life on one machine: it spins up every party as a process of its own, the
+// parties talking over gRPC on localhost, and runs a keygen, a resharing to a new committee, and a signing by the
+// new committee, printing how long each took.
+//
+//	demo -scheme eddsa -n 3 -t 1 -new-n 4 -new-t 2
+//
+// It serves as an end-to-end test of the protocols over a real network, and as an example of how to run a party:
+// see party.go, which is all that each process runs.
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+type (
+	// member is a party of the demo, known to every process from the committees file
+	member struct {
+		ID   string `json:"id"`
+		Key  string `json:"key"`
+		Addr string `json:"addr"`
+	}
+
+	// committees is the committees file of the demo, shared by all its processes
+	committees struct {
+		Scheme       string   `json:"scheme"`
+		Threshold    int      `json:"threshold"`
+		NewThreshold int      `json:"new_threshold"`
+		Old          []member `json:"old"`
+		New          []member `json:"new"`
+	}
+)
+
+const committeesFile = "committees.json"
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "party" {
+		if err := runParty(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	scheme := flag.String("scheme", "eddsa", "ecdsa or eddsa")
+	n := flag.Int("n", 3, "the number of parties of the keygen")
+	t := flag.Int("t", 1, "the threshold of the keygen")
+	newN := flag.Int("new-n", 4, "the number of parties of the new committee")
+	newT := flag.Int("new-t", 2, "the threshold of the new committee")
+	port := flag.Int("port", 47100, "the first of the ports on localhost for the parties to listen on")
+	dir := flag.String("dir", "", "the directory to keep the shares in; a temporary one by default")
+	flag.Parse()
+
+	if err := demo(*scheme, *n, *t, *newN, *newT, *port, *dir); err != nil {
+		fmt.Fprintf(os.Stderr, "demo: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func demo(scheme string, n, t, newN, newT, port int, dir string) error {
+	if scheme != "ecdsa" && scheme != "eddsa" {
+		return fmt.Errorf("unknown scheme %q", scheme)
+	}
+	if t < 1 || n <= t || newT < 1 || newN <= newT {
+		return fmt.Errorf("the committees need more parties than their threshold, and a threshold of at least 1")
+	}
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "tss-demo")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+	c := committees{
+		Scheme:       scheme,
+		Threshold:    t,
+		NewThreshold: newT,
+		Old:          newMembers("old", n, port),
+		New:          newMembers("new", newN, port+n),
+	}
+	bz, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, committeesFile), bz, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("%s key: keygen by %d parties (threshold %d), resharing to %d parties (threshold %d), signing by %d; shares in %s\n",
+		scheme, n, t, newN, newT, newT+1, dir)
+
+	total := time.Now()
+	if err := phase("keygen", dir, c.Old); err != nil {
+		return err
+	}
+	if err := phase("reshare", dir, append(append([]member(nil), c.Old...), c.New...)); err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte("tss-lib demo"))
+	signers := c.New[:newT+1]
+	signerIDs := ""
+	for i, m := range signers {
+		if i > 0 {
+			signerIDs += ","
+		}
+		signerIDs += m.ID
+	}
+	if err := phase("sign", dir, signers, "-signers", signerIDs, "-message", hex.EncodeToString(digest[:])); err != nil {
+		return err
+	}
+	fmt.Printf("done in %s\n", time.Since(total).Round(time.Millisecond))
+	return nil
+}
+
+// newMembers returns `count` parties listening on consecutive ports from `port`
+func newMembers(prefix string, count, port int) []member {
+	members := make([]member, count)
+	for i := range members {
+		members[i] = member{
+			ID:   fmt.Sprintf("%s-%d", prefix, i+1),
+			Key:  common.MustGetRandomInt(rand.Reader, 256).Text(16),
+			Addr: fmt.Sprintf("127.0.0.1:%d", port+i),
+		}
+	}
+	return members
+}
+
+// phase runs a process for each of the parties of a session, passing each its ID and `args`, and waits for them all
+func phase(name, dir string, parties []member, args ...string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	var wg sync.WaitGroup
+	errs := make([]error, len(parties))
+	for i, m := range parties {
+		cmd := exec.Command(self, append([]string{"party", "-phase", name, "-dir", dir, "-id", m.ID}, args...)...)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		cmd.Stderr = cmd.Stdout
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		wg.Add(1)
+		go func(i int, id string, cmd *exec.Cmd, stdout io.Reader) {
+			defer wg.Done()
+			lines := bufio.NewScanner(stdout)
+			for lines.Scan() {
+				fmt.Printf("  [%s] %s\n", id, lines.Text())
+			}
+			if err := cmd.Wait(); err != nil {
+				errs[i] = fmt.Errorf("%s of %s: %w", name, id, err)
+			}
+		}(i, m.ID, cmd, stdout)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%-8s %d parties in %s\n", name, len(parties), time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// loadCommittees reads the committees file of the demo, with the party IDs of both committees
+func loadCommittees(dir string) (*committees, tss.SortedPartyIDs, tss.SortedPartyIDs, map[*tss.PartyID]string, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, committeesFile))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	c := new(committees)
+	if err := json.Unmarshal(bz, c); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	addrs := make(map[*tss.PartyID]string, len(c.Old)+len(c.New))
+	partyIDs := func(members []member) (tss.SortedPartyIDs, error) {
+		ids := make(tss.UnSortedPartyIDs, len(members))
+		for i, m := range members {
+			key, ok := new(big.Int).SetString(m.Key, 16)
+			if !ok {
+				return nil, fmt.Errorf("party %s has an invalid key", m.ID)
+			}
+			ids[i] = tss.NewPartyID(m.ID, m.ID, key)
+			addrs[ids[i]] = m.Addr
+		}
+		return tss.SortPartyIDs(ids), nil
+	}
+	oldIDs, err := partyIDs(c.Old)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	newIDs, err := partyIDs(c.New)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return c, oldIDs, newIDs, addrs, nil
+}
@@ -0,0 +1,255 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	ecdsaresharing "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	ecdsasigning "github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	eddsaresharing "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	eddsasigning "github.com/bnb-chain/tss-lib/v2/eddsa/signing"
+	tssgrpc "github.com/bnb-chain/tss-lib/v2/transport/grpc"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// the buffer of the out channel of a party, ample for a round of messages to every other party
+	outBuffer = 1024
+
+	sessionTimeout = 10 * time.Minute
+	// how long a party stays up once it has its result, for its last messages to reach the others
+	linger = 2 * time.Second
+)
+
+// runParty runs one party of a phase of the demo, in a process of its own
+func runParty(args []string) error {
+	fs := flag.NewFlagSet("party", flag.ExitOnError)
+	phase := fs.String("phase", "", "keygen, reshare or sign")
+	dir := fs.String("dir", "", "the directory of the demo")
+	id := fs.String("id", "", "the ID of this party")
+	signersList := fs.String("signers", "", "the comma-separated IDs of the signers")
+	message := fs.String("message", "", "the digest to sign, in hex")
+	_ = fs.Parse(args)
+
+	c, oldIDs, newIDs, addrs, err := loadCommittees(*dir)
+	if err != nil {
+		return err
+	}
+	ec := tss.S256()
+	if c.Scheme == "eddsa" {
+		ec = tss.Edwards()
+	}
+	sharePath := filepath.Join(*dir, *id+".json")
+
+	switch *phase {
+	case "keygen":
+		self := find(oldIDs, *id)
+		params := tss.NewParameters(ec, tss.NewPeerContext(oldIDs), self, len(oldIDs), c.Threshold)
+		var key interface{}
+		done := make(chan struct{})
+		if c.Scheme == "ecdsa" {
+			S := ecdsakeygen.NewSession(params, outBuffer)
+			go func() { key = <-S.End; close(done) }()
+			err = runSession(self, oldIDs, addrs, S, S.Out, done)
+		} else {
+			S := eddsakeygen.NewSession(params, outBuffer)
+			go func() { key = <-S.End; close(done) }()
+			err = runSession(self, oldIDs, addrs, S, S.Out, done)
+		}
+		if err != nil {
+			return err
+		}
+		return writeJSON(sharePath, key)
+
+	case "reshare":
+		// each party is in one of the committees: the old one holds the shares, the new one receives them
+		self, inOld := find(oldIDs, *id), true
+		if self == nil {
+			self, inOld = find(newIDs, *id), false
+		}
+		params := tss.NewReSharingParameters(ec, tss.NewPeerContext(oldIDs), tss.NewPeerContext(newIDs), self,
+			len(oldIDs), c.Threshold, len(newIDs), c.NewThreshold)
+		both := append(append(tss.SortedPartyIDs(nil), oldIDs...), newIDs...)
+		var key interface{}
+		done := make(chan struct{})
+		if c.Scheme == "ecdsa" {
+			save := ecdsakeygen.NewLocalPartySaveData(len(newIDs))
+			if inOld {
+				if err := readJSON(sharePath, &save); err != nil {
+					return err
+				}
+			}
+			S := ecdsaresharing.NewSession(params, save, outBuffer)
+			go func() { key = <-S.End; close(done) }()
+			err = runSession(self, both, addrs, S, S.Out, done)
+		} else {
+			save := eddsakeygen.NewLocalPartySaveData(len(newIDs))
+			if inOld {
+				if err := readJSON(sharePath, &save); err != nil {
+					return err
+				}
+			}
+			S := eddsaresharing.NewSession(params, save, outBuffer)
+			go func() { key = <-S.End; close(done) }()
+			err = runSession(self, both, addrs, S, S.Out, done)
+		}
+		if err != nil || inOld {
+			return err
+		}
+		return writeJSON(sharePath, key)
+
+	case "sign":
+		signers := make(tss.UnSortedPartyIDs, 0, len(newIDs))
+		for _, signer := range strings.Split(*signersList, ",") {
+			if pid := find(newIDs, signer); pid != nil {
+				signers = append(signers, pid)
+			}
+		}
+		signerIDs := tss.SortPartyIDs(signers)
+		self := find(signerIDs, *id)
+		digest, err := hex.DecodeString(*message)
+		if err != nil {
+			return err
+		}
+		msg := new(big.Int).SetBytes(digest)
+		params := tss.NewParameters(ec, tss.NewPeerContext(signerIDs), self, len(signerIDs), c.NewThreshold)
+		var sig *common.SignatureData
+		done := make(chan struct{})
+		if c.Scheme == "ecdsa" {
+			var save ecdsakeygen.LocalPartySaveData
+			if err := readJSON(sharePath, &save); err != nil {
+				return err
+			}
+			S := ecdsasigning.NewSession(msg, params, ecdsakeygen.BuildLocalSaveDataSubset(save, signerIDs), outBuffer, len(digest))
+			go func() { sig = <-S.End; close(done) }()
+			if err := runSession(self, signerIDs, addrs, S, S.Out, done); err != nil {
+				return err
+			}
+			pk := ecdsa.PublicKey{Curve: ec, X: save.ECDSAPub.X(), Y: save.ECDSAPub.Y()}
+			if !ecdsa.Verify(&pk, digest, new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)) {
+				return errors.New("the signature does not verify")
+			}
+		} else {
+			var save eddsakeygen.LocalPartySaveData
+			if err := readJSON(sharePath, &save); err != nil {
+				return err
+			}
+			S := eddsasigning.NewSession(msg, params, eddsakeygen.BuildLocalSaveDataSubset(save, signerIDs), outBuffer, len(digest))
+			go func() { sig = <-S.End; close(done) }()
+			if err := runSession(self, signerIDs, addrs, S, S.Out, done); err != nil {
+				return err
+			}
+			pk := edwards.PublicKey{Curve: ec, X: save.EDDSAPub.X(), Y: save.EDDSAPub.Y()}
+			if !edwards.Verify(&pk, digest, new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)) {
+				return errors.New("the signature does not verify")
+			}
+		}
+		fmt.Printf("signature %x verifies\n", sig.Signature)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown phase %q", *phase)
+	}
+}
+
+// runSession serves `party` over gRPC at its address, sends its messages to the servers of the other parties, and
+// waits until `done` is closed once the party has its result, or until the party fails
+func runSession(self *tss.PartyID, parties tss.SortedPartyIDs, addrs map[*tss.PartyID]string, party tss.Party, out <-chan tss.Message, done <-chan struct{}) error {
+	if self == nil {
+		return errors.New("this party is not in the session")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sessionTimeout)
+	defer cancel()
+	errCh := make(chan *tss.Error, len(parties))
+
+	lis, err := net.Listen("tcp", addrs[self])
+	if err != nil {
+		return err
+	}
+	gs := grpc.NewServer()
+	tssgrpc.NewServer(party, parties, errCh).Register(gs)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	peers := make([]tssgrpc.Peer, 0, len(parties)-1)
+	for _, pid := range parties {
+		if pid != self {
+			peers = append(peers, tssgrpc.Peer{ID: pid, Addr: addrs[pid]})
+		}
+	}
+	// the processes come up in their own time, so the messages wait for the servers of their recipients
+	client, err := tssgrpc.Dial(peers,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	go client.Run(ctx, party, out, errCh)
+
+	start := time.Now()
+	go func() {
+		if err := party.Start(); err != nil {
+			errCh <- err
+		}
+	}()
+	select {
+	case <-done:
+		fmt.Printf("done in %s\n", time.Since(start).Round(time.Millisecond))
+		time.Sleep(linger)
+		return nil
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return errors.New("the session timed out")
+	}
+}
+
+func find(ids tss.SortedPartyIDs, id string) *tss.PartyID {
+	for _, pid := range ids {
+		if pid.Id == id {
+			return pid
+		}
+	}
+	return nil
+}
+
+func readJSON(path string, v interface{}) error {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bz, v)
+}
+
+func writeJSON(path string, v interface{}) error {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bz, 0600)
+}
@@ -0,0 +1,100 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Command migrate converts the save data files of upstream tss-lib into the format of this library (see package
+// storage/migrate), writing each to the output directory under its own name.
+//
+//	migrate -scheme ecdsa -hash-scheme SHA512_256 -out migrated keygen_data_0.json keygen_data_1.json
+//
+// With TSS_PASSPHRASE set in the environment, the files are written encrypted in the format of storage/keyfile.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/storage/keyfile"
+	"github.com/bnb-chain/tss-lib/v2/storage/migrate"
+)
+
+const passphraseEnv = "TSS_PASSPHRASE"
+
+func main() {
+	scheme := flag.String("scheme", "ecdsa", "the scheme of the save data: ecdsa or eddsa")
+	hashScheme := flag.String("hash-scheme", common.HashSHA512_256.String(),
+		"the hash scheme of the sessions on the keys, e.g. SHA512_256, as upstream, or Poseidon")
+	out := flag.String("out", "", "the directory to write the converted files to")
+	flag.Parse()
+	if *out == "" || flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: migrate -scheme ecdsa|eddsa [-hash-scheme name] -out dir file...")
+		os.Exit(2)
+	}
+	if err := run(*scheme, *hashScheme, *out, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(scheme, hashSchemeName, out string, files []string) error {
+	hashScheme, err := parseHashScheme(hashSchemeName)
+	if err != nil {
+		return err
+	}
+	if scheme != "ecdsa" && scheme != "eddsa" {
+		return fmt.Errorf("unknown scheme %q; use ecdsa or eddsa", scheme)
+	}
+	if err := os.MkdirAll(out, 0700); err != nil {
+		return err
+	}
+	for _, file := range files {
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var save interface{}
+		if scheme == "ecdsa" {
+			save, err = migrate.ECDSA(bz, hashScheme)
+		} else {
+			save, err = migrate.EdDSA(bz, hashScheme)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		path := filepath.Join(out, filepath.Base(file))
+		if err := write(path, save); err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s\n", file, path)
+	}
+	return nil
+}
+
+func parseHashScheme(name string) (common.HashScheme, error) {
+	for scheme := common.HashSHA512_256; scheme <= common.HashSHA3_256; scheme++ {
+		if strings.EqualFold(scheme.String(), name) {
+			return scheme, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown hash scheme %q", name)
+}
+
+// write writes the save data only readable by its owner, encrypted with the passphrase of the environment if there
+// is one
+func write(path string, save interface{}) error {
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		return keyfile.Save(path, []byte(passphrase), save)
+	}
+	bz, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bz, 0600)
+}
@@ -0,0 +1,250 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/refresh"
+	ecdsaresharing "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	ecdsasigning "github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	eddsaresharing "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	eddsasigning "github.com/bnb-chain/tss-lib/v2/eddsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/transport/coordinator"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// the buffer of the out channel of a party, ample for a round of messages to every other party
+const outBuffer = 1024
+
+func keygenCommand(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	scheme := fs.String("scheme", schemeECDSA, "ecdsa, eddsa or bjj")
+	partiesPath := fs.String("parties", "", "the parties file of the committee")
+	id := fs.String("id", "", "the ID of this party in the parties file")
+	threshold := fs.Int("threshold", 0, "the threshold t; t+1 parties sign")
+	out := fs.String("out", "", "the share file to write")
+	var link linkFlags
+	link.register(fs)
+	_ = fs.Parse(args)
+	if *partiesPath == "" || *id == "" || *out == "" {
+		return errors.New("-parties, -id and -out are required")
+	}
+	ec, err := curveOf(*scheme)
+	if err != nil {
+		return err
+	}
+	peers, err := loadParties(*partiesPath)
+	if err != nil {
+		return err
+	}
+	self, err := peers.find(*id)
+	if err != nil {
+		return err
+	}
+	params := tss.NewParameters(ec, tss.NewPeerContext(peers.ids), self, len(peers.ids), *threshold)
+	share := &shareFile{Scheme: *scheme, Self: *id, Threshold: *threshold, Parties: peers.entries}
+	done := make(chan struct{})
+	if *scheme == schemeECDSA {
+		fmt.Fprintln(os.Stderr, "generating the safe primes of this party; this may take a few minutes")
+		S := ecdsakeygen.NewSession(params, outBuffer)
+		go func() { share.ECDSA = <-S.End; close(done) }()
+		err = link.run(coordinator.ProtocolKeygen, self, peers, S, S.Out, done)
+	} else {
+		S := eddsakeygen.NewSession(params, outBuffer)
+		go func() { share.EdDSA = <-S.End; close(done) }()
+		err = link.run(coordinator.ProtocolKeygen, self, peers, S, S.Out, done)
+	}
+	if err != nil {
+		return err
+	}
+	return writeShare(*out, share)
+}
+
+func signCommand(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	sharePath := fs.String("share", "", "the share file of this party")
+	signersList := fs.String("signers", "", "the comma-separated IDs of the signers, this party included; all parties by default")
+	message := fs.String("message", "", "the message to sign in hex: the digest for ecdsa, the message itself for eddsa and bjj")
+	var link linkFlags
+	link.register(fs)
+	_ = fs.Parse(args)
+	if *sharePath == "" || *message == "" {
+		return errors.New("-share and -message are required")
+	}
+	share, parties, err := loadShare(*sharePath)
+	if err != nil {
+		return err
+	}
+	ec, err := curveOf(share.Scheme)
+	if err != nil {
+		return err
+	}
+	msgBytes, err := hex.DecodeString(*message)
+	if err != nil {
+		return fmt.Errorf("-message: %w", err)
+	}
+	signers := parties
+	if *signersList != "" {
+		if signers, err = parties.subset(strings.Split(*signersList, ",")); err != nil {
+			return err
+		}
+	}
+	self, err := signers.find(share.Self)
+	if err != nil {
+		return err
+	}
+	params := tss.NewParameters(ec, tss.NewPeerContext(signers.ids), self, len(signers.ids), share.Threshold)
+	msg := new(big.Int).SetBytes(msgBytes)
+	var sig *common.SignatureData
+	done := make(chan struct{})
+	if share.Scheme == schemeECDSA {
+		key := ecdsakeygen.BuildLocalSaveDataSubset(*share.ECDSA, signers.ids)
+		S := ecdsasigning.NewSession(msg, params, key, outBuffer, len(msgBytes))
+		go func() { sig = <-S.End; close(done) }()
+		err = link.run(coordinator.ProtocolSigning, self, signers, S, S.Out, done)
+	} else {
+		key := eddsakeygen.BuildLocalSaveDataSubset(*share.EdDSA, signers.ids)
+		S := eddsasigning.NewSession(msg, params, key, outBuffer, len(msgBytes))
+		go func() { sig = <-S.End; close(done) }()
+		err = link.run(coordinator.ProtocolSigning, self, signers, S, S.Out, done)
+	}
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(struct {
+		Signature string `json:"signature"`
+		R         string `json:"r"`
+		S         string `json:"s"`
+		Recovery  string `json:"recovery,omitempty"`
+	}{
+		Signature: hex.EncodeToString(sig.Signature),
+		R:         hex.EncodeToString(sig.R),
+		S:         hex.EncodeToString(sig.S),
+		Recovery:  hex.EncodeToString(sig.SignatureRecovery),
+	})
+}
+
+func reshareCommand(args []string) error {
+	fs := flag.NewFlagSet("reshare", flag.ExitOnError)
+	committeeName := fs.String("committee", "old", "the committee of this party: old, holding a share, or new")
+	sharePath := fs.String("share", "", "the share file of this party, in the old committee")
+	scheme := fs.String("scheme", schemeECDSA, "the scheme of the key, in the new committee")
+	partiesPath := fs.String("parties", "", "the parties file of the old committee, in the new committee")
+	id := fs.String("id", "", "the ID of this party, in the new committee")
+	threshold := fs.Int("threshold", 0, "the threshold of the old committee, in the new committee")
+	newPartiesPath := fs.String("new-parties", "", "the parties file of the new committee")
+	newThreshold := fs.Int("new-threshold", 0, "the threshold of the new committee")
+	out := fs.String("out", "", "the share file to write, in the new committee")
+	var link linkFlags
+	link.register(fs)
+	_ = fs.Parse(args)
+
+	newParties, err := loadParties(*newPartiesPath)
+	if err != nil {
+		return fmt.Errorf("-new-parties: %w", err)
+	}
+	var share *shareFile
+	var oldParties *committee
+	var self *tss.PartyID
+	switch *committeeName {
+	case "old":
+		if share, oldParties, err = loadShare(*sharePath); err != nil {
+			return err
+		}
+		*scheme, *threshold = share.Scheme, share.Threshold
+		self, err = oldParties.find(share.Self)
+	case "new":
+		if *out == "" {
+			return errors.New("-out is required in the new committee")
+		}
+		if oldParties, err = loadParties(*partiesPath); err != nil {
+			return fmt.Errorf("-parties: %w", err)
+		}
+		self, err = newParties.find(*id)
+	default:
+		return fmt.Errorf("unknown committee %q", *committeeName)
+	}
+	if err != nil {
+		return err
+	}
+	ec, err := curveOf(*scheme)
+	if err != nil {
+		return err
+	}
+	params := tss.NewReSharingParameters(ec, tss.NewPeerContext(oldParties.ids), tss.NewPeerContext(newParties.ids), self,
+		len(oldParties.ids), *threshold, len(newParties.ids), *newThreshold)
+	both, err := oldParties.join(newParties)
+	if err != nil {
+		return err
+	}
+
+	result := &shareFile{Scheme: *scheme, Self: *id, Threshold: *newThreshold, Parties: newParties.entries}
+	done := make(chan struct{})
+	if *scheme == schemeECDSA {
+		key := ecdsakeygen.NewLocalPartySaveData(len(newParties.ids))
+		if share != nil {
+			key = ecdsakeygen.BuildLocalSaveDataSubset(*share.ECDSA, oldParties.ids)
+		}
+		S := ecdsaresharing.NewSession(params, key, outBuffer)
+		go func() { result.ECDSA = <-S.End; close(done) }()
+		err = link.run(coordinator.ProtocolResharing, self, both, S, S.Out, done)
+	} else {
+		key := eddsakeygen.NewLocalPartySaveData(len(newParties.ids))
+		if share != nil {
+			key = eddsakeygen.BuildLocalSaveDataSubset(*share.EdDSA, oldParties.ids)
+		}
+		S := eddsaresharing.NewSession(params, key, outBuffer)
+		go func() { result.EdDSA = <-S.End; close(done) }()
+		err = link.run(coordinator.ProtocolResharing, self, both, S, S.Out, done)
+	}
+	if err != nil || *committeeName == "old" {
+		return err
+	}
+	return writeShare(*out, result)
+}
+
+func refreshCommand(args []string) error {
+	fs := flag.NewFlagSet("refresh", flag.ExitOnError)
+	sharePath := fs.String("share", "", "the share file of this party")
+	out := fs.String("out", "", "the share file to write; it may be the same as -share")
+	var link linkFlags
+	link.register(fs)
+	_ = fs.Parse(args)
+	if *sharePath == "" || *out == "" {
+		return errors.New("-share and -out are required")
+	}
+	share, parties, err := loadShare(*sharePath)
+	if err != nil {
+		return err
+	}
+	if share.Scheme != schemeECDSA {
+		return errors.New("only ecdsa shares can be refreshed")
+	}
+	self, err := parties.find(share.Self)
+	if err != nil {
+		return err
+	}
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(parties.ids), self, len(parties.ids), share.Threshold)
+	S := refresh.NewSession(params, *share.ECDSA, outBuffer)
+	done := make(chan struct{})
+	go func() { share.ECDSA = <-S.End; close(done) }()
+	if err := link.run(coordinator.ProtocolRefresh, self, parties, S, S.Out, done); err != nil {
+		return err
+	}
+	return writeShare(*out, share)
+}
@@ -0,0 +1,198 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/storage/keyfile"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	schemeECDSA = "ecdsa"
+	schemeEdDSA = "eddsa"
+	schemeBJJ   = "bjj"
+
+	// the environment variable of the passphrase that share files are encrypted with
+	passphraseEnv = "TSS_PASSPHRASE"
+)
+
+type (
+	// partyEntry is a party in a parties file, a JSON array of them
+	partyEntry struct {
+		ID      string `json:"id"`
+		Moniker string `json:"moniker,omitempty"`
+		// the key of the party, in hex
+		Key         string `json:"key"`
+		IdentityKey []byte `json:"identity_key,omitempty"`
+		// the gRPC address of the party, for -listen
+		Addr string `json:"addr,omitempty"`
+	}
+
+	// shareFile is what a party keeps of a key: its save data with the committee it was generated with
+	shareFile struct {
+		Scheme    string                          `json:"scheme"`
+		Self      string                          `json:"self"`
+		Threshold int                             `json:"threshold"`
+		Parties   []partyEntry                    `json:"parties"`
+		ECDSA     *ecdsakeygen.LocalPartySaveData `json:"ecdsa,omitempty"`
+		EdDSA     *eddsakeygen.LocalPartySaveData `json:"eddsa,omitempty"`
+	}
+
+	// committee is the parties of a session, sorted, with their addresses
+	committee struct {
+		entries []partyEntry
+		ids     tss.SortedPartyIDs
+		addrs   map[*tss.PartyID]string
+	}
+)
+
+func curveOf(scheme string) (elliptic.Curve, error) {
+	switch scheme {
+	case schemeECDSA:
+		return tss.S256(), nil
+	case schemeEdDSA:
+		return tss.Edwards(), nil
+	case schemeBJJ:
+		return tss.BabyJubJub(), nil
+	case "rsa":
+		return nil, errors.New("tss-lib has no threshold RSA protocol")
+	default:
+		return nil, fmt.Errorf("unknown scheme %q; use ecdsa, eddsa or bjj", scheme)
+	}
+}
+
+func loadParties(path string) (*committee, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []partyEntry
+	if err := json.Unmarshal(bz, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return newCommittee(entries)
+}
+
+func newCommittee(entries []partyEntry) (*committee, error) {
+	ids := make(tss.UnSortedPartyIDs, len(entries))
+	addrs := make(map[*tss.PartyID]string, len(entries))
+	for i, e := range entries {
+		key, ok := new(big.Int).SetString(e.Key, 16)
+		if !ok || key.Sign() <= 0 {
+			return nil, fmt.Errorf("party %q has an invalid key %q", e.ID, e.Key)
+		}
+		ids[i] = tss.NewPartyID(e.ID, e.Moniker, key)
+		ids[i].IdentityKey = e.IdentityKey
+		addrs[ids[i]] = e.Addr
+	}
+	if err := tss.ValidatePartyIDs(ids); err != nil {
+		return nil, err
+	}
+	return &committee{entries: entries, ids: tss.SortPartyIDs(ids), addrs: addrs}, nil
+}
+
+// find returns the party with the ID
+func (c *committee) find(id string) (*tss.PartyID, error) {
+	for _, pid := range c.ids {
+		if pid.Id == id {
+			return pid, nil
+		}
+	}
+	return nil, fmt.Errorf("party %q is not in the committee", id)
+}
+
+// subset returns the committee of the parties with the IDs
+func (c *committee) subset(ids []string) (*committee, error) {
+	entries := make([]partyEntry, 0, len(ids))
+	for _, id := range ids {
+		found := false
+		for _, e := range c.entries {
+			if e.ID == id {
+				entries, found = append(entries, e), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("party %q is not in the committee", id)
+		}
+	}
+	return newCommittee(entries)
+}
+
+// join returns the parties of both committees, e.g. those of a resharing, keeping the party IDs of each committee
+// as they are indexed in it
+func (c *committee) join(other *committee) (*committee, error) {
+	joint := &committee{
+		entries: append(append([]partyEntry(nil), c.entries...), other.entries...),
+		ids:     append(append(tss.SortedPartyIDs(nil), c.ids...), other.ids...),
+		addrs:   make(map[*tss.PartyID]string, len(c.ids)+len(other.ids)),
+	}
+	seen := make(map[string]bool, len(joint.ids))
+	for _, pid := range joint.ids {
+		if seen[string(pid.Key)] {
+			return nil, fmt.Errorf("party %q is in both committees; give it a new key in the new one", pid.Id)
+		}
+		seen[string(pid.Key)] = true
+	}
+	for pid, addr := range c.addrs {
+		joint.addrs[pid] = addr
+	}
+	for pid, addr := range other.addrs {
+		joint.addrs[pid] = addr
+	}
+	return joint, nil
+}
+
+func loadShare(path string) (*shareFile, *committee, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if keyfile.IsEncrypted(bz) {
+		passphrase := os.Getenv(passphraseEnv)
+		if passphrase == "" {
+			return nil, nil, fmt.Errorf("%s is encrypted; set %s", path, passphraseEnv)
+		}
+		if bz, err = keyfile.Decrypt(bz, []byte(passphrase)); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	share := new(shareFile)
+	if err := json.Unmarshal(bz, share); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if (share.Scheme == schemeECDSA) != (share.ECDSA != nil) || (share.Scheme != schemeECDSA) != (share.EdDSA != nil) {
+		return nil, nil, fmt.Errorf("%s: the share does not match its scheme %q", path, share.Scheme)
+	}
+	c, err := newCommittee(share.Parties)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return share, c, nil
+}
+
+// writeShare writes the share only readable by its owner, as it holds a secret, and encrypted with the passphrase
+// of the environment if there is one
+func writeShare(path string, share *shareFile) error {
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		return keyfile.Save(path, []byte(passphrase), share)
+	}
+	bz, err := json.MarshalIndent(share, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bz, 0600)
+}
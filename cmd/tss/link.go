@@ -0,0 +1,126 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/bnb-chain/tss-lib/v2/transport/coordinator"
+	tssgrpc "github.com/bnb-chain/tss-lib/v2/transport/grpc"
+	"github.com/bnb-chain/tss-lib/v2/transport/websocket"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// linkFlags select the transport of a session
+type linkFlags struct {
+	coordinator string
+	relay       string
+	listen      string
+	session     string
+	timeout     time.Duration
+	linger      time.Duration
+}
+
+func (f *linkFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.coordinator, "coordinator", "", "the URL of the coordinator to exchange the messages through")
+	fs.StringVar(&f.relay, "relay", "", "the URL of the WebSocket relay to exchange the messages through")
+	fs.StringVar(&f.listen, "listen", "", "the address to serve gRPC on; the other parties are reached at their addr")
+	fs.StringVar(&f.session, "session", "", "the ID of the session, the same for every party")
+	fs.DurationVar(&f.timeout, "timeout", 10*time.Minute, "how long the session may take")
+	fs.DurationVar(&f.linger, "linger", 3*time.Second, "how long to stay connected once done, for the last messages to go out")
+}
+
+// run starts `party` of a session of `protocol` with its messages carried over the selected transport, and waits
+// until `end` is closed, once the party has its result, or until the party fails or the timeout expires
+func (f *linkFlags) run(protocol string, self *tss.PartyID, peers *committee, party tss.Party, out <-chan tss.Message, end <-chan struct{}) error {
+	if f.session == "" {
+		return errors.New("-session is required")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout)
+	defer cancel()
+	errCh := make(chan *tss.Error, len(peers.ids))
+
+	closeLink, err := f.connect(ctx, protocol, self, peers, party, out, errCh)
+	if err != nil {
+		return err
+	}
+	defer closeLink()
+	go func() {
+		if err := party.Start(); err != nil {
+			errCh <- err
+		}
+	}()
+	select {
+	case <-end:
+		time.Sleep(f.linger)
+		return nil
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return errors.New("the session timed out")
+	}
+}
+
+func (f *linkFlags) connect(ctx context.Context, protocol string, self *tss.PartyID, peers *committee, party tss.Party, out <-chan tss.Message, errCh chan<- *tss.Error) (func(), error) {
+	switch {
+	case f.coordinator != "":
+		client := coordinator.NewClient(f.coordinator, f.session, self, peers.ids)
+		// the first party to arrive creates the session; the others find it there
+		_ = client.CreateSession(ctx, coordinator.NewSessionSpec(f.session, protocol, 0, peers.ids))
+		go client.Receive(ctx, party, errCh)
+		go client.Run(ctx, party, out, errCh)
+		return func() {}, nil
+
+	case f.relay != "":
+		client, err := websocket.Dial(ctx, f.relay, f.session, self, peers.ids, nil)
+		if err != nil {
+			return nil, err
+		}
+		go client.Receive(party, errCh)
+		go client.Run(ctx, party, out, errCh)
+		return func() { _ = client.Close() }, nil
+
+	case f.listen != "":
+		lis, err := net.Listen("tcp", f.listen)
+		if err != nil {
+			return nil, err
+		}
+		gs := grpc.NewServer()
+		tssgrpc.NewServer(party, peers.ids, errCh).Register(gs)
+		go gs.Serve(lis)
+		dialPeers := make([]tssgrpc.Peer, 0, len(peers.ids))
+		for _, pid := range peers.ids {
+			if pid != self {
+				dialPeers = append(dialPeers, tssgrpc.Peer{ID: pid, Addr: peers.addrs[pid]})
+			}
+		}
+		// the parties are expected to run on a private network; put them behind TLS otherwise. The messages wait for
+		// the servers of the parties that are not up yet.
+		client, err := tssgrpc.Dial(dialPeers,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+		if err != nil {
+			gs.Stop()
+			return nil, err
+		}
+		go client.Run(ctx, party, out, errCh)
+		return func() {
+			_ = client.Close()
+			gs.GracefulStop()
+		}, nil
+
+	default:
+		return nil, errors.New("select a transport with -coordinator, -relay or -listen")
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Command tss runs one party of a keygen, signing, resharing or refresh session, reading and writing its share from
+// a file and reaching the other parties over a transport, so that the protocols can be run without writing Go.
+//
+//	tss keygen  -scheme ecdsa -parties parties.json -id alice -threshold 1 -coordinator http://host:8080 -session k1 -out alice.json
+//	tss sign    -share alice.json -signers alice,bob -message <hex digest> -coordinator http://host:8080 -session s1
+//	tss reshare -share alice.json -new-parties new.json -new-threshold 2 -coordinator http://host:8080 -session r1
+//	tss reshare -committee new -id dave -parties old.json -threshold 1 -new-parties new.json -new-threshold 2 ... -out dave.json
+//	tss refresh -share alice.json -coordinator http://host:8080 -session f1 -out alice.json
+//
+// The schemes are ecdsa (secp256k1), eddsa (ed25519) and bjj (EdDSA on BabyJubJub); refresh is for ecdsa only.
+// The transports are the coordinator of cmd/coordinator (-coordinator), a WebSocket relay (-relay), or gRPC between
+// the parties (-listen, with the address of every party in the parties file). With TSS_PASSPHRASE set in the
+// environment, shares are written encrypted in the format of storage/keyfile, and encrypted shares are read.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: tss <command> [flags]
+
+commands:
+  keygen    generate a key with the other parties and write this party's share
+  sign      sign a message with the other signers
+  reshare   move a key from the parties of its shares to a new committee
+  refresh   replace the shares of a key without changing it (ecdsa)
+
+run "tss <command> -h" for the flags of a command
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	commands := map[string]func(args []string) error{
+		"keygen":  keygenCommand,
+		"sign":    signCommand,
+		"reshare": reshareCommand,
+		"refresh": refreshCommand,
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err := command(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "tss %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
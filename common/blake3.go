@@ -0,0 +1,147 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// BLAKE3 in its default hashing mode with a 32-byte output, following the specification
+// (https://github.com/BLAKE3-team/BLAKE3-specs). Only the portable one-shot hash is implemented.
+const (
+	blake3BlockLen = 64
+	blake3ChunkLen = 1024
+
+	blake3ChunkStart = 1 << 0
+	blake3ChunkEnd   = 1 << 1
+	blake3Parent     = 1 << 2
+	blake3Root       = 1 << 3
+)
+
+var (
+	blake3IV = [8]uint32{
+		0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A, 0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+	}
+	blake3MsgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+)
+
+// Blake3 returns the 32-byte BLAKE3 digest of the data
+func Blake3(data []byte) []byte {
+	chunks := (len(data) + blake3ChunkLen - 1) / blake3ChunkLen
+	var cv [8]uint32
+	if chunks <= 1 {
+		cv = blake3ChunkCV(data, 0, blake3Root)
+	} else {
+		cv = blake3Subtree(data, 0, true)
+	}
+	out := make([]byte, 32)
+	for i, w := range cv {
+		binary.LittleEndian.PutUint32(out[i*4:], w)
+	}
+	return out
+}
+
+func blake3Ints(in ...*big.Int) *big.Int {
+	data := frameInts(in...)
+	if data == nil {
+		return nil
+	}
+	return new(big.Int).SetBytes(Blake3(data))
+}
+
+// blake3Subtree hashes the chunks of `data`, the first of which has index `counter`, as a tree whose left subtree
+// holds the largest power of two of chunks that leaves at least one chunk on the right
+func blake3Subtree(data []byte, counter uint64, root bool) [8]uint32 {
+	if len(data) <= blake3ChunkLen {
+		return blake3ChunkCV(data, counter, 0)
+	}
+	chunks := uint64((len(data) + blake3ChunkLen - 1) / blake3ChunkLen)
+	left := uint64(1) << (bits.Len64(chunks-1) - 1)
+	leftLen := int(left) * blake3ChunkLen
+	l := blake3Subtree(data[:leftLen], counter, false)
+	r := blake3Subtree(data[leftLen:], counter+left, false)
+	var block [16]uint32
+	copy(block[:8], l[:])
+	copy(block[8:], r[:])
+	flags := uint32(blake3Parent)
+	if root {
+		flags |= blake3Root
+	}
+	return blake3Compress(blake3IV, block, 0, blake3BlockLen, flags)
+}
+
+// blake3ChunkCV returns the chaining value of a chunk of at most blake3ChunkLen bytes, adding `endFlags` to its last block
+func blake3ChunkCV(chunk []byte, counter uint64, endFlags uint32) [8]uint32 {
+	cv := blake3IV
+	for start := 0; ; start += blake3BlockLen {
+		end := start + blake3BlockLen
+		last := end >= len(chunk)
+		if last {
+			end = len(chunk)
+		}
+		var buf [blake3BlockLen]byte
+		copy(buf[:], chunk[start:end])
+		var block [16]uint32
+		for i := range block {
+			block[i] = binary.LittleEndian.Uint32(buf[i*4:])
+		}
+		var flags uint32
+		if start == 0 {
+			flags |= blake3ChunkStart
+		}
+		if last {
+			flags |= blake3ChunkEnd | endFlags
+		}
+		cv = blake3Compress(cv, block, counter, uint32(end-start), flags)
+		if last {
+			return cv
+		}
+	}
+}
+
+// blake3Compress returns the first half of the output of the compression function, which is all that is needed here
+func blake3Compress(cv [8]uint32, block [16]uint32, counter uint64, blockLen, flags uint32) [8]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		blake3IV[0], blake3IV[1], blake3IV[2], blake3IV[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	m := block
+	for r := 0; r < 7; r++ {
+		blake3G(&s, 0, 4, 8, 12, m[0], m[1])
+		blake3G(&s, 1, 5, 9, 13, m[2], m[3])
+		blake3G(&s, 2, 6, 10, 14, m[4], m[5])
+		blake3G(&s, 3, 7, 11, 15, m[6], m[7])
+		blake3G(&s, 0, 5, 10, 15, m[8], m[9])
+		blake3G(&s, 1, 6, 11, 12, m[10], m[11])
+		blake3G(&s, 2, 7, 8, 13, m[12], m[13])
+		blake3G(&s, 3, 4, 9, 14, m[14], m[15])
+		var permuted [16]uint32
+		for i, j := range blake3MsgPermutation {
+			permuted[i] = m[j]
+		}
+		m = permuted
+	}
+	var out [8]uint32
+	for i := range out {
+		out[i] = s[i] ^ s[i+8]
+	}
+	return out
+}
+
+func blake3G(s *[16]uint32, a, b, c, d int, x, y uint32) {
+	s[a] = s[a] + s[b] + x
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] = s[c] + s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] = s[a] + s[b] + y
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] = s[c] + s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
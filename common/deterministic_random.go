@@ -0,0 +1,67 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/chacha20"
+)
+
+// DeterministicReader is a seeded ChaCha20 keystream used as the entropy source of a test or audit run, so that
+// its keys and signatures can be reproduced byte-for-byte, or of a session that may be resumed (see tss.Checkpointer).
+// Anyone who knows the seed knows every secret drawn from it: outside of tests, the seed must be random and kept as
+// secret as the key share.
+type DeterministicReader struct {
+	mtx    sync.Mutex
+	seed   []byte
+	stream *chacha20.Cipher
+}
+
+var _ io.Reader = (*DeterministicReader)(nil)
+
+// NewDeterministicReader returns the keystream of ChaCha20 keyed with SHA512_256(seed) and a zero nonce
+func NewDeterministicReader(seed []byte) *DeterministicReader {
+	key := SHA512_256(seed)
+	stream, err := chacha20.NewUnauthenticatedCipher(key, make([]byte, chacha20.NonceSize))
+	if err != nil {
+		// the key and nonce lengths are fixed, so this never happens
+		panic(err)
+	}
+	return &DeterministicReader{seed: key, stream: stream}
+}
+
+func (r *DeterministicReader) Read(p []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for i := range p {
+		p[i] = 0
+	}
+	r.stream.XORKeyStream(p, p)
+	return len(p), nil
+}
+
+// Fork returns an independent stream derived from this reader's seed and `label`, for a consumer that runs
+// concurrently with others and would otherwise make the order of reads, and so the output, non-deterministic
+func (r *DeterministicReader) Fork(label string) *DeterministicReader {
+	return NewDeterministicReader(SHA512_256(r.seed, []byte(label)))
+}
+
+// IsDeterministicRandom reports whether `rand` is a DeterministicReader
+func IsDeterministicRandom(rand io.Reader) bool {
+	_, ok := rand.(*DeterministicReader)
+	return ok
+}
+
+// ForkRandom returns rand.Fork(label) when `rand` is a DeterministicReader, or `rand` itself otherwise
+func ForkRandom(rand io.Reader, label string) io.Reader {
+	if dr, ok := rand.(*DeterministicReader); ok {
+		return dr.Fork(label)
+	}
+	return rand
+}
@@ -0,0 +1,52 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestDeterministicReader(t *testing.T) {
+	read := func(r io.Reader) []byte {
+		bz := make([]byte, 100)
+		_, err := io.ReadFull(r, bz)
+		assert.NoError(t, err)
+		return bz
+	}
+	r1, r2 := common.NewDeterministicReader([]byte("seed")), common.NewDeterministicReader([]byte("seed"))
+	assert.Equal(t, read(r1), read(r2), "same seed should give the same stream")
+	assert.NotEqual(t, read(common.NewDeterministicReader([]byte("other"))), read(common.NewDeterministicReader([]byte("seed"))))
+
+	fork := common.NewDeterministicReader([]byte("seed")).Fork("a")
+	assert.Equal(t, read(fork), read(common.NewDeterministicReader([]byte("seed")).Fork("a")), "forks should be reproducible")
+	assert.NotEqual(t, read(common.NewDeterministicReader([]byte("seed")).Fork("a")), read(common.NewDeterministicReader([]byte("seed")).Fork("b")))
+
+	assert.True(t, common.IsDeterministicRandom(r1))
+	assert.False(t, common.IsDeterministicRandom(nil))
+}
+
+func TestDeterministicPrimes(t *testing.T) {
+	p1 := common.GetRandomPrimeInt(common.NewDeterministicReader([]byte("seed")), 256)
+	p2 := common.GetRandomPrimeInt(common.NewDeterministicReader([]byte("seed")), 256)
+	assert.Equal(t, p1, p2, "primes should be reproducible")
+	assert.Equal(t, 256, p1.BitLen())
+	assert.True(t, p1.ProbablyPrime(30))
+
+	sgps1, err := common.GetRandomSafePrimesConcurrent(context.Background(), 128, 2, 4, common.NewDeterministicReader([]byte("seed")))
+	assert.NoError(t, err)
+	sgps2, err := common.GetRandomSafePrimesConcurrent(context.Background(), 128, 2, 4, common.NewDeterministicReader([]byte("seed")))
+	assert.NoError(t, err)
+	for i := range sgps1 {
+		assert.Equal(t, sgps1[i].SafePrime(), sgps2[i].SafePrime(), "safe primes should be reproducible")
+	}
+}
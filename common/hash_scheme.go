@@ -0,0 +1,109 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/poseidon"
+	"golang.org/x/crypto/sha3"
+)
+
+// HashScheme selects the hash function a session uses to derive its session identifiers (SSIDs) and hash commitments.
+// A scheme implements Hasher through the hasher registered for it.
+// All parties of a session, and every protocol run on the same key (keygen, resharing, signing), must agree on it.
+type HashScheme int
+
+const (
+	HashSHA512_256 HashScheme = iota // default
+	HashKeccak256
+	HashPoseidon
+	HashRescuePrime
+	HashBlake3
+	HashSHA3_256
+)
+
+func (scheme HashScheme) String() string {
+	hashersMtx.RLock()
+	defer hashersMtx.RUnlock()
+	if h, ok := hashers[scheme]; ok {
+		return h.name
+	}
+	return fmt.Sprintf("HashScheme(%d)", int(scheme))
+}
+
+// HashInts hashes the ints with the hasher registered for the scheme, framing them as SHA512_256i does.
+// Unknown schemes, and hasher failures, are logged and return nil.
+func (scheme HashScheme) HashInts(in ...*big.Int) *big.Int {
+	hasher, err := scheme.Hasher()
+	if err != nil {
+		Logger.Error(err)
+		return nil
+	}
+	return hasher.HashInts(in...)
+}
+
+func keccak256Ints(in ...*big.Int) *big.Int {
+	data := frameInts(in...)
+	if data == nil {
+		return nil
+	}
+	state := sha3.NewLegacyKeccak256()
+	state.Write(data)
+	return new(big.Int).SetBytes(state.Sum(nil))
+}
+
+func sha3_256Ints(in ...*big.Int) *big.Int {
+	data := frameInts(in...)
+	if data == nil {
+		return nil
+	}
+	h := sha3.Sum256(data)
+	return new(big.Int).SetBytes(h[:])
+}
+
+func poseidonInts(in ...*big.Int) *big.Int {
+	data := frameInts(in...)
+	if data == nil {
+		return nil
+	}
+	h, err := poseidon.HashBytes(data)
+	if err != nil {
+		Logger.Errorf("Poseidon HashBytes() failed: %v", err)
+		return nil
+	}
+	return h
+}
+
+// frameInts returns the length-prefixed, delimited encoding of the ints hashed by SHA512_256i
+func frameInts(in ...*big.Int) []byte {
+	inLen := len(in)
+	if inLen == 0 {
+		return nil
+	}
+	bzSize := 0
+	// prevent hash collisions with this prefix containing the block count
+	inLenBz := make([]byte, 64/8)
+	binary.LittleEndian.PutUint64(inLenBz, uint64(inLen))
+	ptrs := make([][]byte, inLen)
+	for i, n := range in {
+		ptrs[i] = n.Bytes()
+		bzSize += len(ptrs[i])
+	}
+	data := make([]byte, 0, len(inLenBz)+bzSize+inLen+(inLen*8))
+	data = append(data, inLenBz...)
+	for i := range in {
+		data = append(data, ptrs[i]...)
+		data = append(data, hashInputDelimiter) // safety delimiter
+		dataLen := make([]byte, 8)              // 64-bits
+		binary.LittleEndian.PutUint64(dataLen, uint64(len(ptrs[i])))
+		data = append(data, dataLen...)
+	}
+	return data
+}
@@ -0,0 +1,48 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestHashSchemeHashInts(t *testing.T) {
+	in := []*big.Int{big.NewInt(1), new(big.Int).Lsh(big.NewInt(1), 2048), big.NewInt(0)}
+
+	assert.Equal(t, common.SHA512_256i(in...), common.HashSHA512_256.HashInts(in...), "default scheme should be SHA512_256i")
+
+	seen := make(map[string]common.HashScheme)
+	schemes := []common.HashScheme{
+		common.HashSHA512_256, common.HashKeccak256, common.HashPoseidon, common.HashRescuePrime, common.HashBlake3,
+		common.HashSHA3_256,
+	}
+	for _, scheme := range schemes {
+		h := scheme.HashInts(in...)
+		if !assert.NotNil(t, h, "%s should hash", scheme) {
+			continue
+		}
+		assert.Equal(t, h, scheme.HashInts(in...), "%s should be deterministic", scheme)
+		assert.NotEqual(t, h, scheme.HashInts(in[:2]...), "%s should depend on the input count", scheme)
+		if other, ok := seen[h.String()]; ok {
+			t.Errorf("%s and %s produced the same hash", scheme, other)
+		}
+		seen[h.String()] = scheme
+	}
+
+	framed := []byte{1, 0, 0, 0, 0, 0, 0, 0, 5, '$', 1, 0, 0, 0, 0, 0, 0, 0}
+	sha3Want := sha3.Sum256(framed)
+	assert.Equal(t, new(big.Int).SetBytes(sha3Want[:]), common.HashSHA3_256.HashInts(big.NewInt(5)), "SHA3_256 should hash the framed ints")
+
+	assert.Nil(t, common.HashScheme(99).HashInts(in...), "unknown scheme should fail")
+	assert.Nil(t, common.HashKeccak256.HashInts(), "empty input should return nil like SHA512_256i")
+}
@@ -0,0 +1,71 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// Hasher hashes a list of ints into one. Implementations should frame the ints like SHA512_256i does, so that
+// distinct lists never collide, and return nil on failure or on an empty list.
+type Hasher interface {
+	HashInts(in ...*big.Int) *big.Int
+}
+
+// HasherFunc adapts a function to the Hasher interface
+type HasherFunc func(in ...*big.Int) *big.Int
+
+func (f HasherFunc) HashInts(in ...*big.Int) *big.Int {
+	return f(in...)
+}
+
+type registeredHasher struct {
+	name   string
+	hasher Hasher
+}
+
+var (
+	_ Hasher = HashScheme(0)
+
+	hashersMtx sync.RWMutex
+	hashers    = map[HashScheme]registeredHasher{
+		HashSHA512_256:  {"SHA512_256", HasherFunc(SHA512_256i)},
+		HashKeccak256:   {"Keccak256", HasherFunc(keccak256Ints)},
+		HashPoseidon:    {"Poseidon", HasherFunc(poseidonInts)},
+		HashRescuePrime: {"RescuePrime", HasherFunc(rescuePrimeInts)},
+		HashBlake3:      {"Blake3", HasherFunc(blake3Ints)},
+		HashSHA3_256:    {"SHA3_256", HasherFunc(sha3_256Ints)},
+	}
+)
+
+// RegisterHasher makes `hasher` available under `scheme`, so that sessions may select it with SetHashScheme.
+// Every party of a session must register the same hasher under the same scheme; a scheme cannot be registered twice.
+func RegisterHasher(scheme HashScheme, name string, hasher Hasher) error {
+	if hasher == nil {
+		return fmt.Errorf("RegisterHasher: nil hasher for %s", name)
+	}
+	hashersMtx.Lock()
+	defer hashersMtx.Unlock()
+	if h, ok := hashers[scheme]; ok {
+		return fmt.Errorf("RegisterHasher: scheme %d is already registered to %s", int(scheme), h.name)
+	}
+	hashers[scheme] = registeredHasher{name, hasher}
+	return nil
+}
+
+// Hasher returns the hasher registered under the scheme
+func (scheme HashScheme) Hasher() (Hasher, error) {
+	hashersMtx.RLock()
+	defer hashersMtx.RUnlock()
+	h, ok := hashers[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash scheme %s", scheme)
+	}
+	return h.hasher, nil
+}
@@ -0,0 +1,43 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestRegisterHasher(t *testing.T) {
+	const scheme = common.HashScheme(100)
+	in := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	sum := common.HasherFunc(func(in ...*big.Int) *big.Int {
+		h := new(big.Int)
+		for _, n := range in {
+			h.Add(h, n)
+		}
+		return h
+	})
+
+	_, err := scheme.Hasher()
+	assert.Error(t, err, "scheme should not be registered yet")
+
+	assert.NoError(t, common.RegisterHasher(scheme, "Sum", sum))
+	assert.Equal(t, "Sum", scheme.String())
+	assert.Equal(t, big.NewInt(3), scheme.HashInts(in...))
+
+	assert.Error(t, common.RegisterHasher(scheme, "Sum", sum), "a scheme cannot be registered twice")
+	assert.Error(t, common.RegisterHasher(common.HashSHA512_256, "SHA", sum), "built-in schemes cannot be replaced")
+	assert.Error(t, common.RegisterHasher(common.HashScheme(101), "Nil", nil), "nil hasher should be rejected")
+
+	hasher, err := common.HashPoseidon.Hasher()
+	assert.NoError(t, err)
+	assert.Equal(t, common.HashPoseidon.HashInts(in...), hasher.HashInts(in...))
+}
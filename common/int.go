@@ -69,3 +69,16 @@ func AppendBigIntToBytesSlice(commonBytes []byte, appended *big.Int) []byte {
 	resultBytes = append(resultBytes, appended.Bytes()...)
 	return resultBytes
 }
+
+// ZeroInt overwrites the words of `x` with zeros and sets it to 0. It is best-effort: copies of the value made by
+// earlier arithmetic are out of its reach.
+func ZeroInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	mixedRandomTag = "tss-lib mixed random"
+	// the bytes drawn from each source for every read
+	mixedRandomSeedLen = 32
+)
+
+// MixedReader mixes a hardware RNG with the RNG of the OS: every read draws fresh bytes from both and squeezes the
+// output from SHAKE256 of them, so the output is unpredictable as long as either source is, and a backdoored or
+// failing hardware RNG alone cannot weaken the secrets drawn from it. A read fails if either source fails.
+type MixedReader struct {
+	mtx     sync.Mutex
+	hw, os  io.Reader
+	counter uint64
+}
+
+var _ io.Reader = (*MixedReader)(nil)
+
+// NewMixedReader returns a reader mixing the hardware RNG `hw` with crypto/rand, to be passed to
+// Parameters.SetEntropySource or to any function taking an io.Reader
+func NewMixedReader(hw io.Reader) *MixedReader {
+	return &MixedReader{hw: hw, os: cryptorand.Reader}
+}
+
+func (r *MixedReader) Read(p []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	seed := make([]byte, 2*mixedRandomSeedLen)
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+	if _, err := io.ReadFull(r.hw, seed[:mixedRandomSeedLen]); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(r.os, seed[mixedRandomSeedLen:]); err != nil {
+		return 0, err
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, r.counter)
+	r.counter++
+
+	xof := sha3.NewShake256()
+	xof.Write([]byte(mixedRandomTag))
+	xof.Write(counter)
+	xof.Write(seed)
+	return xof.Read(p)
+}
@@ -0,0 +1,53 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("hardware RNG failure")
+}
+
+func TestMixedReader(t *testing.T) {
+	// a stuck hardware RNG does not make the output predictable
+	r := common.NewMixedReader(zeroReader{})
+	a, b := make([]byte, 100), make([]byte, 100)
+	_, err := io.ReadFull(r, a)
+	assert.NoError(t, err)
+	_, err = io.ReadFull(r, b)
+	assert.NoError(t, err)
+	assert.False(t, bytes.Equal(a, b))
+	assert.False(t, bytes.Equal(a, make([]byte, 100)))
+
+	// the keygen draws work through it
+	n := common.MustGetRandomInt(r, 256)
+	assert.True(t, n.BitLen() <= 256)
+
+	// a failing hardware RNG fails the read rather than falling back to the OS alone
+	_, err = common.NewMixedReader(failingReader{}).Read(a)
+	assert.Error(t, err)
+}
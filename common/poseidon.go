@@ -0,0 +1,24 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/constants"
+)
+
+// PoseidonFieldModulus returns the prime of the field that Poseidon hashes over, the BN254 scalar field, which is also
+// the base field of BabyJubJub. It is the single source of this modulus in the library.
+func PoseidonFieldModulus() *big.Int {
+	return new(big.Int).Set(constants.Q)
+}
+
+// IsPoseidonFieldElement reports whether `n` is an element of the Poseidon field, a valid native Poseidon input
+func IsPoseidonFieldElement(n *big.Int) bool {
+	return n != nil && n.Sign() >= 0 && n.Cmp(constants.Q) < 0
+}
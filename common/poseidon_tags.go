@@ -0,0 +1,69 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"math/big"
+)
+
+// Tags of the Poseidon hashes of the protocols, one per protocol and use, so that the session identifiers and
+// commitments of distinct protocols and rounds never collide
+const (
+	PoseidonTagECDSAKeygenSSID        = "ecdsa-keygen/ssid"
+	PoseidonTagECDSAKeygenCommitment  = "ecdsa-keygen/round-1/commitment"
+	PoseidonTagECDSASigningSSID       = "ecdsa-signing/ssid"
+	PoseidonTagECDSASigningGamma      = "ecdsa-signing/round-1/gamma"
+	PoseidonTagECDSASigningViAi       = "ecdsa-signing/round-5/vi-ai"
+	PoseidonTagECDSASigningUiTi       = "ecdsa-signing/round-7/ui-ti"
+	PoseidonTagECDSARefreshSSID       = "ecdsa-refresh/ssid"
+	PoseidonTagECDSARefreshCommitment = "ecdsa-refresh/round-1/commitment"
+	PoseidonTagECDSAResharingSSID     = "ecdsa-resharing/ssid"
+	PoseidonTagECDSAResharingVs       = "ecdsa-resharing/round-1/vs"
+	PoseidonTagECDSARepairSSID        = "ecdsa-repair/ssid"
+	PoseidonTagEDDSAKeygenSSID        = "eddsa-keygen/ssid"
+	PoseidonTagEDDSAKeygenCommitment  = "eddsa-keygen/round-1/commitment"
+	PoseidonTagEDDSASigningSSID       = "eddsa-signing/ssid"
+	PoseidonTagEDDSASigningRi         = "eddsa-signing/round-1/ri"
+	PoseidonTagEDDSAResharingSSID     = "eddsa-resharing/ssid"
+	PoseidonTagEDDSAResharingVs       = "eddsa-resharing/round-1/vs"
+)
+
+// PoseidonHashTagged is the Poseidon counterpart of SHA512_256i_TAGGED: it hashes the tag, as an int, before the ints
+func PoseidonHashTagged(tag []byte, in ...*big.Int) *big.Int {
+	if len(in) == 0 {
+		return nil
+	}
+	return poseidonInts(append([]*big.Int{new(big.Int).SetBytes(tag)}, in...)...)
+}
+
+// Tagged returns the hasher of the scheme for the use named by `tag`. Poseidon hashes with PoseidonHashTagged; the
+// other schemes ignore the tag, so that their hashes stay those of the original protocols.
+func (scheme HashScheme) Tagged(tag string) Hasher {
+	if scheme != HashPoseidon {
+		return scheme
+	}
+	return HasherFunc(func(in ...*big.Int) *big.Int {
+		return PoseidonHashTagged([]byte(tag), in...)
+	})
+}
+
+// TaggedHash hashes ints under a tag like SHA512_256i_TAGGED, to derive the Fiat-Shamir challenges of the proofs
+type TaggedHash func(tag []byte, in ...*big.Int) *big.Int
+
+// ChallengeHash returns the hash of the Fiat-Shamir challenges of a session: PoseidonHashTagged with Poseidon and
+// RescuePrimeHashTagged with Rescue-Prime, so that the whole transcript is circuit friendly, and SHA512_256i_TAGGED
+// with every other scheme
+func (scheme HashScheme) ChallengeHash() TaggedHash {
+	switch scheme {
+	case HashPoseidon:
+		return PoseidonHashTagged
+	case HashRescuePrime:
+		return RescuePrimeHashTagged
+	default:
+		return SHA512_256i_TAGGED
+	}
+}
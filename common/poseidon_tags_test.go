@@ -0,0 +1,31 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestPoseidonHashTagged(t *testing.T) {
+	in := []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+	keygen := common.PoseidonHashTagged([]byte(common.PoseidonTagECDSAKeygenSSID), in...)
+	signing := common.PoseidonHashTagged([]byte(common.PoseidonTagECDSASigningSSID), in...)
+	assert.NotNil(t, keygen)
+	assert.NotEqual(t, keygen, signing, "tags should separate the hashes")
+	assert.NotEqual(t, common.HashPoseidon.HashInts(in...), keygen, "tagged hash should differ from the untagged one")
+	assert.Nil(t, common.PoseidonHashTagged([]byte(common.PoseidonTagECDSAKeygenSSID)), "empty input should return nil")
+
+	assert.Equal(t, keygen, common.HashPoseidon.Tagged(common.PoseidonTagECDSAKeygenSSID).HashInts(in...))
+	assert.Equal(t, common.SHA512_256i(in...), common.HashSHA512_256.Tagged(common.PoseidonTagECDSAKeygenSSID).HashInts(in...),
+		"other schemes should ignore the tag")
+}
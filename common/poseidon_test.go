@@ -0,0 +1,40 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// the BN254 scalar field prime
+const bn254ScalarField = "21888242871839275222246405745257275088548364400416034343698204186575808495617"
+
+func TestPoseidonFieldModulus(t *testing.T) {
+	q := common.PoseidonFieldModulus()
+	expected, _ := new(big.Int).SetString(bn254ScalarField, 10)
+	assert.Equal(t, expected, q)
+	assert.True(t, q.ProbablyPrime(20))
+
+	q.SetInt64(0)
+	assert.Equal(t, expected, common.PoseidonFieldModulus(), "the modulus should not be mutable through a copy")
+
+	qMinus1 := new(big.Int).Sub(expected, big.NewInt(1))
+	assert.True(t, common.IsPoseidonFieldElement(qMinus1))
+	assert.False(t, common.IsPoseidonFieldElement(expected))
+	assert.False(t, common.IsPoseidonFieldElement(big.NewInt(-1)))
+
+	_, err := poseidon.Hash([]*big.Int{qMinus1})
+	assert.NoError(t, err, "Poseidon should accept the largest field element")
+	_, err = poseidon.Hash([]*big.Int{expected})
+	assert.Error(t, err, "Poseidon should reject the modulus")
+}
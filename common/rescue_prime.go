@@ -0,0 +1,215 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Rescue-Prime over the STARK-252 field (p = 2^251 + 17*2^192 + 1), following "Rescue-Prime: a Standard Specification"
+// (https://eprint.iacr.org/2020/1143) with a state of 3 elements, capacity 1 and 128-bit security.
+// The number of rounds, the MDS matrix and the round constants are derived from these parameters as rescue_prime.sage
+// of the authors' reference implementation does (https://github.com/KULeuven-COSIC/Marvellous): get_number_of_rounds,
+// get_mds_matrix and get_round_constants. RescuePrime is its rescue_prime_hash.
+const (
+	rescueM          = 3
+	rescueCapacity   = 1
+	rescueRate       = rescueM - rescueCapacity
+	rescueSecurity   = 128
+	rescueRounds     = 18 // from the specification's Groebner basis bound: ceil(1.5 * max(5, 12))
+	rescueAlpha      = 3  // the smallest exponent coprime to p-1
+	rescueGenerator  = 3  // the smallest primitive element of the field
+	rescueChunkBytes = 31 // bytes packed into each input element, always below p
+)
+
+type rescuePrimeParams struct {
+	p, alpha, alphaInv *big.Int
+	mds                [rescueM][rescueM]*big.Int
+	roundConstants     []*big.Int
+}
+
+var (
+	rescueOnce   sync.Once
+	rescueParams *rescuePrimeParams
+)
+
+// RescuePrimeModulus returns the prime of the field that RescuePrime hashes over
+func RescuePrimeModulus() *big.Int {
+	return new(big.Int).Set(getRescuePrimeParams().p)
+}
+
+// RescuePrime hashes field elements with the Rescue-Prime sponge and returns the first element of its rate
+func RescuePrime(in ...*big.Int) (*big.Int, error) {
+	params := getRescuePrimeParams()
+	for _, n := range in {
+		if n == nil || n.Sign() < 0 || n.Cmp(params.p) >= 0 {
+			return nil, errors.New("RescuePrime: input is not a field element")
+		}
+	}
+	// pad with a one then zeros to a multiple of the rate
+	padded := make([]*big.Int, 0, len(in)+rescueRate)
+	padded = append(padded, in...)
+	padded = append(padded, big.NewInt(1))
+	for len(padded)%rescueRate != 0 {
+		padded = append(padded, big.NewInt(0))
+	}
+	state := make([]*big.Int, rescueM)
+	for i := range state {
+		state[i] = big.NewInt(0)
+	}
+	for k := 0; k < len(padded); k += rescueRate {
+		for i := 0; i < rescueRate; i++ {
+			state[i].Add(state[i], padded[k+i])
+			state[i].Mod(state[i], params.p)
+		}
+		state = params.permute(state)
+	}
+	return state[0], nil
+}
+
+// RescuePrimeBytes hashes the bytes with RescuePrime, packing them into elements of 31 bytes after their length
+func RescuePrimeBytes(msg []byte) (*big.Int, error) {
+	elems := make([]*big.Int, 0, 1+(len(msg)+rescueChunkBytes-1)/rescueChunkBytes)
+	elems = append(elems, big.NewInt(int64(len(msg))))
+	for i := 0; i < len(msg); i += rescueChunkBytes {
+		end := i + rescueChunkBytes
+		if end > len(msg) {
+			end = len(msg)
+		}
+		elems = append(elems, new(big.Int).SetBytes(msg[i:end]))
+	}
+	return RescuePrime(elems...)
+}
+
+// RescuePrimeHashTagged is the Rescue-Prime counterpart of SHA512_256i_TAGGED: it hashes the tag, as an int, before
+// the ints
+func RescuePrimeHashTagged(tag []byte, in ...*big.Int) *big.Int {
+	if len(in) == 0 {
+		return nil
+	}
+	return rescuePrimeInts(append([]*big.Int{new(big.Int).SetBytes(tag)}, in...)...)
+}
+
+func rescuePrimeInts(in ...*big.Int) *big.Int {
+	data := frameInts(in...)
+	if data == nil {
+		return nil
+	}
+	h, err := RescuePrimeBytes(data)
+	if err != nil {
+		Logger.Errorf("RescuePrimeBytes() failed: %v", err)
+		return nil
+	}
+	return h
+}
+
+func (params *rescuePrimeParams) permute(state []*big.Int) []*big.Int {
+	for r := 0; r < rescueRounds; r++ {
+		for j := range state {
+			state[j].Exp(state[j], params.alpha, params.p)
+		}
+		state = params.mix(state, params.roundConstants[r*2*rescueM:])
+		for j := range state {
+			state[j].Exp(state[j], params.alphaInv, params.p)
+		}
+		state = params.mix(state, params.roundConstants[r*2*rescueM+rescueM:])
+	}
+	return state
+}
+
+// mix multiplies the state by the MDS matrix and adds the first rescueM constants
+func (params *rescuePrimeParams) mix(state, constants []*big.Int) []*big.Int {
+	out := make([]*big.Int, rescueM)
+	tmp := new(big.Int)
+	for i := 0; i < rescueM; i++ {
+		out[i] = new(big.Int).Set(constants[i])
+		for j := 0; j < rescueM; j++ {
+			out[i].Add(out[i], tmp.Mul(params.mds[i][j], state[j]))
+		}
+		out[i].Mod(out[i], params.p)
+	}
+	return out
+}
+
+func getRescuePrimeParams() *rescuePrimeParams {
+	rescueOnce.Do(func() {
+		p, _ := new(big.Int).SetString("800000000000011000000000000000000000000000000000000000000000001", 16)
+		pMinus1 := new(big.Int).Sub(p, big.NewInt(1))
+		alpha := big.NewInt(rescueAlpha)
+		rescueParams = &rescuePrimeParams{
+			p:              p,
+			alpha:          alpha,
+			alphaInv:       new(big.Int).ModInverse(alpha, pMinus1),
+			mds:            rescueMDS(p),
+			roundConstants: rescueRoundConstants(p),
+		}
+	})
+	return rescueParams
+}
+
+// rescueMDS returns the transposed right half of the reduced echelon form of the Vandermonde matrix [g^(i*j)]
+func rescueMDS(p *big.Int) (mds [rescueM][rescueM]*big.Int) {
+	g := big.NewInt(rescueGenerator)
+	var v [rescueM][2 * rescueM]*big.Int
+	for i := 0; i < rescueM; i++ {
+		for j := 0; j < 2*rescueM; j++ {
+			v[i][j] = new(big.Int).Exp(g, big.NewInt(int64(i*j)), p)
+		}
+	}
+	// the left half of a Vandermonde matrix with distinct powers is invertible, so every pivot is on the diagonal
+	tmp := new(big.Int)
+	for c := 0; c < rescueM; c++ {
+		piv := c
+		for piv < rescueM && v[piv][c].Sign() == 0 {
+			piv++
+		}
+		v[c], v[piv] = v[piv], v[c]
+		inv := new(big.Int).ModInverse(v[c][c], p)
+		for j := range v[c] {
+			v[c][j].Mul(v[c][j], inv).Mod(v[c][j], p)
+		}
+		for i := 0; i < rescueM; i++ {
+			if i == c || v[i][c].Sign() == 0 {
+				continue
+			}
+			f := new(big.Int).Set(v[i][c])
+			for j := range v[i] {
+				v[i][j].Sub(v[i][j], tmp.Mul(f, v[c][j])).Mod(v[i][j], p)
+			}
+		}
+	}
+	for i := 0; i < rescueM; i++ {
+		for j := 0; j < rescueM; j++ {
+			mds[i][j] = v[j][rescueM+i]
+		}
+	}
+	return
+}
+
+// rescueRoundConstants expands the parameters with SHAKE256 into 2*m constants per round, read as little-endian ints
+func rescueRoundConstants(p *big.Int) []*big.Int {
+	bytesPerInt := (p.BitLen()+7)/8 + 1
+	count := 2 * rescueM * rescueRounds
+	seed := fmt.Sprintf("Rescue-XLIX(%s,%d,%d,%d)", p.String(), rescueM, rescueCapacity, rescueSecurity)
+	bz := make([]byte, bytesPerInt*count)
+	sha3.ShakeSum256(bz, []byte(seed))
+	constants := make([]*big.Int, count)
+	chunk := make([]byte, bytesPerInt)
+	for i := range constants {
+		for j := 0; j < bytesPerInt; j++ {
+			chunk[bytesPerInt-1-j] = bz[i*bytesPerInt+j]
+		}
+		constants[i] = new(big.Int).SetBytes(chunk)
+		constants[i].Mod(constants[i], p)
+	}
+	return constants
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package common_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// The vectors of RescuePrime are the first output element of rescue_prime_hash in rescue_prime.sage of the reference
+// implementation (https://github.com/KULeuven-COSIC/Marvellous), with p = 2^251 + 17*2^192 + 1, m = 3, capacity 1 and
+// 128-bit security; that of RescuePrimeBytes("abc") hashes [3, 0x616263].
+func TestRescuePrime(t *testing.T) {
+	expected := func(s string) *big.Int {
+		n, _ := new(big.Int).SetString(s, 10)
+		return n
+	}
+	tests := []struct {
+		in   []*big.Int
+		want string
+	}{
+		{nil, "1950645916603208684471297983943879205911812842741566341714987900573231141345"},
+		{[]*big.Int{big.NewInt(0)}, "804397629335242202403443181904019089627524064978706419123230278477987876831"},
+		{[]*big.Int{big.NewInt(1), big.NewInt(2)}, "2454085483676230677571821182747065460030088116419470347858457451440861812617"},
+	}
+	for _, tt := range tests {
+		h, err := common.RescuePrime(tt.in...)
+		assert.NoError(t, err)
+		assert.Equal(t, expected(tt.want), h)
+	}
+
+	h, err := common.RescuePrimeBytes([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected("3416883894001366821740000413578394892536706131003766132295637941050709502644"), h)
+
+	assert.Equal(t, expected("1200068731327604973284646601812327878765863364832481705880453929846531381407"),
+		common.HashRescuePrime.HashInts(big.NewInt(1), big.NewInt(2)))
+
+	_, err = common.RescuePrime(common.RescuePrimeModulus())
+	assert.Error(t, err, "inputs must be reduced")
+}
+
+func TestRescuePrimeChallengeHash(t *testing.T) {
+	tag := []byte("schnorr")
+	in := []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+	h := common.RescuePrimeHashTagged(tag, in...)
+	assert.NotNil(t, h)
+	assert.Equal(t, h, common.HashRescuePrime.ChallengeHash()(tag, in...), "Rescue-Prime sessions should derive their challenges with Rescue-Prime")
+	assert.NotEqual(t, common.SHA512_256i_TAGGED(tag, in...), h)
+	assert.NotEqual(t, h, common.RescuePrimeHashTagged([]byte("other"), in...), "tags should separate the hashes")
+	assert.Nil(t, common.RescuePrimeHashTagged(tag), "empty input should return nil")
+}
@@ -132,6 +132,11 @@ func GetRandomSafePrimesConcurrent(ctx context.Context, bitLen, numPrimes int, c
 		return nil, errors.New("numPrimes should be > 0")
 	}
 
+	if IsDeterministicRandom(rand) {
+		// concurrent searches would race for the reader and the first result; keep the output reproducible
+		concurrency = 1
+	}
+
 	primeCh := make(chan *GermainSafePrime, concurrency*numPrimes)
 	errCh := make(chan error, concurrency)
 	primes := make([]*GermainSafePrime, 0, numPrimes)
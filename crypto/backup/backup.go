@@ -0,0 +1,88 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package backup encrypts key shares to an offline backup key with a proof that the ciphertext holds the share
+// behind a public share BigXj, so that backups can be audited by anyone without being decrypted.
+package backup
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/dlnproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/encproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+)
+
+type (
+	// PublicKey is the key of the backup holder: a Paillier key to encrypt to, and ring-Pedersen parameters for the
+	// range proofs with the proofs that h1 and h2 generate the same group, as produced by keygen.GeneratePreParams
+	PublicKey struct {
+		PaillierPK           *paillier.PublicKey
+		NTilde, H1, H2       *big.Int
+		DLNProof1, DLNProof2 *dlnproof.Proof
+	}
+
+	// EncryptedShare is a share encrypted to a backup PublicKey with its proof of correct encryption
+	EncryptedShare struct {
+		Ciphertext *big.Int
+		Proof      *encproof.ProofEnc
+	}
+)
+
+// ValidateBasic checks the key and its proofs of the ring-Pedersen parameters
+func (pk *PublicKey) ValidateBasic() bool {
+	if pk == nil || pk.PaillierPK == nil || pk.NTilde == nil || pk.H1 == nil || pk.H2 == nil {
+		return false
+	}
+	if pk.DLNProof1 == nil || pk.DLNProof2 == nil {
+		return false
+	}
+	return pk.DLNProof1.Verify(pk.H1, pk.H2, pk.NTilde) && pk.DLNProof2.Verify(pk.H2, pk.H1, pk.NTilde)
+}
+
+// EncryptShare encrypts the share `x` of the public share X = x*G to the backup key. `session` binds the proof to
+// its context, such as the key's SSID and the party index, and must be given again to Verify.
+func EncryptShare(ec elliptic.Curve, pk *PublicKey, session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader) (*EncryptedShare, error) {
+	if !pk.ValidateBasic() {
+		return nil, errors.New("EncryptShare: invalid backup key")
+	}
+	if x == nil || X == nil || x.Sign() < 0 || x.Cmp(ec.Params().N) >= 0 || !crypto.ScalarBaseMult(ec, x).Equals(X) {
+		return nil, errors.New("EncryptShare: the share does not match the public share")
+	}
+	c, r, err := pk.PaillierPK.EncryptAndReturnRandomness(rand, x)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := encproof.NewProof(session, ec, pk.PaillierPK, pk.NTilde, pk.H1, pk.H2, c, X, x, r, rand)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedShare{Ciphertext: c, Proof: proof}, nil
+}
+
+// Verify checks without decrypting that the backup holds the discrete log of X under the backup key
+func (es *EncryptedShare) Verify(ec elliptic.Curve, pk *PublicKey, session []byte, X *crypto.ECPoint) bool {
+	if es == nil || es.Ciphertext == nil || !pk.ValidateBasic() {
+		return false
+	}
+	return es.Proof.Verify(session, ec, pk.PaillierPK, pk.NTilde, pk.H1, pk.H2, es.Ciphertext, X)
+}
+
+// DecryptShare recovers the share from a verified backup with the private backup key
+func DecryptShare(ec elliptic.Curve, sk *paillier.PrivateKey, es *EncryptedShare) (*big.Int, error) {
+	if sk == nil || es == nil || es.Ciphertext == nil {
+		return nil, errors.New("DecryptShare: missing key or backup")
+	}
+	m, err := sk.Decrypt(es.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return m.Mod(m, ec.Params().N), nil
+}
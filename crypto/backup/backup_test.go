@@ -0,0 +1,57 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package backup_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/crypto/backup"
+	"github.com/bnb-chain/tss-lib/v2/crypto/dlnproof"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestEncryptShare(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(2)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	// the pre-params of one party serve as the backup key of another
+	pre := keys[0].LocalPreParams
+	pk := &PublicKey{
+		PaillierPK: &pre.PaillierSK.PublicKey,
+		NTilde:     pre.NTildei,
+		H1:         pre.H1i,
+		H2:         pre.H2i,
+		DLNProof1:  dlnproof.NewDLNProof(pre.H1i, pre.H2i, pre.Alpha, pre.P, pre.Q, pre.NTildei, rand.Reader),
+		DLNProof2:  dlnproof.NewDLNProof(pre.H2i, pre.H1i, pre.Beta, pre.P, pre.Q, pre.NTildei, rand.Reader),
+	}
+	assert.True(t, pk.ValidateBasic())
+
+	ec := tss.S256()
+	key := keys[1]
+	index, err := key.OriginalIndex()
+	assert.NoError(t, err)
+	X := key.BigXj[index]
+	session := append([]byte("backup"), big.NewInt(int64(index)).Bytes()...)
+
+	es, err := EncryptShare(ec, pk, session, key.Xi, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, es.Verify(ec, pk, session, X), "backup must verify")
+	assert.False(t, es.Verify(ec, pk, session, key.BigXj[(index+1)%len(key.BigXj)]), "backup must not verify for another public share")
+
+	xi, err := DecryptShare(ec, pre.PaillierSK, es)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, xi.Cmp(key.Xi), "decrypted share must match")
+
+	_, err = EncryptShare(ec, pk, session, new(big.Int).Add(key.Xi, big.NewInt(1)), X, rand.Reader)
+	assert.Error(t, err, "a share that does not match the public share should be refused")
+}
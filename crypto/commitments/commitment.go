@@ -27,26 +27,43 @@ type (
 	HashCommitDecommit struct {
 		C HashCommitment
 		D HashDeCommitment
+		// Hasher is the hash of the commitment; SHA512_256i is used when it is nil
+		Hasher common.Hasher
 	}
 )
 
 func NewHashCommitmentWithRandomness(r *big.Int, secrets ...*big.Int) *HashCommitDecommit {
+	return newHashCommitment(nil, r, secrets...)
+}
+
+func NewHashCommitment(rand io.Reader, secrets ...*big.Int) *HashCommitDecommit {
+	return NewHashCommitmentWithHasher(nil, rand, secrets...)
+}
+
+// NewHashCommitmentWithHasher commits to the secrets with `hasher`, which must also be set to verify the commitment
+func NewHashCommitmentWithHasher(hasher common.Hasher, rand io.Reader, secrets ...*big.Int) *HashCommitDecommit {
+	r := common.MustGetRandomInt(rand, HashLength) // r
+	return newHashCommitment(hasher, r, secrets...)
+}
+
+func newHashCommitment(hasher common.Hasher, r *big.Int, secrets ...*big.Int) *HashCommitDecommit {
 	parts := make([]*big.Int, len(secrets)+1)
 	parts[0] = r
 	for i := 1; i < len(parts); i++ {
 		parts[i] = secrets[i-1]
 	}
-	hash := common.SHA512_256i(parts...)
 
-	cmt := &HashCommitDecommit{}
-	cmt.C = hash
+	cmt := &HashCommitDecommit{Hasher: hasher}
+	cmt.C = cmt.hash(parts...)
 	cmt.D = parts
 	return cmt
 }
 
-func NewHashCommitment(rand io.Reader, secrets ...*big.Int) *HashCommitDecommit {
-	r := common.MustGetRandomInt(rand, HashLength) // r
-	return NewHashCommitmentWithRandomness(r, secrets...)
+func (cmt *HashCommitDecommit) hash(in ...*big.Int) *big.Int {
+	if cmt.Hasher == nil {
+		return common.SHA512_256i(in...)
+	}
+	return cmt.Hasher.HashInts(in...)
 }
 
 func NewHashDeCommitmentFromBytes(marshalled [][]byte) HashDeCommitment {
@@ -58,8 +75,8 @@ func (cmt *HashCommitDecommit) Verify() bool {
 	if C == nil || D == nil {
 		return false
 	}
-	hash := common.SHA512_256i(D...)
-	return hash.Cmp(C) == 0
+	hash := cmt.hash(D...)
+	return hash != nil && hash.Cmp(C) == 0
 }
 
 func (cmt *HashCommitDecommit) DeCommit() (bool, HashDeCommitment) {
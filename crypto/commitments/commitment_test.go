@@ -13,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	. "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 )
 
@@ -37,3 +38,30 @@ func TestDeCommit(t *testing.T) {
 
 	assert.NotZero(t, len(secrets), "len(secrets) must be non-zero")
 }
+
+func TestDeCommitWithHasher(t *testing.T) {
+	one := big.NewInt(1)
+	zero := big.NewInt(0)
+
+	commitment := NewHashCommitmentWithHasher(common.HashKeccak256, rand.Reader, zero, one)
+	pass, secrets := commitment.DeCommit()
+	assert.True(t, pass, "must pass")
+	assert.Equal(t, []*big.Int{zero, one}, secrets)
+
+	other := HashCommitDecommit{C: commitment.C, D: commitment.D}
+	assert.False(t, other.Verify(), "must not verify with another hasher")
+}
+
+func TestPoseidonCommitment(t *testing.T) {
+	one := big.NewInt(1)
+	zero := big.NewInt(0)
+
+	commitment := NewPoseidonCommitment("keygen", rand.Reader, zero, one)
+	pass, secrets := NewPoseidonCommitDecommit("keygen", commitment.C, commitment.D).DeCommit()
+	assert.True(t, pass, "must pass")
+	assert.Equal(t, []*big.Int{zero, one}, secrets)
+
+	assert.False(t, NewPoseidonCommitDecommit("signing", commitment.C, commitment.D).Verify(), "must not verify in another domain")
+	untagged := HashCommitDecommit{C: commitment.C, D: commitment.D, Hasher: common.HashPoseidon}
+	assert.False(t, untagged.Verify(), "must not verify without the domain")
+}
@@ -0,0 +1,30 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package commitments
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// PoseidonHasher hashes with Poseidon under the tag `domain`, see common.PoseidonHashTagged, so that the commitments
+// of distinct protocols and rounds never open each other
+func PoseidonHasher(domain string) common.Hasher {
+	return common.HashPoseidon.Tagged(domain)
+}
+
+// NewPoseidonCommitment commits to the secrets with the Poseidon hasher of `domain`
+func NewPoseidonCommitment(domain string, rand io.Reader, secrets ...*big.Int) *HashCommitDecommit {
+	return NewHashCommitmentWithHasher(PoseidonHasher(domain), rand, secrets...)
+}
+
+// NewPoseidonCommitDecommit returns a commitment received from another party, to be opened with Verify or DeCommit
+func NewPoseidonCommitDecommit(domain string, C HashCommitment, D HashDeCommitment) *HashCommitDecommit {
+	return &HashCommitDecommit{C: C, D: D, Hasher: PoseidonHasher(domain)}
+}
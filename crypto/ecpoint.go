@@ -226,25 +226,34 @@ func (p *ECPoint) GobDecode(buf []byte) error {
 // ----- //
 
 // crypto.ECPoint is not inherently json marshal-able
+// A point of a custom curve (see tss.RegisterCustomCurve) also carries the spec of its curve, so that it is only
+// unmarshalled where the same curve is registered under its name.
 func (p *ECPoint) MarshalJSON() ([]byte, error) {
+	spec, custom := tss.CurveSpecOf(p.curve)
 	ecName, ok := tss.GetCurveName(p.curve)
+	if custom {
+		ecName, ok = spec.Name, true
+	}
 	if !ok {
 		return nil, fmt.Errorf("cannot find %T name in curve registry, please call tss.RegisterCurve(name, curve) to register it first", p.curve)
 	}
 
 	return json.Marshal(&struct {
-		Curve  string
-		Coords [2]*big.Int
+		Curve     string
+		CurveSpec *tss.CurveSpec `json:",omitempty"`
+		Coords    [2]*big.Int
 	}{
-		Curve:  string(ecName),
-		Coords: p.coords,
+		Curve:     string(ecName),
+		CurveSpec: spec,
+		Coords:    p.coords,
 	})
 }
 
 func (p *ECPoint) UnmarshalJSON(payload []byte) error {
 	aux := &struct {
-		Curve  string
-		Coords [2]*big.Int
+		Curve     string
+		CurveSpec *tss.CurveSpec
+		Coords    [2]*big.Int
 	}{}
 	if err := json.Unmarshal(payload, &aux); err != nil {
 		return err
@@ -256,6 +265,12 @@ func (p *ECPoint) UnmarshalJSON(payload []byte) error {
 		if !ok {
 			return fmt.Errorf("cannot find curve named with %s in curve registry, please call tss.RegisterCurve(name, curve) to register it first", aux.Curve)
 		}
+		// the spec of a custom curve must be the one registered under its name, and is never registered from here
+		if aux.CurveSpec != nil {
+			if spec, custom := tss.CurveSpecOf(ec); !custom || !spec.Equal(aux.CurveSpec) {
+				return fmt.Errorf("ECPoint.UnmarshalJSON: the curve spec differs from the curve registered as %s", aux.Curve)
+			}
+		}
 		p.curve = ec
 	} else {
 		// forward compatible, use global ec as default value
@@ -7,10 +7,12 @@
 package crypto_test
 
 import (
+	"crypto/elliptic"
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -165,3 +167,73 @@ func TestEdwardsEcpointJsonSerialization(t *testing.T) {
 	assert.True(t, point.Equals(&umpoint))
 	assert.True(t, reflect.TypeOf(point.Curve()) == reflect.TypeOf(umpoint.Curve()))
 }
+
+func TestCustomCurveJsonSerialization(t *testing.T) {
+	s256, ed := btcec.S256().Params(), edwards.Edwards().Params()
+	// -121665/121666
+	d := new(big.Int).ModInverse(big.NewInt(121666), ed.P)
+	d.Mul(d, big.NewInt(-121665)).Mod(d, ed.P)
+	specs := []tss.CurveSpec{
+		{Name: "test-secp256k1", Type: tss.Weierstrass, P: s256.P, N: s256.N, A: big.NewInt(0), B: big.NewInt(7), Gx: s256.Gx, Gy: s256.Gy},
+		{Name: "test-ed25519", Type: tss.TwistedEdwards, P: ed.P, N: ed.N, A: big.NewInt(-1), D: d, Gx: ed.Gx, Gy: ed.Gy},
+	}
+	k := big.NewInt(987654321)
+
+	for i, reference := range []elliptic.Curve{btcec.S256(), edwards.Edwards()} {
+		spec := specs[i]
+		curve, err := tss.NewCustomCurve(spec)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		x, y := curve.ScalarBaseMult(k.Bytes())
+		rx, ry := reference.ScalarBaseMult(k.Bytes())
+		assert.Zero(t, x.Cmp(rx))
+		assert.Zero(t, y.Cmp(ry))
+
+		// the curve is not registered, so the point carries its spec
+		point, err := NewECPoint(curve, x, y)
+		assert.NoError(t, err)
+		bz, err := json.Marshal(point)
+		assert.NoError(t, err)
+		_, ok := tss.GetCurveByName(spec.Name)
+		assert.False(t, ok)
+
+		var umpoint ECPoint
+		assert.Error(t, json.Unmarshal(bz, &umpoint), "the curve of a point must be registered first")
+		_, ok = tss.GetCurveByName(spec.Name)
+		assert.False(t, ok, "unmarshalling a point should not register its curve")
+
+		registered, err := tss.RegisterCustomCurve(spec)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(bz, &umpoint))
+		assert.True(t, point.Equals(&umpoint))
+		assert.Equal(t, registered, umpoint.Curve())
+		name, ok := tss.GetCurveName(curve)
+		assert.True(t, ok)
+		assert.Equal(t, spec.Name, name)
+	}
+
+	bad := specs[0]
+	bad.Name, bad.Gy = "test-bad", new(big.Int).Add(bad.Gy, big.NewInt(1))
+	_, err := tss.RegisterCustomCurve(bad)
+	assert.Error(t, err)
+	_, err = tss.RegisterCustomCurve(tss.CurveSpec{Name: specs[0].Name, Type: tss.TwistedEdwards, P: ed.P, N: ed.N, A: big.NewInt(-1), D: d, Gx: ed.Gx, Gy: ed.Gy})
+	assert.Error(t, err, "the name is taken by another curve")
+
+	// a point that claims the name of a registered curve for another spec
+	other := specs[1]
+	other.Name = "test-other"
+	curve, err := tss.NewCustomCurve(other)
+	assert.NoError(t, err)
+	point, err := NewECPoint(curve, other.Gx, other.Gy)
+	assert.NoError(t, err)
+	bz, err := json.Marshal(point)
+	assert.NoError(t, err)
+	squatted := strings.Replace(string(bz), string(other.Name), string(specs[0].Name), -1)
+	assert.Error(t, json.Unmarshal([]byte(squatted), new(ECPoint)), "the spec must match the registered curve")
+
+	huge := specs[0]
+	huge.Name, huge.P = "test-huge", new(big.Int).Lsh(big.NewInt(1), tss.MaxCustomCurveBits+1)
+	_, err = tss.NewCustomCurve(huge)
+	assert.Error(t, err)
+}
@@ -0,0 +1,198 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package encproof
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+)
+
+const (
+	ProofEncBytesParts = 8
+)
+
+type (
+	// ProofEnc proves that the Paillier ciphertext c encrypts the discrete log x of X = x*G, with x in [0, q^3].
+	// It is Alice's range proof of GG18Spec (9) Fig. 9 extended with the commitment B = alpha*G, so that the same
+	// response S1 opens both the ciphertext and X.
+	ProofEnc struct {
+		Z, U, W, S, S1, S2 *big.Int
+		B                  *crypto.ECPoint
+	}
+)
+
+var (
+	zero = big.NewInt(0)
+	one  = big.NewInt(1)
+)
+
+// NewProof proves that c = Gamma^x * r^N mod N^2 and X = x*G. The ring-Pedersen parameters (NTilde, h1, h2) must be
+// generated by the verifier, or by a party trusted by it, for the range bound to hold.
+func NewProof(Session []byte, ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c *big.Int, X *crypto.ECPoint, x, r *big.Int, rand io.Reader) (*ProofEnc, error) {
+	if ec == nil || pk == nil || NTilde == nil || h1 == nil || h2 == nil || c == nil || X == nil || x == nil || r == nil {
+		return nil, errors.New("ProveEnc constructor received nil value(s)")
+	}
+
+	q := ec.Params().N
+	q3 := new(big.Int).Mul(q, q)
+	q3 = new(big.Int).Mul(q, q3)
+	qNTilde := new(big.Int).Mul(q, NTilde)
+	q3NTilde := new(big.Int).Mul(q3, NTilde)
+
+	alpha := common.GetRandomPositiveInt(rand, q3)
+	beta := common.GetRandomPositiveRelativelyPrimeInt(rand, pk.N)
+	gamma := common.GetRandomPositiveInt(rand, q3NTilde)
+	rho := common.GetRandomPositiveInt(rand, qNTilde)
+
+	modNTilde := common.ModInt(NTilde)
+	z := modNTilde.Exp(h1, x)
+	z = modNTilde.Mul(z, modNTilde.Exp(h2, rho))
+
+	modNSquared := common.ModInt(pk.NSquare())
+	u := modNSquared.Exp(pk.Gamma(), alpha)
+	u = modNSquared.Mul(u, modNSquared.Exp(beta, pk.N))
+
+	w := modNTilde.Exp(h1, alpha)
+	w = modNTilde.Mul(w, modNTilde.Exp(h2, gamma))
+
+	B := crypto.ScalarBaseMult(ec, alpha)
+
+	var e *big.Int
+	{ // must use RejectionSample
+		eHash := common.SHA512_256i_TAGGED(Session, append(pk.AsInts(), NTilde, h1, h2, c, X.X(), X.Y(), z, u, w, B.X(), B.Y())...)
+		e = common.RejectionSample(q, eHash)
+	}
+
+	modN := common.ModInt(pk.N)
+	s := modN.Exp(r, e)
+	s = modN.Mul(s, beta)
+
+	// s1 = e * x + alpha
+	s1 := new(big.Int).Mul(e, x)
+	s1 = new(big.Int).Add(s1, alpha)
+
+	// s2 = e * rho + gamma
+	s2 := new(big.Int).Mul(e, rho)
+	s2 = new(big.Int).Add(s2, gamma)
+
+	return &ProofEnc{Z: z, U: u, W: w, S: s, S1: s1, S2: s2, B: B}, nil
+}
+
+func NewProofFromBytes(ec elliptic.Curve, bzs [][]byte) (*ProofEnc, error) {
+	if !common.NonEmptyMultiBytes(bzs, ProofEncBytesParts) {
+		return nil, fmt.Errorf("expected %d byte parts to construct ProofEnc", ProofEncBytesParts)
+	}
+	B, err := crypto.NewECPoint(ec, new(big.Int).SetBytes(bzs[6]), new(big.Int).SetBytes(bzs[7]))
+	if err != nil {
+		return nil, err
+	}
+	return &ProofEnc{
+		Z:  new(big.Int).SetBytes(bzs[0]),
+		U:  new(big.Int).SetBytes(bzs[1]),
+		W:  new(big.Int).SetBytes(bzs[2]),
+		S:  new(big.Int).SetBytes(bzs[3]),
+		S1: new(big.Int).SetBytes(bzs[4]),
+		S2: new(big.Int).SetBytes(bzs[5]),
+		B:  B,
+	}, nil
+}
+
+func (pf *ProofEnc) Verify(Session []byte, ec elliptic.Curve, pk *paillier.PublicKey, NTilde, h1, h2, c *big.Int, X *crypto.ECPoint) bool {
+	if pf == nil || !pf.ValidateBasic() || ec == nil || pk == nil || NTilde == nil || h1 == nil || h2 == nil || c == nil || X == nil {
+		return false
+	}
+
+	q := ec.Params().N
+	q3 := new(big.Int).Mul(q, q)
+	q3 = new(big.Int).Mul(q, q3)
+
+	if !common.IsInInterval(c, pk.NSquare()) || new(big.Int).GCD(nil, nil, c, pk.NSquare()).Cmp(one) != 0 {
+		return false
+	}
+	if !common.IsInInterval(pf.Z, NTilde) || !common.IsInInterval(pf.W, NTilde) {
+		return false
+	}
+	if !common.IsInInterval(pf.U, pk.NSquare()) || !common.IsInInterval(pf.S, pk.N) {
+		return false
+	}
+	if new(big.Int).GCD(nil, nil, pf.Z, NTilde).Cmp(one) != 0 ||
+		new(big.Int).GCD(nil, nil, pf.W, NTilde).Cmp(one) != 0 ||
+		new(big.Int).GCD(nil, nil, pf.U, pk.NSquare()).Cmp(one) != 0 ||
+		new(big.Int).GCD(nil, nil, pf.S, pk.N).Cmp(one) != 0 {
+		return false
+	}
+	if pf.S1.Sign() < 0 || pf.S1.Cmp(q3) == 1 || pf.S2.Sign() < 0 {
+		return false
+	}
+	if !pf.B.ValidateBasic() || !X.ValidateBasic() {
+		return false
+	}
+
+	var e *big.Int
+	{ // must use RejectionSample
+		eHash := common.SHA512_256i_TAGGED(Session, append(pk.AsInts(), NTilde, h1, h2, c, X.X(), X.Y(), pf.Z, pf.U, pf.W, pf.B.X(), pf.B.Y())...)
+		e = common.RejectionSample(q, eHash)
+	}
+	minusE := new(big.Int).Sub(zero, e)
+
+	{ // Gamma^s1 * s^N * c^-e == u
+		modNSquared := common.ModInt(pk.NSquare())
+		products := modNSquared.Mul(modNSquared.Exp(pk.Gamma(), pf.S1), modNSquared.Exp(pf.S, pk.N))
+		products = modNSquared.Mul(products, modNSquared.Exp(c, minusE))
+		if pf.U.Cmp(products) != 0 {
+			return false
+		}
+	}
+
+	{ // h1^s1 * h2^s2 * z^-e == w
+		modNTilde := common.ModInt(NTilde)
+		products := modNTilde.Mul(modNTilde.Exp(h1, pf.S1), modNTilde.Exp(h2, pf.S2))
+		products = modNTilde.Mul(products, modNTilde.Exp(pf.Z, minusE))
+		if pf.W.Cmp(products) != 0 {
+			return false
+		}
+	}
+
+	{ // s1*G == B + e*X
+		LHS := crypto.ScalarBaseMult(ec, new(big.Int).Mod(pf.S1, q))
+		RHS, err := pf.B.Add(X.ScalarMult(e))
+		if err != nil || !LHS.Equals(RHS) {
+			return false
+		}
+	}
+	return true
+}
+
+func (pf *ProofEnc) ValidateBasic() bool {
+	return pf.Z != nil &&
+		pf.U != nil &&
+		pf.W != nil &&
+		pf.S != nil &&
+		pf.S1 != nil &&
+		pf.S2 != nil &&
+		pf.B != nil
+}
+
+func (pf *ProofEnc) Bytes() [ProofEncBytesParts][]byte {
+	return [...][]byte{
+		pf.Z.Bytes(),
+		pf.U.Bytes(),
+		pf.W.Bytes(),
+		pf.S.Bytes(),
+		pf.S1.Bytes(),
+		pf.S2.Bytes(),
+		pf.B.X().Bytes(),
+		pf.B.Y().Bytes(),
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package encproof_test
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/encproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Using a modulus length of 2048 is recommended in the GG18 spec
+const (
+	testPaillierKeyLength = 2048
+	testSafePrimeBits     = 1024
+)
+
+var Session = []byte("session")
+
+func TestEnc(test *testing.T) {
+	ec := tss.EC()
+	q := ec.Params().N
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	_, pk, err := paillier.GenerateKeyPair(ctx, rand.Reader, testPaillierKeyLength)
+	assert.NoError(test, err)
+
+	primes := [2]*big.Int{common.GetRandomPrimeInt(rand.Reader, testSafePrimeBits), common.GetRandomPrimeInt(rand.Reader, testSafePrimeBits)}
+	NTilde, h1, h2, err := crypto.GenerateNTildei(rand.Reader, primes)
+	assert.NoError(test, err)
+
+	x := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(ec, x)
+	c, r, err := pk.EncryptAndReturnRandomness(rand.Reader, x)
+	assert.NoError(test, err)
+
+	proof, err := NewProof(Session, ec, pk, NTilde, h1, h2, c, X, x, r, rand.Reader)
+	assert.NoError(test, err)
+	assert.True(test, proof.Verify(Session, ec, pk, NTilde, h1, h2, c, X), "proof must verify")
+
+	bzs := proof.Bytes()
+	proof2, err := NewProofFromBytes(ec, bzs[:])
+	assert.NoError(test, err)
+	assert.True(test, proof2.Verify(Session, ec, pk, NTilde, h1, h2, c, X), "proof from bytes must verify")
+
+	assert.False(test, proof.Verify([]byte("other session"), ec, pk, NTilde, h1, h2, c, X), "proof must be bound to its session")
+	otherX := crypto.ScalarBaseMult(ec, new(big.Int).Add(x, big.NewInt(1)))
+	assert.False(test, proof.Verify(Session, ec, pk, NTilde, h1, h2, c, otherX), "proof must not verify for another point")
+	otherC, err := pk.Encrypt(rand.Reader, x)
+	assert.NoError(test, err)
+	assert.False(test, proof.Verify(Session, ec, pk, NTilde, h1, h2, otherC, X), "proof must not verify for another ciphertext")
+}
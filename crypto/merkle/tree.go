@@ -0,0 +1,144 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package merkle builds Poseidon Merkle trees over the public shares of a committee, so that contracts and circuits
+// can check with a short proof that a share commitment BigXj belongs to the committee registered by its root.
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/poseidon"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+)
+
+const halfBits = 128
+
+// Tree is a binary Merkle tree whose nodes are Poseidon(left, right). The leaves are padded with zeros to a power of
+// two, so that every proof of a tree has its depth.
+type Tree struct {
+	levels [][]*big.Int // levels[0] are the padded leaves, the last level is the root
+}
+
+// Proof shows that a leaf is at Index in the tree, Siblings going from the leaf level up to the root
+type Proof struct {
+	Index    int        `json:"index"`
+	Siblings []*big.Int `json:"siblings"`
+}
+
+// PointLeaf returns the leaf of a public share: Poseidon(xHi, xLo, yHi, yLo), with the coordinates split in 128-bit
+// halves so that any curve of at most 256 bits fits the field
+func PointLeaf(p *crypto.ECPoint) (*big.Int, error) {
+	if p == nil {
+		return nil, errors.New("PointLeaf: nil point")
+	}
+	ins := make([]*big.Int, 0, 4)
+	for _, c := range []*big.Int{p.X(), p.Y()} {
+		if c.BitLen() > 2*halfBits {
+			return nil, errors.New("PointLeaf: coordinate is too large")
+		}
+		hi := new(big.Int).Rsh(c, halfBits)
+		lo := new(big.Int).Sub(c, new(big.Int).Lsh(hi, halfBits))
+		ins = append(ins, hi, lo)
+	}
+	return poseidon.Hash(ins)
+}
+
+// NewSharesTree builds the tree of the public shares BigXj, in the order of the committee
+func NewSharesTree(bigXj []*crypto.ECPoint) (*Tree, error) {
+	leaves := make([]*big.Int, len(bigXj))
+	for j, Xj := range bigXj {
+		leaf, err := PointLeaf(Xj)
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %v", j, err)
+		}
+		leaves[j] = leaf
+	}
+	return NewTree(leaves)
+}
+
+// NewTree builds the tree of the leaves, which must be elements of the Poseidon field
+func NewTree(leaves []*big.Int) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("NewTree: no leaves")
+	}
+	width := 1
+	for width < len(leaves) {
+		width *= 2
+	}
+	level := make([]*big.Int, width)
+	for i := range level {
+		if i < len(leaves) {
+			if !common.IsPoseidonFieldElement(leaves[i]) {
+				return nil, fmt.Errorf("NewTree: leaf %d is not a field element", i)
+			}
+			level[i] = leaves[i]
+		} else {
+			level[i] = big.NewInt(0)
+		}
+	}
+	tree := &Tree{levels: [][]*big.Int{level}}
+	for len(level) > 1 {
+		next := make([]*big.Int, len(level)/2)
+		for i := range next {
+			node, err := poseidon.Hash([]*big.Int{level[2*i], level[2*i+1]})
+			if err != nil {
+				return nil, err
+			}
+			next[i] = node
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+	return tree, nil
+}
+
+func (t *Tree) Root() *big.Int {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Depth is the number of siblings in the proofs of the tree
+func (t *Tree) Depth() int {
+	return len(t.levels) - 1
+}
+
+// Proof returns the proof of the leaf at `index`
+func (t *Tree) Proof(index int) (*Proof, error) {
+	if index < 0 || index >= len(t.levels[0]) {
+		return nil, fmt.Errorf("Proof: index %d is out of range", index)
+	}
+	pf := &Proof{Index: index, Siblings: make([]*big.Int, t.Depth())}
+	for l := range pf.Siblings {
+		pf.Siblings[l] = t.levels[l][(index>>uint(l))^1]
+	}
+	return pf, nil
+}
+
+// Verify checks that `leaf` is at the index of the proof in the tree of `root`
+func (pf *Proof) Verify(root, leaf *big.Int) bool {
+	if pf == nil || root == nil || leaf == nil || pf.Index < 0 || pf.Index>>uint(len(pf.Siblings)) != 0 {
+		return false
+	}
+	node := leaf
+	for l, sibling := range pf.Siblings {
+		if sibling == nil {
+			return false
+		}
+		pair := []*big.Int{node, sibling}
+		if (pf.Index>>uint(l))&1 == 1 {
+			pair[0], pair[1] = sibling, node
+		}
+		var err error
+		if node, err = poseidon.Hash(pair); err != nil {
+			return false
+		}
+	}
+	return node.Cmp(root) == 0
+}
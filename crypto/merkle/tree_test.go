@@ -0,0 +1,48 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package merkle_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/merkle"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestSharesTree(t *testing.T) {
+	for _, n := range []int{1, 3, 4, 5} {
+		bigXj := make([]*crypto.ECPoint, n)
+		for j := range bigXj {
+			bigXj[j] = crypto.ScalarBaseMult(tss.S256(), big.NewInt(int64(j+1)))
+		}
+		tree, err := NewSharesTree(bigXj)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		for j, Xj := range bigXj {
+			leaf, err := PointLeaf(Xj)
+			assert.NoError(t, err)
+			pf, err := tree.Proof(j)
+			assert.NoError(t, err)
+			assert.Len(t, pf.Siblings, tree.Depth())
+			assert.True(t, pf.Verify(tree.Root(), leaf), "proof %d of %d should verify", j, n)
+
+			other, _ := PointLeaf(crypto.ScalarBaseMult(tss.S256(), big.NewInt(100)))
+			assert.False(t, pf.Verify(tree.Root(), other), "proof should not verify another leaf")
+			if n > 1 {
+				moved := &Proof{Index: j ^ 1, Siblings: pf.Siblings}
+				assert.False(t, moved.Verify(tree.Root(), leaf), "proof should not verify at another index")
+			}
+		}
+	}
+	_, err := NewSharesTree(nil)
+	assert.Error(t, err)
+}
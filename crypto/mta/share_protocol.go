@@ -38,6 +38,19 @@ func BobMid(
 	pf *RangeProofAlice,
 	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
 	rand io.Reader,
+) (beta, cB, betaPrm *big.Int, piB *ProofBob, err error) {
+	return BobMidWithHash(common.SHA512_256i_TAGGED, Session, ec, pkA, pf, b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B, rand)
+}
+
+// BobMidWithHash is BobMid with the challenge of Bob's proof derived by `hash`
+func BobMidWithHash(
+	hash common.TaggedHash,
+	Session []byte,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *RangeProofAlice,
+	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
+	rand io.Reader,
 ) (beta, cB, betaPrm *big.Int, piB *ProofBob, err error) {
 	if !pf.Verify(ec, pkA, NTildeB, h1B, h2B, cA) {
 		err = errors.New("RangeProofAlice.Verify() returned false")
@@ -61,7 +74,7 @@ func BobMid(
 		return
 	}
 	beta = common.ModInt(q).Sub(zero, betaPrm)
-	piB, err = ProveBob(Session, ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, rand)
+	piB, err = ProveBobWithHash(hash, Session, ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, rand)
 	return
 }
 
@@ -73,6 +86,20 @@ func BobMidWC(
 	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
 	B *crypto.ECPoint,
 	rand io.Reader,
+) (beta, cB, betaPrm *big.Int, piB *ProofBobWC, err error) {
+	return BobMidWCWithHash(common.SHA512_256i_TAGGED, Session, ec, pkA, pf, b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B, B, rand)
+}
+
+// BobMidWCWithHash is BobMidWC with the challenge of Bob's proof derived by `hash`
+func BobMidWCWithHash(
+	hash common.TaggedHash,
+	Session []byte,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *RangeProofAlice,
+	b, cA, NTildeA, h1A, h2A, NTildeB, h1B, h2B *big.Int,
+	B *crypto.ECPoint,
+	rand io.Reader,
 ) (beta, cB, betaPrm *big.Int, piB *ProofBobWC, err error) {
 	if !pf.Verify(ec, pkA, NTildeB, h1B, h2B, cA) {
 		err = errors.New("RangeProofAlice.Verify() returned false")
@@ -96,7 +123,7 @@ func BobMidWC(
 		return
 	}
 	beta = common.ModInt(q).Sub(zero, betaPrm)
-	piB, err = ProveBobWC(Session, ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, B, rand)
+	piB, err = ProveBobWCWithHash(hash, Session, ec, pkA, NTildeA, h1A, h2A, cA, cB, b, betaPrm, cRand, B, rand)
 	return
 }
 
@@ -108,7 +135,20 @@ func AliceEnd(
 	h1A, h2A, cA, cB, NTildeA *big.Int,
 	sk *paillier.PrivateKey,
 ) (*big.Int, error) {
-	if !pf.Verify(Session, ec, pkA, NTildeA, h1A, h2A, cA, cB) {
+	return AliceEndWithHash(common.SHA512_256i_TAGGED, Session, ec, pkA, pf, h1A, h2A, cA, cB, NTildeA, sk)
+}
+
+// AliceEndWithHash is AliceEnd for a proof of BobMidWithHash with the same `hash`
+func AliceEndWithHash(
+	hash common.TaggedHash,
+	Session []byte,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *ProofBob,
+	h1A, h2A, cA, cB, NTildeA *big.Int,
+	sk *paillier.PrivateKey,
+) (*big.Int, error) {
+	if !pf.VerifyWithHash(hash, Session, ec, pkA, NTildeA, h1A, h2A, cA, cB) {
 		return nil, errors.New("ProofBob.Verify() returned false")
 	}
 	alphaPrm, err := sk.Decrypt(cB)
@@ -128,7 +168,21 @@ func AliceEndWC(
 	cA, cB, NTildeA, h1A, h2A *big.Int,
 	sk *paillier.PrivateKey,
 ) (*big.Int, error) {
-	if !pf.Verify(Session, ec, pkA, NTildeA, h1A, h2A, cA, cB, B) {
+	return AliceEndWCWithHash(common.SHA512_256i_TAGGED, Session, ec, pkA, pf, B, cA, cB, NTildeA, h1A, h2A, sk)
+}
+
+// AliceEndWCWithHash is AliceEndWC for a proof of BobMidWCWithHash with the same `hash`
+func AliceEndWCWithHash(
+	hash common.TaggedHash,
+	Session []byte,
+	ec elliptic.Curve,
+	pkA *paillier.PublicKey,
+	pf *ProofBobWC,
+	B *crypto.ECPoint,
+	cA, cB, NTildeA, h1A, h2A *big.Int,
+	sk *paillier.PrivateKey,
+) (*big.Int, error) {
+	if !pf.VerifyWithHash(hash, Session, ec, pkA, NTildeA, h1A, h2A, cA, cB, B) {
 		return nil, errors.New("ProofBobWC.Verify() returned false")
 	}
 	alphaPrm, err := sk.Decrypt(cB)
@@ -190,6 +190,16 @@ func (privateKey *PrivateKey) Decrypt(c *big.Int) (m *big.Int, err error) {
 	return
 }
 
+// Wipe overwrites the secrets of the key, lambda, phi and the primes, on a best-effort basis (see common.ZeroInt)
+func (privateKey *PrivateKey) Wipe() {
+	if privateKey == nil {
+		return
+	}
+	for _, x := range []*big.Int{privateKey.LambdaN, privateKey.PhiN, privateKey.P, privateKey.Q} {
+		common.ZeroInt(x)
+	}
+}
+
 // ----- //
 
 // Proof is an implementation of Gennaro, R., Micciancio, D., Rabin, T.:
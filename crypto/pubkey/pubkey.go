@@ -0,0 +1,141 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package pubkey exports the public key of a TSS key, the ECDSAPub or EDDSAPub of its save data, in the formats that
+// PKI and OIDC systems register keys in: a SubjectPublicKeyInfo (RFC 5280), DER or PEM, and a JWK (RFC 7517).
+// secp256k1 keys are exported as in RFC 5480 and RFC 8812, and ed25519 keys as in RFC 8410 and RFC 8037. BabyJubJub
+// has no standard encoding in either format, and is refused.
+package pubkey
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+var (
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// JWK is a public key as a JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	// for EC keys only
+	Y   string `json:"y,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// MarshalPKIX returns the DER encoding of the SubjectPublicKeyInfo of `pub`
+func MarshalPKIX(pub *crypto.ECPoint) ([]byte, error) {
+	name, err := curveName(pub)
+	if err != nil {
+		return nil, err
+	}
+	if name == tss.Ed25519 {
+		return x509.MarshalPKIXPublicKey(ed25519.PublicKey(Ed25519Bytes(pub)))
+	}
+	// x509 knows of the NIST curves only
+	params, err := asn1.Marshal(oidSecp256k1)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidECPublicKey, Parameters: asn1.RawValue{FullBytes: params}},
+		PublicKey: asn1.BitString{Bytes: uncompressed(pub), BitLength: 8 * 65},
+	})
+}
+
+// MarshalPEM returns the SubjectPublicKeyInfo of `pub` in a PEM block of type "PUBLIC KEY"
+func MarshalPEM(pub *crypto.ECPoint) ([]byte, error) {
+	der, err := MarshalPKIX(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// NewJWK returns the JWK of `pub`, for signatures with the algorithm of its curve: ES256K or EdDSA. Set its Kid as the
+// system that the key is registered with expects.
+func NewJWK(pub *crypto.ECPoint) (*JWK, error) {
+	name, err := curveName(pub)
+	if err != nil {
+		return nil, err
+	}
+	enc := base64.RawURLEncoding
+	if name == tss.Ed25519 {
+		return &JWK{Kty: "OKP", Crv: "Ed25519", X: enc.EncodeToString(Ed25519Bytes(pub)), Alg: "EdDSA", Use: "sig"}, nil
+	}
+	point := uncompressed(pub)
+	return &JWK{
+		Kty: "EC",
+		Crv: "secp256k1",
+		X:   enc.EncodeToString(point[1:33]),
+		Y:   enc.EncodeToString(point[33:]),
+		Alg: "ES256K",
+		Use: "sig",
+	}, nil
+}
+
+// MarshalJWK returns the JSON of the JWK of `pub`
+func MarshalJWK(pub *crypto.ECPoint) ([]byte, error) {
+	jwk, err := NewJWK(pub)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jwk)
+}
+
+// Ed25519Bytes returns the 32-byte encoding of RFC 8032 of an ed25519 point: y in little-endian, with the sign of x in
+// the top bit
+func Ed25519Bytes(pub *crypto.ECPoint) []byte {
+	out := make([]byte, ed25519.PublicKeySize)
+	pub.Y().FillBytes(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	out[31] |= byte(pub.X().Bit(0)) << 7
+	return out
+}
+
+// uncompressed returns the uncompressed SEC 1 encoding of a secp256k1 point
+func uncompressed(pub *crypto.ECPoint) []byte {
+	out := make([]byte, 65)
+	out[0] = 4
+	pub.X().FillBytes(out[1:33])
+	pub.Y().FillBytes(out[33:])
+	return out
+}
+
+func curveName(pub *crypto.ECPoint) (tss.CurveName, error) {
+	if pub == nil {
+		return "", errors.New("pubkey: no public key")
+	}
+	name, ok := tss.GetCurveName(pub.Curve())
+	if !ok {
+		return "", errors.New("pubkey: the key is on an unregistered curve")
+	}
+	if name != tss.Secp256k1 && name != tss.Ed25519 {
+		return "", fmt.Errorf("pubkey: the key is on %s; only secp256k1 and ed25519 keys can be exported", name)
+	}
+	return name, nil
+}
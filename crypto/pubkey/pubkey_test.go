@@ -0,0 +1,96 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package pubkey_test
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/pubkey"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestSecp256k1(t *testing.T) {
+	keys, _, err := ecdsakeygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	pub := keys[0].ECDSAPub
+
+	bz, err := MarshalPEM(pub)
+	assert.NoError(t, err)
+	block, rest := pem.Decode(bz)
+	assert.Empty(t, rest)
+	assert.Equal(t, "PUBLIC KEY", block.Type)
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	_, err = asn1.Unmarshal(block.Bytes, &spki)
+	assert.NoError(t, err)
+	assert.Equal(t, asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}, spki.Algorithm.Algorithm)
+	var curve asn1.ObjectIdentifier
+	_, err = asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve)
+	assert.NoError(t, err)
+	assert.Equal(t, asn1.ObjectIdentifier{1, 3, 132, 0, 10}, curve)
+	assert.Equal(t, byte(4), spki.PublicKey.Bytes[0])
+	assert.Equal(t, pub.X(), new(big.Int).SetBytes(spki.PublicKey.Bytes[1:33]))
+	assert.Equal(t, pub.Y(), new(big.Int).SetBytes(spki.PublicKey.Bytes[33:]))
+
+	bz, err = MarshalJWK(pub)
+	assert.NoError(t, err)
+	var jwk JWK
+	assert.NoError(t, json.Unmarshal(bz, &jwk))
+	assert.Equal(t, "EC", jwk.Kty)
+	assert.Equal(t, "secp256k1", jwk.Crv)
+	assert.Equal(t, "ES256K", jwk.Alg)
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	assert.NoError(t, err)
+	assert.Len(t, x, 32)
+	assert.Equal(t, pub.X(), new(big.Int).SetBytes(x))
+}
+
+func TestEd25519(t *testing.T) {
+	keys, _, err := eddsakeygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	pub := keys[0].EDDSAPub
+	expected := edwards.NewPublicKey(pub.X(), pub.Y()).Serialize()
+
+	bz, err := MarshalPEM(pub)
+	assert.NoError(t, err)
+	block, _ := pem.Decode(bz)
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, ed25519.PublicKey(expected), parsed)
+
+	jwk, err := NewJWK(pub)
+	assert.NoError(t, err)
+	assert.Equal(t, "OKP", jwk.Kty)
+	assert.Equal(t, "Ed25519", jwk.Crv)
+	assert.Empty(t, jwk.Y)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(expected), jwk.X)
+}
+
+func TestBabyJubJubIsRefused(t *testing.T) {
+	ec := tss.BabyJubJub()
+	pub := crypto.ScalarBaseMult(ec, big.NewInt(7))
+	_, err := MarshalPEM(pub)
+	assert.Error(t, err)
+	_, err = NewJWK(pub)
+	assert.Error(t, err)
+}
@@ -29,6 +29,11 @@ type (
 
 // NewZKProof constructs a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
 func NewZKProof(Session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader) (*ZKProof, error) {
+	return NewZKProofWithHash(common.SHA512_256i_TAGGED, Session, x, X, rand)
+}
+
+// NewZKProofWithHash is NewZKProof with the challenge derived by `hash`
+func NewZKProofWithHash(hash common.TaggedHash, Session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader) (*ZKProof, error) {
 	if x == nil || X == nil || !X.ValidateBasic() {
 		return nil, errors.New("ZKProof constructor received nil or invalid value(s)")
 	}
@@ -42,7 +47,10 @@ func NewZKProof(Session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader) (
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
+		cHash := hash(Session, X.X(), X.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
+		if cHash == nil {
+			return nil, errors.New("ZKProof challenge hash failed")
+		}
 		c = common.RejectionSample(q, cHash)
 	}
 	t := new(big.Int).Mul(c, x)
@@ -53,6 +61,11 @@ func NewZKProof(Session []byte, x *big.Int, X *crypto.ECPoint, rand io.Reader) (
 
 // NewZKProof verifies a new Schnorr ZK proof of knowledge of the discrete logarithm (GG18Spec Fig. 16)
 func (pf *ZKProof) Verify(Session []byte, X *crypto.ECPoint) bool {
+	return pf.VerifyWithHash(common.SHA512_256i_TAGGED, Session, X)
+}
+
+// VerifyWithHash is Verify for a proof made with NewZKProofWithHash and the same `hash`
+func (pf *ZKProof) VerifyWithHash(hash common.TaggedHash, Session []byte, X *crypto.ECPoint) bool {
 	if pf == nil || !pf.ValidateBasic() {
 		return false
 	}
@@ -63,7 +76,10 @@ func (pf *ZKProof) Verify(Session []byte, X *crypto.ECPoint) bool {
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, X.X(), X.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
+		cHash := hash(Session, X.X(), X.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
+		if cHash == nil {
+			return false
+		}
 		c = common.RejectionSample(q, cHash)
 	}
 	tG := crypto.ScalarBaseMult(ec, pf.T)
@@ -81,6 +97,11 @@ func (pf *ZKProof) ValidateBasic() bool {
 
 // NewZKProof constructs a new Schnorr ZK proof of knowledge s_i, l_i such that V_i = R^s_i, g^l_i (GG18Spec Fig. 17)
 func NewZKVProof(Session []byte, V, R *crypto.ECPoint, s, l *big.Int, rand io.Reader) (*ZKVProof, error) {
+	return NewZKVProofWithHash(common.SHA512_256i_TAGGED, Session, V, R, s, l, rand)
+}
+
+// NewZKVProofWithHash is NewZKVProof with the challenge derived by `hash`
+func NewZKVProofWithHash(hash common.TaggedHash, Session []byte, V, R *crypto.ECPoint, s, l *big.Int, rand io.Reader) (*ZKVProof, error) {
 	if V == nil || R == nil || s == nil || l == nil || !V.ValidateBasic() || !R.ValidateBasic() {
 		return nil, errors.New("ZKVProof constructor received nil value(s)")
 	}
@@ -96,7 +117,10 @@ func NewZKVProof(Session []byte, V, R *crypto.ECPoint, s, l *big.Int, rand io.Re
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
+		cHash := hash(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), alpha.X(), alpha.Y())
+		if cHash == nil {
+			return nil, errors.New("ZKVProof challenge hash failed")
+		}
 		c = common.RejectionSample(q, cHash)
 	}
 	modQ := common.ModInt(q)
@@ -107,6 +131,11 @@ func NewZKVProof(Session []byte, V, R *crypto.ECPoint, s, l *big.Int, rand io.Re
 }
 
 func (pf *ZKVProof) Verify(Session []byte, V, R *crypto.ECPoint) bool {
+	return pf.VerifyWithHash(common.SHA512_256i_TAGGED, Session, V, R)
+}
+
+// VerifyWithHash is Verify for a proof made with NewZKVProofWithHash and the same `hash`
+func (pf *ZKVProof) VerifyWithHash(hash common.TaggedHash, Session []byte, V, R *crypto.ECPoint) bool {
 	if pf == nil || !pf.ValidateBasic() {
 		return false
 	}
@@ -117,7 +146,10 @@ func (pf *ZKVProof) Verify(Session []byte, V, R *crypto.ECPoint) bool {
 
 	var c *big.Int
 	{
-		cHash := common.SHA512_256i_TAGGED(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
+		cHash := hash(Session, V.X(), V.Y(), R.X(), R.Y(), g.X(), g.Y(), pf.Alpha.X(), pf.Alpha.Y())
+		if cHash == nil {
+			return false
+		}
 		c = common.RejectionSample(q, cHash)
 	}
 	tR := R.ScalarMult(pf.T)
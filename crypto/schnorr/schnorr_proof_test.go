@@ -103,3 +103,23 @@ func TestSchnorrVProofVerifyBadS(t *testing.T) {
 
 	assert.False(t, res, "verify result must be false")
 }
+
+func TestSchnorrProofVerifyWithPoseidon(t *testing.T) {
+	q := tss.EC().Params().N
+	u := common.GetRandomPositiveInt(rand.Reader, q)
+	X := crypto.ScalarBaseMult(tss.EC(), u)
+	hash := common.HashPoseidon.ChallengeHash()
+
+	proof, err := NewZKProofWithHash(hash, Session, u, X, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, proof.VerifyWithHash(hash, Session, X), "verify result must be true")
+	assert.False(t, proof.Verify(Session, X), "verify result must be false with another hash")
+
+	s, l := common.GetRandomPositiveInt(rand.Reader, q), common.GetRandomPositiveInt(rand.Reader, q)
+	R := crypto.ScalarBaseMult(tss.EC(), common.GetRandomPositiveInt(rand.Reader, q))
+	V, _ := R.ScalarMult(s).Add(crypto.ScalarBaseMult(tss.EC(), l))
+	vProof, err := NewZKVProofWithHash(hash, Session, V, R, s, l, rand.Reader)
+	assert.NoError(t, err)
+	assert.True(t, vProof.VerifyWithHash(hash, Session, V, R), "verify result must be true")
+	assert.False(t, vProof.Verify(Session, V, R), "verify result must be false with another hash")
+}
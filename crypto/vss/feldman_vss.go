@@ -113,6 +113,75 @@ func (share *Share) Verify(ec elliptic.Curve, threshold int, vs Vs) bool {
 	return sigmaGi.Equals(v)
 }
 
+// CreateZeroSharing returns shares of zero: the evaluations of a random polynomial of degree `threshold` whose constant
+// term is 0. Adding them to an existing sharing re-randomises the shares without changing the secret.
+// As 0*G is not a valid point, the returned Vs omits v0 and holds only v1..vt.
+func CreateZeroSharing(ec elliptic.Curve, threshold int, indexes []*big.Int, rand io.Reader) (Vs, Shares, error) {
+	if indexes == nil {
+		return nil, nil, errors.New("vss indexes == nil")
+	}
+	if threshold < 1 {
+		return nil, nil, errors.New("vss threshold < 1")
+	}
+
+	ids, err := CheckIndexes(ec, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	num := len(indexes)
+	if num < threshold {
+		return nil, nil, ErrNumSharesBelowThreshold
+	}
+
+	poly := samplePolynomial(ec, threshold, zero, rand)
+
+	v := make(Vs, threshold)
+	for i, ai := range poly[1:] {
+		v[i] = crypto.ScalarBaseMult(ec, ai)
+	}
+
+	shares := make(Shares, num)
+	for i := 0; i < num; i++ {
+		share := evaluatePolynomial(ec, threshold, poly, ids[i])
+		shares[i] = &Share{Threshold: threshold, ID: ids[i], Share: share}
+	}
+	return v, shares, nil
+}
+
+// VerifyZeroShare checks a share created by CreateZeroSharing against the commitments v1..vt
+func (share *Share) VerifyZeroShare(ec elliptic.Curve, threshold int, vs Vs) bool {
+	if share.Threshold != threshold || vs == nil || len(vs) != threshold {
+		return false
+	}
+	v, err := EvaluateZeroCommitments(ec, vs, share.ID)
+	if err != nil {
+		return false
+	}
+	sigmaGi := crypto.ScalarBaseMult(ec, share.Share)
+	return sigmaGi.Equals(v)
+}
+
+// EvaluateZeroCommitments returns f(id)*G for the zero sharing committed to by v1..vt
+func EvaluateZeroCommitments(ec elliptic.Curve, vs Vs, id *big.Int) (*crypto.ECPoint, error) {
+	if len(vs) == 0 {
+		return nil, errors.New("vss zero commitments are empty")
+	}
+	var err error
+	modQ := common.ModInt(ec.Params().N)
+	t := new(big.Int).Mod(id, ec.Params().N)
+	v := vs[0].SetCurve(ec).ScalarMult(t)
+	for j := 1; j < len(vs); j++ {
+		// t = id^(j+1)
+		t = modQ.Mul(t, id)
+		v, err = v.Add(vs[j].SetCurve(ec).ScalarMult(t))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
 func (shares Shares) ReConstruct(ec elliptic.Curve) (secret *big.Int, err error) {
 	if shares != nil && shares[0].Threshold > len(shares) {
 		return nil, ErrNumSharesBelowThreshold
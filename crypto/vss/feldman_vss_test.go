@@ -115,3 +115,36 @@ func TestReconstruct(t *testing.T) {
 	assert.NoError(t, err4)
 	assert.NotZero(t, secret4)
 }
+
+func TestZeroSharing(t *testing.T) {
+	num, threshold := 5, 3
+
+	secret := common.GetRandomPositiveInt(rand.Reader, tss.EC().Params().N)
+	ids := make([]*big.Int, 0)
+	for i := 0; i < num; i++ {
+		ids = append(ids, common.GetRandomPositiveInt(rand.Reader, tss.EC().Params().N))
+	}
+
+	_, shares, err := Create(tss.EC(), threshold, secret, ids, rand.Reader)
+	assert.NoError(t, err)
+	zeroVs, zeroShares, err := CreateZeroSharing(tss.EC(), threshold, ids, rand.Reader)
+	assert.NoError(t, err)
+	assert.Equal(t, threshold, len(zeroVs))
+
+	modN := common.ModInt(tss.EC().Params().N)
+	refreshed := make(Shares, num)
+	for i, share := range zeroShares {
+		assert.True(t, share.VerifyZeroShare(tss.EC(), threshold, zeroVs))
+		assert.NotZero(t, share.Share.Sign())
+		refreshed[i] = &Share{Threshold: threshold, ID: ids[i], Share: modN.Add(shares[i].Share, share.Share)}
+	}
+
+	// the refreshed shares still reconstruct the secret
+	secret2, err := refreshed[:threshold+1].ReConstruct(tss.EC())
+	assert.NoError(t, err)
+	assert.Equal(t, secret, secret2)
+
+	// a tampered share fails verification
+	bad := &Share{Threshold: threshold, ID: ids[0], Share: modN.Add(zeroShares[0].Share, big.NewInt(1))}
+	assert.False(t, bad.VerifyZeroShare(tss.EC(), threshold, zeroVs))
+}
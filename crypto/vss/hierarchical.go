@@ -0,0 +1,191 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package vss
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// CreateHierarchical shares the secret along a nested access structure: each group shares the value it holds with a
+// polynomial of degree Threshold-1, evaluated at the positions, from 1, of its members, so that each nested group
+// holds the evaluation at its position and shares it again, down to the parties. The root holds the secret.
+// The returned Vs commits to every coefficient of the polynomial of the root, followed by the coefficients of the
+// polynomials of the other groups, depth first, but their constant terms, which follow from the parent polynomials.
+// The shares are returned in the order of `indexes`, the keys of the parties of the structure.
+func CreateHierarchical(ec elliptic.Curve, as *tss.AccessStructure, secret *big.Int, indexes []*big.Int, rand io.Reader) (Vs, Shares, error) {
+	if secret == nil || indexes == nil {
+		return nil, nil, fmt.Errorf("vss secret or indexes == nil: %v %v", secret, indexes)
+	}
+	if err := as.ValidateBasic(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := CheckIndexes(ec, indexes); err != nil {
+		return nil, nil, err
+	}
+
+	var vs Vs
+	byKey := make(map[string]*big.Int)
+	var share func(group *tss.AccessStructure, value *big.Int, root bool)
+	share = func(group *tss.AccessStructure, value *big.Int, root bool) {
+		poly := samplePolynomial(ec, group.Threshold-1, value, rand)
+		for c, ac := range poly {
+			if root || c > 0 {
+				vs = append(vs, crypto.ScalarBaseMult(ec, ac))
+			}
+		}
+		for i, key := range group.Parties {
+			byKey[key.String()] = evaluatePolynomial(ec, group.Threshold-1, poly, big.NewInt(int64(i+1)))
+		}
+		for i, sub := range group.Groups {
+			share(sub, evaluatePolynomial(ec, group.Threshold-1, poly, big.NewInt(int64(i+1))), false)
+		}
+	}
+	share(as, secret, true)
+
+	if len(byKey) != len(indexes) {
+		return nil, nil, fmt.Errorf("access structure has %d parties, but %d indexes were given", len(byKey), len(indexes))
+	}
+	shares := make(Shares, len(indexes))
+	for i, id := range indexes {
+		sh, ok := byKey[id.String()]
+		if !ok {
+			return nil, nil, fmt.Errorf("index %s is not a party of the access structure", id)
+		}
+		shares[i] = &Share{Threshold: as.Threshold - 1, ID: id, Share: sh}
+	}
+	return vs, shares, nil
+}
+
+// HierarchicalCommitments returns the number of commitments in the Vs of CreateHierarchical
+func HierarchicalCommitments(as *tss.AccessStructure) int {
+	return as.Threshold + hierarchicalSubCommitments(as)
+}
+
+// the number of commitments of the nested groups of `as`
+func hierarchicalSubCommitments(as *tss.AccessStructure) int {
+	n := 0
+	for _, group := range as.Groups {
+		n += group.Threshold - 1 + hierarchicalSubCommitments(group)
+	}
+	return n
+}
+
+// EvaluateHierarchical returns share*G for the party with key `id` from the commitments of CreateHierarchical
+func EvaluateHierarchical(ec elliptic.Curve, as *tss.AccessStructure, vs Vs, id *big.Int) (*crypto.ECPoint, error) {
+	if len(vs) != HierarchicalCommitments(as) {
+		return nil, errors.New("vss commitments do not match the access structure")
+	}
+	coeffs, rest := vs[:as.Threshold], vs[as.Threshold:]
+	for group := as; ; {
+		for i, key := range group.Parties {
+			if key.Cmp(id) == 0 {
+				return evaluateCommitments(ec, coeffs, big.NewInt(int64(i+1)))
+			}
+		}
+		var next *tss.AccessStructure
+		for i, sub := range group.Groups {
+			subLen := sub.Threshold - 1 + hierarchicalSubCommitments(sub)
+			if !containsKey(sub, id) {
+				rest = rest[subLen:]
+				continue
+			}
+			constant, err := evaluateCommitments(ec, coeffs, big.NewInt(int64(i+1)))
+			if err != nil {
+				return nil, err
+			}
+			coeffs = append(Vs{constant}, rest[:sub.Threshold-1]...)
+			rest = rest[sub.Threshold-1:]
+			next = sub
+			break
+		}
+		if next == nil {
+			return nil, fmt.Errorf("party %s is not in the access structure", id)
+		}
+		group = next
+	}
+}
+
+// VerifyHierarchical checks a share created by CreateHierarchical against its commitments
+func (share *Share) VerifyHierarchical(ec elliptic.Curve, as *tss.AccessStructure, vs Vs) bool {
+	if share == nil || share.ID == nil || share.Share == nil {
+		return false
+	}
+	v, err := EvaluateHierarchical(ec, as, vs, share.ID)
+	if err != nil {
+		return false
+	}
+	return crypto.ScalarBaseMult(ec, share.Share).Equals(v)
+}
+
+// ----- //
+
+// CreateWithAccessStructure calls CreateHierarchical, or Create with the threshold without an access structure
+func CreateWithAccessStructure(ec elliptic.Curve, threshold int, as *tss.AccessStructure, secret *big.Int, indexes []*big.Int, rand io.Reader) (Vs, Shares, error) {
+	if as == nil {
+		return Create(ec, threshold, secret, indexes, rand)
+	}
+	return CreateHierarchical(ec, as, secret, indexes, rand)
+}
+
+// VerifyWithAccessStructure calls VerifyHierarchical, or Verify with the threshold without an access structure
+func (share *Share) VerifyWithAccessStructure(ec elliptic.Curve, threshold int, as *tss.AccessStructure, vs Vs) bool {
+	if as == nil {
+		return share.Verify(ec, threshold, vs)
+	}
+	return share.VerifyHierarchical(ec, as, vs)
+}
+
+// CommitmentsLen returns the number of commitments of a sharing with the threshold or the access structure
+func CommitmentsLen(threshold int, as *tss.AccessStructure) int {
+	if as == nil {
+		return threshold + 1
+	}
+	return HierarchicalCommitments(as)
+}
+
+// EvaluateCommitments returns share*G for the party with key `id` from the commitments of a sharing with the access
+// structure, or of a flat sharing without one
+func EvaluateCommitments(ec elliptic.Curve, as *tss.AccessStructure, vs Vs, id *big.Int) (*crypto.ECPoint, error) {
+	if as == nil {
+		return evaluateCommitments(ec, vs, id)
+	}
+	return EvaluateHierarchical(ec, as, vs, id)
+}
+
+// evaluateCommitments returns f(x)*G for the polynomial f committed to by vs
+func evaluateCommitments(ec elliptic.Curve, vs Vs, x *big.Int) (*crypto.ECPoint, error) {
+	if len(vs) == 0 {
+		return nil, errors.New("vss commitments are empty")
+	}
+	var err error
+	modQ := common.ModInt(ec.Params().N)
+	v, t := vs[0].SetCurve(ec), one
+	for j := 1; j < len(vs); j++ {
+		t = modQ.Mul(t, x)
+		if v, err = v.Add(vs[j].SetCurve(ec).ScalarMult(t)); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func containsKey(as *tss.AccessStructure, id *big.Int) bool {
+	for _, key := range as.Keys() {
+		if key.Cmp(id) == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package vss_test
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestCreateHierarchical(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(6)
+	// 2 of: {2 of p0, p1, p2}, {1 of p3, p4}, {p5}
+	as := tss.NewNestedAccessStructure("root", 2,
+		tss.NewAccessStructure("a", 2, pIDs[0], pIDs[1], pIDs[2]),
+		tss.NewAccessStructure("b", 1, pIDs[3], pIDs[4]),
+		tss.NewAccessStructure("c", 1, pIDs[5]),
+	)
+	assert.NoError(t, as.Validate(pIDs))
+	assert.Equal(t, 2, as.MinSigners())
+
+	ec := tss.EC()
+	secret := common.GetRandomPositiveInt(rand.Reader, ec.Params().N)
+	ids := pIDs.Keys()
+	vs, shares, err := CreateHierarchical(ec, as, secret, ids, rand.Reader)
+	assert.NoError(t, err)
+	assert.Len(t, vs, HierarchicalCommitments(as))
+	assert.True(t, crypto.ScalarBaseMult(ec, secret).Equals(vs[0]))
+	for _, share := range shares {
+		assert.True(t, share.VerifyHierarchical(ec, as, vs))
+	}
+	bad := *shares[4]
+	bad.Share = new(big.Int).Add(bad.Share, big.NewInt(1))
+	assert.False(t, bad.VerifyHierarchical(ec, as, vs))
+
+	// any authorized set recombines the secret with the coefficients of the structure
+	modQ := common.ModInt(ec.Params().N)
+	for _, available := range []tss.SortedPartyIDs{
+		{pIDs[0], pIDs[2], pIDs[4]},
+		{pIDs[1], pIDs[2], pIDs[5]},
+		{pIDs[3], pIDs[5]},
+		pIDs,
+	} {
+		signers, err := as.Resolve(available)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		keys := make([]*big.Int, len(signers))
+		for i, p := range signers {
+			keys[i] = p.KeyInt()
+		}
+		coefs, err := as.Coefficients(ec, keys)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		sum := big.NewInt(0)
+		for i, p := range signers {
+			sum = modQ.Add(sum, modQ.Mul(coefs[i], shares[p.Index].Share))
+		}
+		assert.Equal(t, secret, sum)
+	}
+
+	_, err = as.Resolve([]*tss.PartyID{pIDs[0], pIDs[3], pIDs[4]})
+	assert.Error(t, err, "only one group is satisfied")
+	_, err = as.Coefficients(ec, []*big.Int{ids[0], ids[1], ids[3], ids[5]})
+	assert.Error(t, err, "a signer is not needed")
+	assert.Error(t, as.Validate(pIDs[:5]), "p5 is not in the session")
+}
@@ -0,0 +1,120 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package witness builds the JSON inputs of the common signature verification circuits, to be fed to a Groth16
+// witness generator. The signatures are not verified here; an invalid signature yields an unsatisfiable witness.
+package witness
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// ECDSARegisterBits and ECDSARegisters split 256-bit ints as circom-ecdsa does (n = 64, k = 4)
+	ECDSARegisterBits = 64
+	ECDSARegisters    = 4
+)
+
+// EdDSAPoseidonInputs are the inputs of circomlib's EdDSAPoseidonVerifier, as decimal strings
+type EdDSAPoseidonInputs struct {
+	Enabled string `json:"enabled"`
+	Ax      string `json:"Ax"`
+	Ay      string `json:"Ay"`
+	S       string `json:"S"`
+	R8x     string `json:"R8x"`
+	R8y     string `json:"R8y"`
+	M       string `json:"M"`
+}
+
+// ECDSAInputs are the inputs of circom-ecdsa's ECDSAVerifyNoPubkeyCheck, each int split in little-endian registers
+type ECDSAInputs struct {
+	R       []string    `json:"r"`
+	S       []string    `json:"s"`
+	MsgHash []string    `json:"msghash"`
+	PubKey  [2][]string `json:"pubkey"`
+}
+
+// EdDSAPoseidon returns the circuit inputs for the signature of `msg` by the BabyJubJub key `pub`, as produced by
+// eddsa/signing with a BabyJubJub key or by iden3 babyjub.
+// When `msg` is nil, the message of the signature data is used.
+func EdDSAPoseidon(sig *common.SignatureData, pub *crypto.ECPoint, msg *big.Int) (*EdDSAPoseidonInputs, error) {
+	if sig == nil || len(sig.Signature) != 64 || pub == nil {
+		return nil, errors.New("EdDSAPoseidon: missing signature or public key")
+	}
+	if !tss.SameCurve(pub.Curve(), tss.BabyJubJub()) {
+		return nil, errors.New("EdDSAPoseidon: the key is not a BabyJubJub key")
+	}
+	if msg == nil {
+		msg = new(big.Int).SetBytes(sig.M)
+	}
+	if !common.IsPoseidonFieldElement(msg) {
+		return nil, errors.New("EdDSAPoseidon: the message is not a field element")
+	}
+	bz := make([]byte, 0, babyjubjub.CircomSignatureLen)
+	bz = append(bz, sig.Signature...)
+	bz = append(bz, babyjubjub.Compress(pub.X(), pub.Y())...)
+	circomSig, err := babyjubjub.UnmarshalCircomSignature(bz)
+	if err != nil {
+		return nil, err
+	}
+	return &EdDSAPoseidonInputs{
+		Enabled: "1",
+		Ax:      circomSig.Ax.String(),
+		Ay:      circomSig.Ay.String(),
+		S:       circomSig.S.String(),
+		R8x:     circomSig.R8x.String(),
+		R8y:     circomSig.R8y.String(),
+		M:       msg.String(),
+	}, nil
+}
+
+// ECDSA returns the circuit inputs for the signature of the digest `msg` by the secp256k1 key `pub`.
+// When `msg` is nil, the message of the signature data is used.
+func ECDSA(sig *common.SignatureData, pub *crypto.ECPoint, msg *big.Int) (*ECDSAInputs, error) {
+	if sig == nil || len(sig.R) == 0 || len(sig.S) == 0 || pub == nil {
+		return nil, errors.New("ECDSA: missing signature or public key")
+	}
+	if !tss.SameCurve(pub.Curve(), tss.S256()) {
+		return nil, errors.New("ECDSA: the key is not a secp256k1 key")
+	}
+	if msg == nil {
+		msg = new(big.Int).SetBytes(sig.M)
+	}
+	ints := []*big.Int{new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S), msg, pub.X(), pub.Y()}
+	regs := make([][]string, len(ints))
+	for i, n := range ints {
+		if regs[i] = toRegisters(n); regs[i] == nil {
+			return nil, errors.New("ECDSA: an input does not fit the circuit registers")
+		}
+	}
+	return &ECDSAInputs{
+		R:       regs[0],
+		S:       regs[1],
+		MsgHash: regs[2],
+		PubKey:  [2][]string{regs[3], regs[4]},
+	}, nil
+}
+
+// toRegisters splits `n` in ECDSARegisters little-endian registers of ECDSARegisterBits, or returns nil if it is too big
+func toRegisters(n *big.Int) []string {
+	if n.Sign() < 0 || n.BitLen() > ECDSARegisterBits*ECDSARegisters {
+		return nil
+	}
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), ECDSARegisterBits), big.NewInt(1))
+	regs := make([]string, ECDSARegisters)
+	rest := new(big.Int).Set(n)
+	for i := range regs {
+		regs[i] = new(big.Int).And(rest, mask).String()
+		rest.Rsh(rest, ECDSARegisterBits)
+	}
+	return regs
+}
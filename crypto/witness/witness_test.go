@@ -0,0 +1,84 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package witness_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	. "github.com/bnb-chain/tss-lib/v2/crypto/witness"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestEdDSAPoseidon(t *testing.T) {
+	k := iden3bjj.NewRandPrivKey()
+	pk := k.Public()
+	msg := big.NewInt(42)
+	sig := k.SignPoseidon(msg)
+	sigComp := sig.Compress()
+
+	pub, err := crypto.NewECPoint(tss.BabyJubJub(), pk.X, pk.Y)
+	assert.NoError(t, err)
+	data := &common.SignatureData{Signature: sigComp[:], M: msg.Bytes()}
+	in, err := EdDSAPoseidon(data, pub, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &EdDSAPoseidonInputs{
+		Enabled: "1",
+		Ax:      pk.X.String(),
+		Ay:      pk.Y.String(),
+		S:       sig.S.String(),
+		R8x:     sig.R8.X.String(),
+		R8y:     sig.R8.Y.String(),
+		M:       "42",
+	}, in)
+
+	bz, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.Contains(t, string(bz), `"R8x":"`+sig.R8.X.String()+`"`)
+
+	_, err = EdDSAPoseidon(data, crypto.ScalarBaseMult(tss.S256(), big.NewInt(1)), nil)
+	assert.Error(t, err, "a secp256k1 key should be rejected")
+}
+
+func TestECDSA(t *testing.T) {
+	sk, err := ecdsa.GenerateKey(tss.S256(), rand.Reader)
+	assert.NoError(t, err)
+	digest := common.SHA512_256([]byte("hello"))
+	r, s, err := ecdsa.Sign(rand.Reader, sk, digest)
+	assert.NoError(t, err)
+
+	pub, err := crypto.NewECPoint(tss.S256(), sk.X, sk.Y)
+	assert.NoError(t, err)
+	data := &common.SignatureData{R: r.Bytes(), S: s.Bytes(), M: digest}
+	in, err := ECDSA(data, pub, nil)
+	assert.NoError(t, err)
+
+	fromRegisters := func(regs []string) *big.Int {
+		n := new(big.Int)
+		for i := len(regs) - 1; i >= 0; i-- {
+			reg, ok := new(big.Int).SetString(regs[i], 10)
+			assert.True(t, ok)
+			assert.True(t, reg.BitLen() <= ECDSARegisterBits)
+			n.Lsh(n, ECDSARegisterBits).Add(n, reg)
+		}
+		return n
+	}
+	assert.Len(t, in.R, ECDSARegisters)
+	assert.Equal(t, r, fromRegisters(in.R))
+	assert.Equal(t, s, fromRegisters(in.S))
+	assert.Equal(t, new(big.Int).SetBytes(digest), fromRegisters(in.MsgHash))
+	assert.Equal(t, sk.X, fromRegisters(in.PubKey[0]))
+	assert.Equal(t, sk.Y, fromRegisters(in.PubKey[1]))
+}
@@ -0,0 +1,113 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package derivation derives child keys of TSS keys, so that one keygen serves many addresses. A child key is the
+// key plus δG for a public tweak δ that anyone with the public key and the chain code can compute, so the parties
+// derive it without a session: the public shares of the child are those of the key plus δG, and each share x_i of the
+// key becomes x_i + δ when signing, since the Lagrange coefficients of any signing set sum to 1.
+//
+// Only such public derivation is possible in the threshold setting. Hardened derivation hashes the secret key, which
+// no party holds, so hardened indexes are refused with ErrHardened. This rules out SLIP-0010 for ed25519, which only
+// defines hardened derivation: EdDSAChild derives with a tweak instead, whose children are not those that a
+// SLIP-0010 wallet derives. The children of a key are public to anyone with its public key and chain code, and a
+// child secret with the parent public key and chain code reveals the parent secret, as for any non-hardened child.
+package derivation
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/ckd"
+)
+
+const (
+	// ChainCodeLength is the length of the chain code of a key
+	ChainCodeLength = 32
+
+	maxDepth = 1<<8 - 1
+)
+
+// ErrHardened is returned for a hardened index, which a TSS key cannot derive
+var ErrHardened = errors.New("derivation: hardened derivation needs the secret key, which no party holds")
+
+// Child is a child key of a TSS key
+type Child struct {
+	Path []uint32
+	// the sum of the tweaks of the steps of the path, which a signing with the key adds to each share
+	Delta     *big.Int
+	PublicKey *crypto.ECPoint
+	ChainCode []byte
+	// the public key of the parent, to check the save data that the child is applied to against
+	Parent *crypto.ECPoint
+}
+
+// ParsePath parses a derivation path such as "m/44/60/0/0/7". A hardened index, such as 44', is refused.
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation: the path %q does not start with m", path)
+	}
+	out := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H") {
+			return nil, fmt.Errorf("%w: %s", ErrHardened, part)
+		}
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("derivation: the index %q is not a number", part)
+		}
+		if index >= ckd.HardenedKeyStart {
+			return nil, fmt.Errorf("%w: %d", ErrHardened, index)
+		}
+		out = append(out, uint32(index))
+	}
+	return out, nil
+}
+
+// FormatPath formats a derivation path as ParsePath parses it
+func FormatPath(path []uint32) string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range path {
+		sb.WriteString("/")
+		sb.WriteString(strconv.FormatUint(uint64(index), 10))
+	}
+	return sb.String()
+}
+
+// checkPath checks that a path has no hardened index and is not deeper than an extended key can be
+func checkPath(path []uint32) error {
+	if len(path) > maxDepth {
+		return fmt.Errorf("derivation: the path is deeper than %d", maxDepth)
+	}
+	for _, index := range path {
+		if index >= ckd.HardenedKeyStart {
+			return fmt.Errorf("%w: %d", ErrHardened, index)
+		}
+	}
+	return nil
+}
+
+func checkChainCode(chainCode []byte) error {
+	if len(chainCode) != ChainCodeLength {
+		return fmt.Errorf("derivation: the chain code has %d bytes, not %d", len(chainCode), ChainCodeLength)
+	}
+	return nil
+}
+
+func checkParent(pub, parent *crypto.ECPoint) error {
+	if pub == nil {
+		return errors.New("derivation: the save data has no public key")
+	}
+	if !pub.Equals(parent) {
+		return errors.New("derivation: the save data is not of the parent key of the child")
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package derivation
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/address"
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/ckd"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestParsePath(t *testing.T) {
+	path, err := ParsePath("m/44/60/0/0/7")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{44, 60, 0, 0, 7}, path)
+	assert.Equal(t, "m/44/60/0/0/7", FormatPath(path))
+
+	path, err = ParsePath("m")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	for _, bad := range []string{"", "44/60", "m/44'/60", "m/44h", "m/-1", "m/2147483648", "m//1", "m/x"} {
+		_, err := ParsePath(bad)
+		assert.Error(t, err, bad)
+	}
+	_, err = ParsePath("m/44'/60")
+	assert.True(t, errors.Is(err, ErrHardened))
+}
+
+func TestECDSAChild(t *testing.T) {
+	// the test vector 1 of BIP-32, at m/0/1 from m
+	master, err := ckd.NewExtendedKeyFromString("xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8", tss.S256())
+	assert.NoError(t, err)
+	expected, err := ckd.NewExtendedKeyFromString("xpub6AvUGrnEpfvJBbfx7sQ89Q8hEMPM65UteqEX4yUbUiES2jHfjexmfJoxCGSwFMZiPBaKQT1RiKWrKfuDV4vpgVs4Xn8PpPTR2i79rwHd4Zr", tss.S256())
+	assert.NoError(t, err)
+
+	pub, err := crypto.NewECPoint(tss.S256(), master.X, master.Y)
+	assert.NoError(t, err)
+	child, err := ECDSAChild(pub, master.ChainCode, []uint32{0, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, child.PublicKey.X().Cmp(expected.X))
+	assert.Equal(t, 0, child.PublicKey.Y().Cmp(expected.Y))
+	assert.Equal(t, expected.ChainCode, child.ChainCode)
+
+	// the child is the parent plus δG
+	tweaked, err := pub.Add(crypto.ScalarBaseMult(tss.S256(), child.Delta))
+	assert.NoError(t, err)
+	assert.True(t, tweaked.Equals(child.PublicKey))
+
+	_, err = ECDSAChild(pub, master.ChainCode[:31], []uint32{0})
+	assert.Error(t, err)
+	_, err = ECDSAChild(pub, master.ChainCode, []uint32{ckd.HardenedKeyStart})
+	assert.True(t, errors.Is(err, ErrHardened))
+	_, err = ECDSAChild(crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(1)), master.ChainCode, []uint32{0})
+	assert.Error(t, err)
+}
+
+func TestECDSA(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(test.TestParticipants)
+	assert.NoError(t, err)
+	chainCode := make([]byte, ChainCodeLength)
+	chainCode[0] = 1
+	child, err := ECDSAChild(keys[0].ECDSAPub, chainCode, []uint32{44, 60, 0, 0, 7})
+	assert.NoError(t, err)
+
+	childKeys := make([]keygen.LocalPartySaveData, len(keys))
+	for i, key := range keys {
+		key.Metadata = tss.NewKeyMetadata(tss.S256(), test.TestThreshold, len(keys), common.HashSHA512_256).WithKeyID(key.KeyID())
+		childKeys[i], err = ECDSA(key, child)
+		assert.NoError(t, err)
+		// the save data given is left as it is
+		assert.True(t, key.ECDSAPub.Equals(child.Parent))
+		assert.Equal(t, tss.KeyID(tss.S256(), key.ECDSAPub.X(), key.ECDSAPub.Y()), key.KeyID())
+		// and the child has its own key ID
+		assert.Equal(t, tss.KeyID(tss.S256(), child.PublicKey.X(), child.PublicKey.Y()), childKeys[i].KeyID())
+	}
+
+	// the public shares of every party interpolate to the child key
+	pub, err := address.ECDSAKey(childKeys...)
+	assert.NoError(t, err)
+	assert.True(t, pub.Equals(child.PublicKey))
+
+	// and each share plus δ is the secret of the public share of the child
+	modN := common.ModInt(tss.S256().Params().N)
+	for i, key := range childKeys {
+		xi := modN.Add(key.Xi, child.Delta)
+		assert.True(t, crypto.ScalarBaseMult(tss.S256(), xi).Equals(key.BigXj[i]))
+	}
+
+	other := keys[0]
+	other.ECDSAPub = crypto.ScalarBaseMult(tss.S256(), big.NewInt(1))
+	_, err = ECDSA(other, child)
+	assert.Error(t, err)
+}
+
+func TestEdDSAChild(t *testing.T) {
+	chainCode := make([]byte, ChainCodeLength)
+	chainCode[0] = 1
+	for _, ec := range []elliptic.Curve{tss.Edwards(), tss.BabyJubJub()} {
+		pub := crypto.ScalarBaseMult(ec, big.NewInt(7))
+		child, err := EdDSAChild(pub, chainCode, []uint32{0, 1, 2})
+		assert.NoError(t, err)
+		tweaked, err := pub.Add(crypto.ScalarBaseMult(ec, child.Delta))
+		assert.NoError(t, err)
+		assert.True(t, tweaked.Equals(child.PublicKey))
+
+		// a path derives in steps
+		parent, err := EdDSAChild(pub, chainCode, []uint32{0, 1})
+		assert.NoError(t, err)
+		stepped, err := EdDSAChild(parent.PublicKey, parent.ChainCode, []uint32{2})
+		assert.NoError(t, err)
+		assert.True(t, stepped.PublicKey.Equals(child.PublicKey))
+		assert.Equal(t, child.ChainCode, stepped.ChainCode)
+		assert.Equal(t, 0, common.ModInt(ec.Params().N).Add(parent.Delta, stepped.Delta).Cmp(child.Delta))
+
+		sibling, err := EdDSAChild(pub, chainCode, []uint32{0, 1, 3})
+		assert.NoError(t, err)
+		assert.False(t, sibling.PublicKey.Equals(child.PublicKey))
+
+		_, err = EdDSAChild(pub, chainCode, []uint32{0, ckd.HardenedKeyStart + 1})
+		assert.True(t, errors.Is(err, ErrHardened))
+	}
+	_, err := EdDSAChild(crypto.ScalarBaseMult(tss.S256(), big.NewInt(7)), chainCode, []uint32{0})
+	assert.Error(t, err)
+}
+
+func TestEdDSA(t *testing.T) {
+	keys, _, err := eddsakeygen.LoadKeygenTestFixtures(test.TestParticipants)
+	assert.NoError(t, err)
+	chainCode := make([]byte, ChainCodeLength)
+	child, err := EdDSAChild(keys[0].EDDSAPub, chainCode, []uint32{501, 0, 0})
+	assert.NoError(t, err)
+
+	childKeys := make([]eddsakeygen.LocalPartySaveData, len(keys))
+	for i, key := range keys {
+		key.Metadata = tss.NewKeyMetadata(tss.Edwards(), test.TestThreshold, len(keys), common.HashSHA512_256).WithKeyID(key.KeyID())
+		childKeys[i], err = EdDSA(key, child)
+		assert.NoError(t, err)
+		assert.Equal(t, tss.KeyID(tss.Edwards(), key.EDDSAPub.X(), key.EDDSAPub.Y()), key.KeyID())
+		assert.Equal(t, tss.KeyID(tss.Edwards(), child.PublicKey.X(), child.PublicKey.Y()), childKeys[i].KeyID())
+	}
+	pub, err := address.EdDSAKey(childKeys...)
+	assert.NoError(t, err)
+	assert.True(t, pub.Equals(child.PublicKey))
+
+	modN := common.ModInt(tss.Edwards().Params().N)
+	for i, key := range childKeys {
+		xi := modN.Add(key.Xi, child.Delta)
+		assert.True(t, crypto.ScalarBaseMult(tss.Edwards(), xi).Equals(key.BigXj[i]))
+	}
+
+	_, err = EdDSA(keys[0], &Child{Parent: crypto.ScalarBaseMult(tss.Edwards(), big.NewInt(1))})
+	assert.Error(t, err)
+}
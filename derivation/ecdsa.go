@@ -0,0 +1,86 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package derivation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/ckd"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// ECDSAChild derives the child of a secp256k1 TSS key at `path` with the public derivation of BIP-32, from the key
+// and its chain code
+func ECDSAChild(pub *crypto.ECPoint, chainCode []byte, path []uint32) (*Child, error) {
+	if err := checkChainCode(chainCode); err != nil {
+		return nil, err
+	}
+	if err := checkPath(path); err != nil {
+		return nil, err
+	}
+	if pub == nil {
+		return nil, errors.New("derivation: no public key")
+	}
+	if name, ok := tss.GetCurveName(pub.Curve()); !ok || name != tss.Secp256k1 {
+		return nil, errors.New("derivation: the key is not on secp256k1")
+	}
+	ec := tss.S256()
+	parent := &ckd.ExtendedKey{
+		PublicKey: ecdsa.PublicKey{Curve: ec, X: pub.X(), Y: pub.Y()},
+		ChainCode: chainCode,
+		ParentFP:  []byte{0x00, 0x00, 0x00, 0x00},
+		Version:   chaincfg.MainNetParams.HDPublicKeyID[:],
+	}
+	delta, extended, err := ckd.DeriveChildKeyFromHierarchy(path, parent, ec.Params().N, ec)
+	if err != nil {
+		return nil, err
+	}
+	childPub, err := crypto.NewECPoint(ec, extended.X, extended.Y)
+	if err != nil {
+		return nil, err
+	}
+	return &Child{
+		Path:      append([]uint32(nil), path...),
+		Delta:     delta,
+		PublicKey: childPub,
+		ChainCode: extended.ChainCode,
+		Parent:    pub,
+	}, nil
+}
+
+// ECDSA returns a copy of the save data of a party with the public key, the public shares and the key ID of the child,
+// for a signing with the child key. The share itself is left as it is: the signing adds Child.Delta to it, and must be
+// started with signing.NewLocalPartyWithKDD and the delta.
+//
+//	child, err := derivation.ECDSAChild(save.ECDSAPub, chainCode, path)
+//	childSave, err := derivation.ECDSA(save, child)
+//	party := signing.NewLocalPartyWithKDD(msg, params, childSave, child.Delta, out, end)
+func ECDSA(save keygen.LocalPartySaveData, child *Child) (keygen.LocalPartySaveData, error) {
+	if err := checkParent(save.ECDSAPub, child.Parent); err != nil {
+		return keygen.LocalPartySaveData{}, err
+	}
+	gDelta := crypto.ScalarBaseMult(tss.S256(), child.Delta)
+	bigXj := make([]*crypto.ECPoint, len(save.BigXj))
+	for j, bigX := range save.BigXj {
+		if bigX == nil {
+			return keygen.LocalPartySaveData{}, errors.New("derivation: a public share is missing")
+		}
+		var err error
+		if bigXj[j], err = bigX.Add(gDelta); err != nil {
+			return keygen.LocalPartySaveData{}, err
+		}
+	}
+	save.BigXj = bigXj
+	save.ECDSAPub = child.PublicKey
+	save.Metadata = save.Metadata.WithKeyID(tss.KeyID(child.PublicKey.Curve(), child.PublicKey.X(), child.PublicKey.Y()))
+	return save, nil
+}
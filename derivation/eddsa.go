@@ -0,0 +1,119 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package derivation
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/pubkey"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// the prefixes of the HMAC inputs of the tweak and of the chain code of a child, as in BIP32-Ed25519
+const (
+	tweakPrefix     = 0x02
+	chainCodePrefix = 0x03
+)
+
+// EdDSAChild derives the child of an ed25519 or BabyJubJub TSS key at `path` from the key and its chain code. Each
+// step tweaks the key A with the 64 bytes Z = HMAC-SHA512(c, 0x02 || A || i) as a little-endian integer mod the order
+// of the curve, and takes the chain code of the child from HMAC-SHA512(c, 0x03 || A || i), with i in 4 little-endian
+// bytes as in BIP32-Ed25519. A is the 32-byte encoding of the key: that of RFC 8032 for ed25519, and the compressed
+// point of iden3 for BabyJubJub. Hardened indexes, the only ones of SLIP-0010 for ed25519, are refused (see the
+// package documentation).
+func EdDSAChild(pub *crypto.ECPoint, chainCode []byte, path []uint32) (*Child, error) {
+	if err := checkChainCode(chainCode); err != nil {
+		return nil, err
+	}
+	if err := checkPath(path); err != nil {
+		return nil, err
+	}
+	if pub == nil {
+		return nil, errors.New("derivation: no public key")
+	}
+	var encode func(*crypto.ECPoint) []byte
+	switch name, _ := tss.GetCurveName(pub.Curve()); name {
+	case tss.Ed25519:
+		encode = pubkey.Ed25519Bytes
+	case tss.BabyJub:
+		encode = func(p *crypto.ECPoint) []byte { return babyjubjub.Compress(p.X(), p.Y()) }
+	default:
+		return nil, errors.New("derivation: the key is not on ed25519 or BabyJubJub")
+	}
+	ec := pub.Curve()
+	modN := common.ModInt(ec.Params().N)
+	delta, key, code := big.NewInt(0), pub, chainCode
+	for _, index := range path {
+		encoded := encode(key)
+		z := hmacSHA512(code, tweakPrefix, encoded, index)
+		for i, j := 0, len(z)-1; i < j; i, j = i+1, j-1 {
+			z[i], z[j] = z[j], z[i]
+		}
+		tweak := new(big.Int).Mod(new(big.Int).SetBytes(z), ec.Params().N)
+		if tweak.Sign() == 0 {
+			return nil, errors.New("derivation: invalid derived key")
+		}
+		var err error
+		if key, err = key.Add(crypto.ScalarBaseMult(ec, tweak)); err != nil {
+			return nil, err
+		}
+		code = hmacSHA512(code, chainCodePrefix, encoded, index)[32:]
+		delta = modN.Add(delta, tweak)
+	}
+	return &Child{
+		Path:      append([]uint32(nil), path...),
+		Delta:     delta,
+		PublicKey: key,
+		ChainCode: append([]byte(nil), code...),
+		Parent:    pub,
+	}, nil
+}
+
+// EdDSA returns a copy of the save data of a party with the public key, the public shares and the key ID of the child,
+// for a signing with the child key. As for ECDSA, the share itself is left as it is and the signing adds Child.Delta to it:
+//
+//	child, err := derivation.EdDSAChild(save.EDDSAPub, chainCode, path)
+//	childSave, err := derivation.EdDSA(save, child)
+//	party := signing.NewLocalPartyWithKDD(msg, params, childSave, child.Delta, out, end)
+func EdDSA(save keygen.LocalPartySaveData, child *Child) (keygen.LocalPartySaveData, error) {
+	if err := checkParent(save.EDDSAPub, child.Parent); err != nil {
+		return keygen.LocalPartySaveData{}, err
+	}
+	gDelta := crypto.ScalarBaseMult(save.EDDSAPub.Curve(), child.Delta)
+	bigXj := make([]*crypto.ECPoint, len(save.BigXj))
+	for j, bigX := range save.BigXj {
+		if bigX == nil {
+			return keygen.LocalPartySaveData{}, errors.New("derivation: a public share is missing")
+		}
+		var err error
+		if bigXj[j], err = bigX.Add(gDelta); err != nil {
+			return keygen.LocalPartySaveData{}, err
+		}
+	}
+	save.BigXj = bigXj
+	save.EDDSAPub = child.PublicKey
+	save.Metadata = save.Metadata.WithKeyID(tss.KeyID(child.PublicKey.Curve(), child.PublicKey.X(), child.PublicKey.Y()))
+	return save, nil
+}
+
+func hmacSHA512(key []byte, prefix byte, encoded []byte, index uint32) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write([]byte{prefix})
+	mac.Write(encoded)
+	var i [4]byte
+	binary.LittleEndian.PutUint32(i[:], index)
+	mac.Write(i[:])
+	return mac.Sum(nil)
+}
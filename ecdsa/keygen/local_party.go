@@ -19,9 +19,11 @@ import (
 
 // Implements Party
 // Implements Stringer
+// Implements Wiper
 var (
 	_ tss.Party    = (*LocalParty)(nil)
 	_ fmt.Stringer = (*LocalParty)(nil)
+	_ tss.Wiper    = (*LocalParty)(nil)
 )
 
 type (
@@ -33,8 +35,9 @@ type (
 		data LocalPartySaveData
 
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- *LocalPartySaveData
+		out        chan<- tss.Message
+		end        chan<- *LocalPartySaveData
+		transcript chan<- *Transcript
 	}
 
 	localMessageStore struct {
@@ -64,6 +67,18 @@ func NewLocalParty(
 	out chan<- tss.Message,
 	end chan<- *LocalPartySaveData,
 	optionalPreParams ...LocalPreParams,
+) tss.Party {
+	return NewLocalPartyWithTranscript(params, out, end, nil, optionalPreParams...)
+}
+
+// NewLocalPartyWithTranscript returns a party that also sends the public Transcript of the session to `transcript`
+// just before its save data is sent to `end`. The channel should be buffered or drained concurrently with `end`.
+func NewLocalPartyWithTranscript(
+	params *tss.Parameters,
+	out chan<- tss.Message,
+	end chan<- *LocalPartySaveData,
+	transcript chan<- *Transcript,
+	optionalPreParams ...LocalPreParams,
 ) tss.Party {
 	partyCount := params.PartyCount()
 	data := NewLocalPartySaveData(partyCount)
@@ -78,12 +93,13 @@ func NewLocalParty(
 		data.LocalPreParams = optionalPreParams[0]
 	}
 	p := &LocalParty{
-		BaseParty: new(tss.BaseParty),
-		params:    params,
-		temp:      localTempData{},
-		data:      data,
-		out:       out,
-		end:       end,
+		BaseParty:  new(tss.BaseParty),
+		params:     params,
+		temp:       localTempData{},
+		data:       data,
+		out:        out,
+		end:        end,
+		transcript: transcript,
 	}
 	// msgs init
 	p.temp.kgRound1Messages = make([]tss.ParsedMessage, partyCount)
@@ -95,8 +111,26 @@ func NewLocalParty(
 	return p
 }
 
+// Session is a keygen party with the channels it sends its messages and its save data on, so that callers need
+// neither channels of their own nor a type assertion on the tss.Party of NewLocalParty
+type Session struct {
+	*LocalParty
+	Out <-chan tss.Message
+	End <-chan *LocalPartySaveData
+}
+
+// NewSession returns the session of a party created like NewLocalParty, with channels that buffer `buffer` messages
+func NewSession(params *tss.Parameters, buffer int, optionalPreParams ...LocalPreParams) *Session {
+	out, end := make(chan tss.Message, buffer), make(chan *LocalPartySaveData, 1)
+	return &Session{
+		LocalParty: NewLocalParty(params, out, end, optionalPreParams...).(*LocalParty),
+		Out:        out,
+		End:        end,
+	}
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.data, &p.temp, p.out, p.end)
+	return newRound1(p.params, &p.data, &p.temp, p.out, p.end, p.transcript)
 }
 
 func (p *LocalParty) Start() *tss.Error {
@@ -110,7 +144,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
 	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
-		return false, p.WrapError(err)
+		return false, p.WrapError(err).WithCode(tss.CodeInvalidMessage)
 	}
 	return p.Update(msg)
 }
@@ -122,7 +156,7 @@ func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	// check that the message's "from index" will fit into the array
 	if maxFromIdx := p.params.PartyCount() - 1; maxFromIdx < msg.GetFrom().Index {
 		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
-			p.params.PartyCount(), msg.GetFrom().Index), msg.GetFrom())
+			p.params.PartyCount(), msg.GetFrom().Index), msg.GetFrom()).WithCode(tss.CodeInvalidMessage)
 	}
 	return true, nil
 }
@@ -176,3 +210,14 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
+
+// Wipe overwrites the secret coefficient ui of the polynomial of the party and the shares it dealt from it. It is
+// called when the session ends; the save data sent to `end` is the caller's (see LocalPartySaveData.Wipe).
+func (p *LocalParty) Wipe() {
+	common.ZeroInt(p.temp.ui)
+	for _, share := range p.temp.shares {
+		if share != nil {
+			common.ZeroInt(share.Share)
+		}
+	}
+}
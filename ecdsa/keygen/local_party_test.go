@@ -7,6 +7,7 @@
 package keygen
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/json"
@@ -267,7 +268,6 @@ keygen:
 					assert.NoError(t, err, "vss.ReConstruct should not throw error")
 
 					// uG test: u*G[j] == V[0]
-					assert.Equal(t, uj, Pj.temp.ui)
 					uG := crypto.ScalarBaseMult(tss.EC(), uj)
 					assert.True(t, uG.Equals(Pj.temp.vs[0]), "ensure u*G[j] == V_0")
 
@@ -281,10 +281,10 @@ keygen:
 					{
 						badShares := pShares[:threshold]
 						badShares[len(badShares)-1].Share.Set(big.NewInt(0))
-						uj, err := pShares[:threshold].ReConstruct(tss.S256())
+						badUj, err := pShares[:threshold].ReConstruct(tss.S256())
 						assert.NoError(t, err)
-						assert.NotEqual(t, parties[j].temp.ui, uj)
-						BigXjX, BigXjY := tss.EC().ScalarBaseMult(uj.Bytes())
+						assert.NotEqual(t, uj, badUj)
+						BigXjX, BigXjY := tss.EC().ScalarBaseMult(badUj.Bytes())
 						assert.NotEqual(t, BigXjX, Pj.temp.vs[0].X())
 						assert.NotEqual(t, BigXjY, Pj.temp.vs[0].Y())
 					}
@@ -363,3 +363,60 @@ func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 	}
 	//
 }
+
+func TestSaveDataChecksum(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+
+	bz, err := json.Marshal(&keys[0])
+	assert.NoError(t, err)
+	var loaded LocalPartySaveData
+	assert.NoError(t, json.Unmarshal(bz, &loaded))
+	assert.Equal(t, SaveDataVersion, loaded.Version)
+	assert.Equal(t, keys[0].Xi, loaded.Xi)
+	assert.Equal(t, keys[0].PaillierSK.N, loaded.PaillierSK.N)
+
+	// alter the share in the JSON
+	altered := bytes.Replace(bz, []byte(keys[0].Xi.String()), []byte(new(big.Int).Add(keys[0].Xi, big.NewInt(1)).String()), 1)
+	assert.NotEqual(t, bz, altered)
+	assert.Error(t, json.Unmarshal(altered, &loaded))
+
+	// drop the checksum
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(bz, &fields))
+	delete(fields, "Checksum")
+	stripped, err := json.Marshal(fields)
+	assert.NoError(t, err)
+	assert.Error(t, json.Unmarshal(stripped, &loaded))
+}
+
+func TestSaveDataWipe(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	save := keys[0]
+	lambdaN, p := save.PaillierSK.LambdaN, save.P
+
+	save.Wipe()
+	assert.Nil(t, save.Xi)
+	assert.Zero(t, lambdaN.Sign(), "the Paillier secret key should be wiped")
+	assert.Zero(t, p.Sign(), "the safe primes of NTilde should be wiped")
+	assert.Zero(t, keys[0].PaillierSK.P.Sign(), "copies share the wiped big.Ints")
+}
+
+func TestSaveDataKeyID(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err)
+	// the fixtures predate key IDs, so theirs is computed from the public key
+	id := keys[0].KeyID()
+	assert.Len(t, id, 32)
+	for i := range keys {
+		assert.Equal(t, id, keys[i].KeyID(), "every party should compute the same key ID")
+	}
+
+	other := keys[0].ECDSAPub.ScalarMult(big.NewInt(2))
+	assert.NotEqual(t, id, tss.KeyID(tss.S256(), other.X(), other.Y()))
+
+	// the ID recorded in the metadata wins
+	keys[0].Metadata = &tss.KeyMetadata{KeyID: "recorded"}
+	assert.Equal(t, "recorded", keys[0].KeyID())
+}
@@ -75,7 +75,7 @@ func GeneratePreParamsWithContextAndRandom(ctx context.Context, rand io.Reader,
 		common.Logger.Info("generating the Paillier modulus, please wait...")
 		start := time.Now()
 		// more concurrency weight is assigned here because the paillier primes have a requirement of having "large" P-Q
-		PiPaillierSk, _, err := paillier.GenerateKeyPair(ctx, rand, paillierModulusLen, concurrency*2)
+		PiPaillierSk, _, err := paillier.GenerateKeyPair(ctx, common.ForkRandom(rand, "paillier"), paillierModulusLen, concurrency*2)
 		if err != nil {
 			ch <- nil
 			return
@@ -89,7 +89,7 @@ func GeneratePreParamsWithContextAndRandom(ctx context.Context, rand io.Reader,
 		var err error
 		common.Logger.Info("generating the safe primes for the signing proofs, please wait...")
 		start := time.Now()
-		sgps, err := common.GetRandomSafePrimesConcurrent(ctx, safePrimeBitLen, 2, concurrency, rand)
+		sgps, err := common.GetRandomSafePrimesConcurrent(ctx, safePrimeBitLen, 2, concurrency, common.ForkRandom(rand, "safe primes"))
 		if err != nil {
 			ch <- nil
 			return
@@ -9,6 +9,7 @@ package keygen
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
@@ -22,15 +23,15 @@ import (
 var zero = big.NewInt(0)
 
 // round 1 represents round 1 of the keygen part of the GG18 ECDSA TSS spec (Gennaro, Goldfeder; 2018)
-func newRound1(params *tss.Parameters, save *LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *LocalPartySaveData) tss.Round {
+func newRound1(params *tss.Parameters, save *LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *LocalPartySaveData, transcript chan<- *Transcript) tss.Round {
 	return &round1{
-		&base{params, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1},
+		&base{params, save, temp, out, end, transcript, make([]bool, len(params.Parties().IDs())), false, 1},
 	}
 }
 
 func (round *round1) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 1
 	round.started = true
@@ -44,13 +45,23 @@ func (round *round1) Start() *tss.Error {
 
 	round.temp.ui = ui
 
-	// 2. compute the vss shares
+	// 2. compute the vss shares, along the access structure if there is one
 	ids := round.Parties().IDs().Keys()
-	vs, shares, err := vss.Create(round.EC(), round.Threshold(), ui, ids, round.Rand())
+	if as := round.AccessStructure(); as != nil {
+		if err := as.Validate(round.Parties().IDs()); err != nil {
+			return round.WrapError(err, Pi).WithCode(tss.CodeInvalidInput)
+		}
+		if round.Threshold() != as.MinSigners()-1 {
+			return round.WrapError(fmt.Errorf("the threshold must be %d for an access structure of %d signers, got %d",
+				as.MinSigners()-1, as.MinSigners(), round.Threshold()), Pi).WithCode(tss.CodeInvalidInput)
+		}
+	}
+	vs, shares, err := vss.CreateWithAccessStructure(round.EC(), round.Threshold(), round.AccessStructure(), ui, ids, round.Rand())
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
 	round.save.Ks = ids
+	round.save.AccessStructure = round.AccessStructure()
 
 	// security: the original u_i may be discarded
 	ui = zero // clears the secret data from memory
@@ -61,7 +72,7 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
-	cmt := cmts.NewHashCommitment(round.Rand(), pGFlat...)
+	cmt := cmts.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagECDSAKeygenCommitment), round.Rand(), pGFlat...)
 
 	// 4. generate Paillier public key E_i, private key and proof
 	// 5-7. generate safe primes for ZKPs used later on
@@ -75,11 +86,11 @@ func (round *round1) Start() *tss.Error {
 		preParams = &round.save.LocalPreParams
 	} else {
 		{
-			ctx, cancel := context.WithTimeout(context.Background(), round.SafePrimeGenTimeout())
+			ctx, cancel := context.WithTimeout(round.Context(), round.SafePrimeGenTimeout())
 			defer cancel()
 			preParams, err = GeneratePreParamsWithContextAndRandom(ctx, round.Rand(), round.Concurrency())
 			if err != nil {
-				return round.WrapError(errors.New("pre-params generation failed"), Pi)
+				return round.WrapError(errors.New("pre-params generation failed"), Pi).WithCode(tss.CodeInternal)
 			}
 		}
 	}
@@ -108,7 +119,7 @@ func (round *round1) Start() *tss.Error {
 	round.temp.vs = vs
 	ssid, err := round.getSSID()
 	if err != nil {
-		return round.WrapError(errors.New("failed to generate ssid"))
+		return round.WrapError(errors.New("failed to generate ssid")).WithCode(tss.CodeInternal)
 	}
 	round.temp.ssid = ssid
 	round.temp.shares = shares
@@ -126,7 +137,7 @@ func (round *round1) Start() *tss.Error {
 			return round.WrapError(err, Pi)
 		}
 		round.temp.kgRound1Messages[i] = msg
-		round.out <- msg
+		round.out <- round.Outbound(msg)
 	}
 	return nil
 }
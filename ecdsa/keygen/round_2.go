@@ -25,7 +25,7 @@ const (
 
 func (round *round2) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 2
 	round.started = true
@@ -52,20 +52,20 @@ func (round *round2) Start() *tss.Error {
 			r1msg.UnmarshalNTilde(),
 			r1msg.UnmarshalPaillierPK()
 		if paillierPKj.N.BitLen() != paillierBitsLen {
-			return round.WrapError(errors.New("got paillier modulus with insufficient bits for this party"), msg.GetFrom())
+			return round.WrapError(errors.New("got paillier modulus with insufficient bits for this party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		if H1j.Cmp(H2j) == 0 {
-			return round.WrapError(errors.New("h1j and h2j were equal for this party"), msg.GetFrom())
+			return round.WrapError(errors.New("h1j and h2j were equal for this party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		if NTildej.BitLen() != paillierBitsLen {
-			return round.WrapError(errors.New("got NTildej with insufficient bits for this party"), msg.GetFrom())
+			return round.WrapError(errors.New("got NTildej with insufficient bits for this party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		h1JHex, h2JHex := hex.EncodeToString(H1j.Bytes()), hex.EncodeToString(H2j.Bytes())
 		if _, found := h1H2Map[h1JHex]; found {
-			return round.WrapError(errors.New("this h1j was already used by another party"), msg.GetFrom())
+			return round.WrapError(errors.New("this h1j was already used by another party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		if _, found := h1H2Map[h2JHex]; found {
-			return round.WrapError(errors.New("this h2j was already used by another party"), msg.GetFrom())
+			return round.WrapError(errors.New("this h2j was already used by another party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		h1H2Map[h1JHex], h1H2Map[h2JHex] = struct{}{}, struct{}{}
 
@@ -89,7 +89,7 @@ func (round *round2) Start() *tss.Error {
 	wg.Wait()
 	for _, culprit := range append(dlnProof1FailCulprits, dlnProof2FailCulprits...) {
 		if culprit != nil {
-			return round.WrapError(errors.New("dln proof verification failed"), culprit)
+			return round.WrapError(errors.New("dln proof verification failed"), culprit).WithCode(tss.CodeBadProof)
 		}
 	}
 	// save NTilde_j, h1_j, h2_j, ...
@@ -133,7 +133,7 @@ func (round *round2) Start() *tss.Error {
 			round.temp.kgRound2Message1s[j] = r2msg1
 			continue
 		}
-		round.out <- r2msg1
+		round.out <- round.Outbound(r2msg1)
 	}
 
 	// 7. BROADCAST de-commitments of Shamir poly*G
@@ -148,7 +148,7 @@ func (round *round2) Start() *tss.Error {
 	}
 	r2msg2 := NewKGRound2Message2(round.PartyID(), round.temp.deCommitPolyG, modProof)
 	round.temp.kgRound2Message2s[i] = r2msg2
-	round.out <- r2msg2
+	round.out <- round.Outbound(r2msg2)
 
 	return nil
 }
@@ -9,6 +9,8 @@ package keygen
 import (
 	"errors"
 
+	errors2 "github.com/pkg/errors"
+
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/v2/tss"
@@ -16,7 +18,7 @@ import (
 
 func (round *round4) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 4
 	round.started = true
@@ -70,7 +72,19 @@ func (round *round4) Start() *tss.Error {
 
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("paillier verify failed"), culprits...)
+		return round.WrapError(errors.New("paillier verify failed"), culprits...).WithCode(tss.CodeBadProof)
+	}
+
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
+	}
+
+	if round.transcript != nil {
+		tr, err := round.buildTranscript()
+		if err != nil {
+			return round.WrapError(errors2.Wrapf(err, "transcript"))
+		}
+		round.transcript <- tr
 	}
 
 	round.end <- round.save
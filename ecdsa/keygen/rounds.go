@@ -7,6 +7,8 @@
 package keygen
 
 import (
+	"crypto/elliptic"
+	"errors"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
@@ -20,13 +22,14 @@ const (
 type (
 	base struct {
 		*tss.Parameters
-		save    *LocalPartySaveData
-		temp    *localTempData
-		out     chan<- tss.Message
-		end     chan<- *LocalPartySaveData
-		ok      []bool // `ok` tracks parties which have been verified by Update()
-		started bool
-		number  int
+		save       *LocalPartySaveData
+		temp       *localTempData
+		out        chan<- tss.Message
+		end        chan<- *LocalPartySaveData
+		transcript chan<- *Transcript // optional, receives the public transcript of the session
+		ok         []bool             // `ok` tracks parties which have been verified by Update()
+		started    bool
+		number     int
 	}
 	round1 struct {
 		*base
@@ -100,11 +103,24 @@ func (round *base) resetOK() {
 
 // get ssid from local params
 func (round *base) getSSID() ([]byte, error) {
-	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
-	ssidList = append(ssidList, round.Parties().IDs().Keys()...)
-	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
-	ssidList = append(ssidList, round.temp.ssidNonce)
-	ssid := common.SHA512_256i(ssidList...).Bytes()
-
+	ssid := keygenSSID(round.EC(), round.HashScheme(), round.Parties().IDs().Keys(), round.SessionID(), round.number, round.temp.ssidNonce)
+	if ssid == nil {
+		return nil, round.WrapError(errors.New("hash ssid failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
 	return ssid, nil
 }
+
+// keygenSSID hashes the curve, the party keys, the round number, the nonce and the session ID, or returns nil if
+// hashing fails
+func keygenSSID(ec elliptic.Curve, scheme common.HashScheme, ks []*big.Int, sessionID []byte, roundNumber int, nonce *big.Int) []byte {
+	ssidList := []*big.Int{ec.Params().P, ec.Params().N, ec.Params().Gx, ec.Params().Gy} // ec curve
+	ssidList = append(ssidList, ks...)
+	ssidList = append(ssidList, big.NewInt(int64(roundNumber))) // round number
+	ssidList = append(ssidList, nonce)
+	ssidList = append(ssidList, tss.SessionIDInts(sessionID)...)
+	ssidHash := scheme.Tagged(common.PoseidonTagECDSAKeygenSSID).HashInts(ssidList...)
+	if ssidHash == nil {
+		return nil
+	}
+	return ssidHash.Bytes()
+}
@@ -0,0 +1,216 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/dlnproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/modproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Transcript is the public record of a keygen session: everything that was broadcast, and digests of the P2P shares
+// received by the party that emitted it. It holds no secret and marshals to JSON, so that any third party can check
+// offline with Verify that the key and the public shares were derived correctly from the parties' commitments, and
+// that every party proved its Paillier and Ring-Pedersen parameters well formed.
+// Transcripts emitted by the parties of the same session differ only in their ShareHashes.
+type Transcript struct {
+	HashScheme common.HashScheme
+	Threshold  int
+	// the access structure the key was shared along, if any (see tss.Parameters.SetAccessStructure)
+	AccessStructure *tss.AccessStructure `json:",omitempty"`
+
+	// party keys, in the order of the sorted party IDs
+	Ks []*big.Int
+	// the explicit session ID, if any (see tss.Parameters.SetSessionID)
+	SessionID []byte
+	SSID      []byte
+
+	// for each Pj: the round 1 commitment and its round 2 opening to the VSS polynomial in the exponent
+	Commitments   []*big.Int
+	DeCommitments [][]*big.Int
+
+	// for each Pj: its Paillier public key and Ring-Pedersen parameters, the DLN proofs of the latter, the proof that
+	// its Paillier modulus is a Blum integer (nil when the session ran without, see tss.Parameters.NoProofMod) and the
+	// round 3 Paillier proof
+	PaillierPKs       []*paillier.PublicKey
+	NTildej, H1j, H2j []*big.Int
+	DLNProofs1        []*dlnproof.Proof
+	DLNProofs2        []*dlnproof.Proof
+	ModProofs         []*modproof.ProofMod
+	PaillierProofs    []paillier.Proof
+
+	// for each Pj: SHA512_256(SSID, share) of the share that Pj sent to the emitting party
+	ShareHashes [][]byte
+
+	BigXj    []*crypto.ECPoint
+	ECDSAPub *crypto.ECPoint
+}
+
+// buildTranscript collects the transcript from the messages of a finished session
+func (round *round4) buildTranscript() (*Transcript, error) {
+	partyCount := round.PartyCount()
+	tr := &Transcript{
+		HashScheme:      round.HashScheme(),
+		Threshold:       round.Threshold(),
+		AccessStructure: round.AccessStructure(),
+		Ks:              round.save.Ks,
+		SessionID:       round.SessionID(),
+		SSID:            round.temp.ssid,
+		Commitments:     make([]*big.Int, partyCount),
+		DeCommitments:   make([][]*big.Int, partyCount),
+		PaillierPKs:     make([]*paillier.PublicKey, partyCount),
+		NTildej:         make([]*big.Int, partyCount),
+		H1j:             make([]*big.Int, partyCount),
+		H2j:             make([]*big.Int, partyCount),
+		DLNProofs1:      make([]*dlnproof.Proof, partyCount),
+		DLNProofs2:      make([]*dlnproof.Proof, partyCount),
+		ModProofs:       make([]*modproof.ProofMod, partyCount),
+		PaillierProofs:  make([]paillier.Proof, partyCount),
+		ShareHashes:     make([][]byte, partyCount),
+		BigXj:           round.save.BigXj,
+		ECDSAPub:        round.save.ECDSAPub,
+	}
+	for j := 0; j < partyCount; j++ {
+		r1msg := round.temp.kgRound1Messages[j].Content().(*KGRound1Message)
+		tr.Commitments[j] = r1msg.UnmarshalCommitment()
+		tr.PaillierPKs[j] = r1msg.UnmarshalPaillierPK()
+		tr.NTildej[j], tr.H1j[j], tr.H2j[j] = r1msg.UnmarshalNTilde(), r1msg.UnmarshalH1(), r1msg.UnmarshalH2()
+		var err error
+		if tr.DLNProofs1[j], err = r1msg.UnmarshalDLNProof1(); err != nil {
+			return nil, err
+		}
+		if tr.DLNProofs2[j], err = r1msg.UnmarshalDLNProof2(); err != nil {
+			return nil, err
+		}
+
+		r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
+		tr.DeCommitments[j] = r2msg2.UnmarshalDeCommitment()
+		if !round.NoProofMod() {
+			if tr.ModProofs[j], err = r2msg2.UnmarshalModProof(); err != nil {
+				return nil, err
+			}
+		}
+
+		r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
+		tr.ShareHashes[j] = common.SHA512_256(round.temp.ssid, r2msg1.GetShare())
+
+		r3msg := round.temp.kgRound3Messages[j].Content().(*KGRound3Message)
+		tr.PaillierProofs[j] = r3msg.UnmarshalProofInts()
+	}
+	return tr, nil
+}
+
+// Verify checks the transcript of a session on the curve `ec`: the SSID, the opening of every commitment, the DLN,
+// Blum modulus and Paillier proofs of every party, and that the public key and the public shares BigXj follow from
+// the committed polynomials. The ShareHashes cannot be checked without the shares, nor the P2P factorization proofs.
+func (tr *Transcript) Verify(ec elliptic.Curve) error {
+	partyCount := len(tr.Ks)
+	if partyCount == 0 || tr.Threshold < 0 || tr.Threshold >= partyCount {
+		return errors.New("transcript has an invalid threshold or party count")
+	}
+	if tr.AccessStructure != nil {
+		if err := tr.AccessStructure.ValidateBasic(); err != nil {
+			return err
+		}
+		if tr.Threshold != tr.AccessStructure.MinSigners()-1 {
+			return errors.New("transcript threshold does not match the access structure")
+		}
+	}
+	if len(tr.Commitments) != partyCount || len(tr.DeCommitments) != partyCount ||
+		len(tr.PaillierPKs) != partyCount || len(tr.NTildej) != partyCount || len(tr.H1j) != partyCount ||
+		len(tr.H2j) != partyCount || len(tr.DLNProofs1) != partyCount || len(tr.DLNProofs2) != partyCount ||
+		len(tr.ModProofs) != partyCount || len(tr.PaillierProofs) != partyCount ||
+		len(tr.ShareHashes) != partyCount || len(tr.BigXj) != partyCount || tr.ECDSAPub == nil {
+		return errors.New("transcript is missing entries")
+	}
+	ssid := keygenSSID(ec, tr.HashScheme, tr.Ks, tr.SessionID, 1, big.NewInt(0))
+	if ssid == nil || !bytes.Equal(ssid, tr.SSID) {
+		return errors.New("transcript SSID does not match the session parameters")
+	}
+
+	hasher := tr.HashScheme.Tagged(common.PoseidonTagECDSAKeygenCommitment)
+	Vc := make([]*crypto.ECPoint, vss.CommitmentsLen(tr.Threshold, tr.AccessStructure))
+	for j := 0; j < partyCount; j++ {
+		if tr.PaillierPKs[j] == nil || tr.PaillierPKs[j].N == nil ||
+			tr.NTildej[j] == nil || tr.H1j[j] == nil || tr.H2j[j] == nil || tr.H1j[j].Cmp(tr.H2j[j]) == 0 {
+			return fmt.Errorf("party %d has invalid Paillier or Ring-Pedersen parameters", j)
+		}
+		if !dlnProofComplete(tr.DLNProofs1[j]) || !tr.DLNProofs1[j].Verify(tr.H1j[j], tr.H2j[j], tr.NTildej[j]) ||
+			!dlnProofComplete(tr.DLNProofs2[j]) || !tr.DLNProofs2[j].Verify(tr.H2j[j], tr.H1j[j], tr.NTildej[j]) {
+			return fmt.Errorf("dln proof of party %d failed", j)
+		}
+		ContextJ := common.AppendBigIntToBytesSlice(ssid, big.NewInt(int64(j)))
+		if proof := tr.ModProofs[j]; proof != nil && !proof.Verify(ContextJ, tr.PaillierPKs[j].N) {
+			return fmt.Errorf("modProof of party %d failed", j)
+		}
+
+		cmtDeCmt := commitments.HashCommitDecommit{C: tr.Commitments[j], D: tr.DeCommitments[j], Hasher: hasher}
+		ok, flatPolyGs := cmtDeCmt.DeCommit()
+		if !ok || flatPolyGs == nil {
+			return fmt.Errorf("de-commitment of party %d failed", j)
+		}
+		PjVs, err := crypto.UnFlattenECPoints(ec, flatPolyGs)
+		if err != nil || len(PjVs) != len(Vc) {
+			return fmt.Errorf("party %d committed to an invalid polynomial", j)
+		}
+		for c := range Vc {
+			if j == 0 {
+				Vc[c] = PjVs[c]
+				continue
+			}
+			if Vc[c], err = Vc[c].Add(PjVs[c]); err != nil {
+				return fmt.Errorf("polynomial of party %d: %v", j, err)
+			}
+		}
+	}
+
+	if !tr.ECDSAPub.Equals(Vc[0]) {
+		return errors.New("public key does not match the commitments")
+	}
+	for j, kj := range tr.Ks {
+		BigXj, err := vss.EvaluateCommitments(ec, tr.AccessStructure, Vc, kj)
+		if err != nil {
+			return fmt.Errorf("public share of party %d: %v", j, err)
+		}
+		if tr.BigXj[j] == nil || !tr.BigXj[j].Equals(BigXj) {
+			return fmt.Errorf("public share of party %d does not match the commitments", j)
+		}
+		// the Paillier proofs are bound to the public key
+		if !allNonNil(tr.PaillierProofs[j][:]) {
+			return fmt.Errorf("paillier proof of party %d is incomplete", j)
+		}
+		if ok, err := tr.PaillierProofs[j].Verify(tr.PaillierPKs[j].N, kj, tr.ECDSAPub); err != nil || !ok {
+			return fmt.Errorf("paillier proof of party %d failed", j)
+		}
+	}
+	return nil
+}
+
+// dlnProofComplete reports whether a DLN proof decoded from JSON holds all of its values
+func dlnProofComplete(proof *dlnproof.Proof) bool {
+	return proof != nil && allNonNil(proof.Alpha[:]) && allNonNil(proof.T[:])
+}
+
+func allNonNil(xs []*big.Int) bool {
+	for _, x := range xs {
+		if x == nil {
+			return false
+		}
+	}
+	return true
+}
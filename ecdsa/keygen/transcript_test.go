@@ -0,0 +1,111 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestTranscript(t *testing.T) {
+	setUp("info")
+
+	const participants, threshold = 3, 1
+	fixtures, pIDs, err := LoadKeygenTestFixtures(participants)
+	if !assert.NoError(t, err, "should load keygen fixtures") {
+		return
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	trCh := make(chan *Transcript, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pIDs[i], len(pIDs), threshold)
+		// the factorization proofs are P2P, so not part of the transcript; do not use in untrusted setting
+		params.SetNoProofFac()
+		P := NewLocalPartyWithTranscript(params, outCh, endCh, trCh, fixtures[i].LocalPreParams).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	ended := 0
+keygen:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+		case <-endCh:
+			if ended++; ended == len(pIDs) {
+				break keygen
+			}
+		}
+	}
+
+	assert.Len(t, trCh, len(pIDs), "every party should emit a transcript")
+	tr := <-trCh
+	assert.NoError(t, tr.Verify(tss.S256()))
+
+	// a third party verifies the JSON transcript
+	bz, err := json.Marshal(tr)
+	assert.NoError(t, err)
+	var decoded Transcript
+	assert.NoError(t, json.Unmarshal(bz, &decoded))
+	assert.NoError(t, decoded.Verify(tss.S256()))
+
+	// tampering with the broadcast data is detected
+	bad := decoded
+	bad.Commitments = append([]*big.Int{}, decoded.Commitments...)
+	bad.Commitments[1] = new(big.Int).Add(bad.Commitments[1], big.NewInt(1))
+	assert.Error(t, bad.Verify(tss.S256()), "an altered commitment should fail")
+
+	bad = decoded
+	bad.BigXj = append(bad.BigXj[:0:0], decoded.BigXj...)
+	bad.BigXj[0], bad.BigXj[1] = bad.BigXj[1], bad.BigXj[0]
+	assert.Error(t, bad.Verify(tss.S256()), "swapped public shares should fail")
+
+	bad = decoded
+	bad.H1j = append(bad.H1j[:0:0], decoded.H1j...)
+	bad.H1j[2] = new(big.Int).Add(bad.H1j[2], big.NewInt(1))
+	assert.Error(t, bad.Verify(tss.S256()), "altered Ring-Pedersen parameters should fail")
+
+	bad = decoded
+	bad.PaillierProofs = append(bad.PaillierProofs[:0:0], decoded.PaillierProofs...)
+	bad.PaillierProofs[0], bad.PaillierProofs[1] = bad.PaillierProofs[1], bad.PaillierProofs[0]
+	assert.Error(t, bad.Verify(tss.S256()), "swapped Paillier proofs should fail")
+
+	bad = decoded
+	bad.Threshold++
+	assert.Error(t, bad.Verify(tss.S256()), "a wrong threshold should fail")
+}
@@ -0,0 +1,251 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmt "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Implements Party
+// Implements Stringer
+var (
+	_ tss.Party    = (*LocalParty)(nil)
+	_ fmt.Stringer = (*LocalParty)(nil)
+)
+
+type (
+	LocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		temp        localTempData
+		input, save keygen.LocalPartySaveData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- *keygen.LocalPartySaveData
+	}
+
+	localMessageStore struct {
+		rfRound1Messages,
+		rfRound2Message1s,
+		rfRound2Message2s []tss.ParsedMessage
+	}
+
+	localTempData struct {
+		localMessageStore
+
+		// temp data (thrown away after refresh)
+		rotate        bool
+		threshold     int                    // the threshold of the refreshed shares
+		revoked       []*big.Int             // the original indexes of the parties to revoke
+		preParams     *keygen.LocalPreParams // the new pre-params of this party, when rotating
+		RFCs          []cmt.HashCommitment
+		vs            vss.Vs
+		shares        vss.Shares
+		deCommitPolyG cmt.HashDeCommitment
+		ssid          []byte
+		ssidNonce     *big.Int
+	}
+)
+
+// Exported, used in `tss` client
+// NewLocalParty refreshes the share in `key` together with every other party of the key, without changing the public key.
+// The Paillier key, NTilde, h1 and h2 of this party are kept; the refreshed save data is sent to `end`.
+// Every party that took part in keygen must take part, using the same party IDs and threshold.
+func NewLocalParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) tss.Party {
+	partyCount := params.PartyCount()
+	p := &LocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		temp:      localTempData{},
+		input:     key,
+		save:      copySaveData(key),
+		out:       out,
+		end:       end,
+	}
+	// msgs init
+	p.temp.rfRound1Messages = make([]tss.ParsedMessage, partyCount)
+	p.temp.rfRound2Message1s = make([]tss.ParsedMessage, partyCount)
+	p.temp.rfRound2Message2s = make([]tss.ParsedMessage, partyCount)
+	// temp data init
+	p.temp.RFCs = make([]cmt.HashCommitment, partyCount)
+	p.temp.threshold = params.Threshold()
+	return p
+}
+
+// NewLocalPartyWithNewThreshold refreshes the share like NewLocalParty and also changes the threshold of the key to
+// `newThreshold`, keeping the same parties. This is cheaper than resharing as no new committee has to be stood up.
+// Every party must use the same `newThreshold`; `params` still holds the current threshold of the key, and the
+// refreshed save data must be used with `newThreshold` from then on.
+func NewLocalPartyWithNewThreshold(
+	params *tss.Parameters,
+	newThreshold int,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) tss.Party {
+	p := NewLocalParty(params, key, out, end).(*LocalParty)
+	p.temp.threshold = newThreshold
+	return p
+}
+
+// NewLocalPartyWithNewPreParams refreshes the share like NewLocalParty and also replaces this party's Paillier key,
+// NTilde, h1 and h2. When `optionalPreParams` is provided the pre-computed primes are used instead of generating
+// them from scratch. Other parties of the same session may keep their pre-params.
+func NewLocalPartyWithNewPreParams(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+	optionalPreParams ...keygen.LocalPreParams,
+) tss.Party {
+	p := NewLocalParty(params, key, out, end).(*LocalParty)
+	p.temp.rotate = true
+	if 0 < len(optionalPreParams) {
+		if 1 < len(optionalPreParams) {
+			panic(errors.New("refresh.NewLocalPartyWithNewPreParams expected 0 or 1 item in `optionalPreParams`"))
+		}
+		if !optionalPreParams[0].ValidateWithProof() {
+			panic(errors.New("`optionalPreParams` failed to validate; it might have been generated with an older version of tss-lib"))
+		}
+		p.temp.preParams = &optionalPreParams[0]
+	}
+	return p
+}
+
+// NewLocalPartyToRevoke removes the `revoked` parties from the key: the remaining parties of `params` refresh their
+// shares, so that the shares of the revoked parties no longer combine with theirs. `key` is the save data of the key
+// before the revocation; the refreshed save data sent to `end` no longer holds the revoked parties and records them
+// in RevokedKs. Every party that is not revoked must take part, and at least threshold+1 parties must remain.
+func NewLocalPartyToRevoke(
+	params *tss.Parameters,
+	revoked []*tss.PartyID,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) tss.Party {
+	p := NewLocalParty(params, key, out, end).(*LocalParty)
+	p.temp.revoked = make([]*big.Int, len(revoked))
+	for j, Pj := range revoked {
+		p.temp.revoked[j] = Pj.KeyInt()
+	}
+	// every party must hash the revoked parties in the same order
+	sort.Slice(p.temp.revoked, func(a, b int) bool {
+		return p.temp.revoked[a].Cmp(p.temp.revoked[b]) < 0
+	})
+	return p
+}
+
+// Session is a refresh party with the channels it sends its messages and its refreshed save data on
+type Session struct {
+	*LocalParty
+	Out <-chan tss.Message
+	End <-chan *keygen.LocalPartySaveData
+}
+
+// NewSession returns the session of a party created like NewLocalParty, with channels that buffer `buffer` messages
+func NewSession(params *tss.Parameters, key keygen.LocalPartySaveData, buffer int) *Session {
+	out, end := make(chan tss.Message, buffer), make(chan *keygen.LocalPartySaveData, 1)
+	return &Session{
+		LocalParty: NewLocalParty(params, key, out, end).(*LocalParty),
+		Out:        out,
+		End:        end,
+	}
+}
+
+func (p *LocalParty) FirstRound() tss.Round {
+	return newRound1(p.params, &p.input, &p.save, &p.temp, p.out, p.end)
+}
+
+func (p *LocalParty) Start() *tss.Error {
+	return tss.BaseStart(p, TaskName)
+}
+
+func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, TaskName)
+}
+
+func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err).WithCode(tss.CodeInvalidMessage)
+	}
+	return p.Update(msg)
+}
+
+func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := p.params.PartyCount() - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			p.params.PartyCount(), msg.GetFrom().Index), msg.GetFrom()).WithCode(tss.CodeInvalidMessage)
+	}
+	return true, nil
+}
+
+func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	// ValidateBasic is cheap; double-check the message here in case the public StoreMessage was called externally
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	fromPIdx := msg.GetFrom().Index
+
+	// switch/case is necessary to store any messages beyond current round
+	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	switch msg.Content().(type) {
+	case *RFRound1Message:
+		p.temp.rfRound1Messages[fromPIdx] = msg
+	case *RFRound2Message1:
+		p.temp.rfRound2Message1s[fromPIdx] = msg
+	case *RFRound2Message2:
+		p.temp.rfRound2Message2s[fromPIdx] = msg
+	default: // unrecognised message, just ignore!
+		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (p *LocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *LocalParty) String() string {
+	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
+}
+
+// copySaveData copies the per-party slices of the key so that the refresh never writes into the caller's data
+func copySaveData(key keygen.LocalPartySaveData) keygen.LocalPartySaveData {
+	save := key
+	save.Ks = append([]*big.Int(nil), key.Ks...)
+	save.NTildej = append([]*big.Int(nil), key.NTildej...)
+	save.H1j = append([]*big.Int(nil), key.H1j...)
+	save.H2j = append([]*big.Int(nil), key.H2j...)
+	save.BigXj = append([]*crypto.ECPoint(nil), key.BigXj...)
+	save.PaillierPKs = append([]*paillier.PublicKey(nil), key.PaillierPKs...)
+	save.RevokedKs = append([]*big.Int(nil), key.RevokedKs...)
+	return save
+}
@@ -0,0 +1,280 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/ecdsa/refresh"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+// runs a refresh of the parties `pIDs`, each created by `newParty`
+func runRefresh(t *testing.T, pIDs tss.SortedPartyIDs, newParty func(j int, params *tss.Parameters, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Party) []keygen.LocalPartySaveData {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for j, pID := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(pIDs), testThreshold)
+		P := newParty(j, params, outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	newKeys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+			newKeys[index] = *save
+			ended++
+		}
+	}
+	return newKeys
+}
+
+// signs with the first threshold+1 keys and verifies the signature against the public key
+func signAndVerify(t *testing.T, keys []keygen.LocalPartySaveData, pIDs tss.SortedPartyIDs, threshold int) {
+	signPIDs := make(tss.UnSortedPartyIDs, 0, threshold+1)
+	for _, pID := range pIDs[:threshold+1] {
+		signPIDs = append(signPIDs, tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+	}
+	sortedPIDs := tss.SortPartyIDs(signPIDs)
+	p2pCtx := tss.NewPeerContext(sortedPIDs)
+	parties := make([]*signing.LocalParty, 0, len(sortedPIDs))
+
+	errCh := make(chan *tss.Error, len(sortedPIDs))
+	outCh := make(chan tss.Message, len(sortedPIDs))
+	endCh := make(chan *common.SignatureData, len(sortedPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	msg := big.NewInt(42)
+	for j, pID := range sortedPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(sortedPIDs), threshold)
+		key := keygen.BuildLocalSaveDataSubset(keys[j], sortedPIDs)
+		P := signing.NewLocalParty(msg, params, key, outCh, endCh).(*signing.LocalParty)
+		parties = append(parties, P)
+		go func(P *signing.LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	for ended := 0; ended < len(sortedPIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case signData := <-endCh:
+			pk := ecdsa.PublicKey{
+				Curve: tss.S256(),
+				X:     keys[0].ECDSAPub.X(),
+				Y:     keys[0].ECDSAPub.Y(),
+			}
+			ok := ecdsa.Verify(&pk, msg.Bytes(), new(big.Int).SetBytes(signData.R), new(big.Int).SetBytes(signData.S))
+			assert.True(t, ok, "ecdsa verify must pass")
+			ended++
+		}
+	}
+}
+
+func reconstruct(t *testing.T, keys []keygen.LocalPartySaveData, threshold int) *big.Int {
+	shares := make(vss.Shares, 0, len(keys))
+	for _, key := range keys {
+		shares = append(shares, &vss.Share{Threshold: threshold, ID: key.ShareID, Share: key.Xi})
+	}
+	x, err := shares[:threshold+1].ReConstruct(tss.S256())
+	assert.NoError(t, err, "vss.ReConstruct should not throw error")
+	return x
+}
+
+func TestE2ERefresh(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	oldX1 := new(big.Int).Set(keys[1].Xi)
+
+	newKeys := runRefresh(t, pIDs, func(j int, params *tss.Parameters, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Party {
+		return NewLocalParty(params, keys[j], out, end)
+	})
+
+	assert.Equal(t, 0, keys[1].Xi.Cmp(oldX1), "the input key must not be modified")
+	for j, key := range newKeys {
+		assert.NotEqual(t, 0, key.Xi.Cmp(keys[j].Xi), "xj should have been refreshed")
+		assert.True(t, key.ECDSAPub.Equals(keys[j].ECDSAPub), "the public key must not change")
+		assert.Equal(t, 0, key.PaillierSK.N.Cmp(keys[j].PaillierSK.N), "the paillier key should be kept")
+		for c, BigXc := range key.BigXj {
+			assert.True(t, BigXc.Equals(newKeys[c].BigXj[c]), "every party should agree on BigXj")
+		}
+		assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(tss.S256(), key.Xi)), "ensure BigX_j == g^x_j")
+	}
+	assert.Equal(t, reconstruct(t, keys, testThreshold), reconstruct(t, newKeys, testThreshold), "the refreshed shares must still share the same secret")
+
+	signAndVerify(t, newKeys, pIDs, testThreshold)
+}
+
+func TestE2ERefreshWithNewPreParams(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// parties 0 and 1 swap their pre-params, which keeps every h1, h2 unique without generating new safe primes
+	newPreParams := map[int]keygen.LocalPreParams{
+		0: keys[1].LocalPreParams,
+		1: keys[0].LocalPreParams,
+	}
+	newKeys := runRefresh(t, pIDs, func(j int, params *tss.Parameters, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Party {
+		if preParams, ok := newPreParams[j]; ok {
+			return NewLocalPartyWithNewPreParams(params, keys[j], out, end, preParams)
+		}
+		return NewLocalParty(params, keys[j], out, end)
+	})
+
+	for j, key := range newKeys {
+		assert.Equal(t, 0, key.PaillierPKs[0].N.Cmp(keys[1].PaillierSK.N), "party 0's new paillier key should be known by party %d", j)
+		assert.Equal(t, 0, key.PaillierPKs[1].N.Cmp(keys[0].PaillierSK.N), "party 1's new paillier key should be known by party %d", j)
+		assert.Equal(t, 0, key.NTildej[0].Cmp(keys[1].NTildei))
+		assert.Equal(t, 0, key.PaillierPKs[2].N.Cmp(keys[2].PaillierSK.N), "party 2 kept its paillier key")
+		assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(tss.S256(), key.Xi)), "ensure BigX_j == g^x_j")
+	}
+	assert.Equal(t, 0, newKeys[0].PaillierSK.N.Cmp(keys[1].PaillierSK.N))
+	assert.Equal(t, reconstruct(t, keys, testThreshold), reconstruct(t, newKeys, testThreshold), "the refreshed shares must still share the same secret")
+
+	signAndVerify(t, newKeys, pIDs, testThreshold)
+}
+
+func TestE2ERefreshWithNewThreshold(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	for _, newThreshold := range []int{testThreshold + 1, testThreshold - 1} {
+		newKeys := runRefresh(t, pIDs, func(j int, params *tss.Parameters, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Party {
+			return NewLocalPartyWithNewThreshold(params, newThreshold, keys[j], out, end)
+		})
+
+		for j, key := range newKeys {
+			assert.True(t, key.ECDSAPub.Equals(keys[j].ECDSAPub), "the public key must not change")
+			assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(tss.S256(), key.Xi)), "ensure BigX_j == g^x_j")
+		}
+		secret := reconstruct(t, newKeys, newThreshold)
+		assert.Equal(t, reconstruct(t, keys, testThreshold), secret, "the new shares must still share the same secret")
+		assert.True(t, crypto.ScalarBaseMult(tss.S256(), secret).Equals(keys[0].ECDSAPub))
+
+		signAndVerify(t, newKeys, pIDs, newThreshold)
+	}
+}
+
+func TestE2ERefreshToRevoke(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// party 1 is revoked; the remaining parties refresh
+	revoked := pIDs[1]
+	keptPIDs := make(tss.UnSortedPartyIDs, 0, len(pIDs)-1)
+	keptKeys := make([]keygen.LocalPartySaveData, 0, len(pIDs)-1)
+	for j, pID := range pIDs {
+		if pID == revoked {
+			continue
+		}
+		keptPIDs = append(keptPIDs, tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+		keptKeys = append(keptKeys, keys[j])
+	}
+	kept := tss.SortPartyIDs(keptPIDs)
+
+	newKeys := runRefresh(t, kept, func(j int, params *tss.Parameters, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Party {
+		return NewLocalPartyToRevoke(params, []*tss.PartyID{revoked}, keptKeys[j], out, end)
+	})
+
+	for j, key := range newKeys {
+		assert.Equal(t, len(kept), len(key.Ks), "the revoked party should be dropped from the key")
+		assert.Equal(t, 1, len(key.RevokedKs))
+		assert.Equal(t, 0, key.RevokedKs[0].Cmp(revoked.KeyInt()), "the revocation should be recorded")
+		assert.True(t, key.ECDSAPub.Equals(keptKeys[j].ECDSAPub), "the public key must not change")
+		assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(tss.S256(), key.Xi)), "ensure BigX_j == g^x_j")
+	}
+	secret := reconstruct(t, keys, testThreshold)
+	assert.Equal(t, secret, reconstruct(t, newKeys, testThreshold), "the refreshed shares must still share the same secret")
+
+	// the share of the revoked party no longer combines with the refreshed shares
+	mixed := []keygen.LocalPartySaveData{newKeys[0], keys[1], newKeys[1]}
+	assert.NotEqual(t, secret, reconstruct(t, mixed, testThreshold), "the revoked share must be useless")
+
+	signAndVerify(t, newKeys, kept, testThreshold)
+}
@@ -0,0 +1,194 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	cmts "github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/dlnproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// round 1 commits to a random sharing of zero, to be added to the existing shares, and optionally announces new pre-params
+func newRound1(params *tss.Parameters, input, save *keygen.LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Round {
+	return &round1{
+		&base{params, input, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1},
+	}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
+	}
+	round.number = 1
+	round.started = true
+	round.resetOK()
+
+	Pi := round.PartyID()
+	i := Pi.Index
+
+	if 0 < len(round.temp.revoked) {
+		if err := round.revoke(); err != nil {
+			return err
+		}
+	}
+
+	// every share holder must take part, or the shares of those left out would no longer match
+	ks := round.input.Ks
+	if round.input.Xi == nil || len(ks) != round.PartyCount() || len(round.input.BigXj) != round.PartyCount() {
+		return round.WrapError(fmt.Errorf("refresh requires the key of all %d parties", round.PartyCount()), Pi).WithCode(tss.CodeInvalidInput)
+	}
+	for j, Pj := range round.Parties().IDs() {
+		if ks[j].Cmp(Pj.KeyInt()) != 0 {
+			return round.WrapError(fmt.Errorf("party %s does not hold a share of this key", Pj), Pi).WithCode(tss.CodeInvalidInput)
+		}
+	}
+
+	if round.temp.threshold < 1 || round.PartyCount() < round.temp.threshold+1 {
+		return round.WrapError(fmt.Errorf("invalid new threshold %d for %d parties", round.temp.threshold, round.PartyCount()), Pi).WithCode(tss.CodeInvalidInput)
+	}
+
+	if err := round.ValidateHashScheme(round.input.HashScheme); err != nil {
+		return round.WrapError(err, Pi)
+	}
+	if round.input.AccessStructure != nil {
+		return round.WrapError(errors.New("keys shared along an access structure cannot be refreshed"), Pi).WithCode(tss.CodeInvalidInput)
+	}
+
+	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(errors.New("failed to generate ssid")).WithCode(tss.CodeInternal)
+	}
+	round.temp.ssid = ssid
+
+	// 1. compute the shares of zero, or the shares of lambda_i * xi at the new threshold when changing it
+	var vs vss.Vs
+	var shares vss.Shares
+	if round.changesThreshold() {
+		modQ := common.ModInt(round.EC().Params().N)
+		wi := modQ.Mul(round.lagrange(i), round.input.Xi)
+		vs, shares, err = vss.Create(round.EC(), round.temp.threshold, wi, ks, round.Rand())
+	} else {
+		vs, shares, err = vss.CreateZeroSharing(round.EC(), round.Threshold(), ks, round.Rand())
+	}
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	round.temp.vs = vs
+	round.temp.shares = shares
+
+	// make commitment -> (C, D)
+	pGFlat, err := crypto.FlattenECPoints(vs)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	cmt := cmts.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagECDSARefreshCommitment), round.Rand(), pGFlat...)
+	round.temp.deCommitPolyG = cmt.D
+
+	// 2. optionally replace the Paillier key, NTilde, h1, h2, using the pre-params if they were provided to the constructor
+	var dlnProof1, dlnProof2 *dlnproof.Proof
+	if round.temp.rotate {
+		preParams := round.temp.preParams
+		if preParams == nil {
+			ctx, cancel := context.WithTimeout(round.Context(), round.SafePrimeGenTimeout())
+			defer cancel()
+			preParams, err = keygen.GeneratePreParamsWithContextAndRandom(ctx, round.Rand(), round.Concurrency())
+			if err != nil {
+				return round.WrapError(errors.New("pre-params generation failed"), Pi).WithCode(tss.CodeInternal)
+			}
+			round.temp.preParams = preParams
+		}
+		dlnProof1 = dlnproof.NewDLNProof(preParams.H1i, preParams.H2i, preParams.Alpha, preParams.P, preParams.Q, preParams.NTildei, round.Rand())
+		dlnProof2 = dlnproof.NewDLNProof(preParams.H2i, preParams.H1i, preParams.Beta, preParams.P, preParams.Q, preParams.NTildei, round.Rand())
+
+		round.save.LocalPreParams = *preParams
+		round.save.NTildej[i] = preParams.NTildei
+		round.save.H1j[i], round.save.H2j[i] = preParams.H1i, preParams.H2i
+		round.save.PaillierPKs[i] = &preParams.PaillierSK.PublicKey
+	}
+
+	// BROADCAST commitment, and the new paillier pk + proofs when rotating; round 1 message
+	{
+		msg, err := NewRFRound1Message(Pi, cmt.C, round.temp.preParams, dlnProof1, dlnProof2)
+		if err != nil {
+			return round.WrapError(err, Pi)
+		}
+		round.temp.rfRound1Messages[i] = msg
+		round.out <- round.Outbound(msg)
+	}
+	return nil
+}
+
+// revoke drops the revoked parties from the key, which must otherwise hold exactly the parties of this session
+func (round *round1) revoke() *tss.Error {
+	Pi := round.PartyID()
+	kept := make(map[string]struct{}, round.PartyCount())
+	for _, Pj := range round.Parties().IDs() {
+		kept[Pj.KeyInt().String()] = struct{}{}
+	}
+	revoked := make(map[string]struct{}, len(round.temp.revoked))
+	for _, k := range round.temp.revoked {
+		if _, ok := kept[k.String()]; ok {
+			return round.WrapError(fmt.Errorf("revoked party %s must not take part", k), Pi).WithCode(tss.CodeInvalidInput)
+		}
+		revoked[k.String()] = struct{}{}
+	}
+	found := 0
+	for _, k := range round.input.Ks {
+		_, isKept := kept[k.String()]
+		if _, isRevoked := revoked[k.String()]; !isKept && !isRevoked {
+			return round.WrapError(fmt.Errorf("party %s is neither revoked nor taking part", k), Pi).WithCode(tss.CodeInvalidInput)
+		}
+		if isKept {
+			found++
+		}
+	}
+	if found != round.PartyCount() {
+		return round.WrapError(fmt.Errorf("refresh requires the key of all %d parties", round.PartyCount()), Pi).WithCode(tss.CodeInvalidInput)
+	}
+	*round.input = keygen.BuildLocalSaveDataSubset(*round.input, round.Parties().IDs())
+	*round.save = copySaveData(*round.input)
+	round.save.RevokedKs = append(round.save.RevokedKs, round.temp.revoked...)
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	if _, ok := msg.Content().(*RFRound1Message); ok {
+		return msg.IsBroadcast()
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	ret := true
+	for j, msg := range round.temp.rfRound1Messages {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			ret = false
+			continue
+		}
+		// proof checks are in round 2
+		round.ok[j] = true
+	}
+	return ret, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
@@ -0,0 +1,216 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	Ps := round.Parties().IDs()
+	PIdx := round.PartyID().Index
+
+	// 1-5. verify the de-commitments, zero shares and proofs of each Pj (concurrent)
+	type vssOut struct {
+		unWrappedErr error
+		pjVs         vss.Vs
+	}
+	chs := make([]chan vssOut, len(Ps))
+	for j := range chs {
+		if j == PIdx {
+			continue
+		}
+		chs[j] = make(chan vssOut)
+	}
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		go func(j int, ch chan<- vssOut) {
+			r1msg := round.temp.rfRound1Messages[j].Content().(*RFRound1Message)
+			r2msg2 := round.temp.rfRound2Message2s[j].Content().(*RFRound2Message2)
+			cmtDeCmt := commitments.HashCommitDecommit{C: round.temp.RFCs[j], D: r2msg2.UnmarshalDeCommitment(), Hasher: round.HashScheme().Tagged(common.PoseidonTagECDSARefreshCommitment)}
+			ok, flatPolyGs := cmtDeCmt.DeCommit()
+			if !ok || flatPolyGs == nil {
+				ch <- vssOut{tss.CodedError(tss.CodeBadCommitment, errors.New("de-commitment verify failed")), nil}
+				return
+			}
+			PjVs, err := crypto.UnFlattenECPoints(round.Params().EC(), flatPolyGs)
+			if err != nil {
+				ch <- vssOut{err, nil}
+				return
+			}
+			r2msg1 := round.temp.rfRound2Message1s[j].Content().(*RFRound2Message1)
+			PjShare := vss.Share{
+				Threshold: round.temp.threshold,
+				ID:        round.PartyID().KeyInt(),
+				Share:     r2msg1.UnmarshalShare(),
+			}
+			if round.changesThreshold() {
+				// Pj must have shared lambda_j * xj, so v0 = lambda_j * Xj
+				if ok = PjShare.Verify(round.Params().EC(), round.temp.threshold, PjVs); !ok {
+					ch <- vssOut{tss.CodedError(tss.CodeBadShare, errors.New("vss verify failed")), nil}
+					return
+				}
+				if !PjVs[0].Equals(round.input.BigXj[j].ScalarMult(round.lagrange(j))) {
+					ch <- vssOut{tss.CodedError(tss.CodeBadShare, errors.New("vss commitment does not match BigXj")), nil}
+					return
+				}
+			} else if ok = PjShare.VerifyZeroShare(round.Params().EC(), round.Threshold(), PjVs); !ok {
+				ch <- vssOut{tss.CodedError(tss.CodeBadShare, errors.New("vss verify failed")), nil}
+				return
+			}
+			// a new paillier key must come with the proofs of keygen
+			if r1msg.RotatesPreParams() {
+				if !round.Params().NoProofMod() {
+					modProof, err := r2msg2.UnmarshalModProof()
+					if err != nil || !modProof.Verify(ContextJ, round.save.PaillierPKs[j].N) {
+						ch <- vssOut{tss.CodedError(tss.CodeBadProof, errors.New("modProof verify failed")), nil}
+						return
+					}
+				}
+				if !round.Params().NoProofFac() {
+					facProof, err := r2msg1.UnmarshalFacProof()
+					if err != nil || !facProof.Verify(ContextJ, round.EC(), round.save.PaillierPKs[j].N, round.save.NTildei,
+						round.save.H1i, round.save.H2i) {
+						ch <- vssOut{tss.CodedError(tss.CodeBadProof, errors.New("facProof verify failed")), nil}
+						return
+					}
+				}
+			}
+			ch <- vssOut{nil, PjVs}
+		}(j, chs[j])
+	}
+
+	// consume unbuffered channels (end the goroutines)
+	vssResults := make([]vssOut, len(Ps))
+	{
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		var multiErr error
+		for j, Pj := range Ps {
+			if j == PIdx {
+				continue
+			}
+			vssResults[j] = <-chs[j]
+			// collect culprits to error out with
+			if err := vssResults[j].unWrappedErr; err != nil {
+				culprits = append(culprits, Pj)
+				multiErr = multierror.Append(multiErr, err)
+			}
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(multiErr, culprits...)
+		}
+	}
+
+	// 6. xi' = xi + sum of the zero shares received, or the sum of the shares received when changing the threshold
+	modQ := common.ModInt(round.Params().EC().Params().N)
+	xi := round.temp.shares[PIdx].Share
+	if !round.changesThreshold() {
+		xi = modQ.Add(round.input.Xi, xi)
+	}
+	for j := range Ps {
+		if j == PIdx {
+			continue
+		}
+		r2msg1 := round.temp.rfRound2Message1s[j].Content().(*RFRound2Message1)
+		xi = modQ.Add(xi, r2msg1.UnmarshalShare())
+	}
+	round.save.Xi = xi
+
+	// 7. sum the commitments
+	Vc := make(vss.Vs, len(round.temp.vs))
+	copy(Vc, round.temp.vs)
+	{
+		var err error
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		for j, Pj := range Ps {
+			if j == PIdx {
+				continue
+			}
+			PjVs := vssResults[j].pjVs
+			for c := range Vc {
+				Vc[c], err = Vc[c].Add(PjVs[c])
+				if err != nil {
+					culprits = append(culprits, Pj)
+				}
+			}
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(errors.New("adding PjVs[c] to Vc[c] resulted in a point not on the curve"), culprits...).WithCode(tss.CodeBadPublicData)
+		}
+	}
+
+	// 8. Xj' = Xj + f(kj)*G for each Pj, where f is the sum of the zero polynomials;
+	// when changing the threshold Xj' = f(kj)*G, where f is the sum of the new polynomials
+	{
+		BigXjBase, Vz := round.input.BigXj, Vc
+		if round.changesThreshold() {
+			BigXjBase = make([]*crypto.ECPoint, len(Ps))
+			for j := range BigXjBase {
+				BigXjBase[j] = Vc[0]
+			}
+			Vz = Vc[1:]
+		}
+		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
+		for j, Pj := range Ps {
+			delta, err := vss.EvaluateZeroCommitments(round.EC(), Vz, Pj.KeyInt())
+			if err == nil {
+				round.save.BigXj[j], err = BigXjBase[j].Add(delta)
+			}
+			if err != nil {
+				culprits = append(culprits, Pj)
+			}
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(errors.New("refreshing BigXj resulted in a point not on the curve"), culprits...).WithCode(tss.CodeBadPublicData)
+		}
+	}
+	if !crypto.ScalarBaseMult(round.EC(), xi).Equals(round.save.BigXj[PIdx]) {
+		return round.WrapError(errors.New("refreshed xi does not match BigXi"), round.PartyID()).WithCode(tss.CodeVerificationFailed)
+	}
+	// a new threshold or revoked parties
+	round.save.Metadata = round.save.Metadata.WithParties(round.temp.threshold, len(round.save.Ks))
+
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
+	}
+
+	round.end <- round.save
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -0,0 +1,143 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package refresh
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	TaskName = "ecdsa-refresh"
+)
+
+type (
+	base struct {
+		*tss.Parameters
+		input, save *keygen.LocalPartySaveData
+		temp        *localTempData
+		out         chan<- tss.Message
+		end         chan<- *keygen.LocalPartySaveData
+		ok          []bool // `ok` tracks parties which have been verified by Update()
+		started     bool
+		number      int
+	}
+	round1 struct {
+		*base
+	}
+	round2 struct {
+		*round1
+	}
+	round3 struct {
+		*round2
+	}
+)
+
+var (
+	_ tss.Round = (*round1)(nil)
+	_ tss.Round = (*round2)(nil)
+	_ tss.Round = (*round3)(nil)
+)
+
+// ----- //
+
+func (round *base) Params() *tss.Parameters {
+	return round.Parameters
+}
+
+func (round *base) RoundNumber() int {
+	return round.number
+}
+
+// CanProceed is inherited by other rounds
+func (round *base) CanProceed() bool {
+	if !round.started {
+		return false
+	}
+	for _, ok := range round.ok {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitingFor is called by a Party for reporting back to the caller
+func (round *base) WaitingFor() []*tss.PartyID {
+	Ps := round.Parties().IDs()
+	ids := make([]*tss.PartyID, 0, len(round.ok))
+	for j, ok := range round.ok {
+		if ok {
+			continue
+		}
+		ids = append(ids, Ps[j])
+	}
+	return ids
+}
+
+func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
+}
+
+// ----- //
+
+// `ok` tracks parties which have been verified by Update()
+func (round *base) resetOK() {
+	for j := range round.ok {
+		round.ok[j] = false
+	}
+}
+
+// changesThreshold reports whether the refreshed shares have a different threshold than the input key
+func (round *base) changesThreshold() bool {
+	return round.temp.threshold != round.Threshold()
+}
+
+// lagrange returns the Lagrange coefficient of Pj at 0 over the keys of every party
+func (round *base) lagrange(j int) *big.Int {
+	modQ := common.ModInt(round.EC().Params().N)
+	ks := round.input.Ks
+	lambda := big.NewInt(1)
+	for c, kc := range ks {
+		if c == j {
+			continue
+		}
+		lambda = modQ.Mul(lambda, modQ.Mul(kc, modQ.ModInverse(modQ.Sub(kc, ks[j]))))
+	}
+	return lambda
+}
+
+// get ssid from local params
+func (round *base) getSSID() ([]byte, error) {
+	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().B, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
+	ssidList = append(ssidList, round.Parties().IDs().Keys()...)                                                                                // parties
+	BigXjList, err := crypto.FlattenECPoints(round.input.BigXj)
+	if err != nil {
+		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
+	ssidList = append(ssidList, BigXjList...)                            // BigXj
+	ssidList = append(ssidList, round.input.NTildej...)                  // NTilde
+	ssidList = append(ssidList, round.input.H1j...)                      // h1
+	ssidList = append(ssidList, round.input.H2j...)                      // h2
+	ssidList = append(ssidList, round.temp.revoked...)                   // revoked parties
+	ssidList = append(ssidList, big.NewInt(int64(round.temp.threshold))) // new threshold
+	ssidList = append(ssidList, big.NewInt(int64(round.number)))         // round number
+	ssidList = append(ssidList, round.temp.ssidNonce)
+	ssidList = append(ssidList, round.SessionIDInts()...)
+	ssidHash := round.HashScheme().Tagged(common.PoseidonTagECDSARefreshSSID).HashInts(ssidList...)
+	if ssidHash == nil {
+		return nil, round.WrapError(errors.New("hash ssid failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
+	ssid := ssidHash.Bytes()
+
+	return ssid, nil
+}
@@ -0,0 +1,218 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package repair
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Implements Party
+// Implements Stringer
+var (
+	_ tss.Party    = (*LocalParty)(nil)
+	_ fmt.Stringer = (*LocalParty)(nil)
+)
+
+type (
+	LocalParty struct {
+		*tss.BaseParty
+		params *tss.Parameters
+
+		temp        localTempData
+		input, save keygen.LocalPartySaveData
+
+		// outbound messaging
+		out chan<- tss.Message
+		end chan<- *keygen.LocalPartySaveData
+	}
+
+	localMessageStore struct {
+		rpRound1Message1s,
+		rpRound1Message2s,
+		rpRound1Message3s,
+		rpRound2Message1s,
+		rpRound2Message2s []tss.ParsedMessage
+	}
+
+	localTempData struct {
+		localMessageStore
+
+		// temp data (thrown away after repair)
+		target    *tss.PartyID // the party recovering its share, or the party being added
+		targetIdx int          // the index of the target party in the parties of this session
+		isTarget  bool
+		adding    bool                   // whether the target party is being added to the key
+		preParams *keygen.LocalPreParams // the pre-params of the party being added
+		deltas    []*big.Int             // the additive pieces of lambda_i * x_i, one for each helper
+		publicKey *keygen.LocalPartySaveData
+		ssid      []byte
+		ssidNonce *big.Int
+	}
+)
+
+// Exported, used in `tss` client
+// NewLocalParty recovers the share of the `lost` party, whose key data was lost, from the shares of at least
+// threshold+1 helpers, without changing the public key or any other share.
+// The parties of `params` are the helpers together with the lost party.
+// Helpers pass their full save data as `key` and receive it back unchanged on `end`;
+// the lost party passes an empty `key` and receives its recovered save data on `end`.
+// The Paillier key and NTilde secrets of the lost party cannot be recovered; the recovered party should take part
+// in a `refresh.NewLocalPartyWithNewPreParams` session before signing.
+func NewLocalParty(
+	params *tss.Parameters,
+	lost *tss.PartyID,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) tss.Party {
+	partyCount := params.PartyCount()
+	p := &LocalParty{
+		BaseParty: new(tss.BaseParty),
+		params:    params,
+		temp:      localTempData{},
+		input:     key,
+		save:      key,
+		out:       out,
+		end:       end,
+	}
+	// msgs init
+	p.temp.rpRound1Message1s = make([]tss.ParsedMessage, partyCount)
+	p.temp.rpRound1Message2s = make([]tss.ParsedMessage, partyCount)
+	p.temp.rpRound1Message3s = make([]tss.ParsedMessage, partyCount)
+	p.temp.rpRound2Message1s = make([]tss.ParsedMessage, partyCount)
+	p.temp.rpRound2Message2s = make([]tss.ParsedMessage, partyCount)
+	// temp data init
+	p.temp.target = lost
+	p.temp.targetIdx = -1
+	for j, Pj := range params.Parties().IDs() {
+		if Pj.KeyInt().Cmp(lost.KeyInt()) == 0 {
+			p.temp.targetIdx = j
+		}
+	}
+	p.temp.isTarget = params.PartyID().KeyInt().Cmp(lost.KeyInt()) == 0
+	return p
+}
+
+// NewLocalPartyToAdd admits the `newcomer` party into an existing key: the share holders deal sub-shares of the
+// newcomer's share to it only, instead of resharing the key to all the parties. The public key, the threshold and
+// the other shares do not change.
+// The parties of `params` are every share holder of the key together with the newcomer.
+// Share holders pass their full save data as `key` and receive it on `end` with the newcomer added;
+// the newcomer passes an empty `key` and receives its new save data on `end`. When `optionalPreParams` is provided
+// to the newcomer the pre-computed primes are used instead of generating them from scratch.
+// A party revoked from the key (see keygen.LocalPartySaveData.RevokedKs) cannot be added back.
+func NewLocalPartyToAdd(
+	params *tss.Parameters,
+	newcomer *tss.PartyID,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+	optionalPreParams ...keygen.LocalPreParams,
+) tss.Party {
+	p := NewLocalParty(params, newcomer, key, out, end).(*LocalParty)
+	p.temp.adding = true
+	if 0 < len(optionalPreParams) {
+		if 1 < len(optionalPreParams) {
+			panic(errors.New("repair.NewLocalPartyToAdd expected 0 or 1 item in `optionalPreParams`"))
+		}
+		if !optionalPreParams[0].ValidateWithProof() {
+			panic(errors.New("`optionalPreParams` failed to validate; it might have been generated with an older version of tss-lib"))
+		}
+		p.temp.preParams = &optionalPreParams[0]
+	}
+	return p
+}
+
+// Session is a repair party with the channels it sends its messages and its save data on
+type Session struct {
+	*LocalParty
+	Out <-chan tss.Message
+	End <-chan *keygen.LocalPartySaveData
+}
+
+// NewSession returns the session of a party created like NewLocalParty, with channels that buffer `buffer` messages
+func NewSession(params *tss.Parameters, lost *tss.PartyID, key keygen.LocalPartySaveData, buffer int) *Session {
+	out, end := make(chan tss.Message, buffer), make(chan *keygen.LocalPartySaveData, 1)
+	return &Session{
+		LocalParty: NewLocalParty(params, lost, key, out, end).(*LocalParty),
+		Out:        out,
+		End:        end,
+	}
+}
+
+func (p *LocalParty) FirstRound() tss.Round {
+	return newRound1(p.params, &p.input, &p.save, &p.temp, p.out, p.end)
+}
+
+func (p *LocalParty) Start() *tss.Error {
+	return tss.BaseStart(p, TaskName)
+}
+
+func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	return tss.BaseUpdate(p, msg, TaskName)
+}
+
+func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return false, p.WrapError(err).WithCode(tss.CodeInvalidMessage)
+	}
+	return p.Update(msg)
+}
+
+func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	if ok, err := p.BaseParty.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	// check that the message's "from index" will fit into the array
+	if maxFromIdx := p.params.PartyCount() - 1; maxFromIdx < msg.GetFrom().Index {
+		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
+			p.params.PartyCount(), msg.GetFrom().Index), msg.GetFrom()).WithCode(tss.CodeInvalidMessage)
+	}
+	return true, nil
+}
+
+func (p *LocalParty) StoreMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
+	// ValidateBasic is cheap; double-check the message here in case the public StoreMessage was called externally
+	if ok, err := p.ValidateMessage(msg); !ok || err != nil {
+		return ok, err
+	}
+	fromPIdx := msg.GetFrom().Index
+
+	// switch/case is necessary to store any messages beyond current round
+	// this does not handle message replays. we expect the caller to apply replay and spoofing protection.
+	switch msg.Content().(type) {
+	case *RPRound1Message1:
+		p.temp.rpRound1Message1s[fromPIdx] = msg
+	case *RPRound1Message2:
+		p.temp.rpRound1Message2s[fromPIdx] = msg
+	case *RPRound1Message3:
+		p.temp.rpRound1Message3s[fromPIdx] = msg
+	case *RPRound2Message1:
+		p.temp.rpRound2Message1s[fromPIdx] = msg
+	case *RPRound2Message2:
+		p.temp.rpRound2Message2s[fromPIdx] = msg
+	default: // unrecognised message, just ignore!
+		common.Logger.Warningf("unrecognised message ignored: %v", msg)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (p *LocalParty) PartyID() *tss.PartyID {
+	return p.params.PartyID()
+}
+
+func (p *LocalParty) String() string {
+	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
+}
@@ -0,0 +1,278 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package repair_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ipfs/go-log"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/refresh"
+	. "github.com/bnb-chain/tss-lib/v2/ecdsa/repair"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	testParticipants = test.TestParticipants
+	testThreshold    = test.TestThreshold
+)
+
+func setUp(level string) {
+	if err := log.SetLogLevel("tss-lib", level); err != nil {
+		panic(err)
+	}
+}
+
+// routes the messages of `parties` until `count` of them have ended, returning the save data sent to `endCh`
+func run(t *testing.T, parties []tss.Party, outCh <-chan tss.Message, errCh chan *tss.Error, endCh <-chan *keygen.LocalPartySaveData) []*keygen.LocalPartySaveData {
+	updater := test.SharedPartyUpdater
+	for _, P := range parties {
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	saves := make([]*keygen.LocalPartySaveData, 0, len(parties))
+	for len(saves) < len(parties) {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			saves = append(saves, save)
+		}
+	}
+	return saves
+}
+
+func TestE2ERepairThenRefresh(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// 1. party 0 lost its key and recovers its share with the help of every other party
+	lost := pIDs[0]
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	parties := make([]tss.Party, 0, len(pIDs))
+	for j, pID := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(pIDs), testThreshold)
+		key := keys[j]
+		if j == lost.Index {
+			key = keygen.NewLocalPartySaveData(len(pIDs))
+		}
+		parties = append(parties, NewLocalParty(params, lost, key, outCh, endCh))
+	}
+	saves := run(t, parties, outCh, errCh, endCh)
+
+	repaired := make([]keygen.LocalPartySaveData, len(pIDs))
+	for _, save := range saves {
+		index, err := save.OriginalIndex()
+		assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+		repaired[index] = *save
+	}
+	assert.Equal(t, 0, repaired[0].Xi.Cmp(keys[0].Xi), "the lost share should be recovered")
+	assert.True(t, repaired[0].ECDSAPub.Equals(keys[0].ECDSAPub), "the public key should be recovered")
+	assert.Nil(t, repaired[0].PaillierSK, "the paillier key cannot be recovered")
+	for j, key := range repaired[1:] {
+		assert.Equal(t, 0, key.Xi.Cmp(keys[j+1].Xi), "the helpers' shares must not change")
+	}
+
+	// 2. the recovered party replaces its pre-params in a refresh with every party
+	outCh2 := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh2 := make(chan *keygen.LocalPartySaveData, len(pIDs))
+	refreshers := make([]tss.Party, 0, len(pIDs))
+	for j, pID := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(pIDs), testThreshold)
+		if j == lost.Index {
+			refreshers = append(refreshers, refresh.NewLocalPartyWithNewPreParams(params, repaired[j], outCh2, endCh2, keys[j].LocalPreParams))
+		} else {
+			refreshers = append(refreshers, refresh.NewLocalParty(params, repaired[j], outCh2, endCh2))
+		}
+	}
+	saves = run(t, refreshers, outCh2, errCh, endCh2)
+
+	refreshed := make([]keygen.LocalPartySaveData, len(pIDs))
+	for _, save := range saves {
+		index, err := save.OriginalIndex()
+		assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+		refreshed[index] = *save
+	}
+	assert.True(t, refreshed[0].BigXj[0].Equals(crypto.ScalarBaseMult(tss.S256(), refreshed[0].Xi)), "ensure BigX_j == g^x_j")
+
+	// 3. the recovered party signs
+	signAndVerify(t, refreshed[:testThreshold+1], pIDs[:testThreshold+1])
+}
+
+func TestE2EAddParty(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// 1. leave party 2 out of the key, then add it back as a newcomer with its pre-params
+	newcomer := pIDs[2]
+	holderPIDs := make(tss.UnSortedPartyIDs, 0, len(pIDs)-1)
+	for _, pID := range pIDs {
+		if pID != newcomer {
+			holderPIDs = append(holderPIDs, tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+		}
+	}
+	holders := tss.SortPartyIDs(holderPIDs)
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs)*len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	parties := make([]tss.Party, 0, len(pIDs))
+	for j, pID := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(pIDs), testThreshold)
+		if pID == newcomer {
+			key := keygen.NewLocalPartySaveData(len(pIDs))
+			parties = append(parties, NewLocalPartyToAdd(params, newcomer, key, outCh, endCh, keys[j].LocalPreParams))
+			continue
+		}
+		key := keygen.BuildLocalSaveDataSubset(keys[j], holders)
+		parties = append(parties, NewLocalPartyToAdd(params, newcomer, key, outCh, endCh))
+	}
+	saves := run(t, parties, outCh, errCh, endCh)
+
+	added := make([]keygen.LocalPartySaveData, len(pIDs))
+	for _, save := range saves {
+		index, err := save.OriginalIndex()
+		assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+		added[index] = *save
+	}
+	assert.Equal(t, 0, added[2].Xi.Cmp(keys[2].Xi), "the newcomer should receive f(k)")
+	for j, key := range added {
+		assert.Equal(t, 0, key.Xi.Cmp(keys[j].Xi))
+		assert.Equal(t, len(pIDs), len(key.Ks), "every party should know of the newcomer")
+		for c := range key.Ks {
+			assert.Equal(t, 0, key.Ks[c].Cmp(keys[j].Ks[c]), "the newcomer should be inserted in key order")
+			assert.True(t, key.BigXj[c].Equals(keys[j].BigXj[c]))
+			assert.Equal(t, 0, key.NTildej[c].Cmp(keys[j].NTildej[c]))
+			assert.Equal(t, 0, key.PaillierPKs[c].N.Cmp(keys[j].PaillierPKs[c].N))
+		}
+	}
+
+	// 2. the newcomer signs
+	signAndVerify(t, added[:testThreshold+1], pIDs[:testThreshold+1])
+}
+
+func TestAddRevokedParty(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// party 2 was revoked from the key, so the holders refuse to add it back
+	newcomer := pIDs[2]
+	holderPIDs := make(tss.UnSortedPartyIDs, 0, len(pIDs)-1)
+	for _, pID := range pIDs {
+		if pID != newcomer {
+			holderPIDs = append(holderPIDs, tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+		}
+	}
+	key := keygen.BuildLocalSaveDataSubset(keys[0], tss.SortPartyIDs(holderPIDs))
+	key.RevokedKs = []*big.Int{newcomer.KeyInt()}
+
+	params := tss.NewParameters(tss.S256(), tss.NewPeerContext(pIDs), pIDs[0], len(pIDs), testThreshold)
+	P := NewLocalPartyToAdd(params, newcomer, key, make(chan tss.Message, len(pIDs)), nil)
+	if err := P.Start(); assert.NotNil(t, err, "a revoked party must not be added back") {
+		assert.Equal(t, tss.CodeInvalidInput, err.Code())
+	}
+}
+
+func signAndVerify(t *testing.T, keys []keygen.LocalPartySaveData, pIDs []*tss.PartyID) {
+	signPIDs := make(tss.UnSortedPartyIDs, 0, len(pIDs))
+	for _, pID := range pIDs {
+		signPIDs = append(signPIDs, tss.NewPartyID(pID.Id, pID.Moniker, pID.KeyInt()))
+	}
+	sortedPIDs := tss.SortPartyIDs(signPIDs)
+	p2pCtx := tss.NewPeerContext(sortedPIDs)
+	parties := make([]*signing.LocalParty, 0, len(sortedPIDs))
+
+	errCh := make(chan *tss.Error, len(sortedPIDs))
+	outCh := make(chan tss.Message, len(sortedPIDs))
+	endCh := make(chan *common.SignatureData, len(sortedPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	msg := big.NewInt(42)
+	for j, pID := range sortedPIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(sortedPIDs), testThreshold)
+		key := keygen.BuildLocalSaveDataSubset(keys[j], sortedPIDs)
+		P := signing.NewLocalParty(msg, params, key, outCh, endCh).(*signing.LocalParty)
+		parties = append(parties, P)
+		go func(P *signing.LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	for ended := 0; ended < len(sortedPIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case signData := <-endCh:
+			pk := ecdsa.PublicKey{
+				Curve: tss.S256(),
+				X:     keys[0].ECDSAPub.X(),
+				Y:     keys[0].ECDSAPub.Y(),
+			}
+			ok := ecdsa.Verify(&pk, msg.Bytes(), new(big.Int).SetBytes(signData.R), new(big.Int).SetBytes(signData.S))
+			assert.True(t, ok, "ecdsa verify must pass")
+			ended++
+		}
+	}
+}
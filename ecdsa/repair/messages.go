@@ -0,0 +1,267 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package repair
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/dlnproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/facproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/modproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// These messages were generated from Protocol Buffers definitions into ecdsa-repair.pb.go
+// The following messages are registered on the Protocol Buffers "wire"
+
+var (
+	// Ensure that repair messages implement ValidateBasic
+	_ = []tss.MessageContent{
+		(*RPRound1Message1)(nil),
+		(*RPRound1Message2)(nil),
+		(*RPRound1Message3)(nil),
+		(*RPRound2Message1)(nil),
+		(*RPRound2Message2)(nil),
+	}
+)
+
+// ----- //
+
+func NewRPRound1Message1(
+	to, from *tss.PartyID,
+	share *big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &RPRound1Message1{
+		Share: share.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *RPRound1Message1) ValidateBasic() bool {
+	// a random share may legitimately encode to no bytes
+	return m != nil
+}
+
+func (m *RPRound1Message1) UnmarshalShare() *big.Int {
+	return new(big.Int).SetBytes(m.GetShare())
+}
+
+// ----- //
+
+// NewRPRound1Message2 sends the public part of the key data to the recovering party
+func NewRPRound1Message2(
+	to, from *tss.PartyID,
+	key *keygen.LocalPartySaveData,
+) (tss.ParsedMessage, error) {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	bigXjFlat, err := crypto.FlattenECPoints(key.BigXj)
+	if err != nil {
+		return nil, err
+	}
+	paillierNs := make([]*big.Int, len(key.PaillierPKs))
+	for j, pk := range key.PaillierPKs {
+		paillierNs[j] = pk.N
+	}
+	content := &RPRound1Message2{
+		Ks:        common.BigIntsToBytes(key.Ks),
+		BigXj:     common.BigIntsToBytes(bigXjFlat),
+		EcdsaPubX: key.ECDSAPub.X().Bytes(),
+		EcdsaPubY: key.ECDSAPub.Y().Bytes(),
+		NTilde:    common.BigIntsToBytes(key.NTildej),
+		H1:        common.BigIntsToBytes(key.H1j),
+		H2:        common.BigIntsToBytes(key.H2j),
+		PaillierN: common.BigIntsToBytes(paillierNs),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg), nil
+}
+
+func (m *RPRound1Message2) ValidateBasic() bool {
+	if m == nil {
+		return false
+	}
+	n := len(m.GetKs())
+	return 0 < n &&
+		common.NonEmptyMultiBytes(m.GetKs(), n) &&
+		common.NonEmptyMultiBytes(m.GetBigXj(), 2*n) &&
+		common.NonEmptyBytes(m.GetEcdsaPubX()) &&
+		common.NonEmptyBytes(m.GetEcdsaPubY()) &&
+		common.NonEmptyMultiBytes(m.GetNTilde(), n) &&
+		common.NonEmptyMultiBytes(m.GetH1(), n) &&
+		common.NonEmptyMultiBytes(m.GetH2(), n) &&
+		common.NonEmptyMultiBytes(m.GetPaillierN(), n)
+}
+
+// UnmarshalPublicData returns save data holding the public part of the key, without any secrets
+func (m *RPRound1Message2) UnmarshalPublicData(ec elliptic.Curve) (*keygen.LocalPartySaveData, error) {
+	n := len(m.GetKs())
+	save := keygen.NewLocalPartySaveData(n)
+	var err error
+	if save.BigXj, err = crypto.UnFlattenECPoints(ec, common.MultiBytesToBigInts(m.GetBigXj())); err != nil {
+		return nil, err
+	}
+	if save.ECDSAPub, err = crypto.NewECPoint(ec,
+		new(big.Int).SetBytes(m.GetEcdsaPubX()),
+		new(big.Int).SetBytes(m.GetEcdsaPubY())); err != nil {
+		return nil, err
+	}
+	save.Ks = common.MultiBytesToBigInts(m.GetKs())
+	save.NTildej = common.MultiBytesToBigInts(m.GetNTilde())
+	save.H1j = common.MultiBytesToBigInts(m.GetH1())
+	save.H2j = common.MultiBytesToBigInts(m.GetH2())
+	for j, N := range common.MultiBytesToBigInts(m.GetPaillierN()) {
+		save.PaillierPKs[j] = &paillier.PublicKey{N: N}
+	}
+	return &save, nil
+}
+
+// ----- //
+
+// NewRPRound1Message3 broadcasts the Paillier public key, NTilde, h1, h2 of a party being added, with their proofs
+func NewRPRound1Message3(
+	from *tss.PartyID,
+	preParams *keygen.LocalPreParams,
+	dlnProof1, dlnProof2 *dlnproof.Proof,
+	modProof *modproof.ProofMod,
+) (tss.ParsedMessage, error) {
+	meta := tss.MessageRouting{
+		From:        from,
+		IsBroadcast: true,
+	}
+	dlnProof1Bz, err := dlnProof1.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	dlnProof2Bz, err := dlnProof2.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	content := &RPRound1Message3{
+		PaillierN:  preParams.PaillierSK.N.Bytes(),
+		NTilde:     preParams.NTildei.Bytes(),
+		H1:         preParams.H1i.Bytes(),
+		H2:         preParams.H2i.Bytes(),
+		Dlnproof_1: dlnProof1Bz,
+		Dlnproof_2: dlnProof2Bz,
+	}
+	if modProof != nil {
+		proofBzs := modProof.Bytes()
+		content.ModProof = proofBzs[:]
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg), nil
+}
+
+func (m *RPRound1Message3) ValidateBasic() bool {
+	return m != nil &&
+		common.NonEmptyBytes(m.GetPaillierN()) &&
+		common.NonEmptyBytes(m.GetNTilde()) &&
+		common.NonEmptyBytes(m.GetH1()) &&
+		common.NonEmptyBytes(m.GetH2()) &&
+		// expected len of dln proof = sizeof(int64) + len(alpha) + len(t)
+		common.NonEmptyMultiBytes(m.GetDlnproof_1(), 2+(dlnproof.Iterations*2)) &&
+		common.NonEmptyMultiBytes(m.GetDlnproof_2(), 2+(dlnproof.Iterations*2))
+}
+
+func (m *RPRound1Message3) UnmarshalPaillierPK() *paillier.PublicKey {
+	return &paillier.PublicKey{N: new(big.Int).SetBytes(m.GetPaillierN())}
+}
+
+func (m *RPRound1Message3) UnmarshalNTilde() *big.Int {
+	return new(big.Int).SetBytes(m.GetNTilde())
+}
+
+func (m *RPRound1Message3) UnmarshalH1() *big.Int {
+	return new(big.Int).SetBytes(m.GetH1())
+}
+
+func (m *RPRound1Message3) UnmarshalH2() *big.Int {
+	return new(big.Int).SetBytes(m.GetH2())
+}
+
+func (m *RPRound1Message3) UnmarshalDLNProof1() (*dlnproof.Proof, error) {
+	return dlnproof.UnmarshalDLNProof(m.GetDlnproof_1())
+}
+
+func (m *RPRound1Message3) UnmarshalDLNProof2() (*dlnproof.Proof, error) {
+	return dlnproof.UnmarshalDLNProof(m.GetDlnproof_2())
+}
+
+func (m *RPRound1Message3) UnmarshalModProof() (*modproof.ProofMod, error) {
+	return modproof.NewProofFromBytes(m.GetModProof())
+}
+
+// ----- //
+
+func NewRPRound2Message1(
+	to, from *tss.PartyID,
+	share *big.Int,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &RPRound2Message1{
+		Share: share.Bytes(),
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *RPRound2Message1) ValidateBasic() bool {
+	return m != nil
+}
+
+func (m *RPRound2Message1) UnmarshalShare() *big.Int {
+	return new(big.Int).SetBytes(m.GetShare())
+}
+
+// ----- //
+
+// NewRPRound2Message2 sends the proof that the Paillier modulus of a party being added has no small factors;
+// `proof` is nil when the proof is disabled
+func NewRPRound2Message2(
+	to, from *tss.PartyID,
+	proof *facproof.ProofFac,
+) tss.ParsedMessage {
+	meta := tss.MessageRouting{
+		From:        from,
+		To:          []*tss.PartyID{to},
+		IsBroadcast: false,
+	}
+	content := &RPRound2Message2{}
+	if proof != nil {
+		proofBzs := proof.Bytes()
+		content.FacProof = proofBzs[:]
+	}
+	msg := tss.NewMessageWrapper(meta, content)
+	return tss.NewMessage(meta, content, msg)
+}
+
+func (m *RPRound2Message2) ValidateBasic() bool {
+	return m != nil
+}
+
+func (m *RPRound2Message2) UnmarshalFacProof() (*facproof.ProofFac, error) {
+	return facproof.NewProofFromBytes(m.GetFacProof())
+}
@@ -0,0 +1,209 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/dlnproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/modproof"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// round 1 splits the Lagrange-weighted share of each helper into random pieces, one for every helper,
+// and sends the public key data to the target party; a party being added announces its pre-params
+func newRound1(params *tss.Parameters, input, save *keygen.LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *keygen.LocalPartySaveData) tss.Round {
+	return &round1{
+		&base{params, input, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1},
+	}
+}
+
+func (round *round1) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
+	}
+	round.number = 1
+	round.started = true
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	Ps := round.Parties().IDs()
+
+	if round.temp.targetIdx < 0 {
+		return round.WrapError(fmt.Errorf("the target party %s must take part in the repair", round.temp.target), Pi).WithCode(tss.CodeInvalidInput)
+	}
+	if round.PartyCount()-1 < round.Threshold()+1 {
+		return round.WrapError(fmt.Errorf("repair requires at least %d helpers, got %d", round.Threshold()+1, round.PartyCount()-1), Pi).WithCode(tss.CodeInvalidInput)
+	}
+
+	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(errors.New("failed to generate ssid")).WithCode(tss.CodeInternal)
+	}
+	round.temp.ssid = ssid
+
+	round.expectFromHelpers()
+
+	// the lost party only receives in this round; a party being added also announces its pre-params
+	if round.temp.isTarget {
+		if round.temp.adding {
+			return round.announcePreParams()
+		}
+		return nil
+	}
+
+	// every helper must be a share holder of this key
+	if round.input.Xi == nil || round.input.ShareID == nil || round.input.ShareID.Cmp(Pi.KeyInt()) != 0 {
+		return round.WrapError(errors.New("a helper must provide its share of the key"), Pi).WithCode(tss.CodeInvalidInput)
+	}
+	if err := round.ValidateHashScheme(round.input.HashScheme); err != nil {
+		return round.WrapError(err, Pi)
+	}
+	if round.input.AccessStructure != nil {
+		return round.WrapError(errors.New("keys shared along an access structure cannot be repaired"), Pi).WithCode(tss.CodeInvalidInput)
+	}
+	for j, Pj := range Ps {
+		if j == round.temp.targetIdx {
+			continue
+		}
+		if keyIndex(round.input, Pj.KeyInt()) < 0 {
+			return round.WrapError(fmt.Errorf("party %s does not hold a share of this key", Pj), Pi).WithCode(tss.CodeInvalidInput)
+		}
+	}
+	targetIsHolder := 0 <= keyIndex(round.input, round.temp.target.KeyInt())
+	if round.input.IsRevoked(round.temp.target.KeyInt()) {
+		return round.WrapError(fmt.Errorf("party %s was revoked from this key", round.temp.target), Pi).WithCode(tss.CodeInvalidInput)
+	}
+	if round.temp.adding {
+		// every share holder must take part, or those left out would not know of the new party
+		if targetIsHolder || len(round.input.Ks) != round.PartyCount()-1 {
+			return round.WrapError(fmt.Errorf("adding a party requires the key of all %d share holders", len(round.input.Ks)), Pi).WithCode(tss.CodeInvalidInput)
+		}
+		round.ok[round.temp.targetIdx] = false
+	} else if !targetIsHolder {
+		return round.WrapError(fmt.Errorf("party %s does not hold a share of this key", round.temp.target), Pi).WithCode(tss.CodeInvalidInput)
+	}
+
+	// 1. split lambda_i * x_i into additive pieces delta_ij, one for each helper Pj
+	modQ := common.ModInt(round.EC().Params().N)
+	value := modQ.Mul(round.lagrange(i), round.input.Xi)
+	round.temp.deltas = make([]*big.Int, len(Ps))
+	sum := big.NewInt(0)
+	last := -1
+	for j := range Ps {
+		if j == round.temp.targetIdx {
+			continue
+		}
+		if last >= 0 {
+			round.temp.deltas[last] = common.GetRandomPositiveInt(round.Rand(), round.EC().Params().N)
+			sum = modQ.Add(sum, round.temp.deltas[last])
+		}
+		last = j
+	}
+	round.temp.deltas[last] = modQ.Sub(value, sum)
+
+	// P2P send delta_ij to each other helper; round 1 message 1
+	for j, Pj := range Ps {
+		if j == i || j == round.temp.targetIdx {
+			continue
+		}
+		r1msg1 := NewRPRound1Message1(Pj, Pi, round.temp.deltas[j])
+		round.out <- round.Outbound(r1msg1)
+	}
+
+	// P2P send the public key data to the target party; round 1 message 2
+	r1msg2, err := NewRPRound1Message2(Ps[round.temp.targetIdx], Pi, round.input)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	round.out <- round.Outbound(r1msg2)
+	return nil
+}
+
+// announcePreParams broadcasts the Paillier key, NTilde, h1, h2 of the party being added, using the pre-params if
+// they were provided to the constructor
+func (round *round1) announcePreParams() *tss.Error {
+	Pi := round.PartyID()
+	preParams := round.temp.preParams
+	if preParams == nil {
+		ctx, cancel := context.WithTimeout(round.Context(), round.SafePrimeGenTimeout())
+		defer cancel()
+		var err error
+		preParams, err = keygen.GeneratePreParamsWithContextAndRandom(ctx, round.Rand(), round.Concurrency())
+		if err != nil {
+			return round.WrapError(errors.New("pre-params generation failed"), Pi).WithCode(tss.CodeInternal)
+		}
+		round.temp.preParams = preParams
+	}
+	dlnProof1 := dlnproof.NewDLNProof(preParams.H1i, preParams.H2i, preParams.Alpha, preParams.P, preParams.Q, preParams.NTildei, round.Rand())
+	dlnProof2 := dlnproof.NewDLNProof(preParams.H2i, preParams.H1i, preParams.Beta, preParams.P, preParams.Q, preParams.NTildei, round.Rand())
+	var modProof *modproof.ProofMod
+	if !round.Params().NoProofMod() {
+		ContextI := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(Pi.Index)))
+		var err error
+		modProof, err = modproof.NewProof(ContextI, preParams.PaillierSK.N, preParams.PaillierSK.P, preParams.PaillierSK.Q, round.Rand())
+		if err != nil {
+			return round.WrapError(err, Pi)
+		}
+	}
+
+	// BROADCAST the paillier pk, NTilde, h1, h2 and proofs; round 1 message 3
+	r1msg3, err := NewRPRound1Message3(Pi, preParams, dlnProof1, dlnProof2, modProof)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
+	round.temp.rpRound1Message3s[Pi.Index] = r1msg3
+	round.out <- round.Outbound(r1msg3)
+	return nil
+}
+
+func (round *round1) CanAccept(msg tss.ParsedMessage) bool {
+	switch msg.Content().(type) {
+	case *RPRound1Message1:
+		return !msg.IsBroadcast() && !round.temp.isTarget
+	case *RPRound1Message2:
+		return !msg.IsBroadcast() && round.temp.isTarget
+	case *RPRound1Message3:
+		return msg.IsBroadcast() && round.temp.adding && msg.GetFrom().Index == round.temp.targetIdx
+	}
+	return false
+}
+
+func (round *round1) Update() (bool, *tss.Error) {
+	ret := true
+	for j := range round.ok {
+		if round.ok[j] {
+			continue
+		}
+		var msg tss.ParsedMessage
+		switch {
+		case round.temp.isTarget:
+			msg = round.temp.rpRound1Message2s[j]
+		case j == round.temp.targetIdx:
+			msg = round.temp.rpRound1Message3s[j]
+		default:
+			msg = round.temp.rpRound1Message1s[j]
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			ret = false
+			continue
+		}
+		round.ok[j] = true
+	}
+	return ret, nil
+}
+
+func (round *round1) NextRound() tss.Round {
+	round.started = false
+	return &round2{round}
+}
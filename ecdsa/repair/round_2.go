@@ -0,0 +1,152 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package repair
+
+import (
+	"errors"
+	"math/big"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/facproof"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// round 2 sums the pieces received by each helper and sends the sum to the target party;
+// a party being added proves to each helper that its Paillier modulus has no small factors
+func (round *round2) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
+	}
+	round.number = 2
+	round.started = true
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	Ps := round.Parties().IDs()
+
+	if round.temp.isTarget {
+		round.expectFromHelpers()
+		return round.receivePublicData()
+	}
+	round.expectNothing()
+	if round.temp.adding {
+		round.ok[round.temp.targetIdx] = false
+	}
+
+	// 1. sigma_i = sum_j delta_ji
+	modQ := common.ModInt(round.EC().Params().N)
+	sigma := round.temp.deltas[i]
+	for j := range Ps {
+		if j == i || j == round.temp.targetIdx {
+			continue
+		}
+		r1msg1 := round.temp.rpRound1Message1s[j].Content().(*RPRound1Message1)
+		sigma = modQ.Add(sigma, r1msg1.UnmarshalShare())
+	}
+
+	// P2P send sigma_i to the target party; round 2 message 1
+	r2msg1 := NewRPRound2Message1(Ps[round.temp.targetIdx], Pi, sigma)
+	round.out <- round.Outbound(r2msg1)
+	return nil
+}
+
+// receivePublicData checks that every helper sent the same public key data, and when being added sends the
+// fac proofs for the NTilde, h1, h2 of each helper
+func (round *round2) receivePublicData() *tss.Error {
+	Pi := round.PartyID()
+	i := Pi.Index
+	Ps := round.Parties().IDs()
+
+	// 1. every helper must have sent the same public key data
+	var first *RPRound1Message2
+	for j := range Ps {
+		if j == i {
+			continue
+		}
+		r1msg2 := round.temp.rpRound1Message2s[j].Content().(*RPRound1Message2)
+		if first == nil {
+			first = r1msg2
+			continue
+		}
+		if !proto.Equal(first, r1msg2) {
+			return round.WrapError(errors.New("the helpers sent different public key data"), Ps[j]).WithCode(tss.CodeBadPublicData)
+		}
+	}
+	key, err := first.UnmarshalPublicData(round.EC())
+	if err != nil {
+		return round.WrapError(err)
+	}
+	for j, Pj := range Ps {
+		if j == i {
+			continue
+		}
+		if keyIndex(key, Pj.KeyInt()) < 0 {
+			return round.WrapError(errors.New("the helper does not hold a share of this key"), Pj).WithCode(tss.CodeInvalidInput)
+		}
+	}
+	round.temp.publicKey = key
+	if !round.temp.adding {
+		return nil
+	}
+
+	// 2. p2p send the fac proof to each helper Pj
+	preParams := round.temp.preParams
+	ContextI := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(i)))
+	for j, Pj := range Ps {
+		if j == i {
+			continue
+		}
+		var facProof *facproof.ProofFac
+		if !round.Params().NoProofFac() {
+			kIdx := keyIndex(key, Pj.KeyInt())
+			facProof, err = facproof.NewProof(ContextI, round.EC(), preParams.PaillierSK.N, key.NTildej[kIdx],
+				key.H1j[kIdx], key.H2j[kIdx], preParams.PaillierSK.P, preParams.PaillierSK.Q, round.Rand())
+			if err != nil {
+				return round.WrapError(err, Pi)
+			}
+		}
+		r2msg2 := NewRPRound2Message2(Pj, Pi, facProof)
+		round.out <- round.Outbound(r2msg2)
+	}
+	return nil
+}
+
+func (round *round2) CanAccept(msg tss.ParsedMessage) bool {
+	switch msg.Content().(type) {
+	case *RPRound2Message1:
+		return !msg.IsBroadcast() && round.temp.isTarget
+	case *RPRound2Message2:
+		return !msg.IsBroadcast() && round.temp.adding && msg.GetFrom().Index == round.temp.targetIdx
+	}
+	return false
+}
+
+func (round *round2) Update() (bool, *tss.Error) {
+	msgs := round.temp.rpRound2Message2s
+	if round.temp.isTarget {
+		msgs = round.temp.rpRound2Message1s
+	}
+	ret := true
+	for j, msg := range msgs {
+		if round.ok[j] {
+			continue
+		}
+		if msg == nil || !round.CanAccept(msg) {
+			ret = false
+			continue
+		}
+		round.ok[j] = true
+	}
+	return ret, nil
+}
+
+func (round *round2) NextRound() tss.Round {
+	round.started = false
+	return &round3{round}
+}
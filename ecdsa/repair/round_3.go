@@ -0,0 +1,170 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package repair
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	paillierBitsLen = 2048
+)
+
+// round 3 recovers the share of the target party; the helpers finish with their key unchanged, or with the
+// party being added included in it
+func (round *round3) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
+	}
+	round.number = 3
+	round.started = true
+	round.expectNothing()
+
+	if !round.temp.isTarget {
+		if round.temp.adding {
+			if err := round.addNewcomer(); err != nil {
+				return err
+			}
+		}
+		round.end <- round.save
+		return nil
+	}
+
+	Pi := round.PartyID()
+	i := Pi.Index
+	Ps := round.Parties().IDs()
+	key := round.temp.publicKey
+
+	// 1. x_target = sum_j sigma_j
+	modQ := common.ModInt(round.EC().Params().N)
+	xi := big.NewInt(0)
+	culprits := make([]*tss.PartyID, 0, len(Ps))
+	for j, Pj := range Ps {
+		if j == i {
+			continue
+		}
+		r2msg1 := round.temp.rpRound2Message1s[j].Content().(*RPRound2Message1)
+		xi = modQ.Add(xi, r2msg1.UnmarshalShare())
+		culprits = append(culprits, Pj)
+	}
+	var BigXi *crypto.ECPoint
+	if round.temp.adding {
+		var err error
+		if BigXi, err = round.targetBigX(key); err != nil {
+			return round.WrapError(err)
+		}
+	} else {
+		kIdx := keyIndex(key, Pi.KeyInt())
+		if kIdx < 0 {
+			return round.WrapError(errors.New("this party does not hold a share of this key"), Pi).WithCode(tss.CodeInvalidInput)
+		}
+		BigXi = key.BigXj[kIdx]
+	}
+	if !crypto.ScalarBaseMult(round.EC(), xi).Equals(BigXi) {
+		return round.WrapError(errors.New("recovered xi does not match BigXi"), culprits...).WithCode(tss.CodeVerificationFailed)
+	}
+
+	// 2. a lost Paillier key cannot be recovered; only the public parameters of the lost party are restored
+	if round.temp.adding {
+		preParams := round.temp.preParams
+		insertParty(key, Pi.KeyInt(), BigXi, preParams.NTildei, preParams.H1i, preParams.H2i, &preParams.PaillierSK.PublicKey)
+		key.LocalPreParams = *preParams
+	} else {
+		kIdx := keyIndex(key, Pi.KeyInt())
+		key.NTildei = key.NTildej[kIdx]
+		key.H1i, key.H2i = key.H1j[kIdx], key.H2j[kIdx]
+	}
+	key.ShareID = Pi.KeyInt()
+	key.Xi = xi
+	key.HashScheme = round.HashScheme()
+	*round.save = *key
+
+	round.end <- round.save
+	return nil
+}
+
+// addNewcomer verifies the pre-params of the party being added and includes it in the key of this helper
+func (round *round3) addNewcomer() *tss.Error {
+	Pt := round.temp.target
+	t := round.temp.targetIdx
+	r1msg3 := round.temp.rpRound1Message3s[t].Content().(*RPRound1Message3)
+	r2msg2 := round.temp.rpRound2Message2s[t].Content().(*RPRound2Message2)
+
+	// 1. verify the Paillier pk, NTilde, h1, h2 and their proofs
+	H1t, H2t, NTildet, paillierPKt := r1msg3.UnmarshalH1(),
+		r1msg3.UnmarshalH2(),
+		r1msg3.UnmarshalNTilde(),
+		r1msg3.UnmarshalPaillierPK()
+	if paillierPKt.N.BitLen() != paillierBitsLen {
+		return round.WrapError(errors.New("got paillier modulus with insufficient bits for this party"), Pt).WithCode(tss.CodeBadPublicData)
+	}
+	if H1t.Cmp(H2t) == 0 {
+		return round.WrapError(errors.New("h1j and h2j were equal for this party"), Pt).WithCode(tss.CodeBadPublicData)
+	}
+	if NTildet.BitLen() != paillierBitsLen {
+		return round.WrapError(errors.New("got NTildej with insufficient bits for this party"), Pt).WithCode(tss.CodeBadPublicData)
+	}
+	h1H2Map := make(map[string]struct{}, len(round.input.H1j)*2)
+	for j := range round.input.H1j {
+		h1H2Map[hex.EncodeToString(round.input.H1j[j].Bytes())] = struct{}{}
+		h1H2Map[hex.EncodeToString(round.input.H2j[j].Bytes())] = struct{}{}
+	}
+	if _, found := h1H2Map[hex.EncodeToString(H1t.Bytes())]; found {
+		return round.WrapError(errors.New("this h1j was already used by another party"), Pt).WithCode(tss.CodeBadPublicData)
+	}
+	if _, found := h1H2Map[hex.EncodeToString(H2t.Bytes())]; found {
+		return round.WrapError(errors.New("this h2j was already used by another party"), Pt).WithCode(tss.CodeBadPublicData)
+	}
+	if dlnProof1, err := r1msg3.UnmarshalDLNProof1(); err != nil || !dlnProof1.Verify(H1t, H2t, NTildet) {
+		return round.WrapError(errors.New("dln proof verification failed"), Pt).WithCode(tss.CodeBadProof)
+	}
+	if dlnProof2, err := r1msg3.UnmarshalDLNProof2(); err != nil || !dlnProof2.Verify(H2t, H1t, NTildet) {
+		return round.WrapError(errors.New("dln proof verification failed"), Pt).WithCode(tss.CodeBadProof)
+	}
+	ContextT := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(t)))
+	if !round.Params().NoProofMod() {
+		modProof, err := r1msg3.UnmarshalModProof()
+		if err != nil || !modProof.Verify(ContextT, paillierPKt.N) {
+			return round.WrapError(errors.New("modProof verify failed"), Pt).WithCode(tss.CodeBadProof)
+		}
+	}
+	if !round.Params().NoProofFac() {
+		facProof, err := r2msg2.UnmarshalFacProof()
+		if err != nil || !facProof.Verify(ContextT, round.EC(), paillierPKt.N, round.input.NTildei,
+			round.input.H1i, round.input.H2i) {
+			return round.WrapError(errors.New("facProof verify failed"), Pt).WithCode(tss.CodeBadProof)
+		}
+	}
+
+	// 2. include the new party in the key
+	BigXt, err := round.targetBigX(round.input)
+	if err != nil {
+		return round.WrapError(err)
+	}
+	insertParty(round.save, Pt.KeyInt(), BigXt, NTildet, H1t, H2t, paillierPKt)
+	return nil
+}
+
+func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *round3) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *round3) NextRound() tss.Round {
+	return nil // finished!
+}
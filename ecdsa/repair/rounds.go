@@ -0,0 +1,208 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package repair
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	TaskName = "ecdsa-repair"
+)
+
+type (
+	base struct {
+		*tss.Parameters
+		input, save *keygen.LocalPartySaveData
+		temp        *localTempData
+		out         chan<- tss.Message
+		end         chan<- *keygen.LocalPartySaveData
+		ok          []bool // `ok` tracks parties which have been verified by Update()
+		started     bool
+		number      int
+	}
+	round1 struct {
+		*base
+	}
+	round2 struct {
+		*round1
+	}
+	round3 struct {
+		*round2
+	}
+)
+
+var (
+	_ tss.Round = (*round1)(nil)
+	_ tss.Round = (*round2)(nil)
+	_ tss.Round = (*round3)(nil)
+)
+
+// ----- //
+
+func (round *base) Params() *tss.Parameters {
+	return round.Parameters
+}
+
+func (round *base) RoundNumber() int {
+	return round.number
+}
+
+// CanProceed is inherited by other rounds
+func (round *base) CanProceed() bool {
+	if !round.started {
+		return false
+	}
+	for _, ok := range round.ok {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitingFor is called by a Party for reporting back to the caller
+func (round *base) WaitingFor() []*tss.PartyID {
+	Ps := round.Parties().IDs()
+	ids := make([]*tss.PartyID, 0, len(round.ok))
+	for j, ok := range round.ok {
+		if ok {
+			continue
+		}
+		ids = append(ids, Ps[j])
+	}
+	return ids
+}
+
+func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
+	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
+}
+
+// ----- //
+
+// `ok` tracks parties which have been verified by Update()
+func (round *base) resetOK() {
+	for j := range round.ok {
+		round.ok[j] = false
+	}
+}
+
+// expectFromHelpers marks this party and the target party as already verified, so that messages are awaited from the other helpers
+func (round *base) expectFromHelpers() {
+	round.resetOK()
+	round.ok[round.PartyID().Index] = true
+	round.ok[round.temp.targetIdx] = true
+}
+
+// expectNothing marks every party as already verified
+func (round *base) expectNothing() {
+	for j := range round.ok {
+		round.ok[j] = true
+	}
+}
+
+// get ssid from local params
+func (round *base) getSSID() ([]byte, error) {
+	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().B, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
+	ssidList = append(ssidList, round.Parties().IDs().Keys()...)                                                                                // parties
+	ssidList = append(ssidList, round.temp.target.KeyInt())                                                                                     // target party
+	ssidList = append(ssidList, big.NewInt(int64(round.number)))                                                                                // round number
+	ssidList = append(ssidList, round.temp.ssidNonce)
+	ssidList = append(ssidList, round.SessionIDInts()...)
+	ssidHash := round.HashScheme().Tagged(common.PoseidonTagECDSARepairSSID).HashInts(ssidList...)
+	if ssidHash == nil {
+		return nil, round.WrapError(errors.New("hash ssid failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
+	ssid := ssidHash.Bytes()
+
+	return ssid, nil
+}
+
+// lagrange returns the Lagrange coefficient of Pj at the key of the target party, over the helpers of this session
+func (round *base) lagrange(j int) *big.Int {
+	modQ := common.ModInt(round.EC().Params().N)
+	Ps := round.Parties().IDs()
+	kTarget, kj := round.temp.target.KeyInt(), Ps[j].KeyInt()
+	lambda := big.NewInt(1)
+	for c, Pc := range Ps {
+		if c == j || c == round.temp.targetIdx {
+			continue
+		}
+		kc := Pc.KeyInt()
+		lambda = modQ.Mul(lambda, modQ.Mul(modQ.Sub(kTarget, kc), modQ.ModInverse(modQ.Sub(kj, kc))))
+	}
+	return lambda
+}
+
+// targetBigX interpolates X = x*G of the target party from the BigXj of the helpers in `key`
+func (round *base) targetBigX(key *keygen.LocalPartySaveData) (*crypto.ECPoint, error) {
+	var BigX *crypto.ECPoint
+	for j, Pj := range round.Parties().IDs() {
+		if j == round.temp.targetIdx {
+			continue
+		}
+		kIdx := keyIndex(key, Pj.KeyInt())
+		if kIdx < 0 {
+			return nil, fmt.Errorf("party %s does not hold a share of this key", Pj)
+		}
+		BigXj := key.BigXj[kIdx].ScalarMult(round.lagrange(j))
+		if BigX == nil {
+			BigX = BigXj
+			continue
+		}
+		var err error
+		if BigX, err = BigX.Add(BigXj); err != nil {
+			return nil, err
+		}
+	}
+	return BigX, nil
+}
+
+// keyIndex returns the index of the share holder with key `k` in `key`, or -1
+func keyIndex(key *keygen.LocalPartySaveData, k *big.Int) int {
+	for j, kj := range key.Ks {
+		if kj.Cmp(k) == 0 {
+			return j
+		}
+	}
+	return -1
+}
+
+// insertParty adds a share holder to the public data of `key`, keeping the share holders sorted by key.
+// New slices are allocated so that the caller's data is never written into.
+func insertParty(key *keygen.LocalPartySaveData, k *big.Int, BigX *crypto.ECPoint, NTilde, h1, h2 *big.Int, paillierPK *paillier.PublicKey) {
+	at := len(key.Ks)
+	for j, kj := range key.Ks {
+		if k.Cmp(kj) < 0 {
+			at = j
+			break
+		}
+	}
+	insertInt := func(xs []*big.Int, x *big.Int) []*big.Int {
+		res := append(append(make([]*big.Int, 0, len(xs)+1), xs[:at]...), x)
+		return append(res, xs[at:]...)
+	}
+	key.Ks = insertInt(key.Ks, k)
+	key.NTildej = insertInt(key.NTildej, NTilde)
+	key.H1j = insertInt(key.H1j, h1)
+	key.H2j = insertInt(key.H2j, h2)
+	BigXj := append(append(make([]*crypto.ECPoint, 0, len(key.BigXj)+1), key.BigXj[:at]...), BigX)
+	key.BigXj = append(BigXj, key.BigXj[at:]...)
+	paillierPKs := append(append(make([]*paillier.PublicKey, 0, len(key.PaillierPKs)+1), key.PaillierPKs[:at]...), paillierPK)
+	key.PaillierPKs = append(paillierPKs, key.PaillierPKs[at:]...)
+	if key.Metadata != nil {
+		key.Metadata = key.Metadata.WithParties(key.Metadata.Threshold, len(key.Ks))
+	}
+}
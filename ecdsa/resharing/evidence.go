@@ -0,0 +1,105 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// ReasonBadShare blames an old committee party for a share that does not match its committed polynomial.
+	// Messages: DGRound1Message, DGRound3Message2, DGRound3Message1 sent to the accuser.
+	ReasonBadShare = "bad vss share"
+	// ReasonBadDLNProof blames a new committee party for a dln proof that does not verify.
+	// Messages: DGRound2Message1.
+	ReasonBadDLNProof = "bad dln proof"
+)
+
+// VerifyEvidence returns true when the evidence from a failed resharing proves that its culprit misbehaved,
+// or an error when the evidence is malformed. newThreshold is the threshold of the new committee, and scheme the
+// hash scheme of the resharing session. culprit is the party blamed by the evidence as the verifier knows it, with the
+// identity key that its messages must be signed with.
+func VerifyEvidence(ec elliptic.Curve, scheme common.HashScheme, newThreshold int, culprit *tss.PartyID, ev *tss.Evidence) (bool, error) {
+	if ev == nil || ev.Task != TaskName {
+		return false, errors.New("not the evidence of a resharing")
+	}
+	msgs, err := ev.ParseMessages(culprit)
+	if err != nil {
+		return false, err
+	}
+	switch ev.Reason {
+	case ReasonBadShare:
+		if len(msgs) != 3 {
+			return false, fmt.Errorf("expected 3 messages with %q", ev.Reason)
+		}
+		r1msg, ok1 := msgs[0].Content().(*DGRound1Message)
+		r3msg2, ok2 := msgs[1].Content().(*DGRound3Message2)
+		r3msg1, ok3 := msgs[2].Content().(*DGRound3Message1)
+		if !ok1 || !ok2 || !ok3 {
+			return false, fmt.Errorf("unexpected messages with %q", ev.Reason)
+		}
+		return !verifyShare(ec, scheme.Tagged(common.PoseidonTagECDSAResharingVs), newThreshold, ev.Accuser, r1msg, r3msg2, r3msg1), nil
+	case ReasonBadDLNProof:
+		if len(msgs) != 1 {
+			return false, fmt.Errorf("expected 1 message with %q", ev.Reason)
+		}
+		r2msg1, ok := msgs[0].Content().(*DGRound2Message1)
+		if !ok {
+			return false, fmt.Errorf("unexpected messages with %q", ev.Reason)
+		}
+		return !verifyDLNProofs(r2msg1), nil
+	default:
+		return false, fmt.Errorf("unknown evidence reason %q", ev.Reason)
+	}
+}
+
+// verifyShare checks that the share received by Pi opens the committed polynomial of the sender
+func verifyShare(ec elliptic.Curve, hasher common.Hasher, newThreshold int, Pi *tss.PartyID, r1msg *DGRound1Message, r3msg2 *DGRound3Message2, r3msg1 *DGRound3Message1) bool {
+	vj, ok := unpackVs(ec, hasher, newThreshold, r1msg, r3msg2)
+	if !ok {
+		return false
+	}
+	sharej := &vss.Share{
+		Threshold: newThreshold,
+		ID:        Pi.KeyInt(),
+		Share:     new(big.Int).SetBytes(r3msg1.GetShare()),
+	}
+	return sharej.Verify(ec, newThreshold, vj)
+}
+
+// unpackVs de-commits the polynomial commitment of an old committee party
+func unpackVs(ec elliptic.Curve, hasher common.Hasher, newThreshold int, r1msg *DGRound1Message, r3msg2 *DGRound3Message2) ([]*crypto.ECPoint, bool) {
+	vCmtDeCmt := commitments.HashCommitDecommit{C: r1msg.UnmarshalVCommitment(), D: r3msg2.UnmarshalVDeCommitment(), Hasher: hasher}
+	ok, flatVs := vCmtDeCmt.DeCommit()
+	if !ok || len(flatVs) != (newThreshold+1)*2 { // they're points so * 2
+		return nil, false
+	}
+	vj, err := crypto.UnFlattenECPoints(ec, flatVs)
+	if err != nil {
+		return nil, false
+	}
+	return vj, true
+}
+
+func verifyDLNProofs(r2msg1 *DGRound2Message1) bool {
+	NTildej, H1j, H2j := r2msg1.UnmarshalNTilde(), r2msg1.UnmarshalH1(), r2msg1.UnmarshalH2()
+	dlnProof1, err := r2msg1.UnmarshalDLNProof1()
+	if err != nil || !dlnProof1.Verify(H1j, H2j, NTildej) {
+		return false
+	}
+	dlnProof2, err := r2msg1.UnmarshalDLNProof2()
+	return err == nil && dlnProof2.Verify(H2j, H1j, NTildej)
+}
@@ -0,0 +1,148 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+var handoverTag = new(big.Int).SetBytes([]byte("ecdsa-resharing-handover"))
+
+// HandoverStatement describes the new committee of a resharing. The old committee signs its Digest with the key
+// before erasing its shares, so that external verifiers can audit that the new committee was authorized by the old one.
+type HandoverStatement struct {
+	ECDSAPub     *crypto.ECPoint   `json:"ecdsaPub"`
+	NewKs        []*big.Int        `json:"newKs"`
+	NewThreshold int               `json:"newThreshold"`
+	NewBigXj     []*crypto.ECPoint `json:"newBigXj"` // the public commitments to the new shares
+}
+
+// Digest returns the message that the old committee signs, see NewHandoverSigner
+func (st *HandoverStatement) Digest() *big.Int {
+	ints := make([]*big.Int, 0, 5+len(st.NewKs)+2*len(st.NewBigXj))
+	ints = append(ints, handoverTag, st.ECDSAPub.X(), st.ECDSAPub.Y(), big.NewInt(int64(st.NewThreshold)), big.NewInt(int64(len(st.NewKs))))
+	ints = append(ints, st.NewKs...)
+	for _, Xj := range st.NewBigXj {
+		ints = append(ints, Xj.X(), Xj.Y())
+	}
+	return common.SHA512_256i(ints...)
+}
+
+// VerifyHandover checks the signature of the old committee over the statement, made with the key of the statement
+func VerifyHandover(st *HandoverStatement, sig *common.SignatureData) bool {
+	if st == nil || st.ECDSAPub == nil || sig == nil || len(st.NewKs) != len(st.NewBigXj) {
+		return false
+	}
+	pk := ecdsa.PublicKey{
+		Curve: st.ECDSAPub.Curve(),
+		X:     st.ECDSAPub.X(),
+		Y:     st.ECDSAPub.Y(),
+	}
+	return ecdsa.Verify(&pk, st.Digest().Bytes(), new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S))
+}
+
+// newHandoverStatement builds the statement, the new BigXj being those agreed by the new committee
+func (round *base) newHandoverStatement(newBigXjs []*crypto.ECPoint) *HandoverStatement {
+	pub := round.input.ECDSAPub
+	if round.IsNewCommittee() {
+		pub = round.save.ECDSAPub
+	}
+	newKs := make([]*big.Int, 0, round.NewPartyCount())
+	for _, Pj := range round.NewParties().IDs() {
+		newKs = append(newKs, Pj.KeyInt())
+	}
+	return &HandoverStatement{
+		ECDSAPub:     pub,
+		NewKs:        newKs,
+		NewThreshold: round.NewThreshold(),
+		NewBigXj:     newBigXjs,
+	}
+}
+
+// agreedNewBigXjs returns the new BigXj once every party of the new committee has sent the same ones in its "ACK"
+func (round *base) agreedNewBigXjs() ([]*crypto.ECPoint, *tss.Error) {
+	var agreed []*crypto.ECPoint
+	for j, msg := range round.temp.dgRound4Message2s {
+		Pj := round.NewParties().IDs()[j]
+		newBigXjs, err := msg.Content().(*DGRound4Message2).UnmarshalBigXj(round.EC())
+		if err != nil || len(newBigXjs) != round.NewPartyCount() {
+			return nil, round.WrapError(errors.New("got invalid new BigXj for the handover"), Pj).WithCode(tss.CodeBadPublicData)
+		}
+		if agreed == nil {
+			agreed = newBigXjs
+			continue
+		}
+		for c, Xc := range newBigXjs {
+			if !Xc.Equals(agreed[c]) {
+				return nil, round.WrapError(errors.New("the new committee does not agree on the new BigXj"), round.NewParties().IDs()[0], Pj).WithCode(tss.CodeBadPublicData)
+			}
+		}
+	}
+	return agreed, nil
+}
+
+// HandoverSigner is a party of the old committee signing the handover statement, see NewHandoverSigner
+type HandoverSigner struct {
+	*signing.LocalParty
+	xi   *big.Int
+	sigs chan *common.SignatureData
+	end  chan<- *common.SignatureData
+}
+
+// NewHandoverSigner returns the party that signs the handover statement with the old key, once the resharing of a party
+// of the old committee has ended. `params` are those of the signing session among the old committee. When the signature
+// is sent to `end` the share Xi of the old key is erased, as the resharing does without a handover.
+func (p *LocalParty) NewHandoverSigner(params *tss.Parameters, out chan<- tss.Message, end chan<- *common.SignatureData) (*HandoverSigner, error) {
+	if !p.params.IsOldCommittee() || !p.temp.handover {
+		return nil, errors.New("only a party of the old committee made with NewLocalPartyWithHandover signs the handover")
+	}
+	if p.temp.statement == nil {
+		return nil, errors.New("the resharing has not ended")
+	}
+	if params.PartyID().KeyInt().Cmp(p.PartyID().KeyInt()) != 0 {
+		return nil, errors.New("the signing parameters are not those of this party")
+	}
+	sigs := make(chan *common.SignatureData, 1)
+	return &HandoverSigner{
+		LocalParty: signing.NewLocalParty(p.temp.statement.Digest(), params, p.input, out, sigs).(*signing.LocalParty),
+		xi:         p.input.Xi,
+		sigs:       sigs,
+		end:        end,
+	}, nil
+}
+
+func (s *HandoverSigner) Start() *tss.Error {
+	defer s.erase()
+	return s.LocalParty.Start()
+}
+
+func (s *HandoverSigner) Update(msg tss.ParsedMessage) (bool, *tss.Error) {
+	defer s.erase()
+	return s.LocalParty.Update(msg)
+}
+
+func (s *HandoverSigner) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
+	defer s.erase()
+	return s.LocalParty.UpdateFromBytes(wireBytes, from, isBroadcast)
+}
+
+// erase zeroes the old share once the signature has been produced, and passes the signature on
+func (s *HandoverSigner) erase() {
+	select {
+	case sig := <-s.sigs:
+		s.xi.SetInt64(0)
+		s.end <- sig
+	default:
+	}
+}
@@ -34,6 +34,8 @@ type (
 		// outbound messaging
 		out chan<- tss.Message
 		end chan<- *keygen.LocalPartySaveData
+
+		progress *tss.RoundProgress
 	}
 
 	localMessageStore struct {
@@ -61,6 +63,10 @@ type (
 
 		ssid      []byte
 		ssidNonce *big.Int
+
+		// set by NewLocalPartyWithHandover
+		handover  bool
+		statement *HandoverStatement
 	}
 )
 
@@ -68,6 +74,7 @@ type (
 // The `key` is read from and/or written to depending on whether this party is part of the old or the new committee.
 // You may optionally generate and set the LocalPreParams if you would like to use pre-generated safe primes and Paillier secret.
 // (This is similar to providing the `optionalPreParams` to `keygen.LocalParty`).
+// The old committee refuses to reshare the key to a party revoked from it (see keygen.LocalPartySaveData.RevokedKs).
 func NewLocalParty(
 	params *tss.ReSharingParameters,
 	key keygen.LocalPartySaveData,
@@ -87,6 +94,7 @@ func NewLocalParty(
 		save:      keygen.NewLocalPartySaveData(params.NewPartyCount()),
 		out:       out,
 		end:       end,
+		progress:  tss.NewRoundProgress(Rounds),
 	}
 	// msgs init
 	p.temp.dgRound1Messages = make([]tss.ParsedMessage, oldPartyCount)           // from t+1 of Old Committee
@@ -103,22 +111,74 @@ func NewLocalParty(
 	return p
 }
 
+// NewLocalPartyWithHandover is like NewLocalParty, and also builds the handover statement of the resharing, see Handover.
+// Parties of the old committee keep the Xi of their key until they have signed the statement with NewHandoverSigner,
+// which erases it.
+func NewLocalPartyWithHandover(
+	params *tss.ReSharingParameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *keygen.LocalPartySaveData,
+) tss.Party {
+	p := NewLocalParty(params, key, out, end).(*LocalParty)
+	p.temp.handover = true
+	return p
+}
+
+// Handover returns the handover statement once the save data has been received through `end`,
+// or nil when the party was not made with NewLocalPartyWithHandover
+func (p *LocalParty) Handover() *HandoverStatement {
+	return p.temp.statement
+}
+
+// Session is a resharing party with the channels it sends its messages and its new save data on
+type Session struct {
+	*LocalParty
+	Out <-chan tss.Message
+	End <-chan *keygen.LocalPartySaveData
+}
+
+// NewSession returns the session of a party created like NewLocalParty, with channels that buffer `buffer` messages
+func NewSession(params *tss.ReSharingParameters, key keygen.LocalPartySaveData, buffer int) *Session {
+	out, end := make(chan tss.Message, buffer), make(chan *keygen.LocalPartySaveData, 1)
+	return &Session{
+		LocalParty: NewLocalParty(params, key, out, end).(*LocalParty),
+		Out:        out,
+		End:        end,
+	}
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
 	return newRound1(p.params, &p.input, &p.save, &p.temp, p.out, p.end)
 }
 
+// SetOnRoundComplete registers `fn` to be called with the number of each round once this party has completed it.
+// Resharing can take minutes, so this lets a UI follow the ceremony; it must be set before Start.
+// The last round is reported just after the save data has been sent to `end`.
+func (p *LocalParty) SetOnRoundComplete(fn func(round int)) {
+	p.progress.OnRoundComplete = fn
+}
+
+// SetOnProgress registers `fn` to be called with the percentage of the rounds this party has completed.
+// It must be set before Start.
+func (p *LocalParty) SetOnProgress(fn func(percent float64)) {
+	p.progress.OnProgress = fn
+}
+
 func (p *LocalParty) Start() *tss.Error {
+	defer p.progress.Report(p.BaseParty)
 	return tss.BaseStart(p, TaskName)
 }
 
 func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
+	defer p.progress.Report(p.BaseParty)
 	return tss.BaseUpdate(p, msg, TaskName)
 }
 
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
 	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
-		return false, p.WrapError(err)
+		return false, p.WrapError(err).WithCode(tss.CodeInvalidMessage)
 	}
 	return p.Update(msg)
 }
@@ -137,7 +197,7 @@ func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	}
 	if maxFromIdx < msg.GetFrom().Index {
 		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
-			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+			maxFromIdx, msg.GetFrom().Index), msg.GetFrom()).WithCode(tss.CodeInvalidMessage)
 	}
 	return true, nil
 }
@@ -0,0 +1,143 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestE2EBadShareIsBlamed(t *testing.T) {
+	setUp("info")
+
+	threshold, newThreshold := testThreshold, testThreshold
+
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	oldP2PCtx := tss.NewPeerContext(oldPIDs)
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	newP2PCtx := tss.NewPeerContext(newPIDs)
+	newPCount := len(newPIDs)
+
+	// the evidence holds messages signed by the culprit, so every party has an identity key
+	identityKeys := make(map[*tss.PartyID]ed25519.PrivateKey)
+	for _, pID := range append(append(tss.SortedPartyIDs{}, oldPIDs...), newPIDs...) {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		pID.IdentityKey, identityKeys[pID] = pub, priv
+	}
+
+	errCh := make(chan *tss.Error, len(oldPIDs)+newPCount)
+	outCh := make(chan tss.Message, len(oldPIDs)+newPCount)
+	endCh := make(chan *keygen.LocalPartySaveData, len(oldPIDs)+newPCount)
+
+	oldCommittee := make([]*LocalParty, 0, len(oldPIDs))
+	var cheaterParams *tss.ReSharingParameters
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		params.SetIdentityKey(identityKeys[pID])
+		if j == 0 {
+			cheaterParams = params
+		}
+		oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty))
+	}
+	newCommittee := make([]*LocalParty, 0, newPCount)
+	for j, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		// do not use in untrusted setting
+		params.SetNoProofMod()
+		// do not use in untrusted setting
+		params.SetNoProofFac()
+		params.SetIdentityKey(identityKeys[pID])
+		save := keygen.NewLocalPartySaveData(newPCount)
+		save.LocalPreParams = fixtures[j].LocalPreParams
+		newCommittee = append(newCommittee, NewLocalParty(params, save, outCh, endCh).(*LocalParty))
+	}
+	for _, P := range append(newCommittee, oldCommittee...) {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	// the first old party deals a bad share to every new party
+	cheater := oldCommittee[0].PartyID()
+	updater := test.SharedPartyUpdater
+	errs := make([]*tss.Error, 0, newPCount)
+	for len(errs) < newPCount {
+		select {
+		case err := <-errCh:
+			errs = append(errs, err)
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if r3msg1, ok := msg.(tss.ParsedMessage).Content().(*DGRound3Message1); ok && msg.GetFrom() == cheater {
+				share := new(big.Int).Add(new(big.Int).SetBytes(r3msg1.GetShare()), big.NewInt(1))
+				msg = cheaterParams.Outbound(NewDGRound3Message1(dest[0], cheater, &vss.Share{Share: share}))
+			}
+			if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest[:len(oldCommittee)] {
+					go updater(oldCommittee[destP.Index], msg, errCh)
+				}
+			}
+			if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest {
+					go updater(newCommittee[destP.Index], msg, errCh)
+				}
+			}
+		case <-endCh:
+			assert.FailNow(t, "resharing should not end with a bad share")
+		}
+	}
+
+	for _, err := range errs {
+		if assert.Len(t, err.Culprits(), 1) {
+			assert.Equal(t, cheater.KeyInt(), err.Culprits()[0].KeyInt(), "the cheater should be the culprit")
+		}
+		if !assert.Len(t, err.Evidence(), 1) {
+			continue
+		}
+		bz, jErr := json.Marshal(err.Evidence()[0])
+		assert.NoError(t, jErr)
+		ev := new(tss.Evidence)
+		assert.NoError(t, json.Unmarshal(bz, ev))
+		assert.Equal(t, ReasonBadShare, ev.Reason)
+		assert.Equal(t, err.Victim().KeyInt(), ev.Accuser.KeyInt())
+
+		guilty, vErr := VerifyEvidence(tss.S256(), common.HashSHA512_256, newThreshold, cheater, ev)
+		assert.NoError(t, vErr)
+		assert.True(t, guilty, "the evidence should prove the bad share")
+
+		_, vErr = VerifyEvidence(tss.S256(), common.HashSHA512_256, newThreshold, oldPIDs[1], ev)
+		assert.Error(t, vErr, "messages from the cheater cannot blame another party")
+
+		accuser := ev.Accuser
+		ev.Accuser = newPIDs[(accuser.Index+1)%newPCount]
+		_, vErr = VerifyEvidence(tss.S256(), common.HashSHA512_256, newThreshold, cheater, ev)
+		assert.Error(t, vErr, "a share sent to another party cannot be claimed by the accuser")
+		ev.Accuser = accuser
+
+		forged := ev.Messages[2].WireBytes
+		forged[len(forged)-1] ^= 1
+		_, vErr = VerifyEvidence(tss.S256(), common.HashSHA512_256, newThreshold, cheater, ev)
+		assert.Error(t, vErr, "a message altered by the accuser should be rejected")
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/ecdsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestE2EHandover(t *testing.T) {
+	setUp("info")
+
+	threshold, newThreshold := testThreshold, testThreshold
+
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	oldP2PCtx := tss.NewPeerContext(oldPIDs)
+	fixtures, _, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	newP2PCtx := tss.NewPeerContext(newPIDs)
+	newPCount := len(newPIDs)
+
+	errCh := make(chan *tss.Error, len(oldPIDs)+newPCount)
+	outCh := make(chan tss.Message, len(oldPIDs)+newPCount)
+	endCh := make(chan *keygen.LocalPartySaveData, len(oldPIDs)+newPCount)
+
+	oldCommittee := make([]*LocalParty, 0, len(oldPIDs))
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		oldCommittee = append(oldCommittee, NewLocalPartyWithHandover(params, oldKeys[j], outCh, endCh).(*LocalParty))
+	}
+	newCommittee := make([]*LocalParty, 0, newPCount)
+	for j, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.S256(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		// do not use in untrusted setting
+		params.SetNoProofMod()
+		// do not use in untrusted setting
+		params.SetNoProofFac()
+		save := keygen.NewLocalPartySaveData(newPCount)
+		save.LocalPreParams = fixtures[j].LocalPreParams
+		newCommittee = append(newCommittee, NewLocalPartyWithHandover(params, save, outCh, endCh).(*LocalParty))
+	}
+	for _, P := range append(newCommittee, oldCommittee...) {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	updater := test.SharedPartyUpdater
+	newKeys := make([]keygen.LocalPartySaveData, newPCount)
+	for ended := 0; ended < len(oldCommittee)+newPCount; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest[:len(oldCommittee)] {
+					go updater(oldCommittee[destP.Index], msg, errCh)
+				}
+			}
+			if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest {
+					go updater(newCommittee[destP.Index], msg, errCh)
+				}
+			}
+		case save := <-endCh:
+			if save.Xi != nil {
+				index, err := save.OriginalIndex()
+				assert.NoError(t, err)
+				newKeys[index] = *save
+			}
+			ended++
+		}
+	}
+
+	// every party holds the same statement, committing to the new shares
+	statement := oldCommittee[0].Handover()
+	if !assert.NotNil(t, statement) {
+		return
+	}
+	for _, P := range append(newCommittee, oldCommittee...) {
+		assert.Equal(t, statement.Digest(), P.Handover().Digest())
+	}
+	for j, key := range newKeys {
+		assert.True(t, key.BigXj[j].Equals(statement.NewBigXj[j]))
+		assert.Equal(t, 0, key.ShareID.Cmp(statement.NewKs[j]))
+	}
+
+	// the old committee still holds its key, and erases it once the statement is signed with it
+	for _, key := range oldKeys {
+		assert.NotEqual(t, 0, key.Xi.Sign(), "the old committee should keep its key until the statement is signed")
+	}
+	_, err = newCommittee[0].NewHandoverSigner(tss.NewParameters(tss.S256(), oldP2PCtx, oldPIDs[0], len(oldPIDs), threshold), nil, nil)
+	assert.Error(t, err, "the new committee should not sign the handover")
+	sig := signHandover(t, oldCommittee, oldPIDs, threshold)
+	assert.True(t, VerifyHandover(statement, sig), "the handover should verify")
+	for _, key := range oldKeys {
+		assert.Equal(t, 0, key.Xi.Sign(), "the old committee should erase its key once the statement is signed")
+	}
+
+	forged := *statement
+	forged.NewThreshold++
+	assert.False(t, VerifyHandover(&forged, sig), "a changed statement should not verify")
+}
+
+func signHandover(t *testing.T, oldCommittee []*LocalParty, pIDs tss.SortedPartyIDs, threshold int) *common.SignatureData {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	signers := make([]*HandoverSigner, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *common.SignatureData, len(pIDs))
+
+	for j, pID := range pIDs {
+		params := tss.NewParameters(tss.S256(), p2pCtx, pID, len(pIDs), threshold)
+		P, err := oldCommittee[j].NewHandoverSigner(params, outCh, endCh)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		signers = append(signers, P)
+		go func(P *HandoverSigner) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	updater := test.SharedPartyUpdater
+	var sig *common.SignatureData
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range signers {
+					if P.PartyID().Index != msg.GetFrom().Index {
+						go updater(P, msg, errCh)
+					}
+				}
+			} else {
+				go updater(signers[dest[0].Index], msg, errCh)
+			}
+		case sig = <-endCh:
+			ended++
+		}
+	}
+	return sig
+}
@@ -243,23 +243,35 @@ func (m *DGRound3Message2) UnmarshalVDeCommitment() cmt.HashDeCommitment {
 
 // ----- //
 
+// NewDGRound4Message2 sends the "ACK" along with the new BigXj, which the old committee needs for the handover statement
 func NewDGRound4Message2(
 	to []*tss.PartyID,
 	from *tss.PartyID,
-) tss.ParsedMessage {
+	newBigXjs []*crypto.ECPoint,
+) (tss.ParsedMessage, error) {
 	meta := tss.MessageRouting{
 		From:                    from,
 		To:                      to,
 		IsBroadcast:             true,
 		IsToOldAndNewCommittees: true,
 	}
-	content := &DGRound4Message2{}
+	bigXjFlat, err := crypto.FlattenECPoints(newBigXjs)
+	if err != nil {
+		return nil, err
+	}
+	content := &DGRound4Message2{
+		BigXj: common.BigIntsToBytes(bigXjFlat),
+	}
 	msg := tss.NewMessageWrapper(meta, content)
-	return tss.NewMessage(meta, content, msg)
+	return tss.NewMessage(meta, content, msg), nil
 }
 
 func (m *DGRound4Message2) ValidateBasic() bool {
-	return true
+	return m != nil
+}
+
+func (m *DGRound4Message2) UnmarshalBigXj(ec elliptic.Curve) ([]*crypto.ECPoint, error) {
+	return crypto.UnFlattenECPoints(ec, common.MultiBytesToBigInts(m.GetBigXj()))
 }
 
 func NewDGRound4Message1(
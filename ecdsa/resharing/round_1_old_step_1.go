@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
@@ -28,7 +29,7 @@ func newRound1(params *tss.ReSharingParameters, input, save *keygen.LocalPartySa
 
 func (round *round1) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 1
 	round.started = true
@@ -39,6 +40,17 @@ func (round *round1) Start() *tss.Error {
 		return nil
 	}
 	round.allOldOK()
+	if err := round.ValidateHashScheme(round.input.HashScheme); err != nil {
+		return round.WrapError(err)
+	}
+	if round.input.AccessStructure != nil {
+		return round.WrapError(errors.New("keys shared along an access structure cannot be reshared")).WithCode(tss.CodeInvalidInput)
+	}
+	for _, Pj := range round.NewParties().IDs() {
+		if round.input.IsRevoked(Pj.KeyInt()) {
+			return round.WrapError(fmt.Errorf("party %s was revoked from this key", Pj)).WithCode(tss.CodeInvalidInput)
+		}
+	}
 
 	round.temp.ssidNonce = new(big.Int).SetUint64(uint64(0))
 	ssid, err := round.getSSID()
@@ -52,7 +64,7 @@ func (round *round1) Start() *tss.Error {
 	// 1. PrepareForSigning() -> w_i
 	xi, ks, bigXj := round.input.Xi, round.input.Ks, round.input.BigXj
 	if round.Threshold()+1 > len(ks) {
-		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks)), round.PartyID())
+		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks)), round.PartyID()).WithCode(tss.CodeInvalidInput)
 	}
 	newKs := round.NewParties().IDs().Keys()
 	wi, _ := signing.PrepareForSigning(round.Params().EC(), i, len(round.OldParties().IDs()), xi, ks, bigXj)
@@ -68,7 +80,7 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err, round.PartyID())
 	}
-	vCmt := commitments.NewHashCommitment(round.Rand(), flatVis...)
+	vCmt := commitments.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagECDSAResharingVs), round.Rand(), flatVis...)
 
 	// 4. populate temp data
 	round.temp.VD = vCmt.D
@@ -79,7 +91,7 @@ func (round *round1) Start() *tss.Error {
 		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
 		round.input.ECDSAPub, vCmt.C, ssid)
 	round.temp.dgRound1Messages[i] = r1msg
-	round.out <- r1msg
+	round.out <- round.Outbound(r1msg)
 
 	return nil
 }
@@ -117,12 +129,12 @@ func (round *round1) Update() (bool, *tss.Error) {
 		r1msg := round.temp.dgRound1Messages[0].Content().(*DGRound1Message)
 		candidate, err := r1msg.UnmarshalECDSAPub(round.Params().EC())
 		if err != nil {
-			return false, round.WrapError(errors.New("unable to unmarshal the ecdsa pub key"), msg.GetFrom())
+			return false, round.WrapError(errors.New("unable to unmarshal the ecdsa pub key"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		if round.save.ECDSAPub != nil &&
 			!candidate.Equals(round.save.ECDSAPub) {
 			// uh oh - anomaly!
-			return false, round.WrapError(errors.New("ecdsa pub key did not match what we received previously"), msg.GetFrom())
+			return false, round.WrapError(errors.New("ecdsa pub key did not match what we received previously"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		round.save.ECDSAPub = candidate
 	}
@@ -8,6 +8,7 @@ package resharing
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"math/big"
 
@@ -22,7 +23,7 @@ var zero = big.NewInt(0)
 
 func (round *round2) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 2
 	round.started = true
@@ -46,7 +47,7 @@ func (round *round2) Start() *tss.Error {
 		r1msg := round.temp.dgRound1Messages[j].Content().(*DGRound1Message)
 		SSIDj := r1msg.UnmarshalSSID()
 		if !bytes.Equal(SSID, SSIDj) {
-			return round.WrapError(errors.New("ssid mismatch"), Pj)
+			return round.WrapError(errors.New("ssid mismatch"), Pj).WithCode(tss.CodeWrongSession)
 		}
 	}
 	round.temp.ssid = SSID
@@ -55,7 +56,7 @@ func (round *round2) Start() *tss.Error {
 	r2msg1 := NewDGRound2Message2(
 		round.OldParties().IDs().Exclude(round.PartyID()), round.PartyID())
 	round.temp.dgRound2Message2s[i] = r2msg1
-	round.out <- r2msg1
+	round.out <- round.Outbound(r2msg1)
 
 	// 1.
 	// generate Paillier public key E_i, private key and proof
@@ -69,10 +70,12 @@ func (round *round2) Start() *tss.Error {
 	} else if round.save.LocalPreParams.ValidateWithProof() {
 		preParams = &round.save.LocalPreParams
 	} else {
+		ctx, cancel := context.WithTimeout(round.Context(), round.SafePrimeGenTimeout())
+		defer cancel()
 		var err error
-		preParams, err = keygen.GeneratePreParams(round.SafePrimeGenTimeout(), round.Concurrency())
+		preParams, err = keygen.GeneratePreParamsWithContextAndRandom(ctx, round.Rand(), round.Concurrency())
 		if err != nil {
-			return round.WrapError(errors.New("pre-params generation failed"), Pi)
+			return round.WrapError(errors.New("pre-params generation failed"), Pi).WithCode(tss.CodeInternal)
 		}
 	}
 	round.save.LocalPreParams = *preParams
@@ -106,7 +109,7 @@ func (round *round2) Start() *tss.Error {
 		return round.WrapError(err, Pi)
 	}
 	round.temp.dgRound2Message1s[i] = r2msg2
-	round.out <- r2msg2
+	round.out <- round.Outbound(r2msg2)
 
 	// for this P: SAVE de-commitments, paillier keys for round 2
 	round.save.PaillierSK = preParams.PaillierSK
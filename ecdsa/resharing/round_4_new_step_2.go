@@ -18,15 +18,13 @@ import (
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
-	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
-	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 func (round *round4) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 4
 	round.started = true
@@ -63,14 +61,14 @@ func (round *round4) Start() *tss.Error {
 			r2msg1.UnmarshalH1(),
 			r2msg1.UnmarshalH2()
 		if H1j.Cmp(H2j) == 0 {
-			return round.WrapError(errors.New("h1j and h2j were equal for this party"), msg.GetFrom())
+			return round.WrapError(errors.New("h1j and h2j were equal for this party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		h1JHex, h2JHex := hex.EncodeToString(H1j.Bytes()), hex.EncodeToString(H2j.Bytes())
 		if _, found := h1H2Map[h1JHex]; found {
-			return round.WrapError(errors.New("this h1j was already used by another party"), msg.GetFrom())
+			return round.WrapError(errors.New("this h1j was already used by another party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		if _, found := h1H2Map[h2JHex]; found {
-			return round.WrapError(errors.New("this h2j was already used by another party"), msg.GetFrom())
+			return round.WrapError(errors.New("this h2j was already used by another party"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		h1H2Map[h1JHex], h1H2Map[h2JHex] = struct{}{}, struct{}{}
 		wg.Add(3)
@@ -108,11 +106,25 @@ func (round *round4) Start() *tss.Error {
 		})
 	}
 	wg.Wait()
-	for _, culprit := range append(append(paiProofCulprits, dlnProof1FailCulprits...), dlnProof2FailCulprits...) {
-		if culprit != nil {
-			return round.WrapError(errors.New("dln proof verification failed"), culprit)
+	culprits := make([]*tss.PartyID, 0, len(round.temp.dgRound2Message1s))
+	evidence := make([]*tss.Evidence, 0, len(round.temp.dgRound2Message1s))
+	for j, msg := range round.temp.dgRound2Message1s {
+		badDLNProof := dlnProof1FailCulprits[j] != nil || dlnProof2FailCulprits[j] != nil
+		if paiProofCulprits[j] == nil && !badDLNProof {
+			continue
+		}
+		culprits = append(culprits, msg.GetFrom())
+		if badDLNProof {
+			ev, err := round.newEvidence(ReasonBadDLNProof, msg.GetFrom(), msg)
+			if err != nil {
+				return round.WrapError(err, Pi)
+			}
+			evidence = append(evidence, ev)
 		}
 	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("dln proof verification failed"), culprits...).WithCode(tss.CodeBadProof).WithEvidence(evidence...)
+	}
 	// save NTilde_j, h1_j, h2_j received in NewCommitteeStep1 here
 	for j, msg := range round.temp.dgRound2Message1s {
 		if j == i {
@@ -130,40 +142,32 @@ func (round *round4) Start() *tss.Error {
 	// 5-9.
 	modQ := common.ModInt(round.Params().EC().Params().N)
 	vjc := make([][]*crypto.ECPoint, len(round.OldParties().IDs()))
+	culprits = make([]*tss.PartyID, 0, len(vjc))
+	evidence = make([]*tss.Evidence, 0, len(vjc))
 	for j := 0; j <= len(vjc)-1; j++ { // P1..P_t+1. Ps are indexed from 0 here
 		// 6-7.
 		r1msg := round.temp.dgRound1Messages[j].Content().(*DGRound1Message)
 		r3msg2 := round.temp.dgRound3Message2s[j].Content().(*DGRound3Message2)
-
-		vCj, vDj := r1msg.UnmarshalVCommitment(), r3msg2.UnmarshalVDeCommitment()
-
-		// 6. unpack flat "v" commitment content
-		vCmtDeCmt := commitments.HashCommitDecommit{C: vCj, D: vDj}
-		ok, flatVs := vCmtDeCmt.DeCommit()
-		if !ok || len(flatVs) != (round.NewThreshold()+1)*2 { // they're points so * 2
-			// TODO collect culprits and return a list of them as per convention
-			return round.WrapError(errors.New("de-commitment of v_j0..v_jt failed"), round.Parties().IDs()[j])
-		}
-		vj, err := crypto.UnFlattenECPoints(round.Params().EC(), flatVs)
-		if err != nil {
-			return round.WrapError(err, round.Parties().IDs()[j])
-		}
-		vjc[j] = vj
-
-		// 8.
 		r3msg1 := round.temp.dgRound3Message1s[j].Content().(*DGRound3Message1)
-		sharej := &vss.Share{
-			Threshold: round.NewThreshold(),
-			ID:        round.PartyID().KeyInt(),
-			Share:     new(big.Int).SetBytes(r3msg1.Share),
-		}
-		if ok := sharej.Verify(round.Params().EC(), round.NewThreshold(), vj); !ok {
-			// TODO collect culprits and return a list of them as per convention
-			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
+
+		// 6. unpack flat "v" commitment content, 8. verify the share against it
+		if !verifyShare(round.Params().EC(), round.HashScheme().Tagged(common.PoseidonTagECDSAResharingVs), round.NewThreshold(), Pi, r1msg, r3msg2, r3msg1) {
+			Pj := round.OldParties().IDs()[j]
+			ev, err := round.newEvidence(ReasonBadShare, Pj,
+				round.temp.dgRound1Messages[j], round.temp.dgRound3Message2s[j], round.temp.dgRound3Message1s[j])
+			if err != nil {
+				return round.WrapError(err, Pi)
+			}
+			culprits, evidence = append(culprits, Pj), append(evidence, ev)
+			continue
 		}
+		vjc[j], _ = unpackVs(round.Params().EC(), round.HashScheme().Tagged(common.PoseidonTagECDSAResharingVs), round.NewThreshold(), r1msg, r3msg2)
 
 		// 9.
-		newXi = new(big.Int).Add(newXi, sharej.Share)
+		newXi = new(big.Int).Add(newXi, new(big.Int).SetBytes(r3msg1.Share))
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("share from old committee did not pass Verify()"), culprits...).WithCode(tss.CodeBadShare).WithEvidence(evidence...)
 	}
 
 	// 10-13.
@@ -181,7 +185,7 @@ func (round *round4) Start() *tss.Error {
 
 	// 14.
 	if !Vc[0].Equals(round.save.ECDSAPub) {
-		return round.WrapError(errors.New("assertion failed: V_0 != y"), round.PartyID())
+		return round.WrapError(errors.New("assertion failed: V_0 != y"), round.PartyID()).WithCode(tss.CodeVerificationFailed)
 	}
 
 	// 15-19.
@@ -229,13 +233,16 @@ func (round *round4) Start() *tss.Error {
 			}
 		}
 		r4msg1 := NewDGRound4Message1(Pj, Pi, facProof)
-		round.out <- r4msg1
+		round.out <- round.Outbound(r4msg1)
 	}
 
 	// Send an "ACK" message to both committees to signal that we're ready to save our data
-	r4msg2 := NewDGRound4Message2(round.OldAndNewParties(), Pi)
+	r4msg2, err := NewDGRound4Message2(round.OldAndNewParties(), Pi, newBigXjs)
+	if err != nil {
+		return round.WrapError(err, Pi)
+	}
 	round.temp.dgRound4Message2s[i] = r4msg2
-	round.out <- r4msg2
+	round.out <- round.Outbound(r4msg2)
 
 	return nil
 }
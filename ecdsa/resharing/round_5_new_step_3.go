@@ -16,7 +16,7 @@ import (
 
 func (round *round5) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 5
 	round.started = true
@@ -35,6 +35,9 @@ func (round *round5) Start() *tss.Error {
 		round.save.ShareID = round.PartyID().KeyInt()
 		round.save.Xi = round.temp.newXi
 		round.save.Ks = round.temp.newKs
+		round.save.HashScheme = round.HashScheme()
+		round.save.Metadata = tss.NewKeyMetadata(round.EC(), round.NewThreshold(), round.NewPartyCount(), round.HashScheme())
+		round.save.Metadata.KeyID = round.save.KeyID()
 
 		// misc: build list of paillier public keys to save
 		for j, msg := range round.temp.dgRound2Message1s {
@@ -65,8 +68,20 @@ func (round *round5) Start() *tss.Error {
 			}
 
 		}
+		if round.temp.handover {
+			round.temp.statement = round.newHandoverStatement(round.temp.newBigXjs)
+		}
 	} else if round.IsOldCommittee() {
-		round.input.Xi.SetInt64(0)
+		if round.temp.handover {
+			newBigXjs, err := round.agreedNewBigXjs()
+			if err != nil {
+				return err
+			}
+			// the key is erased by the HandoverSigner once the statement is signed
+			round.temp.statement = round.newHandoverStatement(newBigXjs)
+		} else {
+			round.input.Xi.SetInt64(0)
+		}
 	}
 
 	round.end <- round.save
@@ -0,0 +1,43 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// DERSignature returns the ASN.1 DER encoding of the signature, SEQUENCE { r INTEGER, s INTEGER },
+// as produced by crypto/ecdsa.SignASN1 and expected by Bitcoin and X.509
+func DERSignature(data *common.SignatureData) ([]byte, error) {
+	if data == nil || len(data.R) == 0 || len(data.S) == 0 {
+		return nil, errors.New("signature is incomplete")
+	}
+	return asn1.Marshal(struct {
+		R, S *big.Int
+	}{
+		new(big.Int).SetBytes(data.R),
+		new(big.Int).SetBytes(data.S),
+	})
+}
+
+// CompactSignature returns the signature as R || S, each left-padded to `size` bytes (32 for secp256k1)
+func CompactSignature(data *common.SignatureData, size int) ([]byte, error) {
+	if data == nil || len(data.R) == 0 || len(data.S) == 0 {
+		return nil, errors.New("signature is incomplete")
+	}
+	if len(data.R) > size || len(data.S) > size {
+		return nil, errors.New("signature does not fit the requested size")
+	}
+	sig := make([]byte, 2*size)
+	copy(sig[size-len(data.R):size], data.R)
+	copy(sig[2*size-len(data.S):], data.S)
+	return sig, nil
+}
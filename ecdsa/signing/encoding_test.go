@@ -0,0 +1,44 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestDERAndCompactSignature(t *testing.T) {
+	data := &common.SignatureData{
+		R: []byte{0x80, 1},
+		S: []byte{2},
+	}
+	der, err := DERSignature(data)
+	assert.NoError(t, err)
+	var parsed struct {
+		R, S *big.Int
+	}
+	_, err = asn1.Unmarshal(der, &parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0x8001), parsed.R.Int64())
+	assert.Equal(t, int64(2), parsed.S.Int64())
+
+	compact, err := CompactSignature(data, 32)
+	assert.NoError(t, err)
+	assert.Len(t, compact, 64)
+	assert.Equal(t, []byte{0x80, 1}, compact[30:32])
+	assert.Equal(t, byte(2), compact[63])
+
+	_, err = CompactSignature(data, 1)
+	assert.Error(t, err, "R does not fit in 1 byte")
+	_, err = DERSignature(&common.SignatureData{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Keccak256 returns the legacy Keccak-256 digest of the message, the hash that Ethereum signs
+func Keccak256(msg []byte) []byte {
+	state := sha3.NewLegacyKeccak256()
+	state.Write(msg)
+	return state.Sum(nil)
+}
+
+// NewLocalPartyWithKeccak256 returns a party that signs the Keccak-256 digest of `msg`, so that ecrecover of that
+// digest with the EthereumSignature returns the address of the key. SignatureData.M is the digest.
+func NewLocalPartyWithKeccak256(
+	msg []byte,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) tss.Party {
+	return NewLocalPartyWithDigest(Keccak256(msg), params, key, out, end)
+}
+
+// EthereumSignature returns the 65-byte [R || S || V] signature with V in {0, 1}, the format returned by
+// go-ethereum's crypto.Sign and accepted by crypto.Ecrecover
+func EthereumSignature(data *common.SignatureData) ([]byte, error) {
+	recid, err := ethereumRecoveryID(data)
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]byte, 65)
+	copy(sig[32-len(data.R):32], data.R)
+	copy(sig[64-len(data.S):64], data.S)
+	sig[64] = recid
+	return sig, nil
+}
+
+// EthereumV returns the transaction signature V: 27 + recid for legacy transactions when `chainID` is nil,
+// or chainID * 2 + 35 + recid as per EIP-155 otherwise
+func EthereumV(data *common.SignatureData, chainID *big.Int) (*big.Int, error) {
+	recid, err := ethereumRecoveryID(data)
+	if err != nil {
+		return nil, err
+	}
+	if chainID == nil {
+		return big.NewInt(27 + int64(recid)), nil
+	}
+	v := new(big.Int).Lsh(chainID, 1)
+	return v.Add(v, big.NewInt(35+int64(recid))), nil
+}
+
+func ethereumRecoveryID(data *common.SignatureData) (byte, error) {
+	if data == nil || len(data.R) == 0 || len(data.R) > 32 || len(data.S) == 0 || len(data.S) > 32 {
+		return 0, errors.New("signature is not a secp256k1 signature")
+	}
+	if len(data.SignatureRecovery) != 1 {
+		return 0, errors.New("signature has no recovery id")
+	}
+	// ids 2 and 3 mean R.x overflowed the curve order, which Ethereum cannot encode
+	if recid := data.SignatureRecovery[0]; recid <= 1 {
+		return recid, nil
+	}
+	return 0, errors.New("signature recovery id cannot be encoded for Ethereum")
+}
@@ -0,0 +1,50 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+func TestEthereumSignature(t *testing.T) {
+	data := &common.SignatureData{
+		R:                 []byte{1},
+		S:                 []byte{2, 3},
+		SignatureRecovery: []byte{1},
+	}
+	sig, err := EthereumSignature(data)
+	assert.NoError(t, err)
+	assert.Len(t, sig, 65)
+	assert.Equal(t, byte(1), sig[31], "R should be left-padded")
+	assert.Equal(t, []byte{2, 3}, sig[62:64], "S should be left-padded")
+	assert.Equal(t, byte(1), sig[64])
+
+	v, err := EthereumV(data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(28), v.Int64())
+	v, err = EthereumV(data, big.NewInt(1))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(38), v.Int64(), "EIP-155 v for mainnet")
+
+	data.SignatureRecovery = []byte{2}
+	_, err = EthereumSignature(data)
+	assert.Error(t, err, "overflowed R.x cannot be encoded")
+	data.SignatureRecovery = nil
+	_, err = EthereumV(data, nil)
+	assert.Error(t, err, "missing recovery id")
+}
+
+func TestKeccak256(t *testing.T) {
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(Keccak256(nil)))
+	assert.Equal(t, "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45", hex.EncodeToString(Keccak256([]byte("abc"))))
+}
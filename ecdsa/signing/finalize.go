@@ -18,7 +18,7 @@ import (
 
 func (round *finalization) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 10
 	round.started = true
@@ -50,7 +50,7 @@ func (round *finalization) Start() *tss.Error {
 	// This is needed because of tendermint checks here:
 	// https://github.com/tendermint/tendermint/blob/d9481e3648450cb99e15c6a070c1fb69aa0c255b/crypto/secp256k1/secp256k1_nocgo.go#L43-L47
 	secp256k1halfN := new(big.Int).Rsh(round.Params().EC().Params().N, 1)
-	if sumS.Cmp(secp256k1halfN) > 0 {
+	if !round.NoLowS() && sumS.Cmp(secp256k1halfN) > 0 {
 		sumS.Sub(round.Params().EC().Params().N, sumS)
 		recid ^= 1
 	}
@@ -61,7 +61,9 @@ func (round *finalization) Start() *tss.Error {
 	round.data.S = padToLengthBytesInPlace(sumS.Bytes(), bitSizeInBytes)
 	round.data.Signature = append(round.data.R, round.data.S...)
 	round.data.SignatureRecovery = []byte{byte(recid)}
-	if round.temp.fullBytesLen == 0 {
+	if round.temp.digest != nil {
+		round.data.M = round.temp.digest
+	} else if round.temp.fullBytesLen == 0 {
 		round.data.M = round.temp.m.Bytes()
 	} else {
 		var mBytes = make([]byte, round.temp.fullBytesLen)
@@ -77,7 +79,11 @@ func (round *finalization) Start() *tss.Error {
 
 	ok := ecdsa.Verify(&pk, round.data.M, round.temp.rx, sumS)
 	if !ok {
-		return round.WrapError(fmt.Errorf("signature verification failed"))
+		return round.WrapError(fmt.Errorf("signature verification failed")).WithCode(tss.CodeVerificationFailed)
+	}
+
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
 	}
 
 	round.end <- round.data
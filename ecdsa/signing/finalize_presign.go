@@ -0,0 +1,57 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func (round *presignFinalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
+	}
+	round.number = 5
+	round.started = true
+	round.resetOK()
+
+	// compute R exactly as round 5 would
+	if err := round.computeR(); err != nil {
+		return err
+	}
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
+	}
+
+	round.preEnd <- &PreSignatureData{
+		K:     round.temp.k,
+		Sigma: round.temp.sigma,
+		BigR:  round.temp.bigR,
+		SSID:  round.temp.ssid,
+		Ks:    round.Parties().IDs().Keys(),
+	}
+
+	// clear temp.w and temp.k from memory, lint ignore
+	round.temp.w = zero
+	round.temp.k = zero
+	return nil
+}
+
+func (round *presignFinalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *presignFinalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *presignFinalization) NextRound() tss.Round {
+	return nil // finished!
+}
@@ -7,9 +7,11 @@
 package signing
 
 import (
+	"crypto/elliptic"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
@@ -21,9 +23,11 @@ import (
 
 // Implements Party
 // Implements Stringer
+// Implements Wiper
 var (
 	_ tss.Party    = (*LocalParty)(nil)
 	_ fmt.Stringer = (*LocalParty)(nil)
+	_ tss.Wiper    = (*LocalParty)(nil)
 )
 
 type (
@@ -36,8 +40,9 @@ type (
 		data *common.SignatureData
 
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- *common.SignatureData
+		out        chan<- tss.Message
+		end        chan<- *common.SignatureData
+		presignEnd chan<- *PreSignatureData
 	}
 
 	localMessageStore struct {
@@ -66,6 +71,7 @@ type (
 		keyDerivationDelta,
 		gamma *big.Int
 		fullBytesLen int
+		digest       []byte
 		cis          []*big.Int
 		bigWs        []*crypto.ECPoint
 		pointGamma   *crypto.ECPoint
@@ -97,6 +103,9 @@ type (
 
 		ssidNonce *big.Int
 		ssid      []byte
+
+		// set when k, sigma and R were precomputed by a pre-signing party
+		preSigned bool
 	}
 )
 
@@ -160,17 +169,125 @@ func NewLocalPartyWithKDD(
 	return p
 }
 
+// NewLocalPartyWithDigest returns a party that signs an already-computed message digest (e.g. SHA-256, keccak256 or
+// Poseidon output), so the caller controls the hash domain. No further hashing is applied: the digest is converted
+// to a scalar as crypto/ecdsa does (its leftmost bits, reduced mod N) and is output unchanged in SignatureData.M.
+func NewLocalPartyWithDigest(
+	digest []byte,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+) tss.Party {
+	p := NewLocalParty(digestToInt(params.EC(), digest), params, key, out, end).(*LocalParty)
+	p.temp.digest = digest
+	return p
+}
+
+// digestToInt mirrors hashToInt of crypto/ecdsa, taking the leftmost bits of the digest then reducing it mod N
+func digestToInt(ec elliptic.Curve, digest []byte) *big.Int {
+	N := ec.Params().N
+	orderBits := N.BitLen()
+	orderBytes := (orderBits + 7) / 8
+	if len(digest) > orderBytes {
+		digest = digest[:orderBytes]
+	}
+	m := new(big.Int).SetBytes(digest)
+	if excess := len(digest)*8 - orderBits; excess > 0 {
+		m.Rsh(m, uint(excess))
+	}
+	return m.Mod(m, N)
+}
+
+// NewPreSigningLocalParty returns a party that runs the message-independent rounds 1-4 of signing and
+// outputs a PreSignatureData on `end` instead of a signature. Use it later with SignOnline.
+func NewPreSigningLocalParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *PreSignatureData,
+) tss.Party {
+	p := NewLocalParty(nil, params, key, out, nil).(*LocalParty)
+	p.presignEnd = end
+	return p
+}
+
+// SignOnline returns a party that signs `msg` using a pre-signature from NewPreSigningLocalParty.
+// The MtA rounds 1-4 are skipped; the parties run the phase 5 consistency checks of GG18 (rounds 5-8) on the
+// pre-signature and the message before they release their s shares in round 9, so a co-signer who deviated during
+// pre-signing cannot make an honest party reveal its s share.
+// Every member of the pre-signing committee must use the same message. The pre-signature is refused if it has
+// expired or was already consumed; otherwise it is marked consumed, and callers that persist pre-signatures must
+// store that state (or delete the record) before starting the party.
+func SignOnline(
+	preSig *PreSignatureData,
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) (tss.Party, error) {
+	if err := preSig.ValidateFor(params.Parties().IDs()); err != nil {
+		return nil, err
+	}
+	if err := preSig.checkFresh(time.Now()); err != nil {
+		return nil, err
+	}
+	if msg == nil || msg.Cmp(params.EC().Params().N) >= 0 {
+		return nil, errors.New("hashed message is not valid")
+	}
+	preSig.Consumed = true
+	p := NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty)
+	p.temp.k = preSig.K
+	p.temp.sigma = preSig.Sigma
+	p.temp.bigR = preSig.BigR
+	p.temp.rx = preSig.BigR.X()
+	p.temp.ry = preSig.BigR.Y()
+	p.temp.ssid = preSig.SSID
+	p.temp.preSigned = true
+	return p, nil
+}
+
+// Session is a signing party with the channels it sends its messages and the signature on
+type Session struct {
+	*LocalParty
+	Out <-chan tss.Message
+	End <-chan *common.SignatureData
+}
+
+// NewSession returns the session of a party created like NewLocalParty, with channels that buffer `buffer` messages
+func NewSession(msg *big.Int, params *tss.Parameters, key keygen.LocalPartySaveData, buffer int, fullBytesLen ...int) *Session {
+	out, end := make(chan tss.Message, buffer), make(chan *common.SignatureData, 1)
+	return &Session{
+		LocalParty: NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty),
+		Out:        out,
+		End:        end,
+	}
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end)
+	round := newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end, p.presignEnd).(*round1)
+	if p.temp.preSigned {
+		// R, k and sigma were already computed in rounds 1-4, skip straight to the phase 5 checks
+		round.number = 5
+		return &round5{&round4{&round3{&round2{round}}}}
+	}
+	return round
 }
 
 func (p *LocalParty) Start() *tss.Error {
 	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
-		round1, ok := round.(*round1)
-		if !ok {
-			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
+		var r1 *round1
+		switch rnd := round.(type) {
+		case *round1:
+			r1 = rnd
+		case *round5:
+			r1 = rnd.round1
+		default:
+			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round")).WithCode(tss.CodeInternal)
 		}
-		if err := round1.prepare(); err != nil {
+		if err := r1.prepare(); err != nil {
 			return round.WrapError(err)
 		}
 		return nil
@@ -184,7 +301,7 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
 	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
-		return false, p.WrapError(err)
+		return false, p.WrapError(err).WithCode(tss.CodeInvalidMessage)
 	}
 	return p.Update(msg)
 }
@@ -196,7 +313,7 @@ func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	// check that the message's "from index" will fit into the array
 	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
 		return false, p.WrapError(fmt.Errorf("received msg with a sender index too great (%d <= %d)",
-			maxFromIdx, msg.GetFrom().Index), msg.GetFrom())
+			maxFromIdx, msg.GetFrom().Index), msg.GetFrom()).WithCode(tss.CodeInvalidMessage)
 	}
 	return true, nil
 }
@@ -245,3 +362,22 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
+
+// Wipe overwrites the secrets of the signing: the share wi of the signing key, the nonces k and gamma and sigma = k*w,
+// the MtA shares and the randomness of the last rounds. It is called when the session ends. The nonces of a
+// pre-signing party are left to the owner of its PreSignatureData (see PreSignatureData.Wipe), while those of a
+// consumed pre-signature are wiped with the rest.
+func (p *LocalParty) Wipe() {
+	secrets := []*big.Int{p.temp.w, p.temp.gamma, p.temp.li, p.temp.roi}
+	if p.presignEnd == nil {
+		secrets = append(secrets, p.temp.k, p.temp.sigma)
+	}
+	secrets = append(secrets, p.temp.betas...)
+	secrets = append(secrets, p.temp.vs...)
+	for _, x := range secrets {
+		// zero is shared by every party, and the share of the key is the caller's
+		if x != zero && x != p.keys.Xi {
+			common.ZeroInt(x)
+		}
+	}
+}
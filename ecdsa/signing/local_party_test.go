@@ -15,8 +15,10 @@ import (
 	"runtime"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	btcecdsa "github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/ipfs/go-log"
 	"github.com/stretchr/testify/assert"
 
@@ -334,6 +336,395 @@ signing:
 	}
 }
 
+func TestE2EPreSignAndSignOnline(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	updater := test.SharedPartyUpdater
+
+	route := func(parties []*LocalParty, msg tss.Message, errCh chan<- *tss.Error) {
+		dest := msg.GetTo()
+		if dest == nil {
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				go updater(P, msg, errCh)
+			}
+		} else {
+			if dest[0].Index == msg.GetFrom().Index {
+				t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+			}
+			go updater(parties[dest[0].Index], msg, errCh)
+		}
+	}
+
+	// PHASE: pre-signing
+	preParties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	preEndCh := make(chan *PreSignatureData, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P := NewPreSigningLocalParty(params, keys[i], outCh, preEndCh).(*LocalParty)
+		preParties = append(preParties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	preSigs := make([]*PreSignatureData, len(signPIDs))
+	received := 0
+presigning:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break presigning
+
+		case msg := <-outCh:
+			route(preParties, msg, errCh)
+
+		case preSig := <-preEndCh:
+			for i, P := range preParties {
+				if P.temp.sigma == preSig.Sigma {
+					preSigs[i] = preSig
+				}
+			}
+			if received++; received == len(signPIDs) {
+				break presigning
+			}
+		}
+	}
+	for i, preSig := range preSigs {
+		if !assert.NotNil(t, preSig, "party %d should produce a pre-signature", i) {
+			return
+		}
+		assert.True(t, preSigs[0].BigR.Equals(preSig.BigR), "all parties should agree on R")
+
+		// round-trip through storage as an operator would between the offline and online phases
+		preSig.SetExpiry(1, time.Hour)
+		bz, err := preSig.Marshal()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if preSigs[i], err = UnmarshalPreSignatureData(bz); !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, preSigs[i].CheckUsable(1, time.Now()))
+	}
+
+	// PHASE: online signing
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	msg := big.NewInt(42)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P, err := SignOnline(preSigs[i], msg, params, keys[i], outCh, endCh)
+		if !assert.NoError(t, err) {
+			return
+		}
+		parties = append(parties, P.(*LocalParty))
+	}
+	_, err = SignOnline(preSigs[0], msg, tss.NewParameters(tss.S256(), p2pCtx, signPIDs[0], len(signPIDs), threshold), keys[0], outCh, endCh)
+	assert.Error(t, err, "a consumed pre-signature must be refused")
+
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			switch msg.(tss.ParsedMessage).Content().(type) {
+			case *SignRound1Message1, *SignRound1Message2, *SignRound2Message, *SignRound3Message, *SignRound4Message:
+				assert.Fail(t, "the rounds of the pre-signature should not be run again")
+			}
+			route(parties, msg, errCh)
+
+		case sig := <-endCh:
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				t.Logf("Done. Received signature data from %d participants", ended)
+				pk := ecdsa.PublicKey{
+					Curve: tss.EC(),
+					X:     keys[0].ECDSAPub.X(),
+					Y:     keys[0].ECDSAPub.Y(),
+				}
+				r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+				assert.True(t, ecdsa.Verify(&pk, msg.Bytes(), r, s), "ecdsa verify must pass")
+				for _, preSig := range preSigs {
+					assert.Zero(t, preSig.K.Sign(), "the nonce of a consumed pre-signature should be wiped")
+				}
+				break signing
+			}
+		}
+	}
+}
+
+func TestE2EWithDigest(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// a digest above the curve order must be accepted and truncated like crypto/ecdsa does
+	digest, _ := hex.DecodeString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff0")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P := NewLocalPartyWithDigest(digest, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				if dest[0].Index == msg.GetFrom().Index {
+					t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+				}
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case sig := <-endCh:
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				assert.Equal(t, digest, sig.M, "the digest should be output unchanged")
+				pk := ecdsa.PublicKey{
+					Curve: tss.EC(),
+					X:     keys[0].ECDSAPub.X(),
+					Y:     keys[0].ECDSAPub.Y(),
+				}
+				r, s := new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S)
+				assert.True(t, ecdsa.Verify(&pk, digest, r, s), "ecdsa verify must pass")
+				assert.True(t, s.Cmp(new(big.Int).Rsh(tss.EC().Params().N, 1)) <= 0, "S should be low")
+				der, err := DERSignature(sig)
+				if assert.NoError(t, err) {
+					assert.True(t, ecdsa.VerifyASN1(&pk, digest, der), "DER signature verify must pass")
+				}
+
+				// the recovery id must recover the public key without brute-forcing
+				ethSig, err := EthereumSignature(sig)
+				if !assert.NoError(t, err) {
+					break signing
+				}
+				compact := append([]byte{27 + ethSig[64]}, ethSig[:64]...)
+				recovered, _, err := btcecdsa.RecoverCompact(compact, digest)
+				if assert.NoError(t, err) {
+					assert.Equal(t, 0, recovered.X().Cmp(pk.X), "recovered key should match")
+					assert.Equal(t, 0, recovered.Y().Cmp(pk.Y), "recovered key should match")
+				}
+				break signing
+			}
+		}
+	}
+}
+
+func TestE2EDeterministic(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	updater := test.SharedPartyUpdater
+
+	sign := func() []byte {
+		parties := make([]*LocalParty, 0, len(signPIDs))
+
+		errCh := make(chan *tss.Error, len(signPIDs))
+		outCh := make(chan tss.Message, len(signPIDs))
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+
+		for i := 0; i < len(signPIDs); i++ {
+			params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+			params.SetEntropySource(common.NewDeterministicReader([]byte(fmt.Sprintf("party %d", i))))
+			P := NewLocalParty(big.NewInt(42), params, keys[i], outCh, endCh).(*LocalParty)
+			parties = append(parties, P)
+			go func(P *LocalParty) {
+				if err := P.Start(); err != nil {
+					errCh <- err
+				}
+			}(P)
+		}
+
+		var ended int32
+		for {
+			select {
+			case err := <-errCh:
+				assert.FailNow(t, err.Error())
+				return nil
+
+			case msg := <-outCh:
+				dest := msg.GetTo()
+				if dest == nil {
+					for _, P := range parties {
+						if P.PartyID().Index == msg.GetFrom().Index {
+							continue
+						}
+						go updater(P, msg, errCh)
+					}
+				} else {
+					go updater(parties[dest[0].Index], msg, errCh)
+				}
+
+			case sig := <-endCh:
+				if atomic.AddInt32(&ended, 1) == int32(len(signPIDs)) {
+					return sig.Signature
+				}
+			}
+		}
+	}
+
+	sig1, sig2 := sign(), sign()
+	assert.NotEmpty(t, sig1)
+	assert.Equal(t, sig1, sig2, "seeded signing should be reproducible byte-for-byte")
+}
+
+func TestE2ERescuePrime(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	// the scheme of a key only binds the sessions run on it, so the fixtures stand in for a Rescue-Prime keygen
+	for i := range keys {
+		keys[i].HashScheme = common.HashRescuePrime
+	}
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+	msg := big.NewInt(42)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		params.SetHashScheme(common.HashRescuePrime)
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case sig := <-endCh:
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				pk := ecdsa.PublicKey{
+					Curve: tss.EC(),
+					X:     keys[0].ECDSAPub.X(),
+					Y:     keys[0].ECDSAPub.Y(),
+				}
+				ok := ecdsa.Verify(&pk, msg.Bytes(), new(big.Int).SetBytes(sig.R), new(big.Int).SetBytes(sig.S))
+				assert.True(t, ok, "ecdsa verify must pass")
+				break signing
+			}
+		}
+	}
+}
+
+func TestHashSchemeMismatch(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	// the fixtures were generated with the default scheme
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	params := tss.NewParameters(tss.S256(), p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+	params.SetHashScheme(common.HashKeccak256)
+	P := NewLocalParty(big.NewInt(42), params, keys[0], make(chan tss.Message, len(signPIDs)), make(chan *common.SignatureData, 1))
+	tssErr := P.Start()
+	if assert.NotNil(t, tssErr, "a session with another hash scheme than the key must be rejected") {
+		assert.Contains(t, tssErr.Error(), "hash scheme")
+	}
+}
+
 func TestFillTo32BytesInPlace(t *testing.T) {
 	s := big.NewInt(123456789)
 	normalizedS := padToLengthBytesInPlace(s.Bytes(), 32)
@@ -13,6 +13,7 @@ import (
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 // PrepareForSigning(), GG18Spec (11) Fig. 14
@@ -65,3 +66,24 @@ func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int
 	}
 	return
 }
+
+// PrepareForSigningWithAccessStructure is PrepareForSigning for a key shared along an access structure: the
+// coefficients of the structure take the place of the Lagrange coefficients.
+// The signers `ks` must be a set chosen by AccessStructure.Resolve.
+func PrepareForSigningWithAccessStructure(ec elliptic.Curve, i int, xi *big.Int, ks []*big.Int, bigXs []*crypto.ECPoint, as *tss.AccessStructure) (wi *big.Int, bigWs []*crypto.ECPoint, err error) {
+	if len(ks) != len(bigXs) {
+		return nil, nil, fmt.Errorf("PrepareForSigningWithAccessStructure: len(ks) != len(bigXs) (%d != %d)", len(ks), len(bigXs))
+	}
+	if len(ks) <= i {
+		return nil, nil, fmt.Errorf("PrepareForSigningWithAccessStructure: len(ks) <= i (%d <= %d)", len(ks), i)
+	}
+	coefs, err := as.Coefficients(ec, ks)
+	if err != nil {
+		return nil, nil, err
+	}
+	bigWs = make([]*crypto.ECPoint, len(ks))
+	for j, coef := range coefs {
+		bigWs[j] = bigXs[j].ScalarMult(coef)
+	}
+	return common.ModInt(ec.Params().N).Mul(xi, coefs[i]), bigWs, nil
+}
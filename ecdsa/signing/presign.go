@@ -0,0 +1,116 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+type (
+	// PreSignatureData is the message-independent state produced by rounds 1-4 of signing.
+	// It contains this party's nonce share k and sigma = k*w and must be stored as securely as the key share itself.
+	// A PreSignatureData must be used to sign at most one message; reusing it leaks the key share.
+	PreSignatureData struct {
+		// secret nonce share and its product with the key share
+		K,
+		Sigma *big.Int
+
+		// R = g^(1/k) agreed by the signing committee
+		BigR *crypto.ECPoint
+
+		// session the nonce was committed to
+		SSID []byte
+
+		// share IDs of the signing committee, in sorted order
+		Ks []*big.Int
+
+		// key share epoch the pre-signature was made under, set by the caller; resharing should bump it
+		Epoch uint64
+
+		// the pre-signature must not be used after this time; the zero value means it never expires
+		ExpiresAt time.Time
+
+		// set once the nonce has been handed to a signing party
+		Consumed bool
+	}
+)
+
+// ValidateFor checks that the pre-signature is complete and was produced by the given signing committee
+func (preSig *PreSignatureData) ValidateFor(sortedIDs tss.SortedPartyIDs) error {
+	if preSig == nil || preSig.K == nil || preSig.Sigma == nil || preSig.BigR == nil || len(preSig.SSID) == 0 {
+		return errors.New("pre-signature data is incomplete")
+	}
+	if len(preSig.Ks) != sortedIDs.Len() {
+		return fmt.Errorf("pre-signature was made by %d parties but %d are signing", len(preSig.Ks), sortedIDs.Len())
+	}
+	for j, kj := range sortedIDs.Keys() {
+		if preSig.Ks[j] == nil || preSig.Ks[j].Cmp(kj) != 0 {
+			return fmt.Errorf("pre-signature was not made by signing party %s", sortedIDs[j])
+		}
+	}
+	return nil
+}
+
+// Wipe overwrites the nonce share k and sigma of the pre-signature on a best-effort basis (see common.ZeroInt), e.g.
+// when it is discarded unused. A signing party wipes those of the pre-signature it consumes itself.
+func (preSig *PreSignatureData) Wipe() {
+	common.ZeroInt(preSig.K)
+	common.ZeroInt(preSig.Sigma)
+}
+
+// SetExpiry binds the pre-signature to a key share epoch and expires it `ttl` from now
+func (preSig *PreSignatureData) SetExpiry(epoch uint64, ttl time.Duration) {
+	preSig.Epoch = epoch
+	preSig.ExpiresAt = time.Now().Add(ttl)
+}
+
+// Marshal serialises the pre-signature for storage between the offline and online phases
+func (preSig *PreSignatureData) Marshal() ([]byte, error) {
+	return json.Marshal(preSig)
+}
+
+// UnmarshalPreSignatureData parses a pre-signature produced by Marshal
+func UnmarshalPreSignatureData(bz []byte) (*PreSignatureData, error) {
+	preSig := new(PreSignatureData)
+	if err := json.Unmarshal(bz, preSig); err != nil {
+		return nil, err
+	}
+	if preSig.K == nil || preSig.Sigma == nil || preSig.BigR == nil || len(preSig.SSID) == 0 || len(preSig.Ks) == 0 {
+		return nil, errors.New("pre-signature data is incomplete")
+	}
+	return preSig, nil
+}
+
+// CheckUsable returns an error if the pre-signature was already consumed, has expired at `now`,
+// or was made under a key share epoch other than `epoch`
+func (preSig *PreSignatureData) CheckUsable(epoch uint64, now time.Time) error {
+	if err := preSig.checkFresh(now); err != nil {
+		return err
+	}
+	if preSig.Epoch != epoch {
+		return fmt.Errorf("pre-signature is stale: made in epoch %d, current epoch is %d", preSig.Epoch, epoch)
+	}
+	return nil
+}
+
+func (preSig *PreSignatureData) checkFresh(now time.Time) error {
+	if preSig.Consumed {
+		return errors.New("pre-signature was already consumed")
+	}
+	if !preSig.ExpiresAt.IsZero() && now.After(preSig.ExpiresAt) {
+		return fmt.Errorf("pre-signature expired at %s", preSig.ExpiresAt)
+	}
+	return nil
+}
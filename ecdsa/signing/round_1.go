@@ -22,28 +22,32 @@ import (
 var zero = big.NewInt(0)
 
 // round 1 represents round 1 of the signing part of the GG18 ECDSA TSS spec (Gennaro, Goldfeder; 2018)
-func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData, preEnd chan<- *PreSignatureData) tss.Round {
 	return &round1{
-		&base{params, key, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1},
+		&base{params, key, data, temp, out, end, preEnd, make([]bool, len(params.Parties().IDs())), false, 1},
 	}
 }
 
 func (round *round1) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 
 	// Spec requires calculate H(M) here,
 	// but considered different blockchain use different hash function we accept the converted big.Int
 	// if this big.Int is not belongs to Zq, the client might not comply with common rule (for ECDSA):
 	// https://github.com/btcsuite/btcd/blob/c26ffa870fd817666a857af1bf6498fabba1ffe3/btcec/signature.go#L263
-	if round.temp.m.Cmp(round.Params().EC().Params().N) >= 0 {
-		return round.WrapError(errors.New("hashed message is not valid"))
+	// (a pre-signing party has no message yet, it is checked when the pre-signature is used instead)
+	if round.preEnd == nil && round.temp.m.Cmp(round.Params().EC().Params().N) >= 0 {
+		return round.WrapError(errors.New("hashed message is not valid")).WithCode(tss.CodeInvalidInput)
 	}
 
 	round.number = 1
 	round.started = true
 	round.resetOK()
+	if err := round.ValidateHashScheme(round.key.HashScheme); err != nil {
+		return round.WrapError(err)
+	}
 	round.temp.ssidNonce = new(big.Int).SetUint64(0)
 	ssid, err := round.getSSID()
 	if err != nil {
@@ -55,7 +59,7 @@ func (round *round1) Start() *tss.Error {
 	gamma := common.GetRandomPositiveInt(round.Rand(), round.EC().Params().N)
 
 	pointGamma := crypto.ScalarBaseMult(round.Params().EC(), gamma)
-	cmt := commitments.NewHashCommitment(round.Rand(), pointGamma.X(), pointGamma.Y())
+	cmt := commitments.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagECDSASigningGamma), round.Rand(), pointGamma.X(), pointGamma.Y())
 	round.temp.k = k
 	round.temp.gamma = gamma
 	round.temp.pointGamma = pointGamma
@@ -74,12 +78,12 @@ func (round *round1) Start() *tss.Error {
 		}
 		r1msg1 := NewSignRound1Message1(Pj, round.PartyID(), cA, pi)
 		round.temp.cis[j] = cA
-		round.out <- r1msg1
+		round.out <- round.Outbound(r1msg1)
 	}
 
 	r1msg2 := NewSignRound1Message2(round.PartyID(), cmt.C)
 	round.temp.signRound1Message2s[i] = r1msg2
-	round.out <- r1msg2
+	round.out <- round.Outbound(r1msg2)
 
 	return nil
 }
@@ -122,7 +126,19 @@ func (round *round1) NextRound() tss.Round {
 func (round *round1) prepare() error {
 	i := round.PartyID().Index
 
-	xi := round.key.Xi
+	xi, unwrapped, err := round.key.SigningShare(round.Params().ShareWrapper())
+	if err != nil {
+		return err
+	}
+	// an unwrapped share only lives in memory until wi is derived from it
+	zeroUnlessWi := func(x *big.Int) {
+		if x != round.temp.w {
+			common.ZeroInt(x)
+		}
+	}
+	if unwrapped {
+		defer zeroUnlessWi(xi)
+	}
 	ks := round.key.Ks
 	bigXs := round.key.BigXj
 
@@ -132,12 +148,25 @@ func (round *round1) prepare() error {
 		// So x + D has shamir shares  x_0 + D, x_1 + D, ..., x_n + D
 		mod := common.ModInt(round.Params().EC().Params().N)
 		xi = mod.Add(round.temp.keyDerivationDelta, xi)
-		round.key.Xi = xi
+		if unwrapped {
+			defer zeroUnlessWi(xi)
+		} else {
+			round.key.Xi = xi
+		}
 	}
 
 	if round.Threshold()+1 > len(ks) {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
+	if as := round.key.AccessStructure; as != nil {
+		wi, bigWs, err := PrepareForSigningWithAccessStructure(round.Params().EC(), i, xi, ks, bigXs, as)
+		if err != nil {
+			return err
+		}
+		round.temp.w = wi
+		round.temp.bigWs = bigWs
+		return nil
+	}
 	wi, bigWs := PrepareForSigning(round.Params().EC(), i, len(ks), xi, ks, bigXs)
 
 	round.temp.w = wi
@@ -8,18 +8,20 @@ package signing
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 
 	errorspkg "github.com/pkg/errors"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/mta"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 func (round *round2) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 2
 	round.started = true
@@ -42,10 +44,11 @@ func (round *round2) Start() *tss.Error {
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj).WithCode(tss.CodeBadProof)
 				return
 			}
-			beta, c1ji, _, pi1ji, err := mta.BobMid(
+			beta, c1ji, _, pi1ji, err := mta.BobMidWithHash(
+				round.HashScheme().ChallengeHash(),
 				ContextI,
 				round.Parameters.EC(),
 				round.key.PaillierPKs[j],
@@ -58,7 +61,7 @@ func (round *round2) Start() *tss.Error {
 				round.key.NTildej[i],
 				round.key.H1j[i],
 				round.key.H2j[i],
-				round.Rand(),
+				common.ForkRandom(round.Rand(), fmt.Sprintf("bob_mid %d", j)),
 			)
 			// should be thread safe as these are pre-allocated
 			round.temp.betas[j] = beta
@@ -74,10 +77,11 @@ func (round *round2) Start() *tss.Error {
 			r1msg := round.temp.signRound1Message1s[j].Content().(*SignRound1Message1)
 			rangeProofAliceJ, err := r1msg.UnmarshalRangeProofAlice()
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalRangeProofAlice failed"), Pj).WithCode(tss.CodeBadProof)
 				return
 			}
-			v, c2ji, _, pi2ji, err := mta.BobMidWC(
+			v, c2ji, _, pi2ji, err := mta.BobMidWCWithHash(
+				round.HashScheme().ChallengeHash(),
 				ContextI,
 				round.Parameters.EC(),
 				round.key.PaillierPKs[j],
@@ -91,7 +95,7 @@ func (round *round2) Start() *tss.Error {
 				round.key.H1j[i],
 				round.key.H2j[i],
 				round.temp.bigWs[i],
-				round.Rand(),
+				common.ForkRandom(round.Rand(), fmt.Sprintf("bob_mid_wc %d", j)),
 			)
 			round.temp.vs[j] = v
 			round.temp.c2jis[j] = c2ji
@@ -109,7 +113,7 @@ func (round *round2) Start() *tss.Error {
 		culprits = append(culprits, err.Culprits()...)
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("failed to calculate Bob_mid or Bob_mid_wc"), culprits...)
+		return round.WrapError(errors.New("failed to calculate Bob_mid or Bob_mid_wc"), culprits...).WithCode(tss.CodeBadProof)
 	}
 	// create and send messages
 	for j, Pj := range round.Parties().IDs() {
@@ -118,7 +122,7 @@ func (round *round2) Start() *tss.Error {
 		}
 		r2msg := NewSignRound2Message(
 			Pj, round.PartyID(), round.temp.c1jis[j], round.temp.pi1jis[j], round.temp.c2jis[j], round.temp.pi2jis[j])
-		round.out <- r2msg
+		round.out <- round.Outbound(r2msg)
 	}
 	return nil
 }
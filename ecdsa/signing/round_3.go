@@ -20,7 +20,7 @@ import (
 
 func (round *round3) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 3
 	round.started = true
@@ -45,10 +45,11 @@ func (round *round3) Start() *tss.Error {
 			r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
 			proofBob, err := r2msg.UnmarshalProofBob()
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBob failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBob failed"), Pj).WithCode(tss.CodeBadProof)
 				return
 			}
-			alphaIj, err := mta.AliceEnd(
+			alphaIj, err := mta.AliceEndWithHash(
+				round.HashScheme().ChallengeHash(),
 				ContextJ,
 				round.Params().EC(),
 				round.key.PaillierPKs[i],
@@ -70,10 +71,11 @@ func (round *round3) Start() *tss.Error {
 			r2msg := round.temp.signRound2Messages[j].Content().(*SignRound2Message)
 			proofBobWC, err := r2msg.UnmarshalProofBobWC(round.Parameters.EC())
 			if err != nil {
-				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBobWC failed"), Pj)
+				errChs <- round.WrapError(errorspkg.Wrapf(err, "UnmarshalProofBobWC failed"), Pj).WithCode(tss.CodeBadProof)
 				return
 			}
-			uIj, err := mta.AliceEndWC(
+			uIj, err := mta.AliceEndWCWithHash(
+				round.HashScheme().ChallengeHash(),
 				ContextJ,
 				round.Params().EC(),
 				round.key.PaillierPKs[i],
@@ -100,7 +102,7 @@ func (round *round3) Start() *tss.Error {
 		culprits = append(culprits, err.Culprits()...)
 	}
 	if len(culprits) > 0 {
-		return round.WrapError(errors.New("failed to calculate Alice_end or Alice_end_wc"), culprits...)
+		return round.WrapError(errors.New("failed to calculate Alice_end or Alice_end_wc"), culprits...).WithCode(tss.CodeBadProof)
 	}
 
 	modN := common.ModInt(round.Params().EC().Params().N)
@@ -119,7 +121,7 @@ func (round *round3) Start() *tss.Error {
 	round.temp.sigma = sigma
 	r3msg := NewSignRound3Message(round.PartyID(), thelta)
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
-	round.out <- r3msg
+	round.out <- round.Outbound(r3msg)
 
 	return nil
 }
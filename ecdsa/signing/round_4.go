@@ -19,7 +19,7 @@ import (
 
 func (round *round4) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 4
 	round.started = true
@@ -43,14 +43,14 @@ func (round *round4) Start() *tss.Error {
 	thetaInverse = modN.ModInverse(thetaInverse)
 	i := round.PartyID().Index
 	ContextI := append(round.temp.ssid, new(big.Int).SetUint64(uint64(i)).Bytes()...)
-	piGamma, err := schnorr.NewZKProof(ContextI, round.temp.gamma, round.temp.pointGamma, round.Rand())
+	piGamma, err := schnorr.NewZKProofWithHash(round.HashScheme().ChallengeHash(), ContextI, round.temp.gamma, round.temp.pointGamma, round.Rand())
 	if err != nil {
 		return round.WrapError(errors2.Wrapf(err, "NewZKProof(gamma, bigGamma)"))
 	}
 	round.temp.thetaInverse = thetaInverse
 	r4msg := NewSignRound4Message(round.PartyID(), round.temp.deCommit, piGamma)
 	round.temp.signRound4Messages[round.PartyID().Index] = r4msg
-	round.out <- r4msg
+	round.out <- round.Outbound(r4msg)
 
 	return nil
 }
@@ -79,5 +79,8 @@ func (round *round4) CanAccept(msg tss.ParsedMessage) bool {
 
 func (round *round4) NextRound() tss.Round {
 	round.started = false
+	if round.preEnd != nil {
+		return &presignFinalization{round}
+	}
 	return &round5{round}
 }
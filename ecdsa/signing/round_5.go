@@ -20,53 +20,27 @@ import (
 
 func (round *round5) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 5
 	round.started = true
 	round.resetOK()
 
-	R := round.temp.pointGamma
-	for j, Pj := range round.Parties().IDs() {
-		if j == round.PartyID().Index {
-			continue
-		}
-		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
-		r1msg2 := round.temp.signRound1Message2s[j].Content().(*SignRound1Message2)
-		r4msg := round.temp.signRound4Messages[j].Content().(*SignRound4Message)
-		SCj, SDj := r1msg2.UnmarshalCommitment(), r4msg.UnmarshalDeCommitment()
-		cmtDeCmt := commitments.HashCommitDecommit{C: SCj, D: SDj}
-		ok, bigGammaJ := cmtDeCmt.DeCommit()
-		if !ok || len(bigGammaJ) != 2 {
-			return round.WrapError(errors.New("commitment verify failed"), Pj)
-		}
-		bigGammaJPoint, err := crypto.NewECPoint(round.Params().EC(), bigGammaJ[0], bigGammaJ[1])
-		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigGammaJ)"), Pj)
-		}
-		proof, err := r4msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil {
-			return round.WrapError(errors.New("failed to unmarshal bigGamma proof"), Pj)
-		}
-		ok = proof.Verify(ContextJ, bigGammaJPoint)
-		if !ok {
-			return round.WrapError(errors.New("failed to prove bigGamma"), Pj)
-		}
-		R, err = R.Add(bigGammaJPoint)
-		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "R.Add(bigGammaJ)"), Pj)
+	// online signing: R, k and sigma come from the pre-signature
+	if !round.temp.preSigned {
+		if err := round.computeR(); err != nil {
+			return err
 		}
 	}
 
-	R = R.ScalarMult(round.temp.thetaInverse)
+	R := round.temp.bigR
 	N := round.Params().EC().Params().N
 	modN := common.ModInt(N)
-	rx := R.X()
-	ry := R.Y()
-	si := modN.Add(modN.Mul(round.temp.m, round.temp.k), modN.Mul(rx, round.temp.sigma))
+	si := modN.Add(modN.Mul(round.temp.m, round.temp.k), modN.Mul(round.temp.rx, round.temp.sigma))
 
-	// clear temp.w and temp.k from memory, lint ignore
+	// clear temp.w and temp.k from memory, lint ignore; k is overwritten first, as it may be the nonce of a pre-signature
 	round.temp.w = zero
+	common.ZeroInt(round.temp.k)
 	round.temp.k = zero
 
 	li := common.GetRandomPositiveInt(round.Rand(), N)  // li
@@ -79,10 +53,10 @@ func (round *round5) Start() *tss.Error {
 		return round.WrapError(errors2.Wrapf(err, "rToSi.Add(li)"))
 	}
 
-	cmt := commitments.NewHashCommitment(round.Rand(), bigVi.X(), bigVi.Y(), bigAi.X(), bigAi.Y())
+	cmt := commitments.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagECDSASigningViAi), round.Rand(), bigVi.X(), bigVi.Y(), bigAi.X(), bigAi.Y())
 	r5msg := NewSignRound5Message(round.PartyID(), cmt.C)
 	round.temp.signRound5Messages[round.PartyID().Index] = r5msg
-	round.out <- r5msg
+	round.out <- round.Outbound(r5msg)
 
 	round.temp.li = li
 	round.temp.bigAi = bigAi
@@ -90,9 +64,6 @@ func (round *round5) Start() *tss.Error {
 	round.temp.roi = roI
 	round.temp.DPower = cmt.D
 	round.temp.si = si
-	round.temp.rx = rx
-	round.temp.ry = ry
-	round.temp.bigR = R
 
 	return nil
 }
@@ -123,3 +94,44 @@ func (round *round5) NextRound() tss.Round {
 	round.started = false
 	return &round6{round}
 }
+
+// computeR verifies the de-commitments and proofs of the Gamma_j broadcast in rounds 1-4 and computes R = Gamma^(1/theta)
+func (round *round4) computeR() *tss.Error {
+	R := round.temp.pointGamma
+	for j, Pj := range round.Parties().IDs() {
+		if j == round.PartyID().Index {
+			continue
+		}
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		r1msg2 := round.temp.signRound1Message2s[j].Content().(*SignRound1Message2)
+		r4msg := round.temp.signRound4Messages[j].Content().(*SignRound4Message)
+		SCj, SDj := r1msg2.UnmarshalCommitment(), r4msg.UnmarshalDeCommitment()
+		cmtDeCmt := commitments.HashCommitDecommit{C: SCj, D: SDj, Hasher: round.HashScheme().Tagged(common.PoseidonTagECDSASigningGamma)}
+		ok, bigGammaJ := cmtDeCmt.DeCommit()
+		if !ok || len(bigGammaJ) != 2 {
+			return round.WrapError(errors.New("commitment verify failed"), Pj).WithCode(tss.CodeBadCommitment)
+		}
+		bigGammaJPoint, err := crypto.NewECPoint(round.Params().EC(), bigGammaJ[0], bigGammaJ[1])
+		if err != nil {
+			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigGammaJ)"), Pj).WithCode(tss.CodeBadPublicData)
+		}
+		proof, err := r4msg.UnmarshalZKProof(round.Params().EC())
+		if err != nil {
+			return round.WrapError(errors.New("failed to unmarshal bigGamma proof"), Pj).WithCode(tss.CodeBadProof)
+		}
+		ok = proof.VerifyWithHash(round.HashScheme().ChallengeHash(), ContextJ, bigGammaJPoint)
+		if !ok {
+			return round.WrapError(errors.New("failed to prove bigGamma"), Pj).WithCode(tss.CodeBadProof)
+		}
+		R, err = R.Add(bigGammaJPoint)
+		if err != nil {
+			return round.WrapError(errors2.Wrapf(err, "R.Add(bigGammaJ)"), Pj)
+		}
+	}
+
+	R = R.ScalarMult(round.temp.thetaInverse)
+	round.temp.bigR = R
+	round.temp.rx = R.X()
+	round.temp.ry = R.Y()
+	return nil
+}
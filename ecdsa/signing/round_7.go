@@ -20,7 +20,7 @@ import (
 
 func (round *round7) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 7
 	round.started = true
@@ -36,29 +36,29 @@ func (round *round7) Start() *tss.Error {
 		r5msg := round.temp.signRound5Messages[j].Content().(*SignRound5Message)
 		r6msg := round.temp.signRound6Messages[j].Content().(*SignRound6Message)
 		cj, dj := r5msg.UnmarshalCommitment(), r6msg.UnmarshalDeCommitment()
-		cmtDeCmt := commitments.HashCommitDecommit{C: cj, D: dj}
+		cmtDeCmt := commitments.HashCommitDecommit{C: cj, D: dj, Hasher: round.HashScheme().Tagged(common.PoseidonTagECDSASigningViAi)}
 		ok, values := cmtDeCmt.DeCommit()
 		if !ok || len(values) != 4 {
-			return round.WrapError(errors.New("de-commitment for bigVj and bigAj failed"), Pj)
+			return round.WrapError(errors.New("de-commitment for bigVj and bigAj failed"), Pj).WithCode(tss.CodeBadCommitment)
 		}
 		bigVjX, bigVjY, bigAjX, bigAjY := values[0], values[1], values[2], values[3]
 		bigVj, err := crypto.NewECPoint(round.Params().EC(), bigVjX, bigVjY)
 		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigVj)"), Pj)
+			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigVj)"), Pj).WithCode(tss.CodeBadPublicData)
 		}
 		bigVjs[j] = bigVj
 		bigAj, err := crypto.NewECPoint(round.Params().EC(), bigAjX, bigAjY)
 		if err != nil {
-			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigAj)"), Pj)
+			return round.WrapError(errors2.Wrapf(err, "NewECPoint(bigAj)"), Pj).WithCode(tss.CodeBadPublicData)
 		}
 		bigAjs[j] = bigAj
 		pijA, err := r6msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil || !pijA.Verify(ContextJ, bigAj) {
-			return round.WrapError(errors.New("schnorr verify for Aj failed"), Pj)
+		if err != nil || !pijA.VerifyWithHash(round.HashScheme().ChallengeHash(), ContextJ, bigAj) {
+			return round.WrapError(errors.New("schnorr verify for Aj failed"), Pj).WithCode(tss.CodeBadProof)
 		}
 		pijV, err := r6msg.UnmarshalZKVProof(round.Params().EC())
-		if err != nil || !pijV.Verify(ContextJ, bigVj, round.temp.bigR) {
-			return round.WrapError(errors.New("vverify for Vj failed"), Pj)
+		if err != nil || !pijV.VerifyWithHash(round.HashScheme().ChallengeHash(), ContextJ, bigVj, round.temp.bigR) {
+			return round.WrapError(errors.New("vverify for Vj failed"), Pj).WithCode(tss.CodeBadProof)
 		}
 	}
 
@@ -83,10 +83,10 @@ func (round *round7) Start() *tss.Error {
 	TiX, TiY := round.Params().EC().ScalarMult(AX, AY, round.temp.li.Bytes())
 	round.temp.Ui = crypto.NewECPointNoCurveCheck(round.Params().EC(), UiX, UiY)
 	round.temp.Ti = crypto.NewECPointNoCurveCheck(round.Params().EC(), TiX, TiY)
-	cmt := commitments.NewHashCommitment(round.Rand(), UiX, UiY, TiX, TiY)
+	cmt := commitments.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagECDSASigningUiTi), round.Rand(), UiX, UiY, TiX, TiY)
 	r7msg := NewSignRound7Message(round.PartyID(), cmt.C)
 	round.temp.signRound7Messages[round.PartyID().Index] = r7msg
-	round.out <- r7msg
+	round.out <- round.Outbound(r7msg)
 	round.temp.DTelda = cmt.D
 
 	return nil
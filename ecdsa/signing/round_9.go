@@ -9,44 +9,26 @@ package signing
 import (
 	"errors"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 func (round *round9) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 9
 	round.started = true
 	round.resetOK()
 
-	UX, UY := round.temp.Ui.X(), round.temp.Ui.Y()
-	TX, TY := round.temp.Ti.X(), round.temp.Ti.Y()
-	for j, Pj := range round.Parties().IDs() {
-		if j == round.PartyID().Index {
-			continue
-		}
-
-		r7msg := round.temp.signRound7Messages[j].Content().(*SignRound7Message)
-		r8msg := round.temp.signRound8Messages[j].Content().(*SignRound8Message)
-		cj, dj := r7msg.UnmarshalCommitment(), r8msg.UnmarshalDeCommitment()
-		cmt := commitments.HashCommitDecommit{C: cj, D: dj}
-		ok, values := cmt.DeCommit()
-		if !ok && len(values) != 4 {
-			return round.WrapError(errors.New("de-commitment for bigVj and bigAj failed"), Pj)
-		}
-		UjX, UjY, TjX, TjY := values[0], values[1], values[2], values[3]
-		UX, UY = round.Params().EC().Add(UX, UY, UjX, UjY)
-		TX, TY = round.Params().EC().Add(TX, TY, TjX, TjY)
-	}
-	if UX.Cmp(TX) != 0 || UY.Cmp(TY) != 0 {
-		return round.WrapError(errors.New("U doesn't equal T"), round.PartyID())
+	if err := round.checkUEqualsT(); err != nil {
+		return err
 	}
 
 	r9msg := NewSignRound9Message(round.PartyID(), round.temp.si)
 	round.temp.signRound9Messages[round.PartyID().Index] = r9msg
-	round.out <- r9msg
+	round.out <- round.Outbound(r9msg)
 	return nil
 }
 
@@ -76,3 +58,30 @@ func (round *round9) NextRound() tss.Round {
 	round.started = false
 	return &finalization{round}
 }
+
+// checkUEqualsT de-commits the Uj and Tj broadcast in rounds 7-8 and checks that sum(Uj) = sum(Tj)
+func (round *round9) checkUEqualsT() *tss.Error {
+	UX, UY := round.temp.Ui.X(), round.temp.Ui.Y()
+	TX, TY := round.temp.Ti.X(), round.temp.Ti.Y()
+	for j, Pj := range round.Parties().IDs() {
+		if j == round.PartyID().Index {
+			continue
+		}
+
+		r7msg := round.temp.signRound7Messages[j].Content().(*SignRound7Message)
+		r8msg := round.temp.signRound8Messages[j].Content().(*SignRound8Message)
+		cj, dj := r7msg.UnmarshalCommitment(), r8msg.UnmarshalDeCommitment()
+		cmt := commitments.HashCommitDecommit{C: cj, D: dj, Hasher: round.HashScheme().Tagged(common.PoseidonTagECDSASigningUiTi)}
+		ok, values := cmt.DeCommit()
+		if !ok && len(values) != 4 {
+			return round.WrapError(errors.New("de-commitment for bigVj and bigAj failed"), Pj).WithCode(tss.CodeBadCommitment)
+		}
+		UjX, UjY, TjX, TjY := values[0], values[1], values[2], values[3]
+		UX, UY = round.Params().EC().Add(UX, UY, UjX, UjY)
+		TX, TY = round.Params().EC().Add(TX, TY, TjX, TjY)
+	}
+	if UX.Cmp(TX) != 0 || UY.Cmp(TY) != 0 {
+		return round.WrapError(errors.New("U doesn't equal T"), round.PartyID()).WithCode(tss.CodeVerificationFailed)
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ type (
 		temp    *localTempData
 		out     chan<- tss.Message
 		end     chan<- *common.SignatureData
+		preEnd  chan<- *PreSignatureData
 		ok      []bool // `ok` tracks parties which have been verified by Update()
 		started bool
 		number  int
@@ -62,6 +63,9 @@ type (
 	finalization struct {
 		*round9
 	}
+	presignFinalization struct {
+		*round4
+	}
 )
 
 var (
@@ -75,6 +79,7 @@ var (
 	_ tss.Round = (*round8)(nil)
 	_ tss.Round = (*round9)(nil)
 	_ tss.Round = (*finalization)(nil)
+	_ tss.Round = (*presignFinalization)(nil)
 )
 
 // ----- //
@@ -132,7 +137,7 @@ func (round *base) getSSID() ([]byte, error) {
 	ssidList = append(ssidList, round.Parties().IDs().Keys()...)                                                                                // parties
 	BigXjList, err := crypto.FlattenECPoints(round.key.BigXj)
 	if err != nil {
-		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID())
+		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID()).WithCode(tss.CodeInternal)
 	}
 	ssidList = append(ssidList, BigXjList...)                    // BigXj
 	ssidList = append(ssidList, round.key.NTildej...)            // NTilde
@@ -140,7 +145,12 @@ func (round *base) getSSID() ([]byte, error) {
 	ssidList = append(ssidList, round.key.H2j...)                // h2
 	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
 	ssidList = append(ssidList, round.temp.ssidNonce)
-	ssid := common.SHA512_256i(ssidList...).Bytes()
+	ssidList = append(ssidList, round.SessionIDInts()...)
+	ssidHash := round.HashScheme().Tagged(common.PoseidonTagECDSASigningSSID).HashInts(ssidList...)
+	if ssidHash == nil {
+		return nil, round.WrapError(errors.New("hash ssid failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
+	ssid := ssidHash.Bytes()
 
 	return ssid, nil
 }
@@ -7,13 +7,20 @@
 package keygen
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
 	"runtime"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
@@ -108,6 +115,15 @@ keygen:
 			index, err := save.OriginalIndex()
 			assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
 			tryWriteTestFixtureFile(t, index, *save)
+			if assert.NotNil(t, save.Metadata) {
+				assert.Equal(t, tss.Ed25519, save.Metadata.Curve)
+				assert.Equal(t, threshold, save.Metadata.Threshold)
+				assert.Equal(t, len(pIDs), save.Metadata.PartyCount)
+				assert.Equal(t, save.HashScheme, save.Metadata.HashScheme)
+				assert.Equal(t, tss.ProtocolVersion, save.Metadata.ProtocolVersion)
+				assert.False(t, save.Metadata.CreatedAt.IsZero())
+				assert.Equal(t, tss.KeyID(tss.Edwards(), save.EDDSAPub.X(), save.EDDSAPub.Y()), save.Metadata.KeyID)
+			}
 
 			atomic.AddInt32(&ended, 1)
 			if atomic.LoadInt32(&ended) == int32(len(pIDs)) {
@@ -134,7 +150,6 @@ keygen:
 					assert.NoError(t, err, "vss.ReConstruct should not throw error")
 
 					// uG test: u*G[j] == V[0]
-					assert.Equal(t, uj, Pj.temp.ui)
 					uG := crypto.ScalarBaseMult(tss.Edwards(), uj)
 					assert.True(t, uG.Equals(Pj.temp.vs[0]), "ensure u*G[j] == V_0")
 
@@ -148,10 +163,10 @@ keygen:
 					{
 						badShares := pShares[:threshold]
 						badShares[len(badShares)-1].Share.Set(big.NewInt(0))
-						uj, err := pShares[:threshold].ReConstruct(tss.Edwards())
+						badUj, err := pShares[:threshold].ReConstruct(tss.Edwards())
 						assert.NoError(t, err)
-						assert.NotEqual(t, parties[j].temp.ui, uj)
-						BigXjX, BigXjY := tss.Edwards().ScalarBaseMult(uj.Bytes())
+						assert.NotEqual(t, uj, badUj)
+						BigXjX, BigXjY := tss.Edwards().ScalarBaseMult(badUj.Bytes())
 						assert.NotEqual(t, BigXjX, Pj.temp.vs[0].X())
 						assert.NotEqual(t, BigXjY, Pj.temp.vs[0].Y())
 					}
@@ -235,3 +250,995 @@ func tryWriteTestFixtureFile(t *testing.T, index int, data LocalPartySaveData) {
 		t.Logf("Fixture file already exists for party %d; not re-creating: %s", index, fixtureFileName)
 	}
 }
+
+func TestCancelledContext(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), testThreshold)
+	params.SetContext(ctx)
+	P := NewLocalParty(params, outCh, endCh)
+	assert.Nil(t, P.Start(), "a live context should not prevent the start")
+
+	cancel()
+	msg := <-outCh
+	msg2 := NewKGRound1Message(pIDs[1], msg.(tss.ParsedMessage).Content().(*KGRound1Message).UnmarshalCommitment())
+	ok, err := P.Update(msg2)
+	assert.False(t, ok)
+	if assert.NotNil(t, err, "a cancelled session should refuse messages") {
+		assert.ErrorIs(t, err.Cause(), context.Canceled)
+	}
+
+	params2 := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[1], len(pIDs), testThreshold)
+	params2.SetContext(ctx)
+	assert.NotNil(t, NewLocalParty(params2, outCh, endCh).Start(), "a cancelled session should not start")
+}
+
+func TestCheckpointResume(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, 4*len(pIDs)*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	cp, err := tss.NewCheckpointer()
+	assert.NoError(t, err)
+	newParty := func(i int, cp *tss.Checkpointer) tss.Party {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		if cp != nil {
+			params.SetEntropySource(cp.EntropySource())
+		}
+		return NewLocalParty(params, outCh, endCh)
+	}
+	parties := make([]tss.Party, len(pIDs))
+	parties[0] = newParty(0, cp)
+	for i := 1; i < len(parties); i++ {
+		parties[i] = newParty(i, nil)
+	}
+	for _, P := range parties {
+		assert.Nil(t, P.Start())
+	}
+
+	// party 0 crashes once it has accepted every round 1 message, and resumes from its checkpoint
+	sentByParty0 := make(map[string]int)
+	delivered := make(map[string]bool)
+	crashed := false
+	for len(endCh) < len(pIDs) {
+		var msg tss.Message
+		select {
+		case msg = <-outCh:
+		default:
+			t.Fatal("keygen stalled")
+		}
+		bz, _, err := msg.WireBytes()
+		assert.NoError(t, err)
+		if msg.GetFrom().Index == 0 {
+			sentByParty0[string(bz)]++
+		}
+		delivered[string(bz)] = true
+		pMsg, err := tss.ParseWireMessage(bz, msg.GetFrom(), msg.IsBroadcast())
+		assert.NoError(t, err)
+		for j, P := range parties {
+			if j == msg.GetFrom().Index || (msg.GetTo() != nil && msg.GetTo()[0].Index != j) {
+				continue
+			}
+			var tssErr *tss.Error
+			if j == 0 {
+				_, tssErr = cp.Update(P, pMsg)
+			} else {
+				_, tssErr = P.Update(pMsg)
+			}
+			assert.Nil(t, tssErr)
+		}
+		if !crashed && len(cp.Checkpoint().Messages) == len(pIDs)-1 {
+			crashed = true
+			cp, err = tss.NewCheckpointerFrom(cp.Checkpoint())
+			assert.NoError(t, err)
+			parties[0] = newParty(0, cp)
+			assert.Nil(t, cp.Resume(parties[0]))
+		}
+	}
+	assert.True(t, crashed)
+	for _, m := range cp.Checkpoint().Messages {
+		assert.True(t, delivered[string(m.WireBytes)], "the checkpoint should hold the messages as received")
+	}
+	for len(outCh) > 0 {
+		if msg := <-outCh; msg.GetFrom().Index == 0 {
+			bz, _, _ := msg.WireBytes()
+			sentByParty0[string(bz)]++
+		}
+	}
+	for _, count := range sentByParty0 {
+		assert.Equal(t, 2, count, "the resumed party should send the same messages again")
+	}
+
+	var pub *crypto.ECPoint
+	for i := 0; i < len(pIDs); i++ {
+		save := <-endCh
+		if pub == nil {
+			pub = save.EDDSAPub
+		}
+		assert.True(t, pub.Equals(save.EDDSAPub), "every party should derive the same public key")
+	}
+}
+
+func TestCheckpointResumeAuthenticated(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	keys := make([]ed25519.PrivateKey, len(pIDs))
+	for i, pID := range pIDs {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		pID.IdentityKey, keys[i] = pub, priv
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, 4*len(pIDs)*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	cp, err := tss.NewCheckpointer()
+	assert.NoError(t, err)
+	newParty := func(i int, cp *tss.Checkpointer) tss.Party {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetIdentityKey(keys[i])
+		if cp != nil {
+			params.SetEntropySource(cp.EntropySource())
+		}
+		return NewLocalParty(params, outCh, endCh)
+	}
+	P0 := newParty(0, cp)
+	assert.Nil(t, P0.Start())
+	<-outCh
+	for i := 1; i < len(pIDs); i++ {
+		assert.Nil(t, newParty(i, nil).Start())
+		msg := <-outCh
+		bz, _, err := msg.WireBytes()
+		assert.NoError(t, err)
+		pMsg, err := tss.ParseWireMessage(bz, pIDs[i], msg.IsBroadcast())
+		assert.NoError(t, err)
+		_, tssErr := cp.Update(P0, pMsg)
+		assert.Nil(t, tssErr)
+	}
+	for len(outCh) > 0 {
+		<-outCh
+	}
+
+	checkpoint := cp.Checkpoint()
+	if !assert.Len(t, checkpoint.Messages, len(pIDs)-1) {
+		return
+	}
+	for _, m := range checkpoint.Messages {
+		assert.NotNil(t, m.FromIdentityKey, "the checkpoint should hold the identity keys of the senders")
+	}
+	resumed, err := tss.NewCheckpointerFrom(checkpoint)
+	assert.NoError(t, err)
+	assert.Nil(t, resumed.Resume(newParty(0, resumed)), "signed messages should replay")
+	for len(outCh) > 0 {
+		<-outCh
+	}
+
+	// a checkpoint whose messages lost their signatures does not replay
+	checkpoint.Messages[0].WireBytes = checkpoint.Messages[0].WireBytes[ed25519.SignatureSize:]
+	resumed, err = tss.NewCheckpointerFrom(checkpoint)
+	assert.NoError(t, err)
+	assert.NotNil(t, resumed.Resume(newParty(0, resumed)), "an unsigned message should be rejected on replay")
+}
+
+func TestSessionID(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	newParty := func(i int, sessionID string) *LocalParty {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetSessionID([]byte(sessionID))
+		P := NewLocalParty(params, outCh, endCh).(*LocalParty)
+		assert.Nil(t, P.Start())
+		return P
+	}
+	P0 := newParty(0, "session a")
+	msg0 := <-outCh
+	_, routing, err := msg0.WireBytes()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("session a"), routing.SessionID, "outbound messages should carry the session ID")
+
+	P1 := newParty(1, "session b")
+	msg1 := <-outCh
+	assert.NotEqual(t, P0.temp.ssid, P1.temp.ssid, "the session ID should be mixed into the SSID")
+
+	bz, routing, err := msg1.WireBytes()
+	assert.NoError(t, err)
+	pMsg, err := tss.ParseWireMessageInSession(bz, msg1.GetFrom(), msg1.IsBroadcast(), routing.SessionID)
+	assert.NoError(t, err)
+	ok, tssErr := P0.Update(pMsg)
+	assert.False(t, ok)
+	assert.NotNil(t, tssErr, "a message of another session should be rejected")
+	assert.Equal(t, tss.CodeWrongSession, tssErr.Code())
+
+	// a message parsed without a session ID is still accepted
+	pMsg, err = tss.ParseWireMessage(bz, msg1.GetFrom(), msg1.IsBroadcast())
+	assert.NoError(t, err)
+	ok, tssErr = P0.Update(pMsg)
+	assert.True(t, ok)
+	assert.Nil(t, tssErr)
+}
+
+func TestAuthenticatedMessages(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	keys := make([]ed25519.PrivateKey, len(pIDs))
+	for i, pID := range pIDs {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		pID.IdentityKey, keys[i] = pub, priv
+	}
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	sessionID := []byte("authenticated session")
+	parties := make([]*LocalParty, len(pIDs))
+	for i, pID := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pID, len(pIDs), testThreshold)
+		params.SetIdentityKey(keys[i])
+		params.SetSessionID(sessionID)
+		parties[i] = NewLocalParty(params, outCh, endCh).(*LocalParty)
+	}
+	var msg tss.Message
+	for _, P := range parties {
+		assert.Nil(t, P.Start())
+		if out := <-outCh; out.GetFrom().Index == 0 {
+			msg = out
+		} else {
+			bz, _, err := out.WireBytes()
+			assert.NoError(t, err)
+			_, tssErr := parties[0].UpdateFromBytes(bz, out.GetFrom(), out.IsBroadcast())
+			assert.Nil(t, tssErr)
+		}
+	}
+	bz, _, err := msg.WireBytes()
+	assert.NoError(t, err)
+
+	_, err = tss.ParseWireMessage(bz, pIDs[0], msg.IsBroadcast())
+	assert.NoError(t, err, "a signed message should parse")
+
+	_, err = tss.ParseWireMessage(bz, pIDs[1], msg.IsBroadcast())
+	assert.Error(t, err, "a message attributed to another party should be rejected")
+
+	_, err = tss.ParseWireMessage(bz, pIDs[0], !msg.IsBroadcast())
+	assert.Error(t, err, "a broadcast delivered as P2P should be rejected")
+
+	tampered := append([]byte(nil), bz...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = tss.ParseWireMessage(tampered, pIDs[0], msg.IsBroadcast())
+	assert.Error(t, err, "an altered message should be rejected")
+
+	_, err = tss.ParseWireMessage(bz[ed25519.SignatureSize:], pIDs[0], msg.IsBroadcast())
+	assert.Error(t, err, "an unsigned message should be rejected")
+
+	_, err = tss.ParseWireMessageInSession(bz, pIDs[0], msg.IsBroadcast(), []byte("another session"))
+	assert.Error(t, err, "a message replayed in another session should be rejected")
+
+	// the round 1 messages of the others move P0 to round 2, whose P2P messages are each signed for their recipient
+	var p2p tss.Message
+	for p2p == nil || p2p.IsBroadcast() {
+		p2p = <-outCh
+	}
+	bz, _, err = p2p.WireBytes()
+	assert.NoError(t, err)
+	to := p2p.GetTo()[0].Index
+	_, tssErr := parties[to].UpdateFromBytes(bz, pIDs[0], false)
+	assert.Nil(t, tssErr, "a P2P message should be accepted by its recipient")
+	other := parties[(to%(len(parties)-1))+1]
+	_, tssErr = other.UpdateFromBytes(bz, pIDs[0], false)
+	if assert.NotNil(t, tssErr, "a P2P message redirected to another party should be rejected") {
+		assert.Equal(t, tss.CodeInvalidMessage, tssErr.Code())
+	}
+}
+
+func TestRoundTimeout(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+	resendCh := make(chan *tss.ResendRequest, 1)
+	abortCh := make(chan *tss.Error, 1)
+
+	params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), testThreshold)
+	params.SetRoundTimeout(&tss.RoundTimeout{
+		Deadline:   50 * time.Millisecond,
+		MaxResends: 1,
+		OnResend:   func(req *tss.ResendRequest) { resendCh <- req },
+		OnAbort:    func(err *tss.Error) { abortCh <- err },
+	})
+	P := NewLocalParty(params, outCh, endCh).(*LocalParty)
+	assert.Nil(t, P.Start())
+
+	// no other party ever sends its round 1 message
+	req := <-resendCh
+	assert.Equal(t, 1, req.Round)
+	assert.Equal(t, pIDs[0], req.From)
+	assert.Len(t, req.To, len(pIDs)-1, "every other party should be asked to resend")
+
+	err := <-abortCh
+	assert.Len(t, err.Culprits(), len(pIDs)-1, "the silent parties should be blamed")
+	assert.Equal(t, 1, err.Round())
+	assert.Equal(t, tss.CodeTimeout, err.Code())
+	assert.True(t, err.Code().Retryable())
+
+	other := NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[1], len(pIDs), testThreshold), outCh, endCh)
+	assert.Nil(t, other.Start())
+	for msg := range outCh {
+		if msg.GetFrom() != pIDs[1] {
+			continue
+		}
+		ok, tssErr := P.Update(msg.(tss.ParsedMessage))
+		assert.False(t, ok)
+		assert.Equal(t, err, tssErr, "an aborted party should reject every update")
+		break
+	}
+}
+
+// runTestParties starts the parties and delivers their messages until all of them have ended
+func runTestParties(t *testing.T, parties []*LocalParty, outCh chan tss.Message, endCh chan *LocalPartySaveData) {
+	errCh := make(chan *tss.Error, len(parties))
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	ended := 0
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go test.SharedPartyUpdater(P, msg, errCh)
+				}
+			} else {
+				go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+			}
+		case <-endCh:
+			if ended++; ended == len(parties) {
+				return
+			}
+		}
+	}
+}
+
+type testMetrics struct {
+	mtx            sync.Mutex
+	sent, received int
+	rounds         map[int]int
+	started, ended int
+	errs           []*tss.Error
+}
+
+func (m *testMetrics) MessageSent(msgType string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.sent++
+}
+
+func (m *testMetrics) MessageReceived(msgType string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.received++
+}
+
+func (m *testMetrics) RoundFinished(task string, round int, elapsed time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.rounds[round]++
+}
+
+func (m *testMetrics) SessionStarted(task string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.started++
+}
+
+func (m *testMetrics) SessionEnded(task string, err *tss.Error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.ended++
+	if err != nil {
+		m.errs = append(m.errs, err)
+	}
+}
+
+func TestRoundHooksAndMetrics(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	var mtx sync.Mutex
+	var started, finished []int
+	var received int32
+	metrics := &testMetrics{rounds: make(map[int]int)}
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetMetrics(metrics)
+		P := NewLocalParty(params, outCh, endCh).(*LocalParty)
+		if i == 0 {
+			P.SetOnRoundStarted(func(round int) {
+				mtx.Lock()
+				defer mtx.Unlock()
+				started = append(started, round)
+			})
+			P.SetOnRoundFinished(func(round int, elapsed time.Duration) {
+				mtx.Lock()
+				defer mtx.Unlock()
+				assert.True(t, 0 <= elapsed)
+				finished = append(finished, round)
+			})
+			P.SetOnMessageReceived(func(msg tss.ParsedMessage) { atomic.AddInt32(&received, 1) })
+		}
+		parties = append(parties, P)
+	}
+	runTestParties(t, parties, outCh, endCh)
+
+	// the last hooks of party 0 run once it has been unlocked, just after its save data was sent
+	assert.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(finished) == 3
+	}, time.Second, 10*time.Millisecond)
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.Equal(t, []int{1, 2, 3}, started)
+	assert.Equal(t, []int{1, 2, 3}, finished)
+	// a broadcast in round 1, and a share and a broadcast in round 2 from every other party
+	assert.Equal(t, int32(3*(len(pIDs)-1)), atomic.LoadInt32(&received))
+
+	n := len(pIDs)
+	// the parties end their sessions just after their save data was sent
+	assert.Eventually(t, func() bool {
+		metrics.mtx.Lock()
+		defer metrics.mtx.Unlock()
+		return metrics.ended == n
+	}, time.Second, 10*time.Millisecond)
+	metrics.mtx.Lock()
+	defer metrics.mtx.Unlock()
+	assert.Equal(t, n*(n+1), metrics.sent, "every party sends two broadcasts and a share to every other party")
+	assert.Equal(t, n*3*(n-1), metrics.received)
+	assert.Equal(t, map[int]int{1: n, 2: n, 3: n}, metrics.rounds)
+	assert.Equal(t, n, metrics.started)
+	assert.Equal(t, n, metrics.ended)
+	assert.Empty(t, metrics.errs)
+}
+
+func TestDuplicateMessages(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, 2*len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	newParty := func(i int) *LocalParty {
+		P := NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold), outCh, endCh).(*LocalParty)
+		assert.Nil(t, P.Start())
+		return P
+	}
+	P0 := newParty(0)
+	<-outCh
+	newParty(1)
+	msg := (<-outCh).(tss.ParsedMessage)
+
+	ok, err := P0.Update(msg)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	ok, err = P0.Update(msg)
+	assert.True(t, ok, "an identical copy should be ignored")
+	assert.Nil(t, err)
+
+	// party 1 restarted with fresh randomness sends a different round 1 message
+	newParty(1)
+	equivocation := (<-outCh).(tss.ParsedMessage)
+	ok, err = P0.Update(equivocation)
+	assert.False(t, ok)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, tss.CodeDuplicateMessage, err.Code())
+		assert.True(t, err.Code().Blame())
+		assert.Equal(t, pIDs[1], err.Culprits()[0])
+	}
+}
+
+type (
+	testTracer struct {
+		mtx   sync.Mutex
+		spans []*testSpan
+	}
+
+	testSpan struct {
+		tr           *testTracer
+		name, parent string
+		ended        bool
+		err          error
+	}
+
+	testSpanKey struct{}
+)
+
+func (tr *testTracer) Start(ctx context.Context, name string) (context.Context, tss.Span) {
+	tr.mtx.Lock()
+	defer tr.mtx.Unlock()
+	parent, _ := ctx.Value(testSpanKey{}).(string)
+	span := &testSpan{tr: tr, name: name, parent: parent}
+	tr.spans = append(tr.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, name), span
+}
+
+func (tr *testTracer) Inject(ctx context.Context) map[string]string {
+	parent, _ := ctx.Value(testSpanKey{}).(string)
+	return map[string]string{"span": parent}
+}
+
+func (tr *testTracer) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return context.WithValue(ctx, testSpanKey{}, carrier["span"])
+}
+
+func (sp *testSpan) End(err error) {
+	sp.tr.mtx.Lock()
+	defer sp.tr.mtx.Unlock()
+	sp.ended, sp.err = true, err
+}
+
+func TestTracing(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*LocalParty, 0, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	tracer := &testTracer{}
+	for i := 0; i < len(pIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		params.SetContext(context.WithValue(context.Background(), testSpanKey{}, pIDs[i].Moniker))
+		params.SetTracer(tracer)
+		parties = append(parties, NewLocalParty(params, outCh, endCh).(*LocalParty))
+	}
+	runTestParties(t, parties, outCh, endCh)
+
+	n := len(pIDs)
+	assert.Eventually(t, func() bool {
+		tracer.mtx.Lock()
+		defer tracer.mtx.Unlock()
+		for _, span := range tracer.spans {
+			if !span.ended {
+				return false
+			}
+		}
+		return len(tracer.spans) == n*3+n*3*(n-1)
+	}, time.Second, 10*time.Millisecond, "every party should trace its 3 rounds and the messages it received")
+
+	tracer.mtx.Lock()
+	defer tracer.mtx.Unlock()
+	monikers := make(map[string]bool, n)
+	for _, pID := range pIDs {
+		monikers[pID.Moniker] = true
+	}
+	for _, span := range tracer.spans {
+		assert.NoError(t, span.err)
+		// round spans are children of their party's context, and message spans of their sender's
+		assert.True(t, monikers[span.parent], "span %s has an unexpected parent %q", span.name, span.parent)
+	}
+}
+
+// runSteppers runs keygen with a Stepper for each of `params`, delivering the wire bytes of the messages one at a
+// time on this goroutine. `deliver`, if not nil, reports whether to deliver a message to the party of index `to` now;
+// the messages it holds back are delivered, last first, once no other message is pending. It returns the save data
+// of the parties in the order of `params`, or nil if they did not all finish.
+func runSteppers(t *testing.T, params []*tss.Parameters, deliver func(msg tss.Message, to int) bool) []*LocalPartySaveData {
+	endCh := make(chan *LocalPartySaveData, len(params))
+	steppers := make([]*tss.Stepper, len(params))
+	var pending []tss.Message
+	for i, p := range params {
+		steppers[i] = tss.NewStepper(0, func(out chan<- tss.Message) tss.Party {
+			return NewLocalParty(p, out, endCh)
+		})
+		msgs, err := steppers[i].Start()
+		assert.Nil(t, err)
+		pending = append(pending, msgs...)
+	}
+
+	type heldMessage struct {
+		msg tss.Message
+		to  int
+	}
+	var held []heldMessage
+	step := func(msg tss.Message, to int) {
+		bz, _, err := msg.WireBytes()
+		assert.NoError(t, err)
+		msgs, tssErr := steppers[to].StepFromBytes(bz, msg.GetFrom(), msg.IsBroadcast())
+		assert.Nil(t, tssErr)
+		pending = append(pending, msgs...)
+	}
+	for len(pending) > 0 || len(held) > 0 {
+		for len(pending) > 0 {
+			msg := pending[0]
+			pending = pending[1:]
+			for j := range steppers {
+				if j == msg.GetFrom().Index || (msg.GetTo() != nil && msg.GetTo()[0].Index != j) {
+					continue
+				}
+				if deliver != nil && !deliver(msg, j) {
+					held = append(held, heldMessage{msg, j})
+					continue
+				}
+				step(msg, j)
+			}
+		}
+		for i := len(held) - 1; 0 <= i; i-- {
+			step(held[i].msg, held[i].to)
+		}
+		held = nil
+	}
+
+	if !assert.Len(t, endCh, len(params), "every party should finish") {
+		return nil
+	}
+	saves := make([]*LocalPartySaveData, len(params))
+	for range params {
+		save := <-endCh
+		for i, p := range params {
+			if p.PartyID().KeyInt().Cmp(save.ShareID) == 0 {
+				saves[i] = save
+			}
+		}
+	}
+	return saves
+}
+
+func TestStepper(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := make([]*tss.Parameters, len(pIDs))
+	for i := range pIDs {
+		params[i] = tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+	}
+
+	// deliver the messages one at a time, in order, on this goroutine only
+	saves := runSteppers(t, params, nil)
+	if saves == nil {
+		return
+	}
+	for _, save := range saves[1:] {
+		assert.True(t, saves[0].EDDSAPub.Equals(save.EDDSAPub))
+	}
+}
+
+func TestOutOfOrderMessages(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := make([]*tss.Parameters, len(pIDs))
+	for i := range pIDs {
+		params[i] = tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+	}
+
+	// hold back every message to party 0 until the other parties have sent all they can, so that party 0 gets the
+	// messages of round 2 before those of round 1, without any retry by the caller
+	runSteppers(t, params, func(_ tss.Message, to int) bool {
+		return to != 0
+	})
+}
+
+func TestCompression(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := make([]*tss.Parameters, len(pIDs))
+	for i := range pIDs {
+		params[i] = tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		// only the even parties compress: receivers handle both forms
+		params[i].SetCompression(i%2 == 0)
+	}
+	runSteppers(t, params, func(msg tss.Message, _ int) bool {
+		_, routing, err := msg.WireBytes()
+		assert.NoError(t, err)
+		assert.Equal(t, msg.GetFrom().Index%2 == 0, routing.Compressed)
+		return true
+	})
+
+	// a message that would expand beyond the limit is refused
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(make([]byte, 17<<20))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	_, err = tss.ParseWireMessage(buf.Bytes(), pIDs[1], true)
+	assert.Error(t, err)
+}
+
+func TestProtocolVersion(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), testThreshold)
+	P0 := NewLocalParty(params, outCh, endCh).(*LocalParty)
+	assert.Nil(t, P0.Start())
+	msg := <-outCh
+	assert.Equal(t, tss.ProtocolVersion, msg.WireMsg().GetProtocolVersion())
+	bz, _, err := msg.WireBytes()
+	assert.NoError(t, err)
+	pMsg, err := tss.ParseWireMessage(bz, pIDs[0], msg.IsBroadcast())
+	assert.NoError(t, err)
+	assert.Equal(t, tss.ProtocolVersion, pMsg.WireMsg().GetProtocolVersion())
+
+	// the header is a zero byte followed by the version and the oldest compatible version
+	content := bz[3:]
+	withVersions := func(version, minVersion byte) []byte {
+		return append([]byte{0, version, minVersion}, content...)
+	}
+	_, err = tss.ParseWireMessage(withVersions(byte(tss.ProtocolVersion)+5, byte(tss.ProtocolVersion)), pIDs[0], msg.IsBroadcast())
+	assert.NoError(t, err, "a newer sender that is still compatible should be accepted")
+	_, err = tss.ParseWireMessage(withVersions(byte(tss.ProtocolVersion)+5, byte(tss.ProtocolVersion)+1), pIDs[0], msg.IsBroadcast())
+	assert.ErrorIs(t, err, tss.ErrIncompatibleProtocol)
+	_, err = tss.ParseWireMessage(content, pIDs[0], msg.IsBroadcast())
+	assert.ErrorIs(t, err, tss.ErrIncompatibleProtocol, "an unversioned message should be rejected")
+
+	P1 := NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[1], len(pIDs), testThreshold), outCh, endCh).(*LocalParty)
+	_, tssErr := P1.UpdateFromBytes(content, pIDs[0], msg.IsBroadcast())
+	if assert.NotNil(t, tssErr) {
+		assert.Equal(t, tss.CodeInvalidMessage, tssErr.Code())
+		assert.ErrorIs(t, tssErr.Cause(), tss.ErrIncompatibleProtocol)
+	}
+}
+
+func TestCBOREncoding(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	params := make([]*tss.Parameters, len(pIDs))
+	for i := range pIDs {
+		params[i] = tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		// only the odd parties use CBOR: receivers handle both encodings
+		if i%2 == 1 {
+			params[i].SetEncoding(tss.EncodingCBOR)
+		}
+	}
+	saves := runSteppers(t, params, func(msg tss.Message, _ int) bool {
+		if msg.GetFrom().Index%2 == 1 {
+			bz, _, err := msg.WireBytes()
+			assert.NoError(t, err)
+			// after the version header, which asks for version 2, comes the array [type URL, content]
+			assert.Equal(t, []byte{0, byte(tss.ProtocolVersion), 2, 0x82}, bz[:4])
+		}
+		return true
+	})
+	if saves == nil {
+		return
+	}
+
+	save := saves[0]
+	jsonBz, err := tss.MarshalSaveData(save, tss.EncodingProtobuf)
+	assert.NoError(t, err)
+	cborBz, err := tss.MarshalSaveData(save, tss.EncodingCBOR)
+	assert.NoError(t, err)
+	assert.Less(t, len(cborBz), len(jsonBz))
+	for _, bz := range [][]byte{jsonBz, cborBz} {
+		var parsed LocalPartySaveData
+		assert.NoError(t, tss.UnmarshalSaveData(bz, &parsed))
+		assert.Equal(t, save.Xi, parsed.Xi)
+		assert.True(t, save.EDDSAPub.Equals(parsed.EDDSAPub))
+		assert.Equal(t, save.Ks, parsed.Ks)
+	}
+}
+
+func TestAccessStructure(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(6)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	// 2 of: {2 of p0, p1, p2}, {1 of p3, p4}, {p5}
+	as := tss.NewNestedAccessStructure("root", 2,
+		tss.NewAccessStructure("a", 2, pIDs[0], pIDs[1], pIDs[2]),
+		tss.NewAccessStructure("b", 1, pIDs[3], pIDs[4]),
+		tss.NewAccessStructure("c", 1, pIDs[5]),
+	)
+
+	// the threshold must match the structure
+	mismatched := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), as.MinSigners())
+	mismatched.SetAccessStructure(as)
+	P := NewLocalParty(mismatched, make(chan tss.Message, len(pIDs)), make(chan *LocalPartySaveData, 1))
+	if err := P.Start(); assert.NotNil(t, err) {
+		assert.Equal(t, tss.CodeInvalidInput, err.Code())
+	}
+
+	params := make([]*tss.Parameters, len(pIDs))
+	for i := range pIDs {
+		params[i] = tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), as.MinSigners()-1)
+		params[i].SetAccessStructure(as)
+	}
+	saves := runSteppers(t, params, nil)
+	if saves == nil {
+		return
+	}
+
+	ec := tss.Edwards()
+	for i, save := range saves {
+		assert.Equal(t, as, save.AccessStructure)
+		assert.True(t, crypto.ScalarBaseMult(ec, save.Xi).Equals(save.BigXj[i]))
+	}
+
+	// the shares of a set chosen by Resolve recombine the key
+	signers, err := as.Resolve([]*tss.PartyID{pIDs[1], pIDs[2], pIDs[4], pIDs[5]})
+	assert.NoError(t, err)
+	keys := make([]*big.Int, len(signers))
+	for i, p := range signers {
+		keys[i] = p.KeyInt()
+	}
+	coefs, err := as.Coefficients(ec, keys)
+	assert.NoError(t, err)
+	modQ := common.ModInt(ec.Params().N)
+	sk := big.NewInt(0)
+	for i, p := range signers {
+		sk = modQ.Add(sk, modQ.Mul(coefs[i], saves[p.Index].Xi))
+	}
+	assert.True(t, crypto.ScalarBaseMult(ec, sk).Equals(saves[0].EDDSAPub))
+}
+
+func TestRateLimit(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *LocalPartySaveData, len(pIDs))
+
+	P0 := NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), testThreshold), outCh, endCh).(*LocalParty)
+	assert.Nil(t, P0.Start())
+	msg := (<-outCh).(tss.ParsedMessage)
+
+	// three messages at once, none refilled during the test, and one strike allowed
+	limiter := tss.NewRateLimiter(0.001, 3, 1)
+	P1 := NewLocalParty(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[1], len(pIDs), testThreshold), outCh, endCh).(*LocalParty)
+	P1.SetRateLimiter(limiter)
+	assert.Nil(t, P1.Start())
+
+	ok, err := P1.Update(msg)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	// identical copies are ignored and earn no strike
+	for i := 1; i <= 2; i++ {
+		ok, err = P1.Update(msg)
+		assert.True(t, ok)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, limiter.Strikes(pIDs[0]))
+	}
+
+	// the bucket is empty: the next message is dropped with a strike, and the second strike gets the sender reported
+	_, err = P1.Update(msg)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, tss.CodeRateLimited, err.Code())
+		assert.ErrorIs(t, err.Cause(), tss.ErrRateLimited)
+		assert.Empty(t, err.Culprits())
+		assert.Equal(t, 1, limiter.Strikes(pIDs[0]))
+	}
+	_, err = P1.Update(msg)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, tss.CodeRateLimited, err.Code())
+		assert.Equal(t, []*tss.PartyID{pIDs[0]}, err.Culprits())
+		assert.True(t, err.Code().Blame())
+	}
+
+	limiter.Forgive(pIDs[0])
+	assert.Equal(t, 0, limiter.Strikes(pIDs[0]))
+	ok, err = P1.Update(msg)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+}
+
+func TestNewSession(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+
+	sessions := make([]*Session, len(pIDs))
+	for i := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[i], len(pIDs), testThreshold)
+		sessions[i] = NewSession(params, 2*len(pIDs))
+		assert.Nil(t, sessions[i].Start())
+	}
+	for done := false; !done; {
+		done = true
+		for _, S := range sessions {
+			for len(S.Out) > 0 {
+				done = false
+				msg := (<-S.Out).(tss.ParsedMessage)
+				for j, P := range sessions {
+					if j == msg.GetFrom().Index || (msg.GetTo() != nil && msg.GetTo()[0].Index != j) {
+						continue
+					}
+					_, err := P.Update(msg)
+					assert.Nil(t, err)
+				}
+			}
+		}
+	}
+	var pub *crypto.ECPoint
+	for _, S := range sessions {
+		select {
+		case save := <-S.End:
+			if pub == nil {
+				pub = save.EDDSAPub
+			}
+			assert.True(t, pub.Equals(save.EDDSAPub))
+		default:
+			t.Fatalf("the session of %s did not end", S.PartyID())
+		}
+	}
+}
+
+func TestSimulator(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(testParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	newSimulator := func(seed int64, faults test.Faults) (*test.Simulator, chan *LocalPartySaveData) {
+		endCh := make(chan *LocalPartySaveData, len(pIDs))
+		sim := test.NewSimulator(seed, faults)
+		for _, pid := range pIDs {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, pid, len(pIDs), testThreshold)
+			sim.Add(func(out chan<- tss.Message) tss.Party { return NewLocalParty(params, out, endCh) })
+		}
+		return sim, endCh
+	}
+
+	// reordered and duplicated messages are buffered and ignored
+	sim, endCh := newSimulator(1, test.Faults{MinLatency: time.Millisecond, MaxLatency: 50 * time.Millisecond, DuplicateRate: 0.3})
+	sim.Start()
+	sim.Run(0)
+	assert.Empty(t, sim.Errors())
+	assert.Len(t, endCh, len(pIDs))
+	assert.True(t, sim.Stats().Duplicated > 0)
+	assert.True(t, sim.Now() > 0)
+
+	// dropped messages stall the parties waiting for them, until they are sent again
+	sim, endCh = newSimulator(2, test.Faults{})
+	sim.SetLinkFaults(pIDs[0], pIDs[1], test.Faults{DropRate: 1})
+	sim.Start()
+	sim.Run(0)
+	assert.Empty(t, sim.Errors())
+	assert.Equal(t, []*tss.PartyID{pIDs[0]}, sim.Stalled()[pIDs[1]])
+	assert.True(t, sim.ResendDropped() > 0)
+	sim.Run(0)
+	assert.Empty(t, sim.Stalled())
+	assert.Len(t, endCh, len(pIDs))
+
+	// corrupted messages are refused
+	sim, _ = newSimulator(3, test.Faults{})
+	sim.SetLinkFaults(pIDs[2], pIDs[3], test.Faults{CorruptRate: 1})
+	sim.Start()
+	sim.Run(0)
+	if assert.NotEmpty(t, sim.Errors()) {
+		assert.Equal(t, pIDs[3], sim.Errors()[0].To)
+	}
+}
+
+func TestSaveDataChecksum(t *testing.T) {
+	keys, _, err := LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	assert.Zero(t, keys[0].Version, "the fixtures predate versioned save data")
+
+	bz, err := json.Marshal(&keys[0])
+	assert.NoError(t, err)
+	var loaded LocalPartySaveData
+	assert.NoError(t, json.Unmarshal(bz, &loaded))
+	assert.Equal(t, SaveDataVersion, loaded.Version)
+	assert.Equal(t, keys[0].Xi, loaded.Xi)
+	assert.True(t, keys[0].EDDSAPub.Equals(loaded.EDDSAPub))
+
+	// alter the share in the JSON
+	altered := bytes.Replace(bz, []byte(keys[0].Xi.String()), []byte(new(big.Int).Add(keys[0].Xi, big.NewInt(1)).String()), 1)
+	assert.NotEqual(t, bz, altered)
+	assert.EqualError(t, json.Unmarshal(altered, &loaded), "the save data does not match its checksum; it was truncated or altered")
+
+	newer := bytes.Replace(bz, []byte(`"Version":1`), []byte(`"Version":2`), 1)
+	assert.Error(t, json.Unmarshal(newer, &loaded))
+}
@@ -8,6 +8,7 @@ package keygen
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
@@ -20,15 +21,15 @@ import (
 var zero = big.NewInt(0)
 
 // round 1 represents round 1 of the keygen part of the EDDSA TSS spec
-func newRound1(params *tss.Parameters, save *LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *LocalPartySaveData) tss.Round {
+func newRound1(params *tss.Parameters, save *LocalPartySaveData, temp *localTempData, out chan<- tss.Message, end chan<- *LocalPartySaveData, transcript chan<- *Transcript) tss.Round {
 	return &round1{
-		&base{params, save, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1},
+		&base{params, save, temp, out, end, transcript, make([]bool, len(params.Parties().IDs())), false, 1},
 	}
 }
 
 func (round *round1) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 1
 	round.started = true
@@ -48,13 +49,23 @@ func (round *round1) Start() *tss.Error {
 	ui := common.GetRandomPositiveInt(round.PartialKeyRand(), round.Params().EC().Params().N)
 	round.temp.ui = ui
 
-	// 2. compute the vss shares
+	// 2. compute the vss shares, along the access structure if there is one
 	ids := round.Parties().IDs().Keys()
-	vs, shares, err := vss.Create(round.EC(), round.Threshold(), ui, ids, round.Rand())
+	if as := round.AccessStructure(); as != nil {
+		if err := as.Validate(round.Parties().IDs()); err != nil {
+			return round.WrapError(err, Pi).WithCode(tss.CodeInvalidInput)
+		}
+		if round.Threshold() != as.MinSigners()-1 {
+			return round.WrapError(fmt.Errorf("the threshold must be %d for an access structure of %d signers, got %d",
+				as.MinSigners()-1, as.MinSigners(), round.Threshold()), Pi).WithCode(tss.CodeInvalidInput)
+		}
+	}
+	vs, shares, err := vss.CreateWithAccessStructure(round.EC(), round.Threshold(), round.AccessStructure(), ui, ids, round.Rand())
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
 	round.save.Ks = ids
+	round.save.AccessStructure = round.AccessStructure()
 
 	// security: the original u_i may be discarded
 	ui = zero // clears the secret data from memory
@@ -65,7 +76,7 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err, Pi)
 	}
-	cmt := cmts.NewHashCommitment(round.Rand(), pGFlat...)
+	cmt := cmts.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagEDDSAKeygenCommitment), round.Rand(), pGFlat...)
 
 	// for this P: SAVE
 	// - shareID
@@ -82,7 +93,7 @@ func (round *round1) Start() *tss.Error {
 	{
 		msg := NewKGRound1Message(round.PartyID(), cmt.C)
 		round.temp.kgRound1Messages[i] = msg
-		round.out <- msg
+		round.out <- round.Outbound(msg)
 	}
 	return nil
 }
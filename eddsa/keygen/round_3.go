@@ -22,7 +22,7 @@ import (
 
 func (round *round3) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 3
 	round.started = true
@@ -42,9 +42,11 @@ func (round *round3) Start() *tss.Error {
 		xi = new(big.Int).Add(xi, share)
 	}
 	round.save.Xi = new(big.Int).Mod(xi, round.Params().EC().Params().N)
+	round.save.HashScheme = round.HashScheme()
+	round.save.Metadata = tss.NewKeyMetadata(round.Params().EC(), round.Threshold(), len(Ps), round.HashScheme())
 
 	// 2-3.
-	Vc := make(vss.Vs, round.Threshold()+1)
+	Vc := make(vss.Vs, len(round.temp.vs))
 	for c := range Vc {
 		Vc[c] = round.temp.vs[c] // ours
 	}
@@ -73,10 +75,10 @@ func (round *round3) Start() *tss.Error {
 			KGCj := round.temp.KGCs[j]
 			r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
 			KGDj := r2msg2.UnmarshalDeCommitment()
-			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj}
+			cmtDeCmt := commitments.HashCommitDecommit{C: KGCj, D: KGDj, Hasher: round.HashScheme().Tagged(common.PoseidonTagEDDSAKeygenCommitment)}
 			ok, flatPolyGs := cmtDeCmt.DeCommit()
 			if !ok || flatPolyGs == nil {
-				ch <- vssOut{errors.New("de-commitment verify failed"), nil}
+				ch <- vssOut{tss.CodedError(tss.CodeBadCommitment, errors.New("de-commitment verify failed")), nil}
 				return
 			}
 
@@ -91,12 +93,12 @@ func (round *round3) Start() *tss.Error {
 			}
 			proof, err := r2msg2.UnmarshalZKProof(round.Params().EC())
 			if err != nil {
-				ch <- vssOut{errors.New("failed to unmarshal schnorr proof"), nil}
+				ch <- vssOut{tss.CodedError(tss.CodeBadProof, errors.New("failed to unmarshal schnorr proof")), nil}
 				return
 			}
 			ok = proof.Verify(ContextJ, PjVs[0])
 			if !ok {
-				ch <- vssOut{errors.New("failed to prove schnorr proof"), nil}
+				ch <- vssOut{tss.CodedError(tss.CodeBadProof, errors.New("failed to prove schnorr proof")), nil}
 				return
 			}
 			r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
@@ -105,8 +107,8 @@ func (round *round3) Start() *tss.Error {
 				ID:        round.PartyID().KeyInt(),
 				Share:     r2msg1.UnmarshalShare(),
 			}
-			if ok = PjShare.Verify(round.Params().EC(), round.Threshold(), PjVs); !ok {
-				ch <- vssOut{errors.New("vss verify failed"), nil}
+			if ok = PjShare.VerifyWithAccessStructure(round.Params().EC(), round.Threshold(), round.AccessStructure(), PjVs); !ok {
+				ch <- vssOut{tss.CodedError(tss.CodeBadShare, errors.New("vss verify failed")), nil}
 				return
 			}
 			// (9) handled above
@@ -147,7 +149,7 @@ func (round *round3) Start() *tss.Error {
 			}
 			// 11-12.
 			PjVs := vssResults[j].pjVs
-			for c := 0; c <= round.Threshold(); c++ {
+			for c := range Vc {
 				Vc[c], err = Vc[c].Add(PjVs[c])
 				if err != nil {
 					culprits = append(culprits, Pj)
@@ -155,32 +157,25 @@ func (round *round3) Start() *tss.Error {
 			}
 		}
 		if len(culprits) > 0 {
-			return round.WrapError(errors.New("adding PjVs[c] to Vc[c] resulted in a point not on the curve"), culprits...)
+			return round.WrapError(errors.New("adding PjVs[c] to Vc[c] resulted in a point not on the curve"), culprits...).WithCode(tss.CodeBadPublicData)
 		}
 	}
 
 	// 13-17. compute Xj for each Pj
 	{
-		var err error
-		modQ := common.ModInt(round.Params().EC().Params().N)
 		culprits := make([]*tss.PartyID, 0, len(Ps)) // who caused the error(s)
 		bigXj := round.save.BigXj
 		for j := 0; j < round.PartyCount(); j++ {
 			Pj := round.Parties().IDs()[j]
 			kj := Pj.KeyInt()
-			BigXj := Vc[0]
-			z := new(big.Int).SetInt64(int64(1))
-			for c := 1; c <= round.Threshold(); c++ {
-				z = modQ.Mul(z, kj)
-				BigXj, err = BigXj.Add(Vc[c].ScalarMult(z))
-				if err != nil {
-					culprits = append(culprits, Pj)
-				}
+			BigXj, err := vss.EvaluateCommitments(round.Params().EC(), round.AccessStructure(), Vc, kj)
+			if err != nil {
+				culprits = append(culprits, Pj)
 			}
 			bigXj[j] = BigXj
 		}
 		if len(culprits) > 0 {
-			return round.WrapError(errors.New("adding Vc[c].ScalarMult(z) to BigXj resulted in a point not on the curve"), culprits...)
+			return round.WrapError(errors.New("evaluating the commitments Vc at kj resulted in a point not on the curve"), culprits...).WithCode(tss.CodeBadPublicData)
 		}
 		round.save.BigXj = bigXj
 	}
@@ -191,10 +186,23 @@ func (round *round3) Start() *tss.Error {
 		return round.WrapError(errors2.Wrapf(err, "public key is not on the curve"))
 	}
 	round.save.EDDSAPub = eddsaPubKey
+	round.save.Metadata.KeyID = tss.KeyID(round.Params().EC(), eddsaPubKey.X(), eddsaPubKey.Y())
 
 	// PRINT public key & private share
 	common.Logger.Debugf("%s public key: %x", round.PartyID(), eddsaPubKey)
 
+	if round.transcript != nil {
+		tr, err := round.buildTranscript()
+		if err != nil {
+			return round.WrapError(errors2.Wrapf(err, "transcript"))
+		}
+		round.transcript <- tr
+	}
+
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
+	}
+
 	round.end <- round.save
 	return nil
 }
@@ -7,9 +7,15 @@
 package keygen
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
@@ -18,6 +24,8 @@ type (
 	LocalSecrets struct {
 		// secret fields (not shared, but stored locally)
 		Xi, ShareID *big.Int // xi, kj
+		// xi wrapped by a tss.ShareWrapper, in place of Xi (see WrapShare)
+		WrappedXi []byte `json:",omitempty"`
 	}
 
 	// Everything in LocalPartySaveData is saved locally to user's HD when done
@@ -30,17 +38,146 @@ type (
 		// public keys (Xj = uj*G for each Pj)
 		BigXj []*crypto.ECPoint // Xj
 
+		// the hash scheme of the SSIDs of every session on this key (see tss.Parameters.SetHashScheme)
+		HashScheme common.HashScheme
+
+		// who may sign with the key, if it was not shared with a flat threshold (see tss.Parameters.SetAccessStructure)
+		AccessStructure *tss.AccessStructure `json:",omitempty"`
+
+		// the curve, threshold, parties and origin of the key, for tooling that inventories keys (see tss.KeyMetadata)
+		Metadata *tss.KeyMetadata `json:",omitempty"`
+
 		// used for test assertions (may be discarded)
 		EDDSAPub *crypto.ECPoint // y
+
+		// the version of the format of the save data, and the SHA-256 of its JSON without the checksum; both are set
+		// when it is encoded and checked when it is decoded (see MarshalJSON)
+		Version  int    `json:",omitempty"`
+		Checksum []byte `json:",omitempty"`
 	}
+
+	// saveDataJSON is LocalPartySaveData without its JSON methods
+	saveDataJSON LocalPartySaveData
 )
 
+// SaveDataVersion is the version of the format of the save data that this version of the library writes
+const SaveDataVersion = 1
+
 func NewLocalPartySaveData(partyCount int) (saveData LocalPartySaveData) {
 	saveData.Ks = make([]*big.Int, partyCount)
 	saveData.BigXj = make([]*crypto.ECPoint, partyCount)
 	return
 }
 
+// KeyID returns the fingerprint of the key of the save data (see tss.KeyID): the one in its metadata, or else the one
+// of its public key, for save data from before key IDs were recorded
+func (save *LocalPartySaveData) KeyID() string {
+	if save.Metadata != nil && save.Metadata.KeyID != "" {
+		return save.Metadata.KeyID
+	}
+	if save.EDDSAPub == nil {
+		return ""
+	}
+	return tss.KeyID(save.EDDSAPub.Curve(), save.EDDSAPub.X(), save.EDDSAPub.Y())
+}
+
+// Wipe overwrites the share of the save data on a best-effort basis (see common.ZeroInt), once it is stored or no
+// longer needed. The save data can no longer be used, and neither can any copy of it, as they share the same big.Int.
+func (save *LocalPartySaveData) Wipe() {
+	common.ZeroInt(save.Xi)
+	save.Xi = nil
+	for i := range save.WrappedXi {
+		save.WrappedXi[i] = 0
+	}
+}
+
+// WrapShare wraps Xi with `w` into WrappedXi and zeroes Xi, so that the share is only in memory while a signing
+// session derives its part of the signing key from it (see tss.Parameters.SetShareWrapper). Refresh, resharing and
+// repair need the share itself; restore it with UnwrapShare before them.
+func (save *LocalPartySaveData) WrapShare(w tss.ShareWrapper) error {
+	if save.Xi == nil {
+		return errors.New("the save data holds no share to wrap")
+	}
+	wrapped, err := w.WrapShare(save.Xi)
+	if err != nil {
+		return err
+	}
+	save.WrappedXi = wrapped
+	common.ZeroInt(save.Xi)
+	save.Xi = nil
+	return nil
+}
+
+// UnwrapShare restores Xi from WrappedXi with `w`
+func (save *LocalPartySaveData) UnwrapShare(w tss.ShareWrapper) error {
+	if save.Xi != nil {
+		return nil
+	}
+	xi, err := w.UnwrapShare(save.WrappedXi)
+	if err != nil {
+		return err
+	}
+	save.Xi, save.WrappedXi = xi, nil
+	return nil
+}
+
+// SigningShare returns the share of the party for a signing session: Xi, or else WrappedXi unwrapped with `w`. An
+// unwrapped share is a copy of its own that the caller zeroes with common.ZeroInt as soon as it is done with it.
+func (save *LocalPartySaveData) SigningShare(w tss.ShareWrapper) (xi *big.Int, unwrapped bool, err error) {
+	if save.Xi != nil {
+		return save.Xi, false, nil
+	}
+	if len(save.WrappedXi) == 0 {
+		return nil, false, errors.New("the save data holds no share")
+	}
+	if w == nil {
+		return nil, false, errors.New("the share is wrapped, but no share wrapper is set (see tss.Parameters.SetShareWrapper)")
+	}
+	if xi, err = w.UnwrapShare(save.WrappedXi); err != nil {
+		return nil, false, fmt.Errorf("unwrap the share: %w", err)
+	}
+	return xi, true, nil
+}
+
+// MarshalJSON encodes the save data with SaveDataVersion and its checksum
+func (save LocalPartySaveData) MarshalJSON() ([]byte, error) {
+	save.Version, save.Checksum = SaveDataVersion, nil
+	body, err := json.Marshal(saveDataJSON(save))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	save.Checksum = sum[:]
+	return json.Marshal(saveDataJSON(save))
+}
+
+// UnmarshalJSON decodes the save data, and refuses it if it is of a version newer than SaveDataVersion or does not
+// match its checksum, e.g. because it was truncated or altered, so that it fails when loaded rather than in a session.
+// Save data written before versions were introduced has neither, and is decoded as it is.
+func (save *LocalPartySaveData) UnmarshalJSON(bz []byte) error {
+	var decoded saveDataJSON
+	if err := json.Unmarshal(bz, &decoded); err != nil {
+		return err
+	}
+	if decoded.Version > SaveDataVersion {
+		return fmt.Errorf("the save data is of version %d, newer than this library reads (%d)", decoded.Version, SaveDataVersion)
+	}
+	if decoded.Version > 0 || decoded.Checksum != nil {
+		checksum := decoded.Checksum
+		decoded.Checksum = nil
+		body, err := json.Marshal(decoded)
+		if err != nil {
+			return err
+		}
+		if sum := sha256.Sum256(body); !bytes.Equal(checksum, sum[:]) {
+			return errors.New("the save data does not match its checksum; it was truncated or altered")
+		}
+		decoded.Checksum = checksum
+	}
+	*save = LocalPartySaveData(decoded)
+	return nil
+}
+
 // BuildLocalSaveDataSubset re-creates the LocalPartySaveData to contain data for only the list of signing parties.
 func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.SortedPartyIDs) LocalPartySaveData {
 	keysToIndices := make(map[string]int, len(sourceData.Ks))
@@ -50,6 +187,9 @@ func BuildLocalSaveDataSubset(sourceData LocalPartySaveData, sortedIDs tss.Sorte
 	newData := NewLocalPartySaveData(sortedIDs.Len())
 	newData.LocalSecrets = sourceData.LocalSecrets
 	newData.EDDSAPub = sourceData.EDDSAPub
+	newData.HashScheme = sourceData.HashScheme
+	newData.AccessStructure = sourceData.AccessStructure
+	newData.Metadata = sourceData.Metadata
 	for j, id := range sortedIDs {
 		savedIdx, ok := keysToIndices[hex.EncodeToString(id.Key)]
 		if !ok {
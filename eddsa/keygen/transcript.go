@@ -0,0 +1,153 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keygen
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/schnorr"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Transcript is the public record of a keygen session: everything that was broadcast, and digests of the P2P shares
+// received by the party that emitted it. It holds no secret and marshals to JSON, so that any third party can check
+// offline with Verify that the key and the public shares were derived correctly from the parties' commitments.
+// Transcripts emitted by the parties of the same session differ only in their ShareHashes.
+type Transcript struct {
+	HashScheme common.HashScheme
+	Threshold  int
+	// the access structure the key was shared along, if any (see tss.Parameters.SetAccessStructure)
+	AccessStructure *tss.AccessStructure `json:",omitempty"`
+
+	// party keys, in the order of the sorted party IDs
+	Ks []*big.Int
+	// the explicit session ID, if any (see tss.Parameters.SetSessionID)
+	SessionID []byte
+	SSID      []byte
+
+	// for each Pj: the round 1 commitment, its round 2 opening to the VSS polynomial in the exponent, and the Schnorr
+	// proof of knowledge of its constant term
+	Commitments   []*big.Int
+	DeCommitments [][]*big.Int
+	Proofs        []*schnorr.ZKProof
+
+	// for each Pj: SHA512_256(SSID, share) of the share that Pj sent to the emitting party
+	ShareHashes [][]byte
+
+	BigXj    []*crypto.ECPoint
+	EDDSAPub *crypto.ECPoint
+}
+
+// buildTranscript collects the transcript from the messages of a finished session
+func (round *round3) buildTranscript() (*Transcript, error) {
+	partyCount := round.PartyCount()
+	tr := &Transcript{
+		HashScheme:      round.HashScheme(),
+		Threshold:       round.Threshold(),
+		AccessStructure: round.AccessStructure(),
+		Ks:              round.save.Ks,
+		SessionID:       round.SessionID(),
+		SSID:            round.temp.ssid,
+		Commitments:     make([]*big.Int, partyCount),
+		DeCommitments:   make([][]*big.Int, partyCount),
+		Proofs:          make([]*schnorr.ZKProof, partyCount),
+		ShareHashes:     make([][]byte, partyCount),
+		BigXj:           round.save.BigXj,
+		EDDSAPub:        round.save.EDDSAPub,
+	}
+	for j := 0; j < partyCount; j++ {
+		tr.Commitments[j] = round.temp.KGCs[j]
+		r2msg2 := round.temp.kgRound2Message2s[j].Content().(*KGRound2Message2)
+		tr.DeCommitments[j] = r2msg2.UnmarshalDeCommitment()
+		proof, err := r2msg2.UnmarshalZKProof(round.Params().EC())
+		if err != nil {
+			return nil, err
+		}
+		tr.Proofs[j] = proof
+		r2msg1 := round.temp.kgRound2Message1s[j].Content().(*KGRound2Message1)
+		tr.ShareHashes[j] = common.SHA512_256(round.temp.ssid, r2msg1.GetShare())
+	}
+	return tr, nil
+}
+
+// Verify checks the transcript of a session on the curve `ec`: the SSID, the opening of every commitment, every
+// Schnorr proof, and that the public key and the public shares BigXj follow from the committed polynomials.
+// The ShareHashes cannot be checked without the shares.
+func (tr *Transcript) Verify(ec elliptic.Curve) error {
+	partyCount := len(tr.Ks)
+	if partyCount == 0 || tr.Threshold < 0 || tr.Threshold >= partyCount {
+		return errors.New("transcript has an invalid threshold or party count")
+	}
+	if tr.AccessStructure != nil {
+		if err := tr.AccessStructure.ValidateBasic(); err != nil {
+			return err
+		}
+		if tr.Threshold != tr.AccessStructure.MinSigners()-1 {
+			return errors.New("transcript threshold does not match the access structure")
+		}
+	}
+	if len(tr.Commitments) != partyCount || len(tr.DeCommitments) != partyCount || len(tr.Proofs) != partyCount ||
+		len(tr.ShareHashes) != partyCount || len(tr.BigXj) != partyCount || tr.EDDSAPub == nil {
+		return errors.New("transcript is missing entries")
+	}
+	ssid := keygenSSID(ec, tr.HashScheme, tr.Ks, tr.SessionID, 1, big.NewInt(0))
+	if ssid == nil || !bytes.Equal(ssid, tr.SSID) {
+		return errors.New("transcript SSID does not match the session parameters")
+	}
+
+	hasher := tr.HashScheme.Tagged(common.PoseidonTagEDDSAKeygenCommitment)
+	Vc := make([]*crypto.ECPoint, vss.CommitmentsLen(tr.Threshold, tr.AccessStructure))
+	for j := 0; j < partyCount; j++ {
+		cmtDeCmt := commitments.HashCommitDecommit{C: tr.Commitments[j], D: tr.DeCommitments[j], Hasher: hasher}
+		ok, flatPolyGs := cmtDeCmt.DeCommit()
+		if !ok || flatPolyGs == nil {
+			return fmt.Errorf("de-commitment of party %d failed", j)
+		}
+		PjVs, err := crypto.UnFlattenECPoints(ec, flatPolyGs)
+		if err != nil || len(PjVs) != len(Vc) {
+			return fmt.Errorf("party %d committed to an invalid polynomial", j)
+		}
+		for c, PjV := range PjVs {
+			PjVs[c] = PjV.EightInvEight()
+		}
+		ContextJ := common.AppendBigIntToBytesSlice(ssid, big.NewInt(int64(j)))
+		if proof := tr.Proofs[j]; proof == nil || !proof.ValidateBasic() || !proof.Verify(ContextJ, PjVs[0]) {
+			return fmt.Errorf("schnorr proof of party %d failed", j)
+		}
+		for c := range Vc {
+			if j == 0 {
+				Vc[c] = PjVs[c]
+				continue
+			}
+			if Vc[c], err = Vc[c].Add(PjVs[c]); err != nil {
+				return fmt.Errorf("polynomial of party %d: %v", j, err)
+			}
+		}
+	}
+
+	if !tr.EDDSAPub.Equals(Vc[0]) {
+		return errors.New("public key does not match the commitments")
+	}
+	for j, kj := range tr.Ks {
+		BigXj, err := vss.EvaluateCommitments(ec, tr.AccessStructure, Vc, kj)
+		if err != nil {
+			return fmt.Errorf("public share of party %d: %v", j, err)
+		}
+		if tr.BigXj[j] == nil || !tr.BigXj[j].Equals(BigXj) {
+			return fmt.Errorf("public share of party %d does not match the commitments", j)
+		}
+	}
+	return nil
+}
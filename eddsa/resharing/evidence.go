@@ -0,0 +1,91 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// ReasonBadShare blames an old committee party for a share that does not match its committed polynomial.
+	// Messages: DGRound1Message, DGRound3Message2, DGRound3Message1 sent to the accuser.
+	ReasonBadShare = "bad vss share"
+)
+
+// VerifyEvidence returns true when the evidence from a failed resharing proves that its culprit misbehaved,
+// or an error when the evidence is malformed. newThreshold is the threshold of the new committee, and scheme the
+// hash scheme of the resharing session. culprit is the party blamed by the evidence as the verifier knows it, with the
+// identity key that its messages must be signed with.
+func VerifyEvidence(ec elliptic.Curve, scheme common.HashScheme, newThreshold int, culprit *tss.PartyID, ev *tss.Evidence) (bool, error) {
+	if ev == nil || ev.Task != TaskName {
+		return false, errors.New("not the evidence of a resharing")
+	}
+	msgs, err := ev.ParseMessages(culprit)
+	if err != nil {
+		return false, err
+	}
+	switch ev.Reason {
+	case ReasonBadShare:
+		if len(msgs) != 3 {
+			return false, fmt.Errorf("expected 3 messages with %q", ev.Reason)
+		}
+		r1msg, ok1 := msgs[0].Content().(*DGRound1Message)
+		r3msg2, ok2 := msgs[1].Content().(*DGRound3Message2)
+		r3msg1, ok3 := msgs[2].Content().(*DGRound3Message1)
+		if !ok1 || !ok2 || !ok3 {
+			return false, fmt.Errorf("unexpected messages with %q", ev.Reason)
+		}
+		return !verifyShare(ec, scheme.Tagged(common.PoseidonTagEDDSAResharingVs), newThreshold, ev.Accuser, r1msg, r3msg2, r3msg1), nil
+	default:
+		return false, fmt.Errorf("unknown evidence reason %q", ev.Reason)
+	}
+}
+
+// verifyShare checks that the share received by Pi opens the committed polynomial of the sender
+func verifyShare(ec elliptic.Curve, hasher common.Hasher, newThreshold int, Pi *tss.PartyID, r1msg *DGRound1Message, r3msg2 *DGRound3Message2, r3msg1 *DGRound3Message1) bool {
+	vj, ok := unpackVs(ec, hasher, newThreshold, r1msg, r3msg2)
+	if !ok {
+		return false
+	}
+	sharej := &vss.Share{
+		Threshold: newThreshold,
+		ID:        Pi.KeyInt(),
+		Share:     new(big.Int).SetBytes(r3msg1.GetShare()),
+	}
+	return sharej.Verify(ec, newThreshold, vj)
+}
+
+// unpackVs de-commits the polynomial commitment of an old committee party, which must be bound to the SSID of its
+// round 1 message
+func unpackVs(ec elliptic.Curve, hasher common.Hasher, newThreshold int, r1msg *DGRound1Message, r3msg2 *DGRound3Message2) ([]*crypto.ECPoint, bool) {
+	vCmtDeCmt := commitments.HashCommitDecommit{C: r1msg.UnmarshalVCommitment(), D: r3msg2.UnmarshalVDeCommitment(), Hasher: hasher}
+	ok, secrets := vCmtDeCmt.DeCommit()
+	if !ok || len(secrets) != 1+(newThreshold+1)*2 { // the SSID, then points so * 2
+		return nil, false
+	}
+	// the polynomial must have been committed to in the session of the round 1 message
+	if secrets[0].Cmp(new(big.Int).SetBytes(r1msg.UnmarshalSSID())) != 0 {
+		return nil, false
+	}
+	vj, err := crypto.UnFlattenECPoints(ec, secrets[1:])
+	if err != nil {
+		return nil, false
+	}
+	for c, v := range vj {
+		vj[c] = v.EightInvEight()
+	}
+	return vj, true
+}
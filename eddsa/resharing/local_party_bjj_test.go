@@ -0,0 +1,172 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing_test
+
+import (
+	"math/big"
+	"testing"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// there are no BabyJubJub fixtures, so the old committee's keys are generated first
+func runBJJKeygen(t *testing.T, pIDs tss.SortedPartyIDs, threshold int) []keygen.LocalPartySaveData {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*keygen.LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for _, pID := range pIDs {
+		params := tss.NewParameters(tss.BabyJubJub(), p2pCtx, pID, len(pIDs), threshold)
+		assert.Equal(t, common.HashPoseidon, params.HashScheme(), "BabyJubJub sessions should default to Poseidon SSIDs")
+		P := keygen.NewLocalParty(params, outCh, endCh).(*keygen.LocalParty)
+		parties = append(parties, P)
+		go func(P *keygen.LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+			keys[index] = *save
+			ended++
+		}
+	}
+	return keys
+}
+
+func TestE2EConcurrentBJJ(t *testing.T) {
+	setUp("info")
+
+	threshold, newThreshold := testThreshold, testThreshold
+
+	// PHASE: keygen
+	oldPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	oldKeys := runBJJKeygen(t, oldPIDs, threshold)
+	eddsaPub := oldKeys[0].EDDSAPub
+
+	// PHASE: resharing
+	oldP2PCtx := tss.NewPeerContext(oldPIDs)
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	newP2PCtx := tss.NewPeerContext(newPIDs)
+	newPCount := len(newPIDs)
+
+	oldCommittee := make([]*LocalParty, 0, len(oldPIDs))
+	newCommittee := make([]*LocalParty, 0, newPCount)
+	bothCommitteesPax := len(oldPIDs) + newPCount
+
+	errCh := make(chan *tss.Error, bothCommitteesPax)
+	outCh := make(chan tss.Message, bothCommitteesPax)
+	endCh := make(chan *keygen.LocalPartySaveData, bothCommitteesPax)
+
+	updater := test.SharedPartyUpdater
+
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.BabyJubJub(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		P := NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty)
+		oldCommittee = append(oldCommittee, P)
+	}
+	for _, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.BabyJubJub(), oldP2PCtx, newP2PCtx, pID, testParticipants, threshold, newPCount, newThreshold)
+		save := keygen.NewLocalPartySaveData(newPCount)
+		P := NewLocalParty(params, save, outCh, endCh).(*LocalParty)
+		newCommittee = append(newCommittee, P)
+	}
+	for _, P := range append(newCommittee, oldCommittee...) {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	newKeys := make([]keygen.LocalPartySaveData, newPCount)
+	for ended := 0; ended < bothCommitteesPax; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				t.Fatal("did not expect a msg to have a nil destination during resharing")
+			}
+			if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest[:len(oldCommittee)] {
+					go updater(oldCommittee[destP.Index], msg, errCh)
+				}
+			}
+			if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest {
+					go updater(newCommittee[destP.Index], msg, errCh)
+				}
+			}
+
+		case save := <-endCh:
+			if save.Xi != nil {
+				index, err := save.OriginalIndex()
+				assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+				newKeys[index] = *save
+			}
+			ended++
+		}
+	}
+
+	// xj tests: BigXj == xj*G, and the public key is unchanged
+	shares := make(vss.Shares, 0, len(newKeys))
+	for j, key := range newKeys {
+		assert.True(t, key.BigXj[j].Equals(crypto.ScalarBaseMult(tss.BabyJubJub(), key.Xi)), "ensure BigX_j == g^x_j")
+		assert.True(t, key.EDDSAPub.Equals(eddsaPub), "the public key must survive resharing")
+		shares = append(shares, &vss.Share{Threshold: newThreshold, ID: key.ShareID, Share: key.Xi})
+	}
+
+	// the new shares reconstruct the original secret
+	x, err := shares[:newThreshold+1].ReConstruct(tss.BabyJubJub())
+	assert.NoError(t, err, "vss.ReConstruct should not throw error")
+	assert.True(t, crypto.ScalarBaseMult(tss.BabyJubJub(), x).Equals(eddsaPub), "reconstructed secret must match the public key")
+
+	// and the key still signs with Poseidon EdDSA
+	pk := iden3bjj.PublicKey{X: eddsaPub.X(), Y: eddsaPub.Y()}
+	sk := iden3bjj.NewPrivKeyScalar(x)
+	data := big.NewInt(200)
+	assert.True(t, pk.VerifyPoseidon(data, sk.SignPoseidonScalar(data)), "signature should be ok")
+}
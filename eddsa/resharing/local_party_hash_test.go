@@ -0,0 +1,270 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package resharing_test
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/eddsa/resharing"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func runKeygenWithScheme(t *testing.T, pIDs tss.SortedPartyIDs, threshold int, scheme common.HashScheme) []keygen.LocalPartySaveData {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*keygen.LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for _, pID := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pID, len(pIDs), threshold)
+		params.SetHashScheme(scheme)
+		P := keygen.NewLocalParty(params, outCh, endCh).(*keygen.LocalParty)
+		parties = append(parties, P)
+		go func(P *keygen.LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+			keys[index] = *save
+			ended++
+		}
+	}
+	return keys
+}
+
+// runs a resharing from `oldPIDs` to `newPIDs`; every party is passed to `configure` before it is started
+func runResharingWithScheme(t *testing.T, oldPIDs tss.SortedPartyIDs, oldKeys []keygen.LocalPartySaveData, newPIDs tss.SortedPartyIDs, threshold int, scheme common.HashScheme, configure ...func(P *LocalParty)) []keygen.LocalPartySaveData {
+	oldP2PCtx, newP2PCtx := tss.NewPeerContext(oldPIDs), tss.NewPeerContext(newPIDs)
+	oldCommittee := make([]*LocalParty, 0, len(oldPIDs))
+	newCommittee := make([]*LocalParty, 0, len(newPIDs))
+	bothCommitteesPax := len(oldPIDs) + len(newPIDs)
+
+	errCh := make(chan *tss.Error, bothCommitteesPax)
+	outCh := make(chan tss.Message, bothCommitteesPax)
+	endCh := make(chan *keygen.LocalPartySaveData, bothCommitteesPax)
+
+	updater := test.SharedPartyUpdater
+
+	for j, pID := range oldPIDs {
+		params := tss.NewReSharingParameters(tss.Edwards(), oldP2PCtx, newP2PCtx, pID, len(oldPIDs), threshold, len(newPIDs), threshold)
+		params.SetHashScheme(scheme)
+		oldCommittee = append(oldCommittee, NewLocalParty(params, oldKeys[j], outCh, endCh).(*LocalParty))
+	}
+	for _, pID := range newPIDs {
+		params := tss.NewReSharingParameters(tss.Edwards(), oldP2PCtx, newP2PCtx, pID, len(oldPIDs), threshold, len(newPIDs), threshold)
+		params.SetHashScheme(scheme)
+		save := keygen.NewLocalPartySaveData(len(newPIDs))
+		newCommittee = append(newCommittee, NewLocalParty(params, save, outCh, endCh).(*LocalParty))
+	}
+	for _, P := range append(newCommittee, oldCommittee...) {
+		for _, fn := range configure {
+			fn(P)
+		}
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	newKeys := make([]keygen.LocalPartySaveData, len(newPIDs))
+	for ended := 0; ended < bothCommitteesPax; {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest[:len(oldCommittee)] {
+					go updater(oldCommittee[destP.Index], msg, errCh)
+				}
+			}
+			if !msg.IsToOldCommittee() || msg.IsToOldAndNewCommittees() {
+				for _, destP := range dest {
+					go updater(newCommittee[destP.Index], msg, errCh)
+				}
+			}
+
+		case save := <-endCh:
+			if save.Xi != nil {
+				index, err := save.OriginalIndex()
+				assert.NoErrorf(t, err, "should not be an error getting a party's index from save data")
+				newKeys[index] = *save
+			}
+			ended++
+		}
+	}
+	return newKeys
+}
+
+// signs with every key, returning the first error reported by a party
+func runSigningWithScheme(t *testing.T, pIDs tss.SortedPartyIDs, keys []keygen.LocalPartySaveData, threshold int, scheme common.HashScheme) (*common.SignatureData, *tss.Error) {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]*signing.LocalParty, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *common.SignatureData, len(pIDs))
+
+	updater := test.SharedPartyUpdater
+
+	for j, pID := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pID, len(pIDs), threshold)
+		params.SetHashScheme(scheme)
+		P := signing.NewLocalParty(big.NewInt(42), params, keys[j], outCh, endCh).(*signing.LocalParty)
+		parties = append(parties, P)
+		go func(P *signing.LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var signData *common.SignatureData
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			return nil, err
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case signData = <-endCh:
+			ended++
+		}
+	}
+	return signData, nil
+}
+
+func TestE2EHashSchemeAcrossProtocols(t *testing.T) {
+	setUp("info")
+
+	scheme := common.HashKeccak256
+
+	// keygen -> resharing -> signing, every session using the scheme recorded in the key
+	oldPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	oldKeys := runKeygenWithScheme(t, oldPIDs, testThreshold, scheme)
+	for _, key := range oldKeys {
+		assert.Equal(t, scheme, key.HashScheme, "keygen should record the hash scheme")
+	}
+
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+	newKeys := runResharingWithScheme(t, oldPIDs, oldKeys, newPIDs, testThreshold, scheme)
+	for _, key := range newKeys {
+		assert.Equal(t, scheme, key.HashScheme, "resharing should record the hash scheme")
+	}
+
+	signData, err := runSigningWithScheme(t, newPIDs, newKeys, testThreshold, scheme)
+	if !assert.Nil(t, err) {
+		return
+	}
+	pk := edwards.PublicKey{
+		Curve: tss.Edwards(),
+		X:     newKeys[0].EDDSAPub.X(),
+		Y:     newKeys[0].EDDSAPub.Y(),
+	}
+	sig, sigErr := edwards.ParseSignature(signData.Signature)
+	assert.NoError(t, sigErr)
+	assert.True(t, edwards.Verify(&pk, big.NewInt(42).Bytes(), sig.R, sig.S), "eddsa verify must pass")
+
+	// a session that uses another scheme than the key is rejected before any message is sent
+	_, err = runSigningWithScheme(t, newPIDs, newKeys, testThreshold, common.HashSHA512_256)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "hash scheme")
+	}
+}
+
+func TestE2EResharingProgress(t *testing.T) {
+	setUp("info")
+
+	oldKeys, oldPIDs, err := keygen.LoadKeygenTestFixtures(testThreshold + 1)
+	assert.NoError(t, err, "should load keygen fixtures")
+	newPIDs := tss.GenerateTestPartyIDs(testParticipants)
+
+	var mtx sync.Mutex
+	rounds := make(map[*LocalParty][]int)
+	percents := make(map[*LocalParty][]float64)
+	runResharingWithScheme(t, oldPIDs, oldKeys, newPIDs, testThreshold, common.HashSHA512_256, func(P *LocalParty) {
+		P.SetOnRoundComplete(func(round int) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			rounds[P] = append(rounds[P], round)
+		})
+		P.SetOnProgress(func(percent float64) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			percents[P] = append(percents[P], percent)
+		})
+	})
+
+	// the last round is reported once the party returns from the Update that sent its save data
+	assert.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		for _, completed := range rounds {
+			if len(completed) < Rounds {
+				return false
+			}
+		}
+		return len(rounds) == len(oldPIDs)+len(newPIDs)
+	}, 5*time.Second, 10*time.Millisecond, "every party should report its progress")
+	mtx.Lock()
+	defer mtx.Unlock()
+	for P, completed := range rounds {
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, completed, "every round should be reported once and in order")
+		assert.Equal(t, []float64{20, 40, 60, 80, 100}, percents[P])
+	}
+}
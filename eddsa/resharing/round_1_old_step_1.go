@@ -9,7 +9,9 @@ package resharing
 import (
 	"errors"
 	"fmt"
+	"math/big"
 
+	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
 	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
@@ -27,7 +29,7 @@ func newRound1(params *tss.ReSharingParameters, input, save *keygen.LocalPartySa
 
 func (round *round1) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 1
 	round.started = true
@@ -38,14 +40,27 @@ func (round *round1) Start() *tss.Error {
 		return nil
 	}
 	round.allOldOK()
+	if err := round.ValidateHashScheme(round.input.HashScheme); err != nil {
+		return round.WrapError(err)
+	}
+	if round.input.AccessStructure != nil {
+		return round.WrapError(errors.New("keys shared along an access structure cannot be reshared")).WithCode(tss.CodeInvalidInput)
+	}
 
 	Pi := round.PartyID()
 	i := Pi.Index
 
+	round.temp.ssidNonce = new(big.Int).SetUint64(0)
+	ssid, err := round.getSSID()
+	if err != nil {
+		return round.WrapError(errors.New("failed to generate ssid")).WithCode(tss.CodeInternal)
+	}
+	round.temp.ssid = ssid
+
 	// 1. PrepareForSigning() -> w_i
 	xi, ks := round.input.Xi, round.input.Ks
 	if round.Threshold()+1 > len(ks) {
-		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks)), round.PartyID())
+		return round.WrapError(fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks)), round.PartyID()).WithCode(tss.CodeInvalidInput)
 	}
 	newKs := round.NewParties().IDs().Keys()
 	wi := signing.PrepareForSigning(round.Params().EC(), i, len(round.OldParties().IDs()), xi, ks)
@@ -61,7 +76,9 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err, round.PartyID())
 	}
-	vCmt := commitments.NewHashCommitment(round.Rand(), flatVis...)
+	// the commitment binds the polynomial to the session, so that it cannot be opened in another one
+	secrets := append([]*big.Int{new(big.Int).SetBytes(ssid)}, flatVis...)
+	vCmt := commitments.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagEDDSAResharingVs), round.Rand(), secrets...)
 
 	// 4. populate temp data
 	round.temp.VD = vCmt.D
@@ -70,9 +87,9 @@ func (round *round1) Start() *tss.Error {
 	// 5. "broadcast" C_i to members of the NEW committee
 	r1msg := NewDGRound1Message(
 		round.NewParties().IDs().Exclude(round.PartyID()), round.PartyID(),
-		round.input.EDDSAPub, vCmt.C)
+		round.input.EDDSAPub, vCmt.C, ssid)
 	round.temp.dgRound1Messages[i] = r1msg
-	round.out <- r1msg
+	round.out <- round.Outbound(r1msg)
 
 	return nil
 }
@@ -110,12 +127,12 @@ func (round *round1) Update() (bool, *tss.Error) {
 		r1msg := round.temp.dgRound1Messages[0].Content().(*DGRound1Message)
 		candidate, err := r1msg.UnmarshalEDDSAPub(round.Params().EC())
 		if err != nil {
-			return false, round.WrapError(errors.New("unable to unmarshal the eddsa pub key"), msg.GetFrom())
+			return false, round.WrapError(errors.New("unable to unmarshal the eddsa pub key"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		if round.save.EDDSAPub != nil &&
 			!candidate.Equals(round.save.EDDSAPub) {
 			// uh oh - anomaly!
-			return false, round.WrapError(errors.New("eddsa pub key did not match what we received previously"), msg.GetFrom())
+			return false, round.WrapError(errors.New("eddsa pub key did not match what we received previously"), msg.GetFrom()).WithCode(tss.CodeBadPublicData)
 		}
 		round.save.EDDSAPub = candidate
 	}
@@ -7,6 +7,7 @@
 package resharing
 
 import (
+	"bytes"
 	"errors"
 
 	"github.com/bnb-chain/tss-lib/v2/tss"
@@ -14,7 +15,7 @@ import (
 
 func (round *round2) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 2
 	round.started = true
@@ -29,10 +30,24 @@ func (round *round2) Start() *tss.Error {
 	Pi := round.PartyID()
 	i := Pi.Index
 
+	// check consistency of SSID
+	r1msg := round.temp.dgRound1Messages[0].Content().(*DGRound1Message)
+	SSID := r1msg.UnmarshalSSID()
+	for j, Pj := range round.OldParties().IDs() {
+		if j == 0 {
+			continue
+		}
+		r1msg := round.temp.dgRound1Messages[j].Content().(*DGRound1Message)
+		if !bytes.Equal(SSID, r1msg.UnmarshalSSID()) {
+			return round.WrapError(errors.New("ssid mismatch"), Pj).WithCode(tss.CodeWrongSession)
+		}
+	}
+	round.temp.ssid = SSID
+
 	// 1. "broadcast" "ACK" members of the OLD committee
 	r2msg := NewDGRound2Message(round.OldParties().IDs(), Pi)
 	round.temp.dgRound2Messages[i] = r2msg
-	round.out <- r2msg
+	round.out <- round.Outbound(r2msg)
 
 	return nil
 }
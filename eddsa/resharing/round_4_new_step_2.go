@@ -13,14 +13,12 @@ import (
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
-	"github.com/bnb-chain/tss-lib/v2/crypto/commitments"
-	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 func (round *round4) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 4
 	round.started = true
@@ -42,41 +40,30 @@ func (round *round4) Start() *tss.Error {
 	// 2-8.
 	modQ := common.ModInt(round.Params().EC().Params().N)
 	vjc := make([][]*crypto.ECPoint, len(round.OldParties().IDs()))
+	culprits := make([]*tss.PartyID, 0, len(vjc))
+	evidence := make([]*tss.Evidence, 0, len(vjc))
 	for j := 0; j <= len(vjc)-1; j++ { // P1..P_t+1. Ps are indexed from 0 here
 		r1msg := round.temp.dgRound1Messages[j].Content().(*DGRound1Message)
 		r3msg2 := round.temp.dgRound3Message2s[j].Content().(*DGRound3Message2)
-
-		vCj, vDj := r1msg.UnmarshalVCommitment(), r3msg2.UnmarshalVDeCommitment()
-
-		// 3. unpack flat "v" commitment content
-		vCmtDeCmt := commitments.HashCommitDecommit{C: vCj, D: vDj}
-		ok, flatVs := vCmtDeCmt.DeCommit()
-		if !ok || len(flatVs) != (round.NewThreshold()+1)*2 { // they're points so * 2
-			// TODO collect culprits and return a list of them as per convention
-			return round.WrapError(errors.New("de-commitment of v_j0..v_jt failed"), round.Parties().IDs()[j])
-		}
-		vj, err := crypto.UnFlattenECPoints(round.Params().EC(), flatVs)
-		if err != nil {
-			return round.WrapError(err, round.Parties().IDs()[j])
-		}
-
-		for i, v := range vj {
-			vj[i] = v.EightInvEight()
-		}
-
-		vjc[j] = vj
-
 		r3msg1 := round.temp.dgRound3Message1s[j].Content().(*DGRound3Message1)
-		sharej := &vss.Share{
-			Threshold: round.NewThreshold(),
-			ID:        round.PartyID().KeyInt(),
-			Share:     new(big.Int).SetBytes(r3msg1.Share),
-		}
-		if ok := sharej.Verify(round.Params().EC(), round.NewThreshold(), vj); !ok {
-			return round.WrapError(errors.New("share from old committee did not pass Verify()"), round.Parties().IDs()[j])
+
+		// 3. unpack flat "v" commitment content, and verify the share against it
+		if !verifyShare(round.Params().EC(), round.HashScheme().Tagged(common.PoseidonTagEDDSAResharingVs), round.NewThreshold(), Pi, r1msg, r3msg2, r3msg1) {
+			Pj := round.OldParties().IDs()[j]
+			ev, err := round.newEvidence(ReasonBadShare, Pj,
+				round.temp.dgRound1Messages[j], round.temp.dgRound3Message2s[j], round.temp.dgRound3Message1s[j])
+			if err != nil {
+				return round.WrapError(err, Pi)
+			}
+			culprits, evidence = append(culprits, Pj), append(evidence, ev)
+			continue
 		}
+		vjc[j], _ = unpackVs(round.Params().EC(), round.HashScheme().Tagged(common.PoseidonTagEDDSAResharingVs), round.NewThreshold(), r1msg, r3msg2)
 
-		newXi = new(big.Int).Add(newXi, sharej.Share)
+		newXi = new(big.Int).Add(newXi, new(big.Int).SetBytes(r3msg1.Share))
+	}
+	if len(culprits) > 0 {
+		return round.WrapError(errors.New("share from old committee did not pass Verify()"), culprits...).WithCode(tss.CodeBadShare).WithEvidence(evidence...)
 	}
 
 	// 9-12.
@@ -94,13 +81,13 @@ func (round *round4) Start() *tss.Error {
 
 	// 13-15.
 	if !Vc[0].Equals(round.save.EDDSAPub) {
-		return round.WrapError(errors.New("assertion failed: V_0 != y"), round.PartyID())
+		return round.WrapError(errors.New("assertion failed: V_0 != y"), round.PartyID()).WithCode(tss.CodeVerificationFailed)
 	}
 
 	// 16-20.
 	newKs := make([]*big.Int, 0, round.NewPartyCount())
 	newBigXjs := make([]*crypto.ECPoint, round.NewPartyCount())
-	culprits := make([]*tss.PartyID, 0, round.NewPartyCount()) // who caused the error(s)
+	culprits = make([]*tss.PartyID, 0, round.NewPartyCount()) // who caused the error(s)
 	for j := 0; j < round.NewPartyCount(); j++ {
 		Pj := round.NewParties().IDs()[j]
 		kj := Pj.KeyInt()
@@ -127,7 +114,7 @@ func (round *round4) Start() *tss.Error {
 	// 21. Send an "ACK" message to both committees to signal that we're ready to save our data
 	r4msg := NewDGRound4Message(round.OldAndNewParties(), Pi)
 	round.temp.dgRound4Messages[i] = r4msg
-	round.out <- r4msg
+	round.out <- round.Outbound(r4msg)
 
 	return nil
 }
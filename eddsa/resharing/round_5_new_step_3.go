@@ -14,7 +14,7 @@ import (
 
 func (round *round5) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 5
 	round.started = true
@@ -28,6 +28,9 @@ func (round *round5) Start() *tss.Error {
 		round.save.ShareID = round.PartyID().KeyInt()
 		round.save.Xi = round.temp.newXi
 		round.save.Ks = round.temp.newKs
+		round.save.HashScheme = round.HashScheme()
+		round.save.Metadata = tss.NewKeyMetadata(round.EC(), round.NewThreshold(), round.NewPartyCount(), round.HashScheme())
+		round.save.Metadata.KeyID = round.save.KeyID()
 
 	} else if round.IsOldCommittee() {
 		round.input.Xi.SetInt64(0)
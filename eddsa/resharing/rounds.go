@@ -7,12 +7,19 @@
 package resharing
 
 import (
+	"errors"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
 	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 const (
 	TaskName = "eddsa-resharing"
+	// Rounds is the number of rounds of the resharing protocol, for both committees
+	Rounds = 5
 )
 
 type (
@@ -108,6 +115,16 @@ func (round *base) WrapError(err error, culprits ...*tss.PartyID) *tss.Error {
 	return tss.NewError(err, TaskName, round.number, round.PartyID(), culprits...)
 }
 
+// newEvidence records the messages that show a culprit misbehaved towards this party, or returns nil when the culprit
+// did not sign them with an identity key, as they would prove nothing
+func (round *base) newEvidence(reason string, culprit *tss.PartyID, msgs ...tss.ParsedMessage) (*tss.Evidence, error) {
+	ev, err := tss.NewEvidence(TaskName, round.number, reason, culprit, round.PartyID(), msgs...)
+	if errors.Is(err, tss.ErrUnsignedEvidence) {
+		return nil, nil
+	}
+	return ev, err
+}
+
 // ----- //
 
 // `oldOK` tracks parties which have been verified by Update()
@@ -133,3 +150,24 @@ func (round *base) allNewOK() {
 		round.newOK[j] = true
 	}
 }
+
+// get ssid from local params
+func (round *base) getSSID() ([]byte, error) {
+	ssidList := []*big.Int{round.EC().Params().P, round.EC().Params().N, round.EC().Params().Gx, round.EC().Params().Gy} // ec curve
+	ssidList = append(ssidList, round.Parties().IDs().Keys()...)                                                         // parties
+	BigXjList, err := crypto.FlattenECPoints(round.input.BigXj)
+	if err != nil {
+		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
+	ssidList = append(ssidList, BigXjList...)                    // BigXj
+	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
+	ssidList = append(ssidList, round.temp.ssidNonce)
+	ssidList = append(ssidList, round.SessionIDInts()...)
+	ssidHash := round.HashScheme().Tagged(common.PoseidonTagEDDSAResharingSSID).HashInts(ssidList...)
+	if ssidHash == nil {
+		return nil, round.WrapError(errors.New("hash ssid failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
+	ssid := ssidHash.Bytes()
+
+	return ssid, nil
+}
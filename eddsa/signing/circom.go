@@ -0,0 +1,32 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+
+	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// CircomSignature returns the EdDSA-Poseidon signature of a signing party with a BabyJubJub key, with the key, laid
+// out for circomlib. The Signature bytes, the encoded R8 and S, already are the compressed signature that circomlib
+// expects.
+func CircomSignature(sig *common.SignatureData, pub *crypto.ECPoint) (*babyjubjub.CircomSignature, error) {
+	if sig == nil || len(sig.Signature) != 64 || pub == nil {
+		return nil, errors.New("CircomSignature: missing signature or public key")
+	}
+	if !tss.SameCurve(pub.Curve(), tss.BabyJubJub()) {
+		return nil, errors.New("CircomSignature: the key is not a BabyJubJub key")
+	}
+	bz := make([]byte, 0, babyjubjub.CircomSignatureLen)
+	bz = append(bz, sig.Signature...)
+	bz = append(bz, babyjubjub.Compress(pub.X(), pub.Y())...)
+	return babyjubjub.UnmarshalCircomSignature(bz)
+}
@@ -12,18 +12,38 @@ import (
 	"math/big"
 
 	"github.com/agl/ed25519/edwards25519"
+	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/tss"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
 )
 
 func (round *finalization) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 4
 	round.started = true
 	round.resetOK()
 
+	// verify each s share against its commitments before combining them, so that a bad share is attributed
+	if round.temp.bigRjs != nil {
+		var culprits []*tss.PartyID
+		for j, Pj := range round.Parties().IDs() {
+			if j == round.PartyID().Index {
+				continue
+			}
+			r3msg := round.temp.signRound3Messages[j].Content().(*SignRound3Message)
+			if !VerifySignatureShare(round.Params().EC(), r3msg.UnmarshalS(), round.temp.bigRjs[j], round.temp.bigWjs[j], round.temp.lambda) {
+				culprits = append(culprits, Pj)
+			}
+		}
+		if len(culprits) > 0 {
+			return round.WrapError(errors.New("signature share verification failed"), culprits...).WithCode(tss.CodeBadShare)
+		}
+	}
+
+	bjj := isBabyJubJub(round.Params().EC())
+	modN := common.ModInt(round.Params().EC().Params().N)
 	sumS := round.temp.si
 	for j := range round.Parties().IDs() {
 		round.ok[j] = true
@@ -31,6 +51,10 @@ func (round *finalization) Start() *tss.Error {
 			continue
 		}
 		r3msg := round.temp.signRound3Messages[j].Content().(*SignRound3Message)
+		if bjj {
+			sumS = bigIntToEncodedBytes(modN.Add(encodedBytesToBigInt(sumS), r3msg.UnmarshalS()))
+			continue
+		}
 		sjBytes := bigIntToEncodedBytes(r3msg.UnmarshalS())
 		var tmpSumS [32]byte
 		edwards25519.ScMulAdd(&tmpSumS, sumS, bigIntToEncodedBytes(big.NewInt(1)), sjBytes)
@@ -42,13 +66,7 @@ func (round *finalization) Start() *tss.Error {
 	round.data.Signature = append(bigIntToEncodedBytes(round.temp.r)[:], sumS[:]...)
 	round.data.R = round.temp.r.Bytes()
 	round.data.S = s.Bytes()
-	if round.temp.fullBytesLen == 0 {
-		round.data.M = round.temp.m.Bytes()
-	} else {
-		var mBytes = make([]byte, round.temp.fullBytesLen)
-		round.temp.m.FillBytes(mBytes)
-		round.data.M = mBytes
-	}
+	round.data.M = round.messageBytes()
 
 	pk := edwards.PublicKey{
 		Curve: round.Params().EC(),
@@ -56,10 +74,27 @@ func (round *finalization) Start() *tss.Error {
 		Y:     round.key.EDDSAPub.Y(),
 	}
 
-	ok := edwards.Verify(&pk, round.data.M, round.temp.r, s)
+	var ok bool
+	if bjj {
+		R8, err := sumPoints(round.temp.bigRjs)
+		if err != nil {
+			return round.WrapError(err).WithCode(tss.CodeInternal)
+		}
+		ok = verifyPoseidon(round.key.EDDSAPub, R8, s, round.temp.m)
+	} else if round.temp.opts.isPure() {
+		ok = edwards.Verify(&pk, round.data.M, round.temp.r, s)
+	} else {
+		encodedPubKey := ecPointToEncodedBytes(pk.X, pk.Y)
+		ok = round.temp.opts.verify(encodedPubKey, round.data.M, bigIntToEncodedBytes(round.temp.r), sumS)
+	}
 	if !ok {
-		return round.WrapError(fmt.Errorf("signature verification failed"))
+		return round.WrapError(fmt.Errorf("signature verification failed")).WithCode(tss.CodeVerificationFailed)
+	}
+
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
 	}
+
 	round.end <- round.data
 
 	return nil
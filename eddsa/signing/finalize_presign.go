@@ -0,0 +1,54 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"errors"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func (round *presignFinalization) Start() *tss.Error {
+	if round.started {
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
+	}
+	round.number = 3
+	round.started = true
+	round.resetOK()
+
+	// 1-6. verify the nonce commitments Rj and Ej, as round 3 would
+	if err := round.computeR(); err != nil {
+		return err
+	}
+	for j := range round.Parties().IDs() {
+		round.ok[j] = true
+	}
+
+	round.preEnd <- &PreSignatureData{
+		Ri:     round.temp.ri,
+		Ei:     round.temp.ei,
+		SSID:   round.temp.ssid,
+		Ks:     round.Parties().IDs().Keys(),
+		BigRjs: round.temp.bigRjs,
+		BigEjs: round.temp.bigEjs,
+	}
+	return nil
+}
+
+func (round *presignFinalization) CanAccept(msg tss.ParsedMessage) bool {
+	// not expecting any incoming messages in this round
+	return false
+}
+
+func (round *presignFinalization) Update() (bool, *tss.Error) {
+	// not expecting any incoming messages in this round
+	return false, nil
+}
+
+func (round *presignFinalization) NextRound() tss.Round {
+	return nil // finished!
+}
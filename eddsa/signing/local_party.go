@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto"
@@ -20,8 +21,10 @@ import (
 
 // Implements Party
 // Implements Stringer
+// Implements Wiper
 var _ tss.Party = (*LocalParty)(nil)
 var _ fmt.Stringer = (*LocalParty)(nil)
+var _ tss.Wiper = (*LocalParty)(nil)
 
 type (
 	LocalParty struct {
@@ -33,8 +36,9 @@ type (
 		data *common.SignatureData
 
 		// outbound messaging
-		out chan<- tss.Message
-		end chan<- *common.SignatureData
+		out        chan<- tss.Message
+		end        chan<- *common.SignatureData
+		presignEnd chan<- *PreSignatureData
 	}
 
 	localMessageStore struct {
@@ -54,15 +58,32 @@ type (
 		pointRi      *crypto.ECPoint
 		deCommit     cmt.HashDeCommitment
 
+		// the second nonce of a pre-signature and the commitments Ej to it
+		ei      *big.Int
+		pointEi *crypto.ECPoint
+		bigEjs  []*crypto.ECPoint
+
 		// round 2
 		cjs []*big.Int
 		si  *[32]byte
 
 		// round 3
-		r *big.Int
+		r,
+		lambda *big.Int
+		bigRjs,
+		bigWjs []*crypto.ECPoint
 
 		ssid      []byte
 		ssidNonce *big.Int
+
+		// set when the nonce was precomputed by a pre-signing party
+		preSigned bool
+
+		// RFC 8032 variant, nil for pure Ed25519
+		opts *Options
+
+		// added to the share of the key to sign with a child key, nil for the key itself
+		keyDerivationDelta *big.Int
 	}
 )
 
@@ -100,17 +121,125 @@ func NewLocalParty(
 	return p
 }
 
+// NewLocalPartyWithOptions returns a party that signs `msg` with the Ed25519ctx or Ed25519ph variant of RFC 8032
+// selected by `opts`. Every party of the session must use the same options, and the curve must be Ed25519.
+func NewLocalPartyWithOptions(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	opts *Options,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) (tss.Party, error) {
+	if err := opts.validate(params.EC()); err != nil {
+		return nil, err
+	}
+	p := NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty)
+	p.temp.opts = opts
+	return p, nil
+}
+
+// NewLocalPartyWithKDD returns a party with key derivation delta for HD support: it signs with the child key of
+// `key` whose public key and public shares are those of `key` plus keyDerivationDelta*G (see package derivation).
+// Every party of the session must use the same delta.
+func NewLocalPartyWithKDD(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	keyDerivationDelta *big.Int,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) tss.Party {
+	p := NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty)
+	p.temp.keyDerivationDelta = keyDerivationDelta
+	return p
+}
+
+// NewPreSigningLocalParty returns a party that only runs the message-independent rounds 1-2 of signing.
+// The nonce commitments are exchanged and verified, and the resulting PreSignatureData is sent to `end`.
+// It may later be passed to NewLocalPartyWithPreSignature to produce a signature in a single round.
+func NewPreSigningLocalParty(
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	out chan<- tss.Message,
+	end chan<- *PreSignatureData,
+) tss.Party {
+	p := NewLocalParty(nil, params, key, out, nil).(*LocalParty)
+	p.presignEnd = end
+	return p
+}
+
+// NewLocalPartyWithPreSignature returns a party that signs `msg` using a nonce precomputed by NewPreSigningLocalParty.
+// Only the round 3 s-share broadcast is exchanged. Every member of the pre-signing committee must use the same
+// message and each PreSignatureData must never be used more than once. The nonce is bound to the message as in
+// FROST, so that R is only known once the message is, and the nonces of the pre-signature are wiped once bound.
+// The pre-signature is refused if it has expired or was already consumed; otherwise it is marked consumed, and
+// callers that persist pre-signatures must store that state (or delete the record) before starting the party.
+func NewLocalPartyWithPreSignature(
+	msg *big.Int,
+	params *tss.Parameters,
+	key keygen.LocalPartySaveData,
+	preSig *PreSignatureData,
+	out chan<- tss.Message,
+	end chan<- *common.SignatureData,
+	fullBytesLen ...int,
+) (tss.Party, error) {
+	if err := preSig.ValidateFor(params.Parties().IDs()); err != nil {
+		return nil, err
+	}
+	if err := preSig.checkFresh(time.Now()); err != nil {
+		return nil, err
+	}
+	preSig.Consumed = true
+	p := NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty)
+	p.temp.ri, p.temp.ei = preSig.Ri, preSig.Ei
+	p.temp.bigRjs, p.temp.bigEjs = preSig.BigRjs, preSig.BigEjs
+	p.temp.ssid = preSig.SSID
+	p.temp.preSigned = true
+	return p, nil
+}
+
+// Session is a signing party with the channels it sends its messages and the signature on
+type Session struct {
+	*LocalParty
+	Out <-chan tss.Message
+	End <-chan *common.SignatureData
+}
+
+// NewSession returns the session of a party created like NewLocalParty, with channels that buffer `buffer` messages
+func NewSession(msg *big.Int, params *tss.Parameters, key keygen.LocalPartySaveData, buffer int, fullBytesLen ...int) *Session {
+	out, end := make(chan tss.Message, buffer), make(chan *common.SignatureData, 1)
+	return &Session{
+		LocalParty: NewLocalParty(msg, params, key, out, end, fullBytesLen...).(*LocalParty),
+		Out:        out,
+		End:        end,
+	}
+}
+
 func (p *LocalParty) FirstRound() tss.Round {
-	return newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end)
+	round := newRound1(p.params, &p.keys, p.data, &p.temp, p.out, p.end, p.presignEnd).(*round1)
+	if p.temp.preSigned {
+		// the nonce was already agreed upon in rounds 1-2, skip straight to the s-share broadcast
+		round.number = 3
+		return &round3{&round2{round}}
+	}
+	return round
 }
 
 func (p *LocalParty) Start() *tss.Error {
 	return tss.BaseStart(p, TaskName, func(round tss.Round) *tss.Error {
-		round1, ok := round.(*round1)
-		if !ok {
-			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round"))
+		var r1 *round1
+		switch rnd := round.(type) {
+		case *round1:
+			r1 = rnd
+		case *round3:
+			r1 = rnd.round1
+		default:
+			return round.WrapError(errors.New("unable to Start(). party is in an unexpected round")).WithCode(tss.CodeInternal)
 		}
-		if err := round1.prepare(); err != nil {
+		if err := r1.prepare(); err != nil {
 			return round.WrapError(err)
 		}
 		return nil
@@ -124,14 +253,14 @@ func (p *LocalParty) Update(msg tss.ParsedMessage) (ok bool, err *tss.Error) {
 func (p *LocalParty) UpdateFromBytes(wireBytes []byte, from *tss.PartyID, isBroadcast bool) (bool, *tss.Error) {
 	msg, err := tss.ParseWireMessage(wireBytes, from, isBroadcast)
 	if err != nil {
-		return false, p.WrapError(err)
+		return false, p.WrapError(err).WithCode(tss.CodeInvalidMessage)
 	}
 	return p.Update(msg)
 }
 
 func (p *LocalParty) ValidateMessage(msg tss.ParsedMessage) (bool, *tss.Error) {
 	if msg.GetFrom() == nil || !msg.GetFrom().ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg))
+		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg)).WithCode(tss.CodeInvalidMessage)
 	}
 	// check that the message's "from index" will fit into the array
 	if maxFromIdx := len(p.params.Parties().IDs()) - 1; maxFromIdx < msg.GetFrom().Index {
@@ -174,3 +303,15 @@ func (p *LocalParty) PartyID() *tss.PartyID {
 func (p *LocalParty) String() string {
 	return fmt.Sprintf("id: %s, %s", p.PartyID(), p.BaseParty.String())
 }
+
+// Wipe overwrites the secrets of the signing: the share wi of the signing key and the nonce ri. It is called when the
+// session ends. The nonce of a pre-signing party is left to the owner of its PreSignatureData (see
+// PreSignatureData.Wipe), while that of a consumed pre-signature is wiped with the rest.
+func (p *LocalParty) Wipe() {
+	if p.temp.wi != p.keys.Xi {
+		common.ZeroInt(p.temp.wi)
+	}
+	if p.presignEnd == nil {
+		common.ZeroInt(p.temp.ri)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"math/big"
+	"testing"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/witness"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// route delivers a message to its recipients among `parties`
+func route(parties []tss.Party, msg tss.Message, errCh chan<- *tss.Error) {
+	dest := msg.GetTo()
+	if dest == nil {
+		for _, P := range parties {
+			if P.PartyID().Index == msg.GetFrom().Index {
+				continue
+			}
+			go test.SharedPartyUpdater(P, msg, errCh)
+		}
+	} else {
+		go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+	}
+}
+
+// there are no BabyJubJub fixtures, so the keys are generated first
+func runBJJKeygen(t *testing.T, pIDs tss.SortedPartyIDs, threshold int) []keygen.LocalPartySaveData {
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]tss.Party, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *keygen.LocalPartySaveData, len(pIDs))
+
+	for _, pID := range pIDs {
+		params := tss.NewParameters(tss.BabyJubJub(), p2pCtx, pID, len(pIDs), threshold)
+		P := keygen.NewLocalParty(params, outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	keys := make([]keygen.LocalPartySaveData, len(pIDs))
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			route(parties, msg, errCh)
+
+		case save := <-endCh:
+			index, err := save.OriginalIndex()
+			assert.NoError(t, err)
+			keys[index] = *save
+			ended++
+		}
+	}
+	return keys
+}
+
+func TestE2EConcurrentBJJ(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	pIDs := tss.GenerateTestPartyIDs(threshold + 1)
+	keys := runBJJKeygen(t, pIDs, threshold)
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]tss.Party, 0, len(pIDs))
+
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *common.SignatureData, len(pIDs))
+
+	// a message of a circuit is an element of the Poseidon field
+	msg, _ := new(big.Int).SetString("12345678901234567890123456789012345678901234567890", 10)
+	for i := range pIDs {
+		params := tss.NewParameters(tss.BabyJubJub(), p2pCtx, pIDs[i], len(pIDs), threshold)
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var sig *common.SignatureData
+	for ended := 0; ended < len(pIDs); {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+
+		case msg := <-outCh:
+			route(parties, msg, errCh)
+
+		case sig = <-endCh:
+			ended++
+		}
+	}
+
+	// the signature verifies with iden3 babyjub, as the EdDSAPoseidonVerifier of circomlib does
+	var sigComp iden3bjj.SignatureComp
+	copy(sigComp[:], sig.Signature)
+	iden3Sig, err := sigComp.Decompress()
+	if !assert.NoError(t, err) {
+		return
+	}
+	pk := iden3bjj.PublicKey{X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+	assert.True(t, pk.VerifyPoseidon(msg, iden3Sig), "iden3 must verify the EdDSA-Poseidon signature")
+	assert.False(t, pk.VerifyPoseidon(new(big.Int).Add(msg, big.NewInt(1)), iden3Sig))
+
+	circom, err := CircomSignature(sig, keys[0].EDDSAPub)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, circom.S.Cmp(iden3Sig.S))
+	assert.Equal(t, 0, circom.R8x.Cmp(iden3Sig.R8.X))
+	assert.Equal(t, 0, circom.R8y.Cmp(iden3Sig.R8.Y))
+
+	// the circuit inputs of the threshold signature are those of the iden3 signature
+	in, err := witness.EdDSAPoseidon(sig, keys[0].EDDSAPub, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &witness.EdDSAPoseidonInputs{
+		Enabled: "1",
+		Ax:      pk.X.String(),
+		Ay:      pk.Y.String(),
+		S:       iden3Sig.S.String(),
+		R8x:     iden3Sig.R8.X.String(),
+		R8y:     iden3Sig.R8.Y.String(),
+		M:       msg.String(),
+	}, in)
+}
+
+func TestBJJMessageOutOfField(t *testing.T) {
+	setUp("info")
+	threshold := testThreshold
+
+	pIDs := tss.GenerateTestPartyIDs(threshold + 1)
+	keys := runBJJKeygen(t, pIDs, threshold)
+
+	p2pCtx := tss.NewPeerContext(pIDs)
+	parties := make([]tss.Party, 0, len(pIDs))
+	errCh := make(chan *tss.Error, len(pIDs))
+	outCh := make(chan tss.Message, len(pIDs))
+	endCh := make(chan *common.SignatureData, len(pIDs))
+
+	msg := common.PoseidonFieldModulus()
+	for i := range pIDs {
+		params := tss.NewParameters(tss.BabyJubJub(), p2pCtx, pIDs[i], len(pIDs), threshold)
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh)
+		parties = append(parties, P)
+		go func(P tss.Party) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	for {
+		select {
+		case err := <-errCh:
+			assert.Equal(t, tss.CodeInvalidInput, err.Code())
+			return
+
+		case msg := <-outCh:
+			route(parties, msg, errCh)
+
+		case <-endCh:
+			assert.FailNow(t, "a message out of the Poseidon field must not be signed")
+		}
+	}
+}
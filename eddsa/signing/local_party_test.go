@@ -7,11 +7,15 @@
 package signing
 
 import (
+	"crypto"
+	"crypto/ed25519"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/agl/ed25519/edwards25519"
 	"github.com/decred/dcrd/dcrec/edwards/v2"
@@ -19,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/derivation"
 	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
 	"github.com/bnb-chain/tss-lib/v2/test"
 	"github.com/bnb-chain/tss-lib/v2/tss"
@@ -248,3 +253,877 @@ signing:
 		}
 	}
 }
+
+func TestE2EBadSignatureShare(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	msg := big.NewInt(200)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+
+		P := NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	// every party but the cheater must reject the tampered share and blame party 0
+	var failed int
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			assert.Contains(t, err.Error(), "signature share verification failed")
+			if assert.Len(t, err.Culprits(), 1) {
+				assert.Equal(t, signPIDs[0].Index, err.Culprits()[0].Index)
+			}
+			failed++
+			if failed == len(signPIDs)-1 {
+				break signing
+			}
+
+		case msg := <-outCh:
+			// party 0 broadcasts a tampered s share in round 3
+			if r3msg, ok := msg.(tss.ParsedMessage); ok && msg.GetFrom().Index == 0 {
+				if content, ok := r3msg.Content().(*SignRound3Message); ok {
+					s := new(big.Int).Add(content.UnmarshalS(), big.NewInt(1))
+					msg = NewSignRound3Message(msg.GetFrom(), s)
+				}
+			}
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case <-endCh:
+			// the cheater itself combines its own honest share and may finish
+		}
+	}
+}
+
+func TestE2EWithOptions(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	updater := test.SharedPartyUpdater
+	msg := []byte("threshold signed with a context")
+	pubKey := ed25519.PublicKey(ecPointToEncodedBytes(keys[0].EDDSAPub.X(), keys[0].EDDSAPub.Y())[:])
+
+	variants := map[string]struct {
+		opts    *Options
+		stdOpts *ed25519.Options
+	}{
+		"Ed25519ctx": {&Options{Context: []byte("tss")}, &ed25519.Options{Context: "tss"}},
+		"Ed25519ph":  {&Options{PreHash: true, Context: []byte("tss")}, &ed25519.Options{Hash: crypto.SHA512, Context: "tss"}},
+	}
+	for name, variant := range variants {
+		t.Run(name, func(t *testing.T) {
+			parties := make([]*LocalParty, 0, len(signPIDs))
+
+			errCh := make(chan *tss.Error, len(signPIDs))
+			outCh := make(chan tss.Message, len(signPIDs))
+			endCh := make(chan *common.SignatureData, len(signPIDs))
+
+			for i := 0; i < len(signPIDs); i++ {
+				params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+				P, err := NewLocalPartyWithOptions(new(big.Int).SetBytes(msg), params, keys[i], variant.opts, outCh, endCh, len(msg))
+				if !assert.NoError(t, err) {
+					return
+				}
+				parties = append(parties, P.(*LocalParty))
+				go func(P *LocalParty) {
+					if err := P.Start(); err != nil {
+						errCh <- err
+					}
+				}(P.(*LocalParty))
+			}
+
+			var ended int32
+		signing:
+			for {
+				select {
+				case err := <-errCh:
+					common.Logger.Errorf("Error: %s", err)
+					assert.FailNow(t, err.Error())
+					break signing
+
+				case msg := <-outCh:
+					dest := msg.GetTo()
+					if dest == nil {
+						for _, P := range parties {
+							if P.PartyID().Index == msg.GetFrom().Index {
+								continue
+							}
+							go updater(P, msg, errCh)
+						}
+					} else {
+						go updater(parties[dest[0].Index], msg, errCh)
+					}
+
+				case sig := <-endCh:
+					atomic.AddInt32(&ended, 1)
+					if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+						assert.NoError(t, ed25519.VerifyWithOptions(pubKey, variant.opts.preHash(msg), sig.Signature, variant.stdOpts),
+							"signature should verify with crypto/ed25519")
+						assert.False(t, ed25519.Verify(pubKey, msg, sig.Signature), "should not verify as pure Ed25519")
+						break signing
+					}
+				}
+			}
+		})
+	}
+
+	_, err = NewLocalPartyWithOptions(big.NewInt(1), tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[0], len(signPIDs), threshold),
+		keys[0], &Options{Context: make([]byte, 256)}, nil, nil)
+	assert.Error(t, err, "context longer than 255 bytes should be refused")
+
+	_, err = NewLocalPartyWithOptions(big.NewInt(1), tss.NewParameters(tss.BabyJubJub(), p2pCtx, signPIDs[0], len(signPIDs), threshold),
+		keys[0], &Options{Context: []byte("tss")}, nil, nil)
+	assert.Error(t, err, "options on a curve other than Ed25519 should be refused")
+}
+
+func TestE2EWithHDKeyDerivation(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	chainCode := make([]byte, derivation.ChainCodeLength)
+	chainCode[31] = 1
+	child, err := derivation.EdDSAChild(keys[0].EDDSAPub, chainCode, []uint32{501, 0, 7})
+	assert.NoError(t, err, "there should not be an error deriving the child public key")
+	for i := range keys {
+		keys[i], err = derivation.EdDSA(keys[i], child)
+		assert.NoError(t, err, "there should not be an error setting the derived keys")
+	}
+
+	// PHASE: signing
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+	msg := []byte("signed with a child key")
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+
+		P := NewLocalPartyWithKDD(new(big.Int).SetBytes(msg), params, keys[i], child.Delta, outCh, endCh, len(msg)).(*LocalParty)
+		parties = append(parties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			dest := msg.GetTo()
+			if dest == nil {
+				for _, P := range parties {
+					if P.PartyID().Index == msg.GetFrom().Index {
+						continue
+					}
+					go updater(P, msg, errCh)
+				}
+			} else {
+				go updater(parties[dest[0].Index], msg, errCh)
+			}
+
+		case sig := <-endCh:
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				childPubKey := ed25519.PublicKey(ecPointToEncodedBytes(child.PublicKey.X(), child.PublicKey.Y())[:])
+				assert.True(t, ed25519.Verify(childPubKey, msg, sig.Signature), "signature should verify with the child key")
+				break signing
+			}
+		}
+	}
+}
+
+func TestE2EPreSigning(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	assert.Equal(t, testThreshold+1, len(keys))
+	assert.Equal(t, testThreshold+1, len(signPIDs))
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	updater := test.SharedPartyUpdater
+
+	route := func(parties []*LocalParty, msg tss.Message, errCh chan<- *tss.Error) {
+		dest := msg.GetTo()
+		if dest == nil {
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				go updater(P, msg, errCh)
+			}
+		} else {
+			if dest[0].Index == msg.GetFrom().Index {
+				t.Fatalf("party %d tried to send a message to itself (%d)", dest[0].Index, msg.GetFrom().Index)
+			}
+			go updater(parties[dest[0].Index], msg, errCh)
+		}
+	}
+
+	// PHASE: pre-signing
+	preParties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, len(signPIDs))
+	preEndCh := make(chan *PreSignatureData, len(signPIDs))
+
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P := NewPreSigningLocalParty(params, keys[i], outCh, preEndCh).(*LocalParty)
+		preParties = append(preParties, P)
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	preSigs := make([]*PreSignatureData, len(signPIDs))
+	received := 0
+presigning:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break presigning
+
+		case msg := <-outCh:
+			route(preParties, msg, errCh)
+
+		case preSig := <-preEndCh:
+			for i, P := range preParties {
+				if P.temp.ri == preSig.Ri {
+					preSigs[i] = preSig
+				}
+			}
+			if received++; received == len(signPIDs) {
+				break presigning
+			}
+		}
+	}
+	for i, preSig := range preSigs {
+		if !assert.NotNil(t, preSig, "party %d should produce a pre-signature", i) {
+			return
+		}
+		assert.Equal(t, preSigs[0].BigRjs, preSig.BigRjs, "all parties should agree on the commitments Dj")
+		assert.Equal(t, preSigs[0].BigEjs, preSig.BigEjs, "all parties should agree on the commitments Ej")
+
+		// round-trip through storage as an operator would between the offline and online phases
+		preSig.SetExpiry(1, time.Hour)
+		bz, err := preSig.Marshal()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if preSigs[i], err = UnmarshalPreSignatureData(bz); !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, preSigs[i].CheckUsable(1, time.Now()))
+	}
+
+	// PHASE: online signing
+	parties := make([]*LocalParty, 0, len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	msg := big.NewInt(200)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		P, err := NewLocalPartyWithPreSignature(msg, params, keys[i], preSigs[i], outCh, endCh)
+		if !assert.NoError(t, err) {
+			return
+		}
+		parties = append(parties, P.(*LocalParty))
+	}
+	for _, P := range parties {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var ended int32
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			route(parties, msg, errCh)
+
+		case <-endCh:
+			atomic.AddInt32(&ended, 1)
+			if atomic.LoadInt32(&ended) == int32(len(signPIDs)) {
+				t.Logf("Done. Received signature data from %d participants", ended)
+
+				pk := edwards.PublicKey{
+					Curve: tss.Edwards(),
+					X:     keys[0].EDDSAPub.X(),
+					Y:     keys[0].EDDSAPub.Y(),
+				}
+				newSig, err := edwards.ParseSignature(parties[0].data.Signature)
+				if !assert.NoError(t, err) {
+					return
+				}
+				ok := edwards.Verify(&pk, msg.Bytes(), newSig.R, newSig.S)
+				assert.True(t, ok, "eddsa verify must pass")
+
+				// the nonce is bound to the message, so R is not the sum of the commitments made before it was known
+				D, err := sumPoints(preSigs[0].BigRjs)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.NotEqual(t, ecPointToEncodedBytes(D.X(), D.Y())[:], parties[0].data.Signature[:32])
+				for _, preSig := range preSigs {
+					assert.Zero(t, preSig.Ri.Sign(), "the nonce shares should be wiped once bound")
+					assert.Zero(t, preSig.Ei.Sign(), "the nonce shares should be wiped once bound")
+				}
+				break signing
+			}
+		}
+	}
+}
+
+// testPreSignature returns a complete pre-signature of the committee, with the shares of the key as stand-in nonces
+func testPreSignature(keys []keygen.LocalPartySaveData, signPIDs tss.SortedPartyIDs) *PreSignatureData {
+	return &PreSignatureData{
+		Ri:     big.NewInt(1),
+		Ei:     big.NewInt(2),
+		SSID:   []byte{3},
+		Ks:     signPIDs.Keys(),
+		BigRjs: keys[0].BigXj[:len(signPIDs)],
+		BigEjs: keys[0].BigXj[:len(signPIDs)],
+	}
+}
+
+func TestPreSignatureDataValidateFor(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	preSig := testPreSignature(keys, signPIDs)
+	assert.NoError(t, preSig.ValidateFor(signPIDs))
+	assert.Error(t, preSig.ValidateFor(signPIDs[1:]), "committee size mismatch should fail")
+
+	other := *preSig
+	other.Ks = append([]*big.Int{big.NewInt(0)}, preSig.Ks[1:]...)
+	assert.Error(t, other.ValidateFor(signPIDs), "foreign committee should fail")
+
+	other = *preSig
+	other.Ri = nil
+	assert.Error(t, other.ValidateFor(signPIDs), "incomplete data should fail")
+
+	other = *preSig
+	other.BigEjs = preSig.BigEjs[1:]
+	assert.Error(t, other.ValidateFor(signPIDs), "missing commitments should fail")
+}
+
+func TestPreSignatureDataExpiryAndReuse(t *testing.T) {
+	setUp("info")
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	preSig := testPreSignature(keys, signPIDs)
+	preSig.SetExpiry(7, time.Minute)
+	assert.NoError(t, preSig.CheckUsable(7, time.Now()))
+	assert.Error(t, preSig.CheckUsable(8, time.Now()), "other epoch should be stale")
+	assert.Error(t, preSig.CheckUsable(7, time.Now().Add(time.Hour)), "should be expired")
+
+	_, err = UnmarshalPreSignatureData([]byte(`{"Ri":1}`))
+	assert.Error(t, err, "incomplete data should not unmarshal")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[0], len(signPIDs), testThreshold)
+	_, err = NewLocalPartyWithPreSignature(big.NewInt(1), params, keys[0], preSig, nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, preSig.Consumed)
+	assert.Error(t, preSig.CheckUsable(7, time.Now()), "consumed pre-signature should not be usable")
+	_, err = NewLocalPartyWithPreSignature(big.NewInt(1), params, keys[0], preSig, nil, nil)
+	assert.Error(t, err, "consumed pre-signature should be refused")
+
+	bz, err := preSig.Marshal()
+	assert.NoError(t, err)
+	restored, err := UnmarshalPreSignatureData(bz)
+	assert.NoError(t, err)
+	assert.True(t, restored.Consumed, "consumed state should survive storage")
+}
+
+func TestE2EConcurrentSessions(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+
+	// PHASE: load keygen fixtures
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	sessions := map[string]*big.Int{
+		"session-a": big.NewInt(101),
+		"session-b": big.NewInt(202),
+		"session-c": big.NewInt(303),
+	}
+
+	errCh := make(chan *tss.Error, len(signPIDs)*len(sessions))
+	outCh := make(chan tss.Message, len(signPIDs)*len(sessions))
+	endChs := make(map[string]chan *common.SignatureData, len(sessions))
+
+	// one manager per signer, each running every session
+	managers := make([]*tss.SessionManager, len(signPIDs))
+	for i := range signPIDs {
+		managers[i] = tss.NewSessionManager(outCh)
+	}
+	for sid, msg := range sessions {
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		endChs[sid] = endCh
+		for i := range signPIDs {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+			P, err := managers[i].Add([]byte(sid), func(out chan<- tss.Message) tss.Party {
+				return NewLocalParty(msg, params, keys[i], out, endCh)
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			go func(P tss.Party) {
+				if err := P.Start(); err != nil {
+					errCh <- err
+				}
+			}(P)
+		}
+	}
+	_, err = managers[0].Add([]byte("session-a"), func(out chan<- tss.Message) tss.Party { return nil })
+	assert.Error(t, err, "duplicate session id should be refused")
+
+	signatures := make(map[string]int, len(sessions))
+	done := 0
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			common.Logger.Errorf("Error: %s", err)
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			bz, routing, err := msg.WireBytes()
+			if !assert.NoError(t, err) {
+				break signing
+			}
+			if !assert.Contains(t, sessions, string(routing.SessionID), "messages should be tagged with their session") {
+				break signing
+			}
+			for j, m := range managers {
+				if j == msg.GetFrom().Index || (routing.To != nil && routing.To[0].Index != j) {
+					continue
+				}
+				go func(m *tss.SessionManager) {
+					if _, err := m.UpdateFromBytes(routing.SessionID, bz, msg.GetFrom(), msg.IsBroadcast()); err != nil {
+						errCh <- err
+					}
+				}(m)
+			}
+
+		case sig := <-endChs["session-a"]:
+			assert.Equal(t, sessions["session-a"].Bytes(), sig.M)
+			if signatures["session-a"]++; signatures["session-a"] == len(signPIDs) {
+				done++
+			}
+		case sig := <-endChs["session-b"]:
+			assert.Equal(t, sessions["session-b"].Bytes(), sig.M)
+			if signatures["session-b"]++; signatures["session-b"] == len(signPIDs) {
+				done++
+			}
+		case sig := <-endChs["session-c"]:
+			assert.Equal(t, sessions["session-c"].Bytes(), sig.M)
+			if signatures["session-c"]++; signatures["session-c"] == len(signPIDs) {
+				done++
+			}
+		}
+		if done == len(sessions) {
+			break
+		}
+	}
+
+	for _, m := range managers[1:] {
+		for sid := range sessions {
+			m.Remove([]byte(sid))
+		}
+		assert.Equal(t, 0, m.Len())
+	}
+	managers[0].Close()
+	assert.Equal(t, 0, managers[0].Len(), "closing the manager should remove every session")
+	_, err = managers[0].UpdateFromBytes([]byte("session-a"), nil, signPIDs[1], true)
+	assert.Error(t, err, "removed session should be unknown")
+}
+
+func TestRetryCoordinatorExcludesCulprit(t *testing.T) {
+	setUp("info")
+
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	cheater := pIDs[0].KeyInt()
+	msg := big.NewInt(200)
+
+	// one in-process signing session in which the cheater, if selected, tampers with its round 3 share
+	attempt := func(_ int, committee tss.SortedPartyIDs) error {
+		p2pCtx := tss.NewPeerContext(committee)
+		parties := make([]*LocalParty, 0, len(committee))
+
+		errCh := make(chan *tss.Error, len(committee))
+		outCh := make(chan tss.Message, len(committee))
+		endCh := make(chan *common.SignatureData, len(committee))
+
+		for _, pID := range committee {
+			key := keygen.BuildLocalSaveDataSubset(keys[pIDs.FindByKey(pID.KeyInt()).Index], committee)
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, pID, len(committee), testThreshold)
+			P := NewLocalParty(msg, params, key, outCh, endCh).(*LocalParty)
+			parties = append(parties, P)
+			go func(P *LocalParty) {
+				if err := P.Start(); err != nil {
+					errCh <- err
+				}
+			}(P)
+		}
+
+		var ended int
+		for {
+			select {
+			case err := <-errCh:
+				return err
+			case msg := <-outCh:
+				if r3msg, ok := msg.(tss.ParsedMessage); ok && msg.GetFrom().KeyInt().Cmp(cheater) == 0 {
+					if content, ok := r3msg.Content().(*SignRound3Message); ok {
+						msg = NewSignRound3Message(msg.GetFrom(), new(big.Int).Add(content.UnmarshalS(), big.NewInt(1)))
+					}
+				}
+				dest := msg.GetTo()
+				if dest == nil {
+					for _, P := range parties {
+						if P.PartyID().Index == msg.GetFrom().Index {
+							continue
+						}
+						go test.SharedPartyUpdater(P, msg, errCh)
+					}
+				} else {
+					go test.SharedPartyUpdater(parties[dest[0].Index], msg, errCh)
+				}
+			case <-endCh:
+				ended++
+				if ended == len(committee) {
+					return nil
+				}
+			}
+		}
+	}
+
+	rc := tss.NewRetryCoordinator(pIDs, testThreshold, 3, attempt)
+	committee, err := rc.Run()
+	assert.NoError(t, err, "signing should succeed once the cheater is excluded")
+	assert.Nil(t, committee.FindByKey(cheater), "the cheater must not be in the final committee")
+
+	history := rc.History()
+	if assert.Len(t, history, 2) {
+		assert.Error(t, history[0].Err)
+		if assert.Len(t, history[0].Culprits, 1) {
+			assert.Equal(t, 0, history[0].Culprits[0].KeyInt().Cmp(cheater))
+		}
+		assert.NoError(t, history[1].Err)
+	}
+	if assert.Len(t, rc.Excluded(), 1) {
+		assert.Equal(t, 0, rc.Excluded()[0].KeyInt().Cmp(cheater))
+	}
+}
+
+func TestRetryCoordinatorKeepsIdentityKeys(t *testing.T) {
+	keys, pIDs, err := keygen.LoadKeygenTestFixtures(testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	identityKeys := make(map[string]ed25519.PrivateKey, len(pIDs))
+	for _, pID := range pIDs {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		assert.NoError(t, err)
+		pID.IdentityKey, identityKeys[pID.KeyInt().String()] = pub, priv
+	}
+
+	// the first attempt blames a party, so the second one runs with a new committee
+	attempt := func(i int, committee tss.SortedPartyIDs) error {
+		if i == 0 {
+			return tss.NewError(errors.New("misbehaved"), TaskName, 1, nil, committee[0])
+		}
+		for _, pID := range committee {
+			assert.NotNil(t, pID.IdentityKey, "a retry committee should keep the identity keys")
+		}
+		signer := committee[0]
+		outCh := make(chan tss.Message, len(committee))
+		params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(committee), signer, len(committee), testThreshold)
+		params.SetIdentityKey(identityKeys[signer.KeyInt().String()])
+		key := keygen.BuildLocalSaveDataSubset(keys[pIDs.FindByKey(signer.KeyInt()).Index], committee)
+		P := NewLocalParty(big.NewInt(200), params, key, outCh, nil)
+		if err := P.Start(); err != nil {
+			return err
+		}
+		out := <-outCh
+		bz, _, err := out.WireBytes()
+		assert.NoError(t, err)
+		_, err = tss.ParseWireMessage(bz, signer, out.IsBroadcast())
+		assert.NoError(t, err, "a signed message should parse")
+		_, err = tss.ParseWireMessage(bz[ed25519.SignatureSize:], signer, out.IsBroadcast())
+		assert.Error(t, err, "an unsigned message should be rejected")
+		return nil
+	}
+
+	rc := tss.NewRetryCoordinator(pIDs, testThreshold, 2, attempt)
+	_, err = rc.Run()
+	assert.NoError(t, err)
+	assert.Len(t, rc.History(), 2)
+}
+
+func TestE2EMessagesBeforeStart(t *testing.T) {
+	setUp("info")
+
+	threshold := testThreshold
+
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	parties := make([]*LocalParty, 0, len(signPIDs))
+
+	errCh := make(chan *tss.Error, len(signPIDs))
+	outCh := make(chan tss.Message, 2*len(signPIDs))
+	endCh := make(chan *common.SignatureData, len(signPIDs))
+
+	updater := test.SharedPartyUpdater
+
+	msg := big.NewInt(200)
+	for i := 0; i < len(signPIDs); i++ {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), threshold)
+		parties = append(parties, NewLocalParty(msg, params, keys[i], outCh, endCh).(*LocalParty))
+	}
+	// party 0 starts late, after every other party's round 1 message has been delivered to it
+	late := parties[0]
+	for _, P := range parties[1:] {
+		go func(P *LocalParty) {
+			if err := P.Start(); err != nil {
+				errCh <- err
+			}
+		}(P)
+	}
+
+	var early, ended int
+signing:
+	for {
+		select {
+		case err := <-errCh:
+			assert.FailNow(t, err.Error())
+			break signing
+
+		case msg := <-outCh:
+			for _, P := range parties {
+				if P.PartyID().Index == msg.GetFrom().Index {
+					continue
+				}
+				if dest := msg.GetTo(); dest != nil && dest[0].Index != P.PartyID().Index {
+					continue
+				}
+				if P != late || late.Running() || ended > 0 {
+					go updater(P, msg, errCh)
+					continue
+				}
+				if _, err := late.Update(msg.(tss.ParsedMessage)); err != nil {
+					assert.FailNow(t, err.Error())
+				}
+				if early++; early == len(signPIDs)-1 {
+					assert.Nil(t, late.Start())
+					// the buffered messages complete round 1 as soon as the party starts
+					assert.Contains(t, late.String(), "round: 2")
+				}
+			}
+
+		case <-endCh:
+			if ended++; ended == len(signPIDs) {
+				break signing
+			}
+		}
+	}
+}
+
+func TestMessagesBeforeStartBounded(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	p2pCtx := tss.NewPeerContext(signPIDs)
+
+	outCh := make(chan tss.Message, len(signPIDs))
+	sender := NewLocalParty(big.NewInt(200), tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[1], len(signPIDs), testThreshold),
+		keys[1], outCh, nil)
+	assert.Nil(t, sender.Start())
+	msg := (<-outCh).(tss.ParsedMessage)
+
+	// a party that is never started holds a bounded number of messages
+	P := NewLocalParty(big.NewInt(200), tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[0], len(signPIDs), testThreshold),
+		keys[0], nil, nil)
+	for i := 0; i < tss.MaxQueuedMessages; i++ {
+		if _, err := P.Update(msg); !assert.Nil(t, err) {
+			return
+		}
+	}
+	_, tssErr := P.Update(msg)
+	if assert.NotNil(t, tssErr, "a message beyond the bound should be dropped") {
+		assert.Equal(t, tss.CodeRateLimited, tssErr.Code())
+		assert.ErrorIs(t, tssErr.Cause(), tss.ErrQueueFull)
+	}
+}
+
+// xorShareWrapper "wraps" shares by XORing their bytes with a pad, and counts the unwraps
+type xorShareWrapper struct {
+	pad     byte
+	unwraps int
+}
+
+func (w *xorShareWrapper) WrapShare(xi *big.Int) ([]byte, error) {
+	bz := xi.Bytes()
+	for i := range bz {
+		bz[i] ^= w.pad
+	}
+	return bz, nil
+}
+
+func (w *xorShareWrapper) UnwrapShare(wrapped []byte) (*big.Int, error) {
+	w.unwraps++
+	bz := append([]byte(nil), wrapped...)
+	for i := range bz {
+		bz[i] ^= w.pad
+	}
+	return new(big.Int).SetBytes(bz), nil
+}
+
+func TestE2EWrappedShare(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err)
+	wrapper := &xorShareWrapper{pad: 0x5a}
+	for i := range keys {
+		xi := new(big.Int).Set(keys[i].Xi)
+		assert.NoError(t, keys[i].WrapShare(wrapper))
+		assert.Nil(t, keys[i].Xi)
+		assert.NotEmpty(t, keys[i].WrappedXi)
+		unwrapped, err := wrapper.UnwrapShare(keys[i].WrappedXi)
+		assert.NoError(t, err)
+		assert.Equal(t, xi, unwrapped)
+	}
+	wrapper.unwraps = 0
+
+	p2pCtx := tss.NewPeerContext(signPIDs)
+	msg := big.NewInt(42)
+	newSimulator := func(w tss.ShareWrapper) (*test.Simulator, chan *common.SignatureData) {
+		endCh := make(chan *common.SignatureData, len(signPIDs))
+		sim := test.NewSimulator(1, test.Faults{})
+		for i := range signPIDs {
+			params := tss.NewParameters(tss.Edwards(), p2pCtx, signPIDs[i], len(signPIDs), testThreshold)
+			if w != nil {
+				params.SetShareWrapper(w)
+			}
+			key := keys[i]
+			sim.Add(func(out chan<- tss.Message) tss.Party { return NewLocalParty(msg, params, key, out, endCh) })
+		}
+		return sim, endCh
+	}
+
+	sim, endCh := newSimulator(wrapper)
+	sim.Start()
+	sim.Run(0)
+	assert.Empty(t, sim.Errors())
+	if assert.Len(t, endCh, len(signPIDs)) {
+		sig := <-endCh
+		pk := edwards.PublicKey{Curve: tss.Edwards(), X: keys[0].EDDSAPub.X(), Y: keys[0].EDDSAPub.Y()}
+		parsed, err := edwards.ParseSignature(sig.Signature)
+		assert.NoError(t, err)
+		assert.True(t, edwards.Verify(&pk, msg.Bytes(), parsed.R, parsed.S), "eddsa verify must pass")
+	}
+	assert.Equal(t, len(signPIDs), wrapper.unwraps, "every party unwraps its share once")
+	for i := range keys {
+		assert.Nil(t, keys[i].Xi, "the share stays wrapped in the save data")
+	}
+
+	// a wrapped share cannot be used without the wrapper
+	sim, _ = newSimulator(nil)
+	sim.Start()
+	assert.Len(t, sim.Errors(), len(signPIDs))
+}
+
+func TestWipe(t *testing.T) {
+	keys, signPIDs, err := keygen.LoadKeygenTestFixturesRandomSet(testThreshold+1, testParticipants)
+	assert.NoError(t, err, "should load keygen fixtures")
+	params := tss.NewParameters(tss.Edwards(), tss.NewPeerContext(signPIDs), signPIDs[0], len(signPIDs), testThreshold)
+	xi := new(big.Int).Set(keys[0].Xi)
+
+	P := NewLocalParty(big.NewInt(1), params, keys[0], nil, nil).(*LocalParty)
+	P.temp.wi, P.temp.ri = big.NewInt(5), big.NewInt(7)
+	P.Wipe()
+	assert.Zero(t, P.temp.wi.Sign())
+	assert.Zero(t, P.temp.ri.Sign())
+	assert.Equal(t, xi, keys[0].Xi, "the share of the key is the caller's")
+
+	preSig := &PreSignatureData{Ri: big.NewInt(9), Ei: big.NewInt(10)}
+	preSig.Wipe()
+	assert.Zero(t, preSig.Ri.Sign())
+	assert.Zero(t, preSig.Ei.Sign())
+}
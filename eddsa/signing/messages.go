@@ -78,8 +78,9 @@ func NewSignRound2Message(
 }
 
 func (m *SignRound2Message) ValidateBasic() bool {
+	// the de-commitment opens Ri, and also Ei when pre-signing
 	return m != nil &&
-		common.NonEmptyMultiBytes(m.DeCommitment, 3) &&
+		(common.NonEmptyMultiBytes(m.DeCommitment, 3) || common.NonEmptyMultiBytes(m.DeCommitment, 5)) &&
 		common.NonEmptyBytes(m.ProofAlphaX) &&
 		common.NonEmptyBytes(m.ProofAlphaY) &&
 		common.NonEmptyBytes(m.ProofT)
@@ -0,0 +1,88 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	iden3bjj "github.com/iden3/go-iden3-crypto/babyjub"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+
+	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// A BabyJubJub key signs with the EdDSA-Poseidon of iden3 and circomlib: for the public key A = x*B8 and the nonce
+// R8 = r*B8, S = r + 8*Poseidon(R8x, R8y, Ax, Ay, M)*x mod the subgroup order. The signature is R8 compressed as
+// iden3 babyjub does, followed by S in little-endian, and M must be an element of the Poseidon field.
+
+func isBabyJubJub(ec elliptic.Curve) bool {
+	return tss.SameCurve(ec, tss.BabyJubJub())
+}
+
+// sumPoints returns R8, the sum of the nonce commitments Rj of the signing parties
+func sumPoints(bigRjs []*crypto.ECPoint) (*crypto.ECPoint, error) {
+	if len(bigRjs) == 0 || bigRjs[0] == nil {
+		return nil, errors.New("missing nonce commitment")
+	}
+	sum := bigRjs[0]
+	for _, Rj := range bigRjs[1:] {
+		if Rj == nil {
+			return nil, errors.New("missing nonce commitment")
+		}
+		var err error
+		if sum, err = sum.Add(Rj); err != nil {
+			return nil, err
+		}
+	}
+	return sum, nil
+}
+
+// encodeBabyJubJubPoint returns the compressed point as an integer, the way r holds the encoded R of Ed25519
+func encodeBabyJubJubPoint(p *crypto.ECPoint) *big.Int {
+	var encoded [32]byte
+	copy(encoded[:], babyjubjub.Compress(p.X(), p.Y()))
+	return encodedBytesToBigInt(&encoded)
+}
+
+// poseidonChallenge returns 8*Poseidon(R8x, R8y, Ax, Ay, M) mod the subgroup order
+func poseidonChallenge(ec elliptic.Curve, R8, A *crypto.ECPoint, m *big.Int) (*big.Int, error) {
+	if !common.IsPoseidonFieldElement(m) {
+		return nil, errors.New("the message of a BabyJubJub signature must be an element of the Poseidon field")
+	}
+	hm, err := poseidon.Hash([]*big.Int{R8.X(), R8.Y(), A.X(), A.Y(), m})
+	if err != nil {
+		return nil, err
+	}
+	return common.ModInt(ec.Params().N).Mul(hm, big.NewInt(int64(babyjubjub.Params().H))), nil
+}
+
+// verifyPoseidon checks the signature (R8, S) of `m` by A with iden3 babyjub
+func verifyPoseidon(A, R8 *crypto.ECPoint, s, m *big.Int) bool {
+	pk := iden3bjj.PublicKey{X: A.X(), Y: A.Y()}
+	return pk.VerifyPoseidon(m, &iden3bjj.Signature{R8: &iden3bjj.Point{X: R8.X(), Y: R8.Y()}, S: s})
+}
+
+// signPoseidonShare computes lambda and the share si = ri + lambda*wi of S
+func (round *round3) signPoseidonShare() *tss.Error {
+	R8, err := sumPoints(round.temp.bigRjs)
+	if err != nil {
+		return round.WrapError(err).WithCode(tss.CodeInternal)
+	}
+	lambda, err := poseidonChallenge(round.Params().EC(), R8, round.key.EDDSAPub, round.temp.m)
+	if err != nil {
+		return round.WrapError(err).WithCode(tss.CodeInvalidInput)
+	}
+	modN := common.ModInt(round.Params().EC().Params().N)
+	round.temp.lambda = lambda
+	round.temp.si = bigIntToEncodedBytes(modN.Add(round.temp.ri, modN.Mul(lambda, round.temp.wi)))
+	return nil
+}
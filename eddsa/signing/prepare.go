@@ -12,6 +12,8 @@ import (
 	"math/big"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
 )
 
 // PrepareForSigning(), Fig. 7
@@ -42,3 +44,61 @@ func PrepareForSigning(ec elliptic.Curve, i, pax int, xi *big.Int, ks []*big.Int
 
 	return
 }
+
+// PrepareForSigningWithAccessStructure is PrepareForSigning for a key shared along an access structure: the
+// coefficients of the structure take the place of the Lagrange coefficients, and Wj = wj*G is returned along with wi.
+// The signers `ks` must be a set chosen by AccessStructure.Resolve.
+func PrepareForSigningWithAccessStructure(ec elliptic.Curve, i int, xi *big.Int, ks []*big.Int, bigXs []*crypto.ECPoint, as *tss.AccessStructure) (wi *big.Int, bigWs []*crypto.ECPoint, err error) {
+	if len(ks) != len(bigXs) {
+		return nil, nil, fmt.Errorf("PrepareForSigningWithAccessStructure: len(ks) != len(bigXs) (%d != %d)", len(ks), len(bigXs))
+	}
+	if len(ks) <= i {
+		return nil, nil, fmt.Errorf("PrepareForSigningWithAccessStructure: len(ks) <= i (%d <= %d)", len(ks), i)
+	}
+	coefs, err := as.Coefficients(ec, ks)
+	if err != nil {
+		return nil, nil, err
+	}
+	bigWs = make([]*crypto.ECPoint, len(ks))
+	for j, coef := range coefs {
+		bigWs[j] = bigXs[j].ScalarMult(coef)
+	}
+	return common.ModInt(ec.Params().N).Mul(xi, coefs[i]), bigWs, nil
+}
+
+// PrepareShareCommitments returns Wj = wj*G for every signer, the public counterparts of the wj of PrepareForSigning.
+// They are used to verify each party's signature share before the shares are combined.
+func PrepareShareCommitments(ec elliptic.Curve, ks []*big.Int, bigXs []*crypto.ECPoint) ([]*crypto.ECPoint, error) {
+	modQ := common.ModInt(ec.Params().N)
+	if len(ks) != len(bigXs) {
+		return nil, fmt.Errorf("PrepareShareCommitments: len(ks) != len(bigXs) (%d != %d)", len(ks), len(bigXs))
+	}
+	bigWs := make([]*crypto.ECPoint, len(ks))
+	for j := range ks {
+		coef := big.NewInt(1)
+		for c := range ks {
+			if c == j {
+				continue
+			}
+			if ks[c].Cmp(ks[j]) == 0 {
+				return nil, fmt.Errorf("index of two parties are equal")
+			}
+			coef = modQ.Mul(coef, modQ.Mul(ks[c], modQ.ModInverse(new(big.Int).Sub(ks[c], ks[j]))))
+		}
+		bigWs[j] = bigXs[j].ScalarMult(coef)
+	}
+	return bigWs, nil
+}
+
+// VerifySignatureShare checks the s share of one party against its nonce commitment Rj and its public Wj:
+// sj*G = Rj + lambda*Wj, where lambda is the challenge hash of round 3 reduced mod the group order
+func VerifySignatureShare(ec elliptic.Curve, sj *big.Int, bigRj, bigWj *crypto.ECPoint, lambda *big.Int) bool {
+	if sj == nil || bigRj == nil || bigWj == nil || lambda == nil {
+		return false
+	}
+	right, err := bigRj.Add(bigWj.ScalarMult(lambda))
+	if err != nil {
+		return false
+	}
+	return crypto.ScalarBaseMult(ec, sj).Equals(right)
+}
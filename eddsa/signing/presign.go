@@ -0,0 +1,187 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const bindingFactorTag = "tss-lib eddsa pre-signature binding factor"
+
+type (
+	// PreSignatureData is the message-independent state produced by rounds 1-2 of signing.
+	// It contains this party's secret nonce shares and must be stored as securely as the key share itself.
+	// A PreSignatureData must be used to sign at most one message; reusing it leaks the key share.
+	//
+	// As in FROST, each party commits to two nonces di and ei, and the nonce of the signature is only fixed once the
+	// message is known: Rj = Dj + rho_j*Ej, where the binding factor rho_j hashes the message and the commitments of
+	// the whole committee. An attacker running many signing sessions at once therefore cannot choose the messages
+	// after seeing R, which defeats the ROS attack on pre-signatures made with a single nonce.
+	PreSignatureData struct {
+		// secret nonce shares di and ei
+		Ri *big.Int
+		Ei *big.Int
+
+		// session the nonces were committed to
+		SSID []byte
+
+		// share IDs of the signing committee, in sorted order
+		Ks []*big.Int
+
+		// nonce commitments Dj and Ej of each party
+		BigRjs []*crypto.ECPoint
+		BigEjs []*crypto.ECPoint
+
+		// key share epoch the pre-signature was made under, set by the caller; resharing should bump it
+		Epoch uint64
+
+		// the pre-signature must not be used after this time; the zero value means it never expires
+		ExpiresAt time.Time
+
+		// set once the nonce has been handed to a signing party
+		Consumed bool
+	}
+)
+
+// Wipe overwrites the nonce shares of the pre-signature on a best-effort basis (see common.ZeroInt), e.g. when it
+// is discarded unused. A signing party wipes that of the pre-signature it consumes itself.
+func (preSig *PreSignatureData) Wipe() {
+	common.ZeroInt(preSig.Ri)
+	common.ZeroInt(preSig.Ei)
+}
+
+// SetExpiry binds the pre-signature to a key share epoch and expires it `ttl` from now
+func (preSig *PreSignatureData) SetExpiry(epoch uint64, ttl time.Duration) {
+	preSig.Epoch = epoch
+	preSig.ExpiresAt = time.Now().Add(ttl)
+}
+
+// Marshal serialises the pre-signature for storage between the offline and online phases
+func (preSig *PreSignatureData) Marshal() ([]byte, error) {
+	return json.Marshal(preSig)
+}
+
+// UnmarshalPreSignatureData parses a pre-signature produced by Marshal
+func UnmarshalPreSignatureData(bz []byte) (*PreSignatureData, error) {
+	preSig := new(PreSignatureData)
+	if err := json.Unmarshal(bz, preSig); err != nil {
+		return nil, err
+	}
+	if !preSig.complete() || len(preSig.Ks) == 0 {
+		return nil, errors.New("pre-signature data is incomplete")
+	}
+	return preSig, nil
+}
+
+// CheckUsable returns an error if the pre-signature was already consumed, has expired at `now`,
+// or was made under a key share epoch other than `epoch`
+func (preSig *PreSignatureData) CheckUsable(epoch uint64, now time.Time) error {
+	if err := preSig.checkFresh(now); err != nil {
+		return err
+	}
+	if preSig.Epoch != epoch {
+		return fmt.Errorf("pre-signature is stale: made in epoch %d, current epoch is %d", preSig.Epoch, epoch)
+	}
+	return nil
+}
+
+func (preSig *PreSignatureData) checkFresh(now time.Time) error {
+	if preSig.Consumed {
+		return errors.New("pre-signature was already consumed")
+	}
+	if !preSig.ExpiresAt.IsZero() && now.After(preSig.ExpiresAt) {
+		return fmt.Errorf("pre-signature expired at %s", preSig.ExpiresAt)
+	}
+	return nil
+}
+
+// ValidateFor checks that the pre-signature is complete and was produced by the given signing committee
+func (preSig *PreSignatureData) ValidateFor(sortedIDs tss.SortedPartyIDs) error {
+	if preSig == nil || !preSig.complete() {
+		return errors.New("pre-signature data is incomplete")
+	}
+	if len(preSig.Ks) != sortedIDs.Len() {
+		return fmt.Errorf("pre-signature was made by %d parties but %d are signing", len(preSig.Ks), sortedIDs.Len())
+	}
+	for j, kj := range sortedIDs.Keys() {
+		if preSig.Ks[j] == nil || preSig.Ks[j].Cmp(kj) != 0 {
+			return fmt.Errorf("pre-signature was not made by signing party %s", sortedIDs[j])
+		}
+	}
+	return nil
+}
+
+// complete reports whether the pre-signature holds both nonce shares and a commitment pair for each party
+func (preSig *PreSignatureData) complete() bool {
+	if preSig.Ri == nil || preSig.Ei == nil || len(preSig.SSID) == 0 ||
+		len(preSig.BigRjs) != len(preSig.Ks) || len(preSig.BigEjs) != len(preSig.Ks) {
+		return false
+	}
+	for j := range preSig.Ks {
+		if preSig.BigRjs[j] == nil || preSig.BigEjs[j] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// bindNonces computes the nonce Rj = Dj + rho_j*Ej of each party of a pre-signature and their sum R, once the
+// message is known, and the nonce share ri = di + rho_i*ei of this party. The nonce shares di and ei are then wiped.
+func (round *round3) bindNonces() *tss.Error {
+	ec := round.Params().EC()
+	modN := common.ModInt(ec.Params().N)
+	bigDjs, bigEjs := round.temp.bigRjs, round.temp.bigEjs
+	if len(bigDjs) != len(round.Parties().IDs()) || len(bigEjs) != len(bigDjs) {
+		return round.WrapError(errors.New("pre-signature does not hold a nonce commitment of each party")).WithCode(tss.CodeInvalidInput)
+	}
+
+	// the binding factors hash the message and the commitments of the whole committee
+	in := [][]byte{[]byte(bindingFactorTag), round.temp.ssid, round.temp.opts.dom2(), round.temp.opts.preHash(round.messageBytes())}
+	for j := range bigDjs {
+		in = append(in, bigDjs[j].X().Bytes(), bigDjs[j].Y().Bytes(), bigEjs[j].X().Bytes(), bigEjs[j].Y().Bytes())
+	}
+	base := common.SHA512_256(in...)
+
+	bigRjs := make([]*crypto.ECPoint, len(bigDjs))
+	var rhoI *big.Int
+	for j := range bigDjs {
+		rhoJ := new(big.Int).Mod(new(big.Int).SetBytes(common.SHA512_256(base, big.NewInt(int64(j)).Bytes())), ec.Params().N)
+		Rj, err := bigDjs[j].Add(bigEjs[j].ScalarMult(rhoJ))
+		if err != nil {
+			return round.WrapError(err, round.Parties().IDs()[j]).WithCode(tss.CodeBadPublicData)
+		}
+		bigRjs[j] = Rj
+		if j == round.PartyID().Index {
+			rhoI = rhoJ
+		}
+	}
+	R, err := sumPoints(bigRjs)
+	if err != nil {
+		return round.WrapError(err).WithCode(tss.CodeInternal)
+	}
+	if isBabyJubJub(ec) {
+		round.temp.r = encodeBabyJubJubPoint(R)
+	} else {
+		round.temp.r = encodedBytesToBigInt(ecPointToEncodedBytes(R.X(), R.Y()))
+	}
+
+	di, ei := round.temp.ri, round.temp.ei
+	round.temp.ri = modN.Add(di, modN.Mul(rhoI, ei))
+	round.temp.bigRjs = bigRjs
+	common.ZeroInt(di)
+	common.ZeroInt(ei)
+	round.temp.ei = nil
+	return nil
+}
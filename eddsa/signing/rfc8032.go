@@ -0,0 +1,107 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package signing
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/sha512"
+	"errors"
+
+	"github.com/agl/ed25519/edwards25519"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// dom2 prefix of RFC 8032 section 5.1
+const dom2Prefix = "SigEd25519 no Ed25519 collisions"
+
+// Options selects the RFC 8032 variant of the signature, mirroring crypto/ed25519.Options.
+// The zero value (or nil) selects pure Ed25519.
+type Options struct {
+	// PreHash selects Ed25519ph: the message is hashed with SHA-512 before signing
+	PreHash bool
+
+	// Context is the context string of Ed25519ctx or Ed25519ph, at most 255 bytes.
+	// A non-empty context without PreHash selects Ed25519ctx.
+	Context []byte
+}
+
+// validate checks the options for a session on the curve `ec`: the variants of RFC 8032 are defined for Ed25519 only
+func (opts *Options) validate(ec elliptic.Curve) error {
+	if opts == nil {
+		return nil
+	}
+	if !tss.SameCurve(ec, tss.Edwards()) {
+		return errors.New("the RFC 8032 options apply to Ed25519 only")
+	}
+	if len(opts.Context) > 255 {
+		return errors.New("ed25519 context string must be at most 255 bytes")
+	}
+	return nil
+}
+
+// isPure reports whether the options select plain Ed25519, which has no dom2 prefix
+func (opts *Options) isPure() bool {
+	return opts == nil || (!opts.PreHash && len(opts.Context) == 0)
+}
+
+// dom2 returns the dom2(phflag, context) prefix, or nil for pure Ed25519
+func (opts *Options) dom2() []byte {
+	if opts.isPure() {
+		return nil
+	}
+	var phFlag byte
+	if opts.PreHash {
+		phFlag = 1
+	}
+	dom := make([]byte, 0, len(dom2Prefix)+2+len(opts.Context))
+	dom = append(dom, dom2Prefix...)
+	dom = append(dom, phFlag, byte(len(opts.Context)))
+	return append(dom, opts.Context...)
+}
+
+// preHash returns PH(M): SHA-512(M) for Ed25519ph, M otherwise
+func (opts *Options) preHash(msg []byte) []byte {
+	if opts == nil || !opts.PreHash {
+		return msg
+	}
+	digest := sha512.Sum512(msg)
+	return digest[:]
+}
+
+// challenge computes k = SHA-512(dom2(F, C) || R || A || PH(M)) reduced mod L
+func (opts *Options) challenge(encodedR, encodedPubKey *[32]byte, msg []byte) *[32]byte {
+	h := sha512.New()
+	h.Write(opts.dom2())
+	h.Write(encodedR[:])
+	h.Write(encodedPubKey[:])
+	h.Write(opts.preHash(msg))
+
+	var k [64]byte
+	h.Sum(k[:0])
+	var kReduced [32]byte
+	edwards25519.ScReduce(&kReduced, &k)
+	return &kReduced
+}
+
+// verify checks [S]B = R + [k]A for the signature R || S of `msg` as RFC 8032 section 5.1.7
+func (opts *Options) verify(encodedPubKey *[32]byte, msg []byte, encodedR, encodedS *[32]byte) bool {
+	var A edwards25519.ExtendedGroupElement
+	if !A.FromBytes(encodedPubKey) {
+		return false
+	}
+	edwards25519.FeNeg(&A.X, &A.X)
+	edwards25519.FeNeg(&A.T, &A.T)
+
+	k := opts.challenge(encodedR, encodedPubKey, msg)
+	var checkR edwards25519.ProjectiveGroupElement
+	edwards25519.GeDoubleScalarMultVartime(&checkR, k, &A, encodedS)
+	var checkRBytes [32]byte
+	checkR.ToBytes(&checkRBytes)
+	return bytes.Equal(encodedR[:], checkRBytes[:])
+}
@@ -19,20 +19,23 @@ import (
 )
 
 // round 1 represents round 1 of the signing part of the EDDSA TSS spec
-func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData) tss.Round {
+func newRound1(params *tss.Parameters, key *keygen.LocalPartySaveData, data *common.SignatureData, temp *localTempData, out chan<- tss.Message, end chan<- *common.SignatureData, preEnd chan<- *PreSignatureData) tss.Round {
 	return &round1{
-		&base{params, key, data, temp, out, end, make([]bool, len(params.Parties().IDs())), false, 1},
+		&base{params, key, data, temp, out, end, preEnd, make([]bool, len(params.Parties().IDs())), false, 1},
 	}
 }
 
 func (round *round1) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 
 	round.number = 1
 	round.started = true
 	round.resetOK()
+	if err := round.ValidateHashScheme(round.key.HashScheme); err != nil {
+		return round.WrapError(err)
+	}
 
 	round.temp.ssidNonce = new(big.Int).SetUint64(0)
 	var err error
@@ -40,12 +43,18 @@ func (round *round1) Start() *tss.Error {
 	if err != nil {
 		return round.WrapError(err)
 	}
-	// 1. select ri
+	// 1. select ri, and for a pre-signature the second nonce ei that binds R to the message (see bindNonces)
 	ri := common.GetRandomPositiveInt(round.Rand(), round.Params().EC().Params().N)
 
 	// 2. make commitment
 	pointRi := crypto.ScalarBaseMult(round.Params().EC(), ri)
-	cmt := commitments.NewHashCommitment(round.Rand(), pointRi.X(), pointRi.Y())
+	secrets := []*big.Int{pointRi.X(), pointRi.Y()}
+	if round.preEnd != nil {
+		round.temp.ei = common.GetRandomPositiveInt(round.Rand(), round.Params().EC().Params().N)
+		round.temp.pointEi = crypto.ScalarBaseMult(round.Params().EC(), round.temp.ei)
+		secrets = append(secrets, round.temp.pointEi.X(), round.temp.pointEi.Y())
+	}
+	cmt := commitments.NewHashCommitmentWithHasher(round.HashScheme().Tagged(common.PoseidonTagEDDSASigningRi), round.Rand(), secrets...)
 
 	// 3. store r1 message pieces
 	round.temp.ri = ri
@@ -58,7 +67,7 @@ func (round *round1) Start() *tss.Error {
 	// 4. broadcast commitment
 	r1msg2 := NewSignRound1Message(round.PartyID(), cmt.C)
 	round.temp.signRound1Messages[i] = r1msg2
-	round.out <- r1msg2
+	round.out <- round.Outbound(r1msg2)
 
 	return nil
 }
@@ -96,14 +105,48 @@ func (round *round1) NextRound() tss.Round {
 func (round *round1) prepare() error {
 	i := round.PartyID().Index
 
-	xi := round.key.Xi
+	xi, unwrapped, err := round.key.SigningShare(round.Params().ShareWrapper())
+	if err != nil {
+		return err
+	}
+	if round.temp.keyDerivationDelta != nil {
+		// Suppose x has shamir shares x_0,     x_1,     ..., x_n
+		// So x + D has shamir shares  x_0 + D, x_1 + D, ..., x_n + D
+		derived := common.ModInt(round.Params().EC().Params().N).Add(round.temp.keyDerivationDelta, xi)
+		if unwrapped {
+			common.ZeroInt(xi)
+		}
+		xi, unwrapped = derived, true
+	}
+	if unwrapped {
+		// an unwrapped share only lives in memory until wi is derived from it
+		defer func() {
+			if xi != round.temp.wi {
+				common.ZeroInt(xi)
+			}
+		}()
+	}
 	ks := round.key.Ks
 
 	if round.Threshold()+1 > len(ks) {
 		return fmt.Errorf("t+1=%d is not satisfied by the key count of %d", round.Threshold()+1, len(ks))
 	}
+	if as := round.key.AccessStructure; as != nil {
+		wi, bigWjs, err := PrepareForSigningWithAccessStructure(round.Params().EC(), i, xi, ks, round.key.BigXj, as)
+		if err != nil {
+			return err
+		}
+		round.temp.wi = wi
+		round.temp.bigWjs = bigWjs
+		return nil
+	}
 	wi := PrepareForSigning(round.Params().EC(), i, len(ks), xi, ks)
+	bigWjs, err := PrepareShareCommitments(round.Params().EC(), ks, round.key.BigXj)
+	if err != nil {
+		return err
+	}
 
 	round.temp.wi = wi
+	round.temp.bigWjs = bigWjs
 	return nil
 }
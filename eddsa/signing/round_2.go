@@ -18,7 +18,7 @@ import (
 
 func (round *round2) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 	round.number = 2
 	round.started = true
@@ -42,7 +42,7 @@ func (round *round2) Start() *tss.Error {
 	// 3. BROADCAST de-commitments of Shamir poly*G and Schnorr prove
 	r2msg2 := NewSignRound2Message(round.PartyID(), round.temp.deCommit, pir)
 	round.temp.signRound2Messages[i] = r2msg2
-	round.out <- r2msg2
+	round.out <- round.Outbound(r2msg2)
 
 	return nil
 }
@@ -71,5 +71,8 @@ func (round *round2) Update() (bool, *tss.Error) {
 
 func (round *round2) NextRound() tss.Round {
 	round.started = false
+	if round.preEnd != nil {
+		return &presignFinalization{round}
+	}
 	return &round3{round}
 }
@@ -21,90 +21,60 @@ import (
 
 func (round *round3) Start() *tss.Error {
 	if round.started {
-		return round.WrapError(errors.New("round already started"))
+		return round.WrapError(errors.New("round already started")).WithCode(tss.CodeInternal)
 	}
 
 	round.number = 3
 	round.started = true
 	round.resetOK()
 
-	// 1. init R
-	var R edwards25519.ExtendedGroupElement
-	riBytes := bigIntToEncodedBytes(round.temp.ri)
-	edwards25519.GeScalarMultBase(&R, riBytes)
-
-	// 2-6. compute R
-	i := round.PartyID().Index
-	for j, Pj := range round.Parties().IDs() {
-		if j == i {
-			continue
-		}
-
-		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
-		msg := round.temp.signRound2Messages[j]
-		r2msg := msg.Content().(*SignRound2Message)
-		cmtDeCmt := commitments.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment()}
-		ok, coordinates := cmtDeCmt.DeCommit()
-		if !ok {
-			return round.WrapError(errors.New("de-commitment verify failed"))
-		}
-		if len(coordinates) != 2 {
-			return round.WrapError(errors.New("length of de-commitment should be 2"))
-		}
-
-		Rj, err := crypto.NewECPoint(round.Params().EC(), coordinates[0], coordinates[1])
-		Rj = Rj.EightInvEight()
-		if err != nil {
-			return round.WrapError(errors.Wrapf(err, "NewECPoint(Rj)"), Pj)
+	// 1-6. compute R, or bind the nonces of the pre-signature to the message
+	if !round.temp.preSigned {
+		if err := round.computeR(); err != nil {
+			return err
 		}
-		proof, err := r2msg.UnmarshalZKProof(round.Params().EC())
-		if err != nil {
-			return round.WrapError(errors.New("failed to unmarshal Rj proof"), Pj)
-		}
-		ok = proof.Verify(ContextJ, Rj)
-		if !ok {
-			return round.WrapError(errors.New("failed to prove Rj"), Pj)
-		}
-
-		extendedRj := ecPointToExtendedElement(round.Params().EC(), Rj.X(), Rj.Y(), round.Rand())
-		R = addExtendedElements(R, extendedRj)
+	} else if err := round.bindNonces(); err != nil {
+		return err
 	}
 
-	// 7. compute lambda
-	var encodedR [32]byte
-	R.ToBytes(&encodedR)
-	encodedPubKey := ecPointToEncodedBytes(round.key.EDDSAPub.X(), round.key.EDDSAPub.Y())
-
-	// h = hash512(k || A || M)
-	h := sha512.New()
-	h.Reset()
-	h.Write(encodedR[:])
-	h.Write(encodedPubKey[:])
-	if round.temp.fullBytesLen == 0 {
-		h.Write(round.temp.m.Bytes())
+	// 7-9. compute lambda and si
+	if isBabyJubJub(round.Params().EC()) {
+		if err := round.signPoseidonShare(); err != nil {
+			return err
+		}
 	} else {
-		var mBytes = make([]byte, round.temp.fullBytesLen)
-		round.temp.m.FillBytes(mBytes)
-		h.Write(mBytes)
+		riBytes := bigIntToEncodedBytes(round.temp.ri)
+
+		// 7. compute lambda
+		encodedR := bigIntToEncodedBytes(round.temp.r)
+		encodedPubKey := ecPointToEncodedBytes(round.key.EDDSAPub.X(), round.key.EDDSAPub.Y())
+
+		// h = hash512(dom2(F, C) || k || A || PH(M)); for pure Ed25519 dom2 is empty and PH is the identity
+		h := sha512.New()
+		h.Reset()
+		h.Write(round.temp.opts.dom2())
+		h.Write(encodedR[:])
+		h.Write(encodedPubKey[:])
+		h.Write(round.temp.opts.preHash(round.messageBytes()))
+
+		var lambda [64]byte
+		h.Sum(lambda[:0])
+		var lambdaReduced [32]byte
+		edwards25519.ScReduce(&lambdaReduced, &lambda)
+		round.temp.lambda = encodedBytesToBigInt(&lambdaReduced)
+
+		// 8. compute si
+		var localS [32]byte
+		edwards25519.ScMulAdd(&localS, &lambdaReduced, bigIntToEncodedBytes(round.temp.wi), riBytes)
+
+		// 9. store r3 message pieces
+		round.temp.si = &localS
 	}
 
-	var lambda [64]byte
-	h.Sum(lambda[:0])
-	var lambdaReduced [32]byte
-	edwards25519.ScReduce(&lambdaReduced, &lambda)
-
-	// 8. compute si
-	var localS [32]byte
-	edwards25519.ScMulAdd(&localS, &lambdaReduced, bigIntToEncodedBytes(round.temp.wi), riBytes)
-
-	// 9. store r3 message pieces
-	round.temp.si = &localS
-	round.temp.r = encodedBytesToBigInt(&encodedR)
-
 	// 10. broadcast si to other parties
-	r3msg := NewSignRound3Message(round.PartyID(), encodedBytesToBigInt(&localS))
+	r3msg := NewSignRound3Message(round.PartyID(), encodedBytesToBigInt(round.temp.si))
 	round.temp.signRound3Messages[round.PartyID().Index] = r3msg
-	round.out <- r3msg
+	round.out <- round.Outbound(r3msg)
 
 	return nil
 }
@@ -131,7 +101,99 @@ func (round *round3) CanAccept(msg tss.ParsedMessage) bool {
 	return false
 }
 
+// messageBytes returns the message to sign, padded to fullBytesLen when it was given
+func (round *round3) messageBytes() []byte {
+	if round.temp.fullBytesLen == 0 {
+		return round.temp.m.Bytes()
+	}
+	mBytes := make([]byte, round.temp.fullBytesLen)
+	round.temp.m.FillBytes(mBytes)
+	return mBytes
+}
+
 func (round *round3) NextRound() tss.Round {
 	round.started = false
 	return &finalization{round}
 }
+
+// computeR verifies the de-commitments and proofs of the Rj broadcast in rounds 1-2 and sums them into R
+func (round *round2) computeR() *tss.Error {
+	// 1. init R
+	bjj := isBabyJubJub(round.Params().EC())
+	var R edwards25519.ExtendedGroupElement
+	if !bjj {
+		riBytes := bigIntToEncodedBytes(round.temp.ri)
+		edwards25519.GeScalarMultBase(&R, riBytes)
+	}
+
+	// 2-6. compute R
+	i := round.PartyID().Index
+	bigRjs := make([]*crypto.ECPoint, len(round.Parties().IDs()))
+	bigRjs[i] = round.temp.pointRi
+	// the de-commitment of a pre-signing party also opens its second nonce Ej
+	coordCount := 2
+	if round.preEnd != nil {
+		coordCount = 4
+		round.temp.bigEjs = make([]*crypto.ECPoint, len(bigRjs))
+		round.temp.bigEjs[i] = round.temp.pointEi
+	}
+	for j, Pj := range round.Parties().IDs() {
+		if j == i {
+			continue
+		}
+
+		ContextJ := common.AppendBigIntToBytesSlice(round.temp.ssid, big.NewInt(int64(j)))
+		msg := round.temp.signRound2Messages[j]
+		r2msg := msg.Content().(*SignRound2Message)
+		cmtDeCmt := commitments.HashCommitDecommit{C: round.temp.cjs[j], D: r2msg.UnmarshalDeCommitment(), Hasher: round.HashScheme().Tagged(common.PoseidonTagEDDSASigningRi)}
+		ok, coordinates := cmtDeCmt.DeCommit()
+		if !ok {
+			return round.WrapError(errors.New("de-commitment verify failed")).WithCode(tss.CodeBadCommitment)
+		}
+		if len(coordinates) != coordCount {
+			return round.WrapError(errors.Errorf("length of de-commitment should be %d", coordCount)).WithCode(tss.CodeBadCommitment)
+		}
+
+		Rj, err := crypto.NewECPoint(round.Params().EC(), coordinates[0], coordinates[1])
+		Rj = Rj.EightInvEight()
+		if err != nil {
+			return round.WrapError(errors.Wrapf(err, "NewECPoint(Rj)"), Pj).WithCode(tss.CodeBadPublicData)
+		}
+		proof, err := r2msg.UnmarshalZKProof(round.Params().EC())
+		if err != nil {
+			return round.WrapError(errors.New("failed to unmarshal Rj proof"), Pj).WithCode(tss.CodeBadProof)
+		}
+		ok = proof.Verify(ContextJ, Rj)
+		if !ok {
+			return round.WrapError(errors.New("failed to prove Rj"), Pj).WithCode(tss.CodeBadProof)
+		}
+
+		if round.preEnd != nil {
+			Ej, err := crypto.NewECPoint(round.Params().EC(), coordinates[2], coordinates[3])
+			if err != nil {
+				return round.WrapError(errors.Wrapf(err, "NewECPoint(Ej)"), Pj).WithCode(tss.CodeBadPublicData)
+			}
+			round.temp.bigEjs[j] = Ej.EightInvEight()
+		}
+
+		bigRjs[j] = Rj
+		if !bjj {
+			extendedRj := ecPointToExtendedElement(round.Params().EC(), Rj.X(), Rj.Y(), round.Rand())
+			R = addExtendedElements(R, extendedRj)
+		}
+	}
+
+	if bjj {
+		R8, err := sumPoints(bigRjs)
+		if err != nil {
+			return round.WrapError(err)
+		}
+		round.temp.r = encodeBabyJubJubPoint(R8)
+	} else {
+		var encodedR [32]byte
+		R.ToBytes(&encodedR)
+		round.temp.r = encodedBytesToBigInt(&encodedR)
+	}
+	round.temp.bigRjs = bigRjs
+	return nil
+}
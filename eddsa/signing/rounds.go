@@ -28,6 +28,7 @@ type (
 		temp    *localTempData
 		out     chan<- tss.Message
 		end     chan<- *common.SignatureData
+		preEnd  chan<- *PreSignatureData
 		ok      []bool // `ok` tracks parties which have been verified by Update()
 		started bool
 		number  int
@@ -44,6 +45,9 @@ type (
 	finalization struct {
 		*round3
 	}
+	presignFinalization struct {
+		*round2
+	}
 )
 
 var (
@@ -51,6 +55,7 @@ var (
 	_ tss.Round = (*round2)(nil)
 	_ tss.Round = (*round3)(nil)
 	_ tss.Round = (*finalization)(nil)
+	_ tss.Round = (*presignFinalization)(nil)
 )
 
 // ----- //
@@ -108,12 +113,17 @@ func (round *base) getSSID() ([]byte, error) {
 	ssidList = append(ssidList, round.Parties().IDs().Keys()...)                                                         // parties
 	BigXjList, err := crypto.FlattenECPoints(round.key.BigXj)
 	if err != nil {
-		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID())
+		return nil, round.WrapError(errors.New("read BigXj failed"), round.PartyID()).WithCode(tss.CodeInternal)
 	}
 	ssidList = append(ssidList, BigXjList...)                    // BigXj
 	ssidList = append(ssidList, big.NewInt(int64(round.number))) // round number
 	ssidList = append(ssidList, round.temp.ssidNonce)
-	ssid := common.SHA512_256i(ssidList...).Bytes()
+	ssidList = append(ssidList, round.SessionIDInts()...)
+	ssidHash := round.HashScheme().Tagged(common.PoseidonTagEDDSASigningSSID).HashInts(ssidList...)
+	if ssidHash == nil {
+		return nil, round.WrapError(errors.New("hash ssid failed"), round.PartyID()).WithCode(tss.CodeInternal)
+	}
+	ssid := ssidHash.Bytes()
 
 	return ssid, nil
 }
@@ -8,13 +8,19 @@ require (
 	github.com/btcsuite/btcd/btcec/v2 v2.3.2
 	github.com/btcsuite/btcutil v1.0.2
 	github.com/decred/dcrd/dcrec/edwards/v2 v2.0.3
+	github.com/flynn/noise v1.0.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/hashicorp/go-multierror v1.1.1
 	github.com/iden3/go-iden3-crypto v0.0.17 // indirect
 	github.com/ipfs/go-log v1.0.5
+	github.com/miekg/pkcs11 v1.1.1
+	github.com/nats-io/nats.go v1.13.0
 	github.com/otiai10/primes v0.0.0-20210501021515-f1b2be525a11
 	github.com/pkg/errors v0.9.1
+	github.com/segmentio/kafka-go v0.4.38
 	github.com/stretchr/testify v1.8.4
 	golang.org/x/crypto v0.13.0
+	google.golang.org/grpc v1.47.0
 	google.golang.org/protobuf v1.31.0
 )
 
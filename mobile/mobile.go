@@ -0,0 +1,340 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package mobile wraps keygen and signing in an API of primitive types only, byte slices, strings, ints and
+// callbacks, so that iOS and Android wallets can embed tss-lib through gomobile:
+//
+//	gomobile bind -target=ios github.com/bnb-chain/tss-lib/v2/mobile
+//
+// The app carries the messages: the Listener it passes to a session is given every outgoing message with its
+// recipient, and the app hands the messages it receives to Session.Receive. A party is named by its key, the bytes of
+// the *big.Int key of its tss.PartyID. Shares are the JSON save data of keygen, for the app to keep in its keychain
+// or keystore.
+package mobile
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto/pubkey"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	ecdsasigning "github.com/bnb-chain/tss-lib/v2/ecdsa/signing"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	eddsasigning "github.com/bnb-chain/tss-lib/v2/eddsa/signing"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// the schemes of the keys
+const (
+	SchemeECDSA = "ecdsa"
+	SchemeEdDSA = "eddsa"
+	// EdDSA on BabyJubJub
+	SchemeBJJ = "bjj"
+)
+
+type (
+	// Listener is implemented by the app to receive what a session produces. Its methods are called from goroutines
+	// of the session and must not block for long.
+	Listener interface {
+		// OnOutgoing is called with each message of the party, for the app to deliver to the party with the key `to`,
+		// or to every other party of the session when `to` is empty
+		OnOutgoing(wireBytes []byte, to []byte, isBroadcast bool)
+		// OnShare is called once by a keygen session with the share of the party
+		OnShare(share []byte)
+		// OnSignature is called once by a signing session with the signature
+		OnSignature(signature *Signature)
+		// OnError is called with the errors that the session runs into outside of Start and Receive, which return
+		// theirs
+		OnError(message string)
+	}
+
+	// Signature is the signature of a signing session
+	Signature struct {
+		// the signature in the encoding of its scheme: R || S, 64 bytes, for ECDSA and EdDSA
+		Bytes []byte
+		R, S  []byte
+		// the recovery ID of an ECDSA signature, in its single byte
+		Recovery []byte
+	}
+
+	// Committee is the parties of a session
+	Committee struct {
+		ids tss.UnSortedPartyIDs
+	}
+
+	// Session is the party of this device in a session
+	Session struct {
+		party    tss.Party
+		params   *tss.Parameters
+		peers    map[string]*tss.PartyID
+		listener Listener
+		out      chan tss.Message
+		stop     chan struct{}
+		once     sync.Once
+	}
+)
+
+// NewCommittee returns an empty committee
+func NewCommittee() *Committee {
+	return &Committee{}
+}
+
+// Add adds a party with its ID, moniker and key, which must be unique in the committee
+func (c *Committee) Add(id, moniker string, key []byte) error {
+	k := new(big.Int).SetBytes(key)
+	if k.Sign() == 0 {
+		return errors.New("the key of a party must not be zero")
+	}
+	for _, pid := range c.ids {
+		if pid.KeyInt().Cmp(k) == 0 {
+			return fmt.Errorf("the key of %q is taken", id)
+		}
+	}
+	c.ids = append(c.ids, tss.NewPartyID(id, moniker, k))
+	return nil
+}
+
+// Size returns the number of parties of the committee
+func (c *Committee) Size() int {
+	return len(c.ids)
+}
+
+// sorted returns the parties sorted as the sessions index them, and this party among them
+func (c *Committee) sorted(self []byte) (tss.SortedPartyIDs, *tss.PartyID, error) {
+	ids := make(tss.UnSortedPartyIDs, len(c.ids))
+	for i, pid := range c.ids {
+		ids[i] = pid.Clone()
+	}
+	sorted := tss.SortPartyIDs(ids)
+	if pid := sorted.FindByKey(new(big.Int).SetBytes(self)); pid != nil {
+		return sorted, pid, nil
+	}
+	return nil, nil, errors.New("this party is not in the committee")
+}
+
+// NewKeygen returns the session of the party with the key `self` in a keygen of `scheme` with the committee. ECDSA
+// keygen first generates the safe primes of the party, which takes minutes on a phone.
+func NewKeygen(scheme string, committee *Committee, self []byte, threshold int, listener Listener) (*Session, error) {
+	ec, err := curveOf(scheme)
+	if err != nil {
+		return nil, err
+	}
+	ids, pid, err := committee.sorted(self)
+	if err != nil {
+		return nil, err
+	}
+	params := tss.NewParameters(ec, tss.NewPeerContext(ids), pid, len(ids), threshold)
+	s := newSession(params, ids, listener)
+	if scheme == SchemeECDSA {
+		end := make(chan *ecdsakeygen.LocalPartySaveData, 1)
+		s.party = ecdsakeygen.NewLocalParty(params, s.out, end)
+		go func() {
+			select {
+			case save := <-end:
+				s.share(save)
+			case <-s.stop:
+			}
+		}()
+	} else {
+		end := make(chan *eddsakeygen.LocalPartySaveData, 1)
+		s.party = eddsakeygen.NewLocalParty(params, s.out, end)
+		go func() {
+			select {
+			case save := <-end:
+				s.share(save)
+			case <-s.stop:
+			}
+		}()
+	}
+	return s, nil
+}
+
+// NewSigning returns the session of the party with the key `self` in the signing of `message` with its share by the
+// signers of `committee`, at least threshold+1 of the parties of the key. `message` is the digest for ECDSA, and the
+// message itself for EdDSA.
+func NewSigning(scheme string, committee *Committee, self []byte, threshold int, share []byte, message []byte, listener Listener) (*Session, error) {
+	ec, err := curveOf(scheme)
+	if err != nil {
+		return nil, err
+	}
+	ids, pid, err := committee.sorted(self)
+	if err != nil {
+		return nil, err
+	}
+	params := tss.NewParameters(ec, tss.NewPeerContext(ids), pid, len(ids), threshold)
+	s := newSession(params, ids, listener)
+	msg := new(big.Int).SetBytes(message)
+	end := make(chan *common.SignatureData, 1)
+	checkSigners := func(ks []*big.Int) error {
+		for _, pid := range ids {
+			found := false
+			for _, k := range ks {
+				found = found || (k != nil && k.Cmp(pid.KeyInt()) == 0)
+			}
+			if !found {
+				return fmt.Errorf("the share is not of a key of %q", pid.Id)
+			}
+		}
+		return nil
+	}
+	if scheme == SchemeECDSA {
+		var save ecdsakeygen.LocalPartySaveData
+		if err := json.Unmarshal(share, &save); err != nil {
+			return nil, fmt.Errorf("the share: %w", err)
+		}
+		if err := checkSigners(save.Ks); err != nil {
+			return nil, err
+		}
+		s.party = ecdsasigning.NewLocalParty(msg, params, ecdsakeygen.BuildLocalSaveDataSubset(save, ids), s.out, end, len(message))
+	} else {
+		var save eddsakeygen.LocalPartySaveData
+		if err := json.Unmarshal(share, &save); err != nil {
+			return nil, fmt.Errorf("the share: %w", err)
+		}
+		if err := checkSigners(save.Ks); err != nil {
+			return nil, err
+		}
+		s.party = eddsasigning.NewLocalParty(msg, params, eddsakeygen.BuildLocalSaveDataSubset(save, ids), s.out, end, len(message))
+	}
+	go func() {
+		select {
+		case sig := <-end:
+			listener.OnSignature(&Signature{Bytes: sig.Signature, R: sig.R, S: sig.S, Recovery: sig.SignatureRecovery})
+		case <-s.stop:
+		}
+	}()
+	return s, nil
+}
+
+func newSession(params *tss.Parameters, ids tss.SortedPartyIDs, listener Listener) *Session {
+	s := &Session{
+		params:   params,
+		peers:    make(map[string]*tss.PartyID, len(ids)),
+		listener: listener,
+		out:      make(chan tss.Message, len(ids)*4),
+		stop:     make(chan struct{}),
+	}
+	for _, pid := range ids {
+		s.peers[string(pid.KeyInt().Bytes())] = pid
+	}
+	return s
+}
+
+// SetSessionID sets the ID of the session, the same for every party, before it starts
+func (s *Session) SetSessionID(id []byte) {
+	s.params.SetSessionID(id)
+}
+
+// Start starts the session; its first messages go to the listener
+func (s *Session) Start() error {
+	go s.forward()
+	if err := s.party.Start(); err != nil {
+		s.Stop()
+		return err
+	}
+	return nil
+}
+
+// Receive hands the session a message from the party with the key `from`. It verifies the message, which may take
+// a while, so call it off the main thread.
+func (s *Session) Receive(wireBytes []byte, from []byte, isBroadcast bool) error {
+	pid, ok := s.peers[string(new(big.Int).SetBytes(from).Bytes())]
+	if !ok {
+		return errors.New("the sender is not a party of the session")
+	}
+	if _, err := s.party.UpdateFromBytes(wireBytes, pid, isBroadcast); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Stop stops forwarding the messages and the result of the session, e.g. when the app gives up on it
+func (s *Session) Stop() {
+	s.once.Do(func() { close(s.stop) })
+}
+
+func (s *Session) forward() {
+	for {
+		select {
+		case msg := <-s.out:
+			bz, _, err := msg.WireBytes()
+			if err != nil {
+				s.listener.OnError(err.Error())
+				continue
+			}
+			if msg.GetTo() == nil {
+				s.listener.OnOutgoing(bz, nil, msg.IsBroadcast())
+				continue
+			}
+			for _, to := range msg.GetTo() {
+				s.listener.OnOutgoing(bz, to.KeyInt().Bytes(), msg.IsBroadcast())
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Session) share(save interface{}) {
+	bz, err := json.Marshal(save)
+	if err != nil {
+		s.listener.OnError(err.Error())
+		return
+	}
+	s.listener.OnShare(bz)
+}
+
+// PublicKey returns the public key of a share: the uncompressed SEC 1 encoding for ECDSA, the 32-byte encoding of
+// RFC 8032 for EdDSA, and X || Y, 32 bytes each, for BabyJubJub
+func PublicKey(scheme string, share []byte) ([]byte, error) {
+	switch scheme {
+	case SchemeECDSA:
+		var save ecdsakeygen.LocalPartySaveData
+		if err := json.Unmarshal(share, &save); err != nil {
+			return nil, err
+		}
+		if save.ECDSAPub == nil {
+			return nil, errors.New("the share has no public key")
+		}
+		return elliptic.Marshal(tss.S256(), save.ECDSAPub.X(), save.ECDSAPub.Y()), nil
+	case SchemeEdDSA, SchemeBJJ:
+		var save eddsakeygen.LocalPartySaveData
+		if err := json.Unmarshal(share, &save); err != nil {
+			return nil, err
+		}
+		if save.EDDSAPub == nil {
+			return nil, errors.New("the share has no public key")
+		}
+		if scheme == SchemeBJJ {
+			out := make([]byte, 64)
+			save.EDDSAPub.X().FillBytes(out[:32])
+			save.EDDSAPub.Y().FillBytes(out[32:])
+			return out, nil
+		}
+		return pubkey.Ed25519Bytes(save.EDDSAPub), nil
+	default:
+		_, err := curveOf(scheme)
+		return nil, err
+	}
+}
+
+func curveOf(scheme string) (elliptic.Curve, error) {
+	switch scheme {
+	case SchemeECDSA:
+		return tss.S256(), nil
+	case SchemeEdDSA:
+		return tss.Edwards(), nil
+	case SchemeBJJ:
+		return tss.BabyJubJub(), nil
+	default:
+		return nil, fmt.Errorf("unknown scheme %q; use ecdsa, eddsa or bjj", scheme)
+	}
+}
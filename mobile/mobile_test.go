@@ -0,0 +1,125 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package mobile_test
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/mobile"
+)
+
+// device stands in for an app: it routes the messages of its session to the sessions of the other devices
+type device struct {
+	key       []byte
+	session   *Session
+	devices   []*device
+	mu        sync.Mutex
+	share     []byte
+	signature *Signature
+	errs      []string
+	done      chan struct{}
+}
+
+func (d *device) OnOutgoing(wireBytes []byte, to []byte, isBroadcast bool) {
+	for _, other := range d.devices {
+		if other == d || (len(to) > 0 && string(other.key) != string(to)) {
+			continue
+		}
+		go func(other *device) {
+			if err := other.session.Receive(wireBytes, d.key, isBroadcast); err != nil {
+				other.OnError(err.Error())
+			}
+		}(other)
+	}
+}
+
+func (d *device) OnShare(share []byte) {
+	d.mu.Lock()
+	d.share = share
+	d.mu.Unlock()
+	close(d.done)
+}
+
+func (d *device) OnSignature(signature *Signature) {
+	d.mu.Lock()
+	d.signature = signature
+	d.mu.Unlock()
+	close(d.done)
+}
+
+func (d *device) OnError(message string) {
+	d.mu.Lock()
+	d.errs = append(d.errs, message)
+	d.mu.Unlock()
+}
+
+func run(t *testing.T, devices []*device) {
+	for _, d := range devices {
+		assert.NoError(t, d.session.Start())
+	}
+	for _, d := range devices {
+		select {
+		case <-d.done:
+		case <-time.After(time.Minute):
+			t.Fatal("the session timed out")
+		}
+		assert.Empty(t, d.errs)
+	}
+}
+
+func TestKeygenAndSigning(t *testing.T) {
+	const parties, threshold = 3, 1
+	committee := NewCommittee()
+	devices := make([]*device, parties)
+	for i := range devices {
+		devices[i] = &device{key: []byte{byte(i + 1)}, devices: devices, done: make(chan struct{})}
+		assert.NoError(t, committee.Add(fmt.Sprintf("phone-%d", i+1), "", devices[i].key))
+	}
+	assert.Error(t, committee.Add("again", "", devices[0].key), "the key is taken")
+	assert.Equal(t, parties, committee.Size())
+
+	for _, d := range devices {
+		var err error
+		d.session, err = NewKeygen(SchemeEdDSA, committee, d.key, threshold, d)
+		assert.NoError(t, err)
+	}
+	run(t, devices)
+	pk, err := PublicKey(SchemeEdDSA, devices[0].share)
+	assert.NoError(t, err)
+	for _, d := range devices[1:] {
+		other, err := PublicKey(SchemeEdDSA, d.share)
+		assert.NoError(t, err)
+		assert.Equal(t, pk, other, "the parties agree on the public key")
+	}
+
+	// two of the three sign
+	signers := NewCommittee()
+	signing := devices[:threshold+1]
+	for i, d := range signing {
+		assert.NoError(t, signers.Add(fmt.Sprintf("phone-%d", i+1), "", d.key))
+	}
+	message := []byte("hello from a phone")
+	for _, d := range signing {
+		d.devices, d.done = signing, make(chan struct{})
+		var err error
+		d.session, err = NewSigning(SchemeEdDSA, signers, d.key, threshold, d.share, message, d)
+		assert.NoError(t, err)
+	}
+	run(t, signing)
+	assert.True(t, ed25519.Verify(pk, message, signing[0].signature.Bytes), "the signature verifies with the public key")
+
+	_, err = NewSigning(SchemeEdDSA, signers, []byte{9}, threshold, devices[0].share, message, devices[0])
+	assert.Error(t, err, "the party is not in the committee")
+	_, err = NewKeygen("rsa", committee, devices[0].key, threshold, devices[0])
+	assert.Error(t, err)
+}
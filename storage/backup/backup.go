@@ -0,0 +1,211 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package backup splits the save data of a party into fragments for recovery custodians, any `threshold` of which
+// reassemble it while fewer reveal nothing of it. It is Shamir's secret sharing over GF(2^8), byte by byte, of the
+// serialized save data, independent of the shares of the key itself: a party backs up its own share, and the
+// custodians of one party need not know of the others.
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// Version is the version of the fragments that Split makes
+	Version = 1
+
+	backupIDSize = 16
+	maxFragments = 255
+)
+
+// Fragment is the part of a backup given to one custodian
+type Fragment struct {
+	Version int `json:"version"`
+	// the ID of the backup, the same in all of its fragments, so that fragments of different backups are not mixed
+	BackupID []byte `json:"backup_id"`
+	// the number of fragments that reassemble the backup, and the number made
+	Threshold int `json:"threshold"`
+	Total     int `json:"total"`
+	// the point of the fragment, in [1, Total]
+	Index int    `json:"index"`
+	Data  []byte `json:"data"`
+}
+
+// Split encodes `data`, e.g. a *keygen.LocalPartySaveData of ecdsa or eddsa, and splits it into `total` fragments,
+// any `threshold` of which reassemble it
+func Split(data interface{}, threshold, total int) ([]*Fragment, error) {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(bz)
+	return SplitBytes(bz, threshold, total)
+}
+
+// SplitBytes splits `secret` into `total` fragments, any `threshold` of which reassemble it
+func SplitBytes(secret []byte, threshold, total int) ([]*Fragment, error) {
+	if threshold < 2 || threshold > total {
+		return nil, fmt.Errorf("backup: the threshold %d must be in [2, %d]", threshold, total)
+	}
+	if total > maxFragments {
+		return nil, fmt.Errorf("backup: at most %d fragments can be made", maxFragments)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("backup: nothing to split")
+	}
+	backupID := make([]byte, backupIDSize)
+	if _, err := rand.Read(backupID); err != nil {
+		return nil, err
+	}
+	// the checksum is shared along with the secret, so that a wrong reassembly is detected without the fragments
+	// revealing anything of the secret
+	sum := sha256.Sum256(secret)
+	payload := append(append(make([]byte, 0, len(secret)+len(sum)), secret...), sum[:]...)
+	defer zero(payload)
+
+	fragments := make([]*Fragment, total)
+	for i := range fragments {
+		fragments[i] = &Fragment{
+			Version:   Version,
+			BackupID:  backupID,
+			Threshold: threshold,
+			Total:     total,
+			Index:     i + 1,
+			Data:      make([]byte, len(payload)),
+		}
+	}
+	coefficients := make([]byte, threshold)
+	defer zero(coefficients)
+	for j, b := range payload {
+		// a random polynomial of degree threshold-1 with the byte as its constant term
+		coefficients[0] = b
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+		for _, f := range fragments {
+			f.Data[j] = evaluate(coefficients, byte(f.Index))
+		}
+	}
+	return fragments, nil
+}
+
+// Combine reassembles the save data from `threshold` fragments of its backup and decodes it into `data`
+func Combine(fragments []*Fragment, data interface{}) error {
+	bz, err := CombineBytes(fragments)
+	if err != nil {
+		return err
+	}
+	defer zero(bz)
+	return json.Unmarshal(bz, data)
+}
+
+// CombineBytes reassembles a secret split by SplitBytes from `threshold` of its fragments; extra fragments are not
+// used
+func CombineBytes(fragments []*Fragment) ([]byte, error) {
+	if len(fragments) == 0 {
+		return nil, errors.New("backup: no fragments")
+	}
+	first := fragments[0]
+	if first.Version != Version {
+		return nil, fmt.Errorf("backup: unknown fragment version %d", first.Version)
+	}
+	if first.Threshold < 2 || len(first.Data) <= sha256.Size {
+		return nil, errors.New("backup: the fragment is malformed")
+	}
+	if len(fragments) < first.Threshold {
+		return nil, fmt.Errorf("backup: %d fragments are needed, %d were given", first.Threshold, len(fragments))
+	}
+	used := fragments[:first.Threshold]
+	xs := make([]byte, len(used))
+	for i, f := range used {
+		switch {
+		case f.Version != first.Version || !bytes.Equal(f.BackupID, first.BackupID) ||
+			f.Threshold != first.Threshold || f.Total != first.Total:
+			return nil, errors.New("backup: the fragments are of different backups")
+		case len(f.Data) != len(first.Data):
+			return nil, errors.New("backup: the fragments differ in length")
+		case f.Index < 1 || f.Index > f.Total:
+			return nil, fmt.Errorf("backup: the fragment index %d is out of [1, %d]", f.Index, f.Total)
+		}
+		for _, x := range xs[:i] {
+			if x == byte(f.Index) {
+				return nil, fmt.Errorf("backup: the fragment %d is given twice", f.Index)
+			}
+		}
+		xs[i] = byte(f.Index)
+	}
+
+	// the Lagrange basis at 0 of the points of the fragments
+	basis := make([]byte, len(xs))
+	for i, xi := range xs {
+		num, den := byte(1), byte(1)
+		for j, xj := range xs {
+			if i != j {
+				num = mul(num, xj)
+				den = mul(den, xi^xj)
+			}
+		}
+		basis[i] = mul(num, inverse(den))
+	}
+	payload := make([]byte, len(first.Data))
+	for j := range payload {
+		var b byte
+		for i, f := range used {
+			b ^= mul(basis[i], f.Data[j])
+		}
+		payload[j] = b
+	}
+	secret, sum := payload[:len(payload)-sha256.Size], payload[len(payload)-sha256.Size:]
+	if expected := sha256.Sum256(secret); !bytes.Equal(sum, expected[:]) {
+		zero(payload)
+		return nil, errors.New("backup: the fragments do not reassemble the backup; one was altered")
+	}
+	return secret, nil
+}
+
+// ----- //
+
+// evaluate evaluates the polynomial of `coefficients`, the constant term first, at `x`
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+	return y
+}
+
+// mul multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x + 1, in constant time
+func mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= -(b & 1) & a
+		// reduce when the top bit is shifted out
+		a = (a << 1) ^ (-(a >> 7) & 0x1b)
+		b >>= 1
+	}
+	return p
+}
+
+// inverse returns a^254, the inverse of a non-zero `a` in GF(2^8)
+func inverse(a byte) byte {
+	r := a
+	for i := 0; i < 6; i++ {
+		r = mul(mul(r, r), a)
+	}
+	return mul(r, r)
+}
+
+func zero(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
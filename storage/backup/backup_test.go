@@ -0,0 +1,65 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package backup_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/storage/backup"
+)
+
+func TestSplitCombine(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	fragments, err := Split(&keys[0], 3, 5)
+	assert.NoError(t, err)
+	assert.Len(t, fragments, 5)
+
+	// any 3 of the 5 fragments, in any order
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {3, 4, 0, 1}} {
+		given := make([]*Fragment, len(subset))
+		for i, j := range subset {
+			given[i] = fragments[j]
+		}
+		var restored keygen.LocalPartySaveData
+		assert.NoError(t, Combine(given, &restored))
+		assert.Equal(t, keys[0].Xi, restored.Xi)
+		assert.True(t, keys[0].EDDSAPub.Equals(restored.EDDSAPub))
+	}
+
+	var restored keygen.LocalPartySaveData
+	assert.Error(t, Combine(fragments[:2], &restored), "2 fragments are too few")
+	assert.Error(t, Combine([]*Fragment{fragments[0], fragments[0], fragments[1]}, &restored))
+}
+
+func TestCombineDetectsAlteredAndMixedFragments(t *testing.T) {
+	secret := []byte("the save data of a party")
+	fragments, err := SplitBytes(secret, 2, 3)
+	assert.NoError(t, err)
+	restored, err := CombineBytes(fragments[1:])
+	assert.NoError(t, err)
+	assert.Equal(t, secret, restored)
+
+	altered := *fragments[0]
+	altered.Data = append([]byte(nil), altered.Data...)
+	altered.Data[0] ^= 1
+	_, err = CombineBytes([]*Fragment{&altered, fragments[1]})
+	assert.Error(t, err)
+
+	other, err := SplitBytes(secret, 2, 3)
+	assert.NoError(t, err)
+	_, err = CombineBytes([]*Fragment{fragments[0], other[1]})
+	assert.EqualError(t, err, "backup: the fragments are of different backups")
+
+	_, err = SplitBytes(secret, 1, 3)
+	assert.Error(t, err, "a single fragment would hold the secret")
+	_, err = SplitBytes(secret, 4, 3)
+	assert.Error(t, err)
+}
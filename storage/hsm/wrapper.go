@@ -0,0 +1,165 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package hsm wraps the share of a party with an AES key held in an HSM, through PKCS#11. It implements
+// tss.ShareWrapper: the share is encrypted with AES-GCM inside the HSM, and signing decrypts it only for as long as
+// it takes to derive its part of the signing key. It needs cgo and the PKCS#11 module of the HSM, e.g. SoftHSM's
+// libsofthsm2.so for testing.
+package hsm
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	gcmNonceSize = 12
+	gcmTagBits   = 128
+)
+
+// additional data of the encryption, so that a blob wrapped for another purpose with the same key is refused
+var wrapAAD = []byte("tss-lib share")
+
+// Wrapper wraps shares with an AES key of a token of an HSM. Its session is shared by its calls, which it serialises.
+type Wrapper struct {
+	mu      sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+}
+
+var _ tss.ShareWrapper = (*Wrapper)(nil)
+
+// Open loads the PKCS#11 module at `modulePath`, logs into the token labelled `tokenLabel` with the user PIN, and
+// finds the AES key labelled `keyLabel`. The key should be generated in the HSM as non-extractable, with CKA_ENCRYPT
+// and CKA_DECRYPT. Close the wrapper when done.
+func Open(modulePath, tokenLabel, pin, keyLabel string) (*Wrapper, error) {
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("hsm: cannot load the PKCS#11 module %s", modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("hsm: initialize: %w", err)
+	}
+	w := &Wrapper{ctx: ctx}
+	if err := w.open(tokenLabel, pin, keyLabel); err != nil {
+		w.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Wrapper) open(tokenLabel, pin, keyLabel string) error {
+	slots, err := w.ctx.GetSlotList(true)
+	if err != nil {
+		return fmt.Errorf("hsm: list the slots: %w", err)
+	}
+	slot, found := uint(0), false
+	for _, s := range slots {
+		info, err := w.ctx.GetTokenInfo(s)
+		if err == nil && info.Label == tokenLabel {
+			slot, found = s, true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("hsm: no token labelled %q", tokenLabel)
+	}
+	if w.session, err = w.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION); err != nil {
+		return fmt.Errorf("hsm: open a session: %w", err)
+	}
+	if err := w.ctx.Login(w.session, pkcs11.CKU_USER, pin); err != nil {
+		return fmt.Errorf("hsm: log in: %w", err)
+	}
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, keyLabel),
+	}
+	if err := w.ctx.FindObjectsInit(w.session, template); err != nil {
+		return fmt.Errorf("hsm: find the key: %w", err)
+	}
+	keys, _, err := w.ctx.FindObjects(w.session, 2)
+	_ = w.ctx.FindObjectsFinal(w.session)
+	if err != nil {
+		return fmt.Errorf("hsm: find the key: %w", err)
+	}
+	if len(keys) != 1 {
+		return fmt.Errorf("hsm: %d AES keys are labelled %q; there must be one", len(keys), keyLabel)
+	}
+	w.key = keys[0]
+	return nil
+}
+
+// WrapShare encrypts `xi` in the HSM; the result is the nonce followed by the ciphertext and its tag
+func (w *Wrapper) WrapShare(xi *big.Int) ([]byte, error) {
+	nonce := make([]byte, gcmNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	plaintext := xi.Bytes()
+	defer zero(plaintext)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	params := pkcs11.NewGCMParams(nonce, wrapAAD, gcmTagBits)
+	defer params.Free()
+	if err := w.ctx.EncryptInit(w.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, w.key); err != nil {
+		return nil, fmt.Errorf("hsm: encrypt: %w", err)
+	}
+	ciphertext, err := w.ctx.Encrypt(w.session, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("hsm: encrypt: %w", err)
+	}
+	return append(nonce, ciphertext...), nil
+}
+
+// UnwrapShare decrypts a share wrapped by WrapShare in the HSM
+func (w *Wrapper) UnwrapShare(wrapped []byte) (*big.Int, error) {
+	if len(wrapped) <= gcmNonceSize {
+		return nil, errors.New("hsm: the wrapped share is too short")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	params := pkcs11.NewGCMParams(wrapped[:gcmNonceSize], wrapAAD, gcmTagBits)
+	defer params.Free()
+	if err := w.ctx.DecryptInit(w.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, w.key); err != nil {
+		return nil, fmt.Errorf("hsm: decrypt: %w", err)
+	}
+	plaintext, err := w.ctx.Decrypt(w.session, wrapped[gcmNonceSize:])
+	if err != nil {
+		return nil, fmt.Errorf("hsm: decrypt: %w", err)
+	}
+	defer zero(plaintext)
+	return new(big.Int).SetBytes(plaintext), nil
+}
+
+// Close logs out of the token and unloads the module
+func (w *Wrapper) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.session != 0 {
+		_ = w.ctx.Logout(w.session)
+		_ = w.ctx.CloseSession(w.session)
+		w.session = 0
+	}
+	_ = w.ctx.Finalize()
+	w.ctx.Destroy()
+}
+
+func zero(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
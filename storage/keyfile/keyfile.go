@@ -0,0 +1,271 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package keyfile is the encrypted file format for the save data of a party, to use in production instead of the
+// plaintext JSON of the test fixtures. The JSON of the save data is encrypted with AES-256-GCM under a key derived
+// from a passphrase with Argon2id.
+//
+// A file is a header followed by the ciphertext and its tag:
+//
+//	magic   "TSSKEY"      6 bytes
+//	version 1             1 byte
+//	time    Argon2 passes 4 bytes, big-endian
+//	memory  in KiB        4 bytes, big-endian
+//	threads               1 byte
+//	salt                  16 bytes
+//	nonce                 12 bytes
+//
+// The header is authenticated as the additional data of the encryption, so that its parameters cannot be lowered
+// without the file failing to decrypt.
+package keyfile
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/bnb-chain/tss-lib/v2/storage"
+)
+
+const (
+	// Version is the version of the format that Encrypt writes
+	Version = 1
+
+	saltSize   = 16
+	nonceSize  = 12
+	keySize    = 32
+	headerSize = len(magic) + 1 + 4 + 4 + 1 + saltSize + nonceSize
+
+	magic = "TSSKEY"
+
+	// the bounds of the parameters read from a file, so that a crafted header cannot exhaust the host
+	maxTime   = 64
+	maxMemory = 4 * 1024 * 1024
+)
+
+// ErrWrongPassphrase is returned when a file does not decrypt, because the passphrase is wrong or the file was altered
+var ErrWrongPassphrase = errors.New("keyfile: wrong passphrase, or the file was altered")
+
+type (
+	// Params are the Argon2id parameters of the key derivation
+	Params struct {
+		Time uint32
+		// the memory in KiB
+		Memory  uint32
+		Threads uint8
+	}
+
+	// Store is a storage.Store that encrypts the data it puts in another store with a passphrase
+	Store struct {
+		backend    storage.Store
+		passphrase []byte
+		params     Params
+	}
+)
+
+// DefaultParams are the parameters recommended by RFC 9106 for memory-constrained environments: 3 passes over 64 MiB
+var DefaultParams = Params{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+var _ storage.Store = (*Store)(nil)
+
+// Encrypt encrypts `data` with a key derived from `passphrase` with `params`
+func Encrypt(data, passphrase []byte, params Params) ([]byte, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = append(header, Version)
+	header = appendUint32(header, params.Time)
+	header = appendUint32(header, params.Memory)
+	header = append(header, params.Threads)
+	salt, nonce := make([]byte, saltSize), make([]byte, nonceSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header = append(append(header, salt...), nonce...)
+
+	aead, err := newAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, data, header), nil
+}
+
+// Decrypt decrypts a file made by Encrypt
+func Decrypt(file, passphrase []byte) ([]byte, error) {
+	if !IsEncrypted(file) {
+		return nil, errors.New("keyfile: not an encrypted key file")
+	}
+	if len(file) < headerSize {
+		return nil, errors.New("keyfile: the file is truncated")
+	}
+	if v := file[len(magic)]; v != Version {
+		return nil, fmt.Errorf("keyfile: unknown version %d", v)
+	}
+	header, rest := file[:headerSize], file[len(magic)+1:]
+	params := Params{
+		Time:    binary.BigEndian.Uint32(rest[0:4]),
+		Memory:  binary.BigEndian.Uint32(rest[4:8]),
+		Threads: rest[8],
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	salt := rest[9 : 9+saltSize]
+	nonce := rest[9+saltSize : 9+saltSize+nonceSize]
+
+	aead, err := newAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	data, err := aead.Open(nil, nonce, file[headerSize:], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return data, nil
+}
+
+// IsEncrypted returns whether `file` starts as an encrypted key file does, e.g. to tell it from a plaintext fixture
+func IsEncrypted(file []byte) bool {
+	return bytes.HasPrefix(file, []byte(magic))
+}
+
+// Save encodes `data`, e.g. a *keygen.LocalPartySaveData of ecdsa or eddsa, and writes it encrypted with
+// `passphrase` and DefaultParams to `path`, only readable by its owner
+func Save(path string, passphrase []byte, data interface{}) error {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	defer zero(bz)
+	file, err := Encrypt(bz, passphrase, DefaultParams)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, file)
+}
+
+// Load reads the encrypted file at `path` and decodes it into `data`
+func Load(path string, passphrase []byte, data interface{}) error {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	bz, err := Decrypt(file, passphrase)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	defer zero(bz)
+	return json.Unmarshal(bz, data)
+}
+
+// ----- //
+
+// NewStore returns a store that encrypts the data it puts in `backend` with `passphrase` and DefaultParams
+func NewStore(backend storage.Store, passphrase []byte) *Store {
+	return &Store{backend: backend, passphrase: passphrase, params: DefaultParams}
+}
+
+// SetParams sets the parameters of the key derivation of the data put from now on
+func (s *Store) SetParams(params Params) {
+	s.params = params
+}
+
+func (s *Store) Put(ctx context.Context, id string, data []byte) error {
+	file, err := Encrypt(data, s.passphrase, s.params)
+	if err != nil {
+		return err
+	}
+	return s.backend.Put(ctx, id, file)
+}
+
+func (s *Store) Get(ctx context.Context, id string) ([]byte, error) {
+	file, err := s.backend.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := Decrypt(file, s.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+	return data, nil
+}
+
+func (s *Store) Delete(ctx context.Context, id string) error {
+	return s.backend.Delete(ctx, id)
+}
+
+// ----- //
+
+func (p Params) validate() error {
+	if p.Threads == 0 {
+		return errors.New("keyfile: the Argon2 threads must be positive")
+	}
+	if p.Time == 0 || p.Time > maxTime {
+		return fmt.Errorf("keyfile: the Argon2 time %d is out of [1, %d]", p.Time, maxTime)
+	}
+	if p.Memory < 8*uint32(p.Threads) || p.Memory > maxMemory {
+		return fmt.Errorf("keyfile: the Argon2 memory %d KiB is out of [%d, %d]", p.Memory, 8*uint32(p.Threads), maxMemory)
+	}
+	return nil
+}
+
+func newAEAD(passphrase, salt []byte, params Params) (cipher.AEAD, error) {
+	key := argon2.IDKey(passphrase, salt, params.Time, params.Memory, params.Threads, keySize)
+	defer zero(key)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeFile writes aside and renames, so that a crash never leaves a truncated share behind
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func appendUint32(bz []byte, v uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	return append(bz, b[:]...)
+}
+
+func zero(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
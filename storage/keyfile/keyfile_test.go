@@ -0,0 +1,94 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package keyfile_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/storage"
+	. "github.com/bnb-chain/tss-lib/v2/storage/keyfile"
+)
+
+// cheap parameters, to keep the tests fast
+var testParams = Params{Time: 1, Memory: 64, Threads: 1}
+
+func TestSaveLoad(t *testing.T) {
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "alice.key")
+	assert.NoError(t, Save(path, []byte("correct horse"), &keys[0]))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "only the owner may read a share")
+	file, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(file))
+	assert.NotContains(t, string(file), keys[0].Xi.String())
+
+	var loaded keygen.LocalPartySaveData
+	assert.NoError(t, Load(path, []byte("correct horse"), &loaded))
+	assert.Equal(t, keys[0].Xi, loaded.Xi)
+	assert.True(t, keys[0].EDDSAPub.Equals(loaded.EDDSAPub))
+
+	err = Load(path, []byte("wrong horse"), &loaded)
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+}
+
+func TestHeaderIsAuthenticated(t *testing.T) {
+	file, err := Encrypt([]byte("share"), []byte("pass"), testParams)
+	assert.NoError(t, err)
+	data, err := Decrypt(file, []byte("pass"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("share"), data)
+
+	// raise the Argon2 time in the header: the key changes, and the header is the additional data anyway
+	altered := append([]byte(nil), file...)
+	altered[10]++
+	_, err = Decrypt(altered, []byte("pass"))
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+
+	altered = append([]byte(nil), file...)
+	altered[6] = 2
+	_, err = Decrypt(altered, []byte("pass"))
+	assert.EqualError(t, err, "keyfile: unknown version 2")
+
+	_, err = Decrypt(file[:20], []byte("pass"))
+	assert.Error(t, err)
+	_, err = Decrypt([]byte(`{"Xi":1}`), []byte("pass"))
+	assert.Error(t, err, "a plaintext share is not a key file")
+
+	_, err = Encrypt([]byte("share"), []byte("pass"), Params{Time: 1, Memory: 1 << 30, Threads: 1})
+	assert.Error(t, err)
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	files, err := storage.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	store := NewStore(files, []byte("pass"))
+	store.SetParams(testParams)
+
+	assert.NoError(t, store.Put(ctx, "key-1", []byte("share")))
+	file, err := files.Get(ctx, "key-1")
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(file))
+	data, err := store.Get(ctx, "key-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("share"), data)
+
+	_, err = NewStore(files, []byte("other")).Get(ctx, "key-1")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+	_, err = store.Get(ctx, "key-2")
+	assert.Equal(t, storage.ErrNotFound, err)
+}
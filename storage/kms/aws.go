@@ -0,0 +1,178 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package kms
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+
+	awsService = "kms"
+)
+
+type (
+	// AWSCredentials are the credentials the requests to AWS KMS are signed with
+	AWSCredentials struct {
+		AccessKeyID, SecretAccessKey string
+		// the token of temporary credentials, e.g. those of an IAM role
+		SessionToken string
+	}
+
+	// AWSWrapper wraps data keys with a symmetric key of AWS KMS, through the Encrypt and Decrypt actions of its API
+	AWSWrapper struct {
+		region   string
+		keyID    string
+		creds    AWSCredentials
+		endpoint string
+		http     *http.Client
+	}
+)
+
+var _ Wrapper = (*AWSWrapper)(nil)
+
+// NewAWSWrapper returns a wrapper with the key `keyID`, an ID, ARN or alias ARN, in `region`. The credentials must
+// allow kms:Encrypt and kms:Decrypt on the key, and on the former keys of shares that are still to be rewrapped.
+func NewAWSWrapper(region, keyID string, creds AWSCredentials) *AWSWrapper {
+	return &AWSWrapper{
+		region:   region,
+		keyID:    keyID,
+		creds:    creds,
+		endpoint: "https://kms." + region + ".amazonaws.com/",
+		http:     &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// SetEndpoint sets the URL of the API, e.g. that of a VPC endpoint
+func (w *AWSWrapper) SetEndpoint(endpoint string) {
+	w.endpoint = endpoint
+}
+
+// SetHTTPClient sets the client of the requests
+func (w *AWSWrapper) SetHTTPClient(hc *http.Client) {
+	w.http = hc
+}
+
+func (w *AWSWrapper) KeyID() string {
+	return w.keyID
+}
+
+func (w *AWSWrapper) Wrap(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var out struct {
+		CiphertextBlob []byte
+	}
+	in := map[string]interface{}{"KeyId": w.keyID, "Plaintext": dataKey}
+	if err := w.call(ctx, "Encrypt", in, &out); err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (w *AWSWrapper) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var out struct {
+		Plaintext []byte
+	}
+	// the ciphertext names its key; passing the key as well makes KMS refuse a ciphertext of any other key
+	in := map[string]interface{}{"KeyId": keyID, "CiphertextBlob": wrapped}
+	if err := w.call(ctx, "Decrypt", in, &out); err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// call calls an action of the API with a request signed with Signature Version 4
+func (w *AWSWrapper) call(ctx context.Context, action string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	w.sign(req, body, time.Now().UTC())
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		bz, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = json.Unmarshal(bz, &e)
+		return fmt.Errorf("aws kms: %s: %s: %s %s", action, resp.Status, e.Type, e.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (w *AWSWrapper) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate, date := now.Format("20060102T150405Z"), now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if w.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", w.creds.SessionToken)
+	}
+	// the signed headers, sorted
+	headers := []struct{ name, value string }{
+		{"content-type", req.Header.Get("Content-Type")},
+		{"host", req.URL.Host},
+		{"x-amz-date", amzDate},
+	}
+	if w.creds.SessionToken != "" {
+		headers = append(headers, struct{ name, value string }{"x-amz-security-token", w.creds.SessionToken})
+	}
+	headers = append(headers, struct{ name, value string }{"x-amz-target", req.Header.Get("X-Amz-Target")})
+	var canonicalHeaders, signedHeaders string
+	for i, h := range headers {
+		canonicalHeaders += h.name + ":" + h.value + "\n"
+		if i > 0 {
+			signedHeaders += ";"
+		}
+		signedHeaders += h.name
+	}
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := req.Method + "\n" + path + "\n" + req.URL.Query().Encode() + "\n" +
+		canonicalHeaders + "\n" + signedHeaders + "\n" + hashHex(body)
+	scope := date + "/" + w.region + "/" + awsService + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+w.creds.SecretAccessKey), date)
+	key = hmacSHA256(key, w.region)
+	key = hmacSHA256(key, awsService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+w.creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hashHex(bz []byte) string {
+	sum := sha256.Sum256(bz)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testCreds = AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+func TestAWSSignature(t *testing.T) {
+	w := NewAWSWrapper("eu-west-1", "alias/tss", testCreds)
+	body := []byte(`{"KeyId":"alias/tss","Plaintext":"AAEC"}`)
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Encrypt")
+	w.sign(req, body, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	assert.Equal(t, "20240102T030405Z", req.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240102/eu-west-1/kms/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date;x-amz-target, "+
+		"Signature=a37962674e33b9a225716efaa449b3cca59d734981d40c31d2d262579c3d0a03", req.Header.Get("Authorization"))
+}
+
+func TestAWSWrapper(t *testing.T) {
+	// the fake KMS "encrypts" by prefixing the key ID
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"__type":"MissingAuthenticationTokenException","message":"no signature"}`))
+			return
+		}
+		var in struct {
+			KeyId                     string
+			Plaintext, CiphertextBlob []byte
+		}
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(rw).Encode(map[string]interface{}{"CiphertextBlob": append([]byte(in.KeyId+":"), in.Plaintext...), "KeyId": in.KeyId})
+		case "TrentService.Decrypt":
+			if !bytes.HasPrefix(in.CiphertextBlob, []byte(in.KeyId+":")) {
+				rw.WriteHeader(http.StatusBadRequest)
+				_, _ = rw.Write([]byte(`{"__type":"IncorrectKeyException","message":"wrong key"}`))
+				return
+			}
+			_ = json.NewEncoder(rw).Encode(map[string]interface{}{"Plaintext": bytes.TrimPrefix(in.CiphertextBlob, []byte(in.KeyId+":"))})
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	w := NewAWSWrapper("eu-west-1", "alias/tss", testCreds)
+	w.SetEndpoint(server.URL + "/")
+	wrapped, err := w.Wrap(ctx, []byte{1, 2, 3})
+	assert.NoError(t, err)
+	dataKey, err := w.Unwrap(ctx, "alias/tss", wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, dataKey)
+
+	_, err = w.Unwrap(ctx, "alias/other", wrapped)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "IncorrectKeyException")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package kms encrypts save data at rest with envelope encryption: every share is encrypted with AES-256-GCM under a
+// fresh data key, and the data key is encrypted, or wrapped, by a master key held in a cloud KMS, AWS KMS or Google
+// Cloud KMS, that never leaves it. The encrypted share and its wrapped data key are kept together in another
+// storage.Store, e.g. a storage.FileStore.
+//
+// To rotate the master key, point the Wrapper at the new key: new shares are wrapped with it, and Rewrap moves the
+// existing ones over by rewrapping their data keys, without decrypting the shares outside of memory.
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bnb-chain/tss-lib/v2/storage"
+)
+
+const (
+	envelopeVersion = 1
+	dataKeySize     = 32
+)
+
+type (
+	// Wrapper encrypts data keys with a master key in a KMS
+	Wrapper interface {
+		// KeyID returns the ID of the master key that Wrap encrypts with
+		KeyID() string
+		Wrap(ctx context.Context, dataKey []byte) ([]byte, error)
+		// Unwrap decrypts a data key wrapped by the master key `keyID`, which may be a former key of the wrapper
+		Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+	}
+
+	// Store is a storage.Store that encrypts the data it puts in another store
+	Store struct {
+		backend storage.Store
+		wrapper Wrapper
+	}
+
+	// envelope is what the backend holds for a key
+	envelope struct {
+		Version    int    `json:"version"`
+		KeyID      string `json:"key_id"`
+		WrappedKey []byte `json:"wrapped_key"`
+		Nonce      []byte `json:"nonce"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+)
+
+var _ storage.Store = (*Store)(nil)
+
+// NewStore returns a store that encrypts the data it puts in `backend` with data keys wrapped by `wrapper`
+func NewStore(backend storage.Store, wrapper Wrapper) *Store {
+	return &Store{backend: backend, wrapper: wrapper}
+}
+
+func (s *Store) Put(ctx context.Context, id string, data []byte) error {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return err
+	}
+	defer zero(dataKey)
+	wrapped, err := s.wrapper.Wrap(ctx, dataKey)
+	if err != nil {
+		return fmt.Errorf("kms: wrap the data key: %w", err)
+	}
+	env := &envelope{Version: envelopeVersion, KeyID: s.wrapper.KeyID(), WrappedKey: wrapped}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+	env.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return err
+	}
+	// the ID is authenticated, so that a share cannot be passed off as that of another key
+	env.Ciphertext = aead.Seal(nil, env.Nonce, data, []byte(id))
+	return s.putEnvelope(ctx, id, env)
+}
+
+func (s *Store) Get(ctx context.Context, id string) ([]byte, error) {
+	env, err := s.getEnvelope(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := s.wrapper.Unwrap(ctx, env.KeyID, env.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("kms: unwrap the data key of %q: %w", id, err)
+	}
+	defer zero(dataKey)
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("kms: the envelope of %q has a nonce of %d bytes", id, len(env.Nonce))
+	}
+	data, err := aead.Open(nil, env.Nonce, env.Ciphertext, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("kms: the share of %q does not decrypt; it was altered or belongs to another ID", id)
+	}
+	return data, nil
+}
+
+func (s *Store) Delete(ctx context.Context, id string) error {
+	return s.backend.Delete(ctx, id)
+}
+
+// Rewrap wraps the data key of `id` with the current master key of the wrapper if it is wrapped with another one,
+// and returns whether it did. The share itself is not re-encrypted.
+func (s *Store) Rewrap(ctx context.Context, id string) (bool, error) {
+	env, err := s.getEnvelope(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if env.KeyID == s.wrapper.KeyID() {
+		return false, nil
+	}
+	dataKey, err := s.wrapper.Unwrap(ctx, env.KeyID, env.WrappedKey)
+	if err != nil {
+		return false, fmt.Errorf("kms: unwrap the data key of %q: %w", id, err)
+	}
+	defer zero(dataKey)
+	if env.WrappedKey, err = s.wrapper.Wrap(ctx, dataKey); err != nil {
+		return false, fmt.Errorf("kms: wrap the data key of %q: %w", id, err)
+	}
+	env.KeyID = s.wrapper.KeyID()
+	return true, s.putEnvelope(ctx, id, env)
+}
+
+func (s *Store) putEnvelope(ctx context.Context, id string, env *envelope) error {
+	bz, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return s.backend.Put(ctx, id, bz)
+}
+
+func (s *Store) getEnvelope(ctx context.Context, id string) (*envelope, error) {
+	bz, err := s.backend.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	env := new(envelope)
+	if err := json.Unmarshal(bz, env); err != nil {
+		return nil, fmt.Errorf("kms: the envelope of %q: %w", id, err)
+	}
+	if env.Version != envelopeVersion {
+		return nil, fmt.Errorf("kms: the envelope of %q has the unknown version %d", id, env.Version)
+	}
+	if env.KeyID == "" || len(env.WrappedKey) == 0 {
+		return nil, fmt.Errorf("kms: the envelope of %q has no data key", id)
+	}
+	return env, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func zero(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
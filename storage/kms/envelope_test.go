@@ -0,0 +1,173 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package kms_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/storage"
+	. "github.com/bnb-chain/tss-lib/v2/storage/kms"
+)
+
+// xorWrapper "wraps" data keys by XORing them with a byte of its key ID
+type xorWrapper struct {
+	keyID string
+}
+
+func (w *xorWrapper) KeyID() string { return w.keyID }
+
+func (w *xorWrapper) Wrap(_ context.Context, dataKey []byte) ([]byte, error) {
+	return xor(dataKey, w.keyID), nil
+}
+
+func (w *xorWrapper) Unwrap(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if keyID == "revoked" {
+		return nil, errors.New("access denied")
+	}
+	return xor(wrapped, keyID), nil
+}
+
+func xor(bz []byte, keyID string) []byte {
+	out := make([]byte, len(bz))
+	for i := range bz {
+		out[i] = bz[i] ^ keyID[0]
+	}
+	return out
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	backend, err := storage.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	wrapper := &xorWrapper{keyID: "k1"}
+	store := NewStore(backend, wrapper)
+
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	assert.NoError(t, storage.Save(ctx, store, "alice-key1", &keys[0]))
+
+	raw, err := backend.Get(ctx, "alice-key1")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), keys[0].Xi.String(), "the share is encrypted at rest")
+
+	var loaded keygen.LocalPartySaveData
+	assert.NoError(t, storage.Load(ctx, store, "alice-key1", &loaded))
+	assert.Equal(t, keys[0].Xi, loaded.Xi)
+
+	// the envelope of a key cannot pass for that of another
+	assert.NoError(t, backend.Put(ctx, "bob-key1", raw))
+	_, err = store.Get(ctx, "bob-key1")
+	assert.Error(t, err)
+
+	// a flipped bit of the ciphertext is detected
+	var env map[string]interface{}
+	assert.NoError(t, json.Unmarshal(raw, &env))
+	ct := []byte(env["ciphertext"].(string))
+	ct[len(ct)/2] ^= 'A' ^ 'B'
+	env["ciphertext"] = string(ct)
+	tampered, _ := json.Marshal(env)
+	assert.NoError(t, backend.Put(ctx, "alice-key1-tampered", tampered))
+	_, err = store.Get(ctx, "alice-key1-tampered")
+	assert.Error(t, err)
+
+	_, err = store.Get(ctx, "missing")
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func TestStoreRewrap(t *testing.T) {
+	ctx := context.Background()
+	backend, err := storage.NewFileStore(t.TempDir())
+	assert.NoError(t, err)
+	wrapper := &xorWrapper{keyID: "k1"}
+	store := NewStore(backend, wrapper)
+	assert.NoError(t, store.Put(ctx, "key1", []byte("share")))
+
+	rewrapped, err := store.Rewrap(ctx, "key1")
+	assert.NoError(t, err)
+	assert.False(t, rewrapped, "the data key is wrapped with the current master key")
+
+	// rotate the master key
+	wrapper.keyID = "k2"
+	rewrapped, err = store.Rewrap(ctx, "key1")
+	assert.NoError(t, err)
+	assert.True(t, rewrapped)
+	raw, err := backend.Get(ctx, "key1")
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `"key_id":"k2"`)
+
+	data, err := store.Get(ctx, "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("share"), data)
+
+	// a share whose master key cannot be used is not readable
+	wrapper.keyID = "revoked"
+	assert.NoError(t, store.Put(ctx, "key2", []byte("share")))
+	_, err = store.Get(ctx, "key2")
+	assert.Error(t, err)
+}
+
+func TestGCPWrapper(t *testing.T) {
+	const keyName = "projects/p/locations/global/keyRings/r/cryptoKeys/tss"
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var in map[string][]byte
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		switch r.URL.Path {
+		case "/v1/" + keyName + ":encrypt":
+			_ = json.NewEncoder(rw).Encode(map[string][]byte{"ciphertext": append([]byte("ct:"), in["plaintext"]...)})
+		case "/v1/" + keyName + ":decrypt":
+			_ = json.NewEncoder(rw).Encode(map[string][]byte{"plaintext": []byte(strings.TrimPrefix(string(in["ciphertext"]), "ct:"))})
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte(`{"error":{"message":"key not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	w := NewGCPWrapper(keyName, server.Client())
+	w.SetEndpoint(server.URL)
+	store := NewStore(newMemStore(), w)
+	assert.NoError(t, store.Put(ctx, "key1", []byte("share")))
+	data, err := store.Get(ctx, "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("share"), data)
+
+	_, err = w.Unwrap(ctx, "projects/p/locations/global/keyRings/r/cryptoKeys/gone", []byte("ct:x"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "key not found")
+	}
+}
+
+type memStore map[string][]byte
+
+func newMemStore() memStore { return make(memStore) }
+
+func (m memStore) Put(_ context.Context, id string, data []byte) error {
+	m[id] = data
+	return nil
+}
+
+func (m memStore) Get(_ context.Context, id string) ([]byte, error) {
+	if bz, ok := m[id]; ok {
+		return bz, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (m memStore) Delete(_ context.Context, id string) error {
+	delete(m, id)
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// GCPWrapper wraps data keys with a symmetric key of Google Cloud KMS, through the encrypt and decrypt methods of
+// its REST API. Google Cloud KMS rotates a key by adding versions to it, and decrypts with the version that
+// encrypted, so shares need rewrapping only when they move to another key.
+type GCPWrapper struct {
+	keyName  string
+	endpoint string
+	http     *http.Client
+}
+
+var _ Wrapper = (*GCPWrapper)(nil)
+
+// NewGCPWrapper returns a wrapper with the key `keyName`, i.e.
+// "projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>". `hc` must authorize its requests, e.g.
+// the client of google.DefaultClient of golang.org/x/oauth2 with the cloudkms scope, for an identity with the
+// roles/cloudkms.cryptoKeyEncrypterDecrypter role on the key.
+func NewGCPWrapper(keyName string, hc *http.Client) *GCPWrapper {
+	return &GCPWrapper{
+		keyName:  keyName,
+		endpoint: "https://cloudkms.googleapis.com",
+		http:     hc,
+	}
+}
+
+// SetEndpoint sets the URL of the API, e.g. that of a Private Service Connect endpoint
+func (w *GCPWrapper) SetEndpoint(endpoint string) {
+	w.endpoint = strings.TrimRight(endpoint, "/")
+}
+
+func (w *GCPWrapper) KeyID() string {
+	return w.keyName
+}
+
+func (w *GCPWrapper) Wrap(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var out struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	if err := w.call(ctx, w.keyName, "encrypt", map[string][]byte{"plaintext": dataKey}, &out); err != nil {
+		return nil, err
+	}
+	return out.Ciphertext, nil
+}
+
+func (w *GCPWrapper) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var out struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	if err := w.call(ctx, keyID, "decrypt", map[string][]byte{"ciphertext": wrapped}, &out); err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+func (w *GCPWrapper) call(ctx context.Context, keyName, method string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	u := w.endpoint + "/v1/" + keyName + ":" + method
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		bz, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = json.Unmarshal(bz, &e)
+		return fmt.Errorf("gcp kms: %s: %s: %s", method, resp.Status, e.Error.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
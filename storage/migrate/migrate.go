@@ -0,0 +1,195 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package migrate imports the save data of upstream tss-lib, github.com/binance-chain/tss-lib v1 and
+// github.com/bnb-chain/tss-lib v2, so that a deployment can move its keys to this library without a new keygen.
+//
+// The upstream JSON is close to this library's, and differs in:
+//   - the names of the curves of points, which early v1 releases left out and some forks spell differently; they are
+//     mapped to the names of the tss registry, by the scheme where missing;
+//   - the hash scheme of the SSIDs, which upstream does not record: upstream sessions hash with SHA-512/256, and the
+//     key is given the hash scheme chosen for the sessions to come, e.g. common.HashPoseidon, which all of its parties
+//     must agree on;
+//   - the version and the checksum of the save data, which are added when the result is encoded.
+//
+// The imported save data is checked for consistency before it is returned: the share must match its public share.
+// ECDSA save data of v1 releases before 1.3 lacks the safe primes and the proof parameters of its pre-params; it signs
+// and reshares, but its pre-params cannot be reused for another keygen.
+package migrate
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// the fields of the upstream save data
+var (
+	ecdsaFields = []string{
+		"PaillierSK", "NTildei", "H1i", "H2i", "Alpha", "Beta", "P", "Q",
+		"Xi", "ShareID", "Ks", "NTildej", "H1j", "H2j", "BigXj", "PaillierPKs", "ECDSAPub",
+	}
+	eddsaFields = []string{"Xi", "ShareID", "Ks", "BigXj", "EDDSAPub"}
+)
+
+// the spellings of the curve names found in upstream save data and its forks
+var curveAliases = map[string]tss.CurveName{
+	"secp256k1":    tss.Secp256k1,
+	"s256":         tss.Secp256k1,
+	"s256k1":       tss.Secp256k1,
+	"ed25519":      tss.Ed25519,
+	"edwards25519": tss.Ed25519,
+	"edwards":      tss.Ed25519,
+}
+
+// ECDSA imports the upstream ECDSA save data in `bz`, giving the key the hash scheme `hashScheme`
+func ECDSA(bz []byte, hashScheme common.HashScheme) (*ecdsakeygen.LocalPartySaveData, error) {
+	fields, err := prepare(bz, ecdsaFields, tss.Secp256k1, hashScheme)
+	if err != nil {
+		return nil, err
+	}
+	save := new(ecdsakeygen.LocalPartySaveData)
+	if err := json.Unmarshal(fields, save); err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	n := len(save.Ks)
+	switch {
+	case save.PaillierSK == nil || save.NTildei == nil || save.H1i == nil || save.H2i == nil:
+		return nil, errors.New("migrate: the save data has no pre-params")
+	case len(save.NTildej) != n || len(save.H1j) != n || len(save.H2j) != n || len(save.PaillierPKs) != n:
+		return nil, errors.New("migrate: the save data has not as many parameters of the parties as parties")
+	case save.ECDSAPub == nil:
+		return nil, errors.New("migrate: the save data has no public key")
+	}
+	if err := checkShare(save.Xi, save.ShareID, save.Ks, save.BigXj, tss.S256()); err != nil {
+		return nil, err
+	}
+	return save, nil
+}
+
+// EdDSA imports the upstream EdDSA save data in `bz`, giving the key the hash scheme `hashScheme`
+func EdDSA(bz []byte, hashScheme common.HashScheme) (*eddsakeygen.LocalPartySaveData, error) {
+	fields, err := prepare(bz, eddsaFields, tss.Ed25519, hashScheme)
+	if err != nil {
+		return nil, err
+	}
+	save := new(eddsakeygen.LocalPartySaveData)
+	if err := json.Unmarshal(fields, save); err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	if save.EDDSAPub == nil {
+		return nil, errors.New("migrate: the save data has no public key")
+	}
+	if err := checkShare(save.Xi, save.ShareID, save.Ks, save.BigXj, tss.Edwards()); err != nil {
+		return nil, err
+	}
+	return save, nil
+}
+
+// prepare maps the curve names of the points in the save data, refuses the fields that upstream save data does not
+// have, and sets the hash scheme
+func prepare(bz []byte, known []string, defaultCurve tss.CurveName, hashScheme common.HashScheme) ([]byte, error) {
+	if _, err := hashScheme.Hasher(); err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	// the numbers are kept as they are written, as they are too large for float64
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	if fields == nil {
+		return nil, errors.New("migrate: the save data is empty")
+	}
+	if _, ok := fields["Version"]; ok {
+		return nil, errors.New("migrate: the save data is already in the format of this library")
+	}
+	for name := range fields {
+		if !contains(known, name) {
+			return nil, fmt.Errorf("migrate: the save data has the field %q, which upstream save data does not", name)
+		}
+	}
+	for name, v := range fields {
+		if err := mapCurves(v, defaultCurve); err != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", name, err)
+		}
+	}
+	fields["HashScheme"] = int(hashScheme)
+	return json.Marshal(fields)
+}
+
+// mapCurves sets the curve names of the points in `v`, the objects with coordinates
+func mapCurves(v interface{}, defaultCurve tss.CurveName) error {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			if err := mapCurves(e, defaultCurve); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		if _, ok := v["Coords"]; !ok {
+			return nil
+		}
+		name, _ := v["Curve"].(string)
+		if name == "" {
+			v["Curve"] = string(defaultCurve)
+			return nil
+		}
+		mapped, ok := curveAliases[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unknown curve %q", name)
+		}
+		if mapped != defaultCurve {
+			return fmt.Errorf("a point is on %s, not %s", mapped, defaultCurve)
+		}
+		v["Curve"] = string(mapped)
+	}
+	return nil
+}
+
+// checkShare checks that the share of the party is that of its public share
+func checkShare(xi, shareID *big.Int, ks []*big.Int, bigXj []*crypto.ECPoint, ec elliptic.Curve) error {
+	if xi == nil || shareID == nil {
+		return errors.New("migrate: the save data has no share")
+	}
+	if len(bigXj) != len(ks) {
+		return errors.New("migrate: the save data has not as many public shares as parties")
+	}
+	for j, kj := range ks {
+		if kj == nil || kj.Cmp(shareID) != 0 {
+			continue
+		}
+		if bigXj[j] == nil {
+			return errors.New("migrate: the save data has no public share of the party")
+		}
+		if !crypto.ScalarBaseMult(ec, new(big.Int).Mod(xi, ec.Params().N)).Equals(bigXj[j]) {
+			return errors.New("migrate: the share of the party does not match its public share")
+		}
+		return nil
+	}
+	return errors.New("migrate: the party is not among the parties of the save data")
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package migrate_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+	ecdsakeygen "github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	eddsakeygen "github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/storage/migrate"
+)
+
+// the fixtures of the tests are in the format of upstream tss-lib
+func readFixture(t *testing.T, dir string) []byte {
+	_, file, _, _ := runtime.Caller(0)
+	bz, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "..", "test", dir, "keygen_data_0.json"))
+	assert.NoError(t, err)
+	return bz
+}
+
+func TestECDSA(t *testing.T) {
+	bz := readFixture(t, "_ecdsa_fixtures")
+	save, err := ECDSA(bz, common.HashPoseidon)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HashPoseidon, save.HashScheme)
+
+	// the result is encoded in the format of this library
+	encoded, err := json.Marshal(save)
+	assert.NoError(t, err)
+	var decoded ecdsakeygen.LocalPartySaveData
+	assert.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, ecdsakeygen.SaveDataVersion, decoded.Version)
+	assert.True(t, save.ECDSAPub.Equals(decoded.ECDSAPub))
+	_, err = ECDSA(encoded, common.HashPoseidon)
+	assert.Error(t, err, "the save data is already migrated")
+
+	_, err = EdDSA(bz, common.HashSHA512_256)
+	assert.Error(t, err, "ECDSA save data is not EdDSA save data")
+}
+
+func TestEdDSACurveNames(t *testing.T) {
+	bz := readFixture(t, "_eddsa_fixtures")
+
+	// early v1 releases wrote points without the name of their curve
+	unnamed := bytes.ReplaceAll(bz, []byte(`"Curve": "ed25519",`), nil)
+	assert.NotEqual(t, bz, unnamed)
+	renamed := bytes.ReplaceAll(bz, []byte(`"Curve": "ed25519"`), []byte(`"Curve": "Edwards25519"`))
+	for _, in := range [][]byte{bz, unnamed, renamed} {
+		save, err := EdDSA(in, common.HashSHA512_256)
+		assert.NoError(t, err)
+		assert.Equal(t, common.HashSHA512_256, save.HashScheme)
+		var fixture eddsakeygen.LocalPartySaveData
+		assert.NoError(t, json.Unmarshal(bz, &fixture))
+		assert.True(t, fixture.EDDSAPub.Equals(save.EDDSAPub))
+	}
+
+	wrongCurve := bytes.ReplaceAll(bz, []byte(`"Curve": "ed25519"`), []byte(`"Curve": "secp256k1"`))
+	_, err := EdDSA(wrongCurve, common.HashSHA512_256)
+	assert.Error(t, err)
+
+	var fields map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(bz, &fields))
+	fields["Xi"] = json.RawMessage("12345")
+	altered, err := json.Marshal(fields)
+	assert.NoError(t, err)
+	_, err = EdDSA(altered, common.HashSHA512_256)
+	assert.EqualError(t, err, "migrate: the share of the party does not match its public share")
+}
@@ -0,0 +1,125 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package storage keeps the save data of parties, the LocalPartySaveData of keygen, resharing and refresh, in a
+// Store. FileStore keeps it in files; package storage/vault keeps it in HashiCorp Vault, out of plaintext files.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrNotFound is returned by the stores for a key that they do not hold
+var ErrNotFound = errors.New("storage: not found")
+
+// the IDs of the keys are used in file names and URL paths
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+type (
+	// Store keeps encoded save data by the ID of its key, e.g. the ID of the party and the key
+	Store interface {
+		Put(ctx context.Context, id string, data []byte) error
+		// Get returns ErrNotFound if the store holds no data under `id`
+		Get(ctx context.Context, id string) ([]byte, error)
+		Delete(ctx context.Context, id string) error
+	}
+
+	// FileStore keeps the save data in a file per key in a directory, only readable by its owner
+	FileStore struct {
+		dir string
+	}
+)
+
+// ValidateID returns an error if `id` cannot name a key in a store
+func ValidateID(id string) error {
+	if !idPattern.MatchString(id) || id == "." || id == ".." {
+		return fmt.Errorf("storage: invalid ID %q; use letters, digits, '_', '.' and '-'", id)
+	}
+	return nil
+}
+
+// Save encodes `data`, e.g. a *keygen.LocalPartySaveData of ecdsa or eddsa, and puts it in the store
+func Save(ctx context.Context, store Store, id string, data interface{}) error {
+	bz, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, id, bz)
+}
+
+// Load gets the save data of `id` from the store and decodes it into `data`
+func Load(ctx context.Context, store Store, id string, data interface{}) error {
+	bz, err := store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bz, data)
+}
+
+// ----- //
+
+// NewFileStore returns a store in `dir`, creating it if need be
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Put(_ context.Context, id string, data []byte) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	// write aside and rename, so that a crash never leaves a truncated share behind
+	tmp, err := os.CreateTemp(s.dir, "."+id+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(id))
+}
+
+func (s *FileStore) Get(_ context.Context, id string) ([]byte, error) {
+	if err := ValidateID(id); err != nil {
+		return nil, err
+	}
+	bz, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return bz, err
+}
+
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	if err := ValidateID(id); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
@@ -0,0 +1,47 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	. "github.com/bnb-chain/tss-lib/v2/storage"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewFileStore(filepath.Join(dir, "shares"))
+	assert.NoError(t, err)
+
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+	assert.NoError(t, Save(ctx, store, "alice-key1", &keys[0]))
+
+	info, err := os.Stat(filepath.Join(dir, "shares", "alice-key1.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "only the owner may read a share")
+
+	var loaded keygen.LocalPartySaveData
+	assert.NoError(t, Load(ctx, store, "alice-key1", &loaded))
+	assert.Equal(t, keys[0].Xi, loaded.Xi)
+	assert.True(t, keys[0].EDDSAPub.Equals(loaded.EDDSAPub))
+
+	assert.NoError(t, store.Delete(ctx, "alice-key1"))
+	assert.NoError(t, store.Delete(ctx, "alice-key1"), "deleting twice is fine")
+	_, err = store.Get(ctx, "alice-key1")
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Error(t, store.Put(ctx, "../escape", []byte("{}")))
+	assert.Error(t, store.Put(ctx, "..", []byte("{}")))
+}
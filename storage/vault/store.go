@@ -0,0 +1,191 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package vault keeps the save data of parties in HashiCorp Vault. The data is a secret of the KV version 2 secrets
+// engine, and may in addition be encrypted by a key of the transit secrets engine, so that the KV secret is useless
+// without the right to decrypt with the key, and the key never leaves Vault. It talks to the HTTP API of Vault.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/storage"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+
+	// the field of the KV secret holding the data
+	dataField = "share"
+)
+
+// Store is a storage.Store in Vault
+type Store struct {
+	addr      string
+	token     string
+	namespace string
+	mount     string
+	path      string
+	// the transit mount and key encrypting the data, if any
+	transitMount, transitKey string
+	http                     *http.Client
+}
+
+var _ storage.Store = (*Store)(nil)
+
+// NewStore returns a store in the KV version 2 secrets engine mounted at `mount`, e.g. "secret", of the Vault at
+// `addr`, e.g. "https://vault.example.com:8200". The data of a key is the secret `<path>/<id>`. `token` must allow
+// create, read, update and delete on the secrets, and delete on their metadata.
+func NewStore(addr, token, mount, path string) *Store {
+	return &Store{
+		addr:  strings.TrimRight(addr, "/"),
+		token: token,
+		mount: strings.Trim(mount, "/"),
+		path:  strings.Trim(path, "/"),
+		http:  &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// UseTransit encrypts the data with the key `key` of the transit secrets engine mounted at `mount`, e.g. "transit",
+// before it is put in the KV secret, and decrypts it when it is read. The token must allow update on the encrypt and
+// decrypt endpoints of the key.
+func (s *Store) UseTransit(mount, key string) {
+	s.transitMount, s.transitKey = strings.Trim(mount, "/"), key
+}
+
+// SetNamespace sets the Vault Enterprise namespace of the requests
+func (s *Store) SetNamespace(namespace string) {
+	s.namespace = namespace
+}
+
+// SetHTTPClient sets the client of the requests, e.g. one trusting the CA of Vault
+func (s *Store) SetHTTPClient(hc *http.Client) {
+	s.http = hc
+}
+
+func (s *Store) Put(ctx context.Context, id string, data []byte) error {
+	if err := storage.ValidateID(id); err != nil {
+		return err
+	}
+	value := base64.StdEncoding.EncodeToString(data)
+	if s.transitKey != "" {
+		var out struct {
+			Data struct {
+				Ciphertext string `json:"ciphertext"`
+			} `json:"data"`
+		}
+		in := map[string]string{"plaintext": value}
+		if err := s.transit(ctx, "encrypt", in, &out); err != nil {
+			return err
+		}
+		value = out.Data.Ciphertext
+	}
+	in := map[string]interface{}{"data": map[string]string{dataField: value}}
+	return s.do(ctx, http.MethodPost, s.mount+"/data/"+s.secret(id), in, nil)
+}
+
+func (s *Store) Get(ctx context.Context, id string) ([]byte, error) {
+	if err := storage.ValidateID(id); err != nil {
+		return nil, err
+	}
+	var secret struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := s.do(ctx, http.MethodGet, s.mount+"/data/"+s.secret(id), nil, &secret); err != nil {
+		return nil, err
+	}
+	value, ok := secret.Data.Data[dataField]
+	if !ok {
+		// a deleted version reads as a secret without data
+		return nil, storage.ErrNotFound
+	}
+	if s.transitKey != "" {
+		var out struct {
+			Data struct {
+				Plaintext string `json:"plaintext"`
+			} `json:"data"`
+		}
+		in := map[string]string{"ciphertext": value}
+		if err := s.transit(ctx, "decrypt", in, &out); err != nil {
+			return nil, err
+		}
+		value = out.Data.Plaintext
+	}
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// Delete deletes every version of the secret of `id`, so that the share cannot be restored from Vault
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if err := storage.ValidateID(id); err != nil {
+		return err
+	}
+	err := s.do(ctx, http.MethodDelete, s.mount+"/metadata/"+s.secret(id), nil, nil)
+	if err == storage.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *Store) secret(id string) string {
+	if s.path == "" {
+		return id
+	}
+	return s.path + "/" + id
+}
+
+func (s *Store) transit(ctx context.Context, op string, in, out interface{}) error {
+	err := s.do(ctx, http.MethodPost, s.transitMount+"/"+op+"/"+s.transitKey, in, out)
+	if err == storage.ErrNotFound {
+		return fmt.Errorf("vault: no transit key %q at %q", s.transitKey, s.transitMount)
+	}
+	return err
+}
+
+func (s *Store) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.addr+"/v1/"+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", s.token)
+	if s.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.namespace)
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return storage.ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("vault: %s %s: %s: %s", method, path, resp.Status, strings.Join(e.Errors, "; "))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
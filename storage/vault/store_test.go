@@ -0,0 +1,116 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package vault_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/storage"
+	. "github.com/bnb-chain/tss-lib/v2/storage/vault"
+)
+
+const testToken = "s.test"
+
+// fakeVault serves the endpoints of the KV version 2 and transit secrets engines that the store uses; its transit
+// "encryption" only marks the plaintext
+type fakeVault struct {
+	mu      sync.Mutex
+	secrets map[string]map[string]string
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if r.Header.Get("X-Vault-Token") != testToken {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	var in map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+	switch {
+	case strings.HasPrefix(path, "secret/data/"):
+		name := strings.TrimPrefix(path, "secret/data/")
+		if r.Method == http.MethodGet {
+			data, ok := v.secrets[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": data}})
+			return
+		}
+		data := make(map[string]string)
+		for k, val := range in["data"].(map[string]interface{}) {
+			data[k] = val.(string)
+		}
+		v.secrets[name] = data
+	case strings.HasPrefix(path, "secret/metadata/"):
+		name := strings.TrimPrefix(path, "secret/metadata/")
+		if _, ok := v.secrets[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(v.secrets, name)
+		w.WriteHeader(http.StatusNoContent)
+	case path == "transit/encrypt/shares":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"ciphertext": "vault:v1:" + in["plaintext"].(string)}})
+	case path == "transit/decrypt/shares":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"plaintext": strings.TrimPrefix(in["ciphertext"].(string), "vault:v1:")}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeVault{secrets: make(map[string]map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	keys, _, err := keygen.LoadKeygenTestFixtures(1)
+	assert.NoError(t, err)
+
+	for _, useTransit := range []bool{false, true} {
+		store := NewStore(server.URL, testToken, "secret", "tss/alice")
+		if useTransit {
+			store.UseTransit("transit", "shares")
+		}
+		assert.NoError(t, storage.Save(ctx, store, "key1", &keys[0]))
+
+		stored := fake.secrets["tss/alice/key1"]["share"]
+		assert.Equal(t, useTransit, strings.HasPrefix(stored, "vault:v1:"), "the share is encrypted with transit")
+
+		var loaded keygen.LocalPartySaveData
+		assert.NoError(t, storage.Load(ctx, store, "key1", &loaded))
+		assert.Equal(t, keys[0].Xi, loaded.Xi)
+
+		assert.NoError(t, store.Delete(ctx, "key1"))
+		assert.NoError(t, store.Delete(ctx, "key1"), "deleting twice is fine")
+		_, err = store.Get(ctx, "key1")
+		assert.Equal(t, storage.ErrNotFound, err)
+	}
+
+	store := NewStore(server.URL, "s.wrong", "secret", "tss/alice")
+	err = store.Put(ctx, "key1", []byte("{}"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "permission denied")
+	}
+	store = NewStore(server.URL, testToken, "secret", "tss/alice")
+	store.UseTransit("transit", "missing")
+	assert.Error(t, store.Put(ctx, "key1", []byte("{}")))
+}
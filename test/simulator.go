@@ -0,0 +1,262 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package test
+
+import (
+	"container/heap"
+	"math/rand"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+type (
+	// Faults are what the network does to the messages on a link. Every message is delayed by a latency drawn
+	// between MinLatency and MaxLatency, so messages overtake each other when the two differ.
+	Faults struct {
+		MinLatency, MaxLatency time.Duration
+		// the probabilities that a message is dropped, delivered twice, or has a byte of its wire bytes flipped
+		DropRate, DuplicateRate, CorruptRate float64
+	}
+
+	// SimulatorStats counts what the network did to the messages so far
+	SimulatorStats struct {
+		Sent, Delivered, Dropped, Duplicated, Corrupted int
+	}
+
+	// SimulatorError is an error of a party on the delivery of a message
+	SimulatorError struct {
+		To  *tss.PartyID
+		At  time.Duration
+		Err *tss.Error
+	}
+
+	// Simulator runs parties over an in-memory network with faults, in virtual time and without goroutines of its
+	// own. The latencies and faults are drawn from its seed, so a failing run can be replayed, up to the randomness of
+	// the parties themselves. It drives every party with a tss.Stepper.
+	Simulator struct {
+		rnd      *rand.Rand
+		now      time.Duration
+		faults   Faults
+		links    map[[2]string]Faults
+		steppers []*tss.Stepper
+		byKey    map[string]*tss.Stepper
+		queue    deliveryQueue
+		seq      uint64
+		dropped  []*delivery
+		errors   []SimulatorError
+		stats    SimulatorStats
+	}
+
+	delivery struct {
+		at        time.Duration
+		seq       uint64
+		from, to  *tss.PartyID
+		wireBytes []byte
+		broadcast bool
+	}
+
+	deliveryQueue []*delivery
+)
+
+// NewSimulator returns a simulator whose links all have `faults`
+func NewSimulator(seed int64, faults Faults) *Simulator {
+	return &Simulator{
+		rnd:    rand.New(rand.NewSource(seed)),
+		faults: faults,
+		links:  make(map[[2]string]Faults),
+		byKey:  make(map[string]*tss.Stepper),
+	}
+}
+
+// Add adds a party, constructed like for tss.NewStepper; its end channel must be buffered
+func (s *Simulator) Add(newParty func(out chan<- tss.Message) tss.Party) tss.Party {
+	stepper := tss.NewStepper(0, newParty)
+	s.steppers = append(s.steppers, stepper)
+	s.byKey[simKey(stepper.Party().PartyID())] = stepper
+	return stepper.Party()
+}
+
+// SetLinkFaults sets the faults of the link from one party to another, in place of those of the simulator
+func (s *Simulator) SetLinkFaults(from, to *tss.PartyID, faults Faults) {
+	s.links[[2]string{simKey(from), simKey(to)}] = faults
+}
+
+// Start starts every party in the order they were added and sends their first messages
+func (s *Simulator) Start() {
+	for _, stepper := range s.steppers {
+		msgs, err := stepper.Start()
+		s.record(stepper.Party().PartyID(), err)
+		s.send(msgs)
+	}
+}
+
+// Step delivers the next message, advancing the virtual time to its arrival, and returns false once there is none
+func (s *Simulator) Step() bool {
+	if s.queue.Len() == 0 {
+		return false
+	}
+	d := heap.Pop(&s.queue).(*delivery)
+	s.now = d.at
+	stepper, ok := s.byKey[simKey(d.to)]
+	if !ok {
+		return true
+	}
+	s.stats.Delivered++
+	msgs, err := stepper.StepFromBytes(d.wireBytes, d.from, d.broadcast)
+	s.record(d.to, err)
+	s.send(msgs)
+	return true
+}
+
+// Run delivers messages until there are none left in flight, or until `maxSteps` have been delivered when it is
+// positive, and returns the number delivered
+func (s *Simulator) Run(maxSteps int) int {
+	steps := 0
+	for (maxSteps <= 0 || steps < maxSteps) && s.Step() {
+		steps++
+	}
+	return steps
+}
+
+// ResendDropped sends again, without faults, the messages that the network has dropped so far, as their senders
+// would on a tss.ResendRequest
+func (s *Simulator) ResendDropped() int {
+	dropped := s.dropped
+	s.dropped = nil
+	for _, d := range dropped {
+		d.at, d.seq = s.now, s.nextSeq()
+		heap.Push(&s.queue, d)
+	}
+	return len(dropped)
+}
+
+// Stalled returns the running parties, once the network is quiet, with the parties whose messages they still wait
+// for, e.g. to be blamed for a timeout
+func (s *Simulator) Stalled() map[*tss.PartyID][]*tss.PartyID {
+	stalled := make(map[*tss.PartyID][]*tss.PartyID)
+	for _, stepper := range s.steppers {
+		party := stepper.Party()
+		if waiting := party.WaitingFor(); party.Running() && len(waiting) > 0 {
+			stalled[party.PartyID()] = waiting
+		}
+	}
+	return stalled
+}
+
+// Now returns the virtual time elapsed since the start
+func (s *Simulator) Now() time.Duration {
+	return s.now
+}
+
+// Errors returns the errors of the parties so far
+func (s *Simulator) Errors() []SimulatorError {
+	return s.errors
+}
+
+// Stats returns what the network did to the messages so far
+func (s *Simulator) Stats() SimulatorStats {
+	return s.stats
+}
+
+func (s *Simulator) record(to *tss.PartyID, err *tss.Error) {
+	if err != nil {
+		s.errors = append(s.errors, SimulatorError{To: to, At: s.now, Err: err})
+	}
+}
+
+// send puts the messages in flight to their recipients, with the faults of their links
+func (s *Simulator) send(msgs []tss.Message) {
+	for _, msg := range msgs {
+		bz, _, err := msg.WireBytes()
+		if err != nil {
+			s.record(msg.GetFrom(), tss.NewError(err, "simulator", -1, nil))
+			continue
+		}
+		for _, to := range s.recipients(msg) {
+			s.stats.Sent++
+			faults := s.linkFaults(msg.GetFrom(), to)
+			d := &delivery{from: msg.GetFrom(), to: to, wireBytes: bz, broadcast: msg.IsBroadcast()}
+			if s.rnd.Float64() < faults.DropRate {
+				s.stats.Dropped++
+				s.dropped = append(s.dropped, d)
+				continue
+			}
+			copies := 1
+			if s.rnd.Float64() < faults.DuplicateRate {
+				s.stats.Duplicated++
+				copies++
+			}
+			for i := 0; i < copies; i++ {
+				c := *d
+				if s.rnd.Float64() < faults.CorruptRate {
+					s.stats.Corrupted++
+					c.wireBytes = append([]byte(nil), bz...)
+					c.wireBytes[s.rnd.Intn(len(bz))] ^= byte(1 + s.rnd.Intn(255))
+				}
+				c.at, c.seq = s.now+s.latency(faults), s.nextSeq()
+				heap.Push(&s.queue, &c)
+			}
+		}
+	}
+}
+
+func (s *Simulator) recipients(msg tss.Message) []*tss.PartyID {
+	if to := msg.GetTo(); to != nil {
+		return to
+	}
+	recipients := make([]*tss.PartyID, 0, len(s.steppers))
+	for _, stepper := range s.steppers {
+		if pid := stepper.Party().PartyID(); simKey(pid) != simKey(msg.GetFrom()) {
+			recipients = append(recipients, pid)
+		}
+	}
+	return recipients
+}
+
+func (s *Simulator) linkFaults(from, to *tss.PartyID) Faults {
+	if faults, ok := s.links[[2]string{simKey(from), simKey(to)}]; ok {
+		return faults
+	}
+	return s.faults
+}
+
+func (s *Simulator) latency(faults Faults) time.Duration {
+	if faults.MaxLatency <= faults.MinLatency {
+		return faults.MinLatency
+	}
+	return faults.MinLatency + time.Duration(s.rnd.Int63n(int64(faults.MaxLatency-faults.MinLatency)))
+}
+
+func (s *Simulator) nextSeq() uint64 {
+	s.seq++
+	return s.seq
+}
+
+func simKey(pid *tss.PartyID) string {
+	return string(pid.KeyInt().Bytes())
+}
+
+// ----- //
+
+func (q deliveryQueue) Len() int { return len(q) }
+
+// deliveries arriving at the same time are delivered in the order they were sent
+func (q deliveryQueue) Less(i, j int) bool {
+	return q[i].at < q[j].at || (q[i].at == q[j].at && q[i].seq < q[j].seq)
+}
+
+func (q deliveryQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *deliveryQueue) Push(x interface{}) { *q = append(*q, x.(*delivery)) }
+
+func (q *deliveryQueue) Pop() interface{} {
+	old := *q
+	d := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return d
+}
@@ -15,7 +15,7 @@ func SharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Erro
 	if party.PartyID() == msg.GetFrom() {
 		return
 	}
-	bz, _, err := msg.WireBytes()
+	bz, routing, err := msg.WireBytes()
 	if err != nil {
 		errCh <- party.WrapError(err)
 		return
@@ -25,6 +25,7 @@ func SharedPartyUpdater(party tss.Party, msg tss.Message, errCh chan<- *tss.Erro
 		errCh <- party.WrapError(err)
 		return
 	}
+	pMsg = tss.WithTraceContext(pMsg, routing.TraceContext)
 	if _, err := party.Update(pMsg); err != nil {
 		errCh <- err
 	}
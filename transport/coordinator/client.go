@@ -0,0 +1,199 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	defaultPollWait = 30 * time.Second
+)
+
+// Client is the connection of a party to a session of a coordinator
+type Client struct {
+	base    string
+	session string
+	self    *tss.PartyID
+	peers   *transport.Peers
+	http    *http.Client
+	// the sequence of the last message received
+	after uint64
+}
+
+// PartyKey returns how the coordinator names a party: its key, in hex
+func PartyKey(pid *tss.PartyID) string {
+	return hex.EncodeToString(pid.KeyInt().Bytes())
+}
+
+// NewSessionSpec returns the spec of a session of `parties`, with their identity keys
+func NewSessionSpec(id, protocol string, threshold int, parties []*tss.PartyID) SessionSpec {
+	spec := SessionSpec{
+		ID:        id,
+		Protocol:  protocol,
+		Threshold: threshold,
+		Parties:   make([]PartySpec, len(parties)),
+	}
+	for i, pid := range parties {
+		spec.Parties[i] = PartySpec{Key: PartyKey(pid), IdentityKey: pid.IdentityKey}
+	}
+	return spec
+}
+
+// NewClient returns the client of `self` in session `session` of the coordinator at `baseURL`, e.g.
+// "https://coordinator.example.com". `peers` are the parties of the session, including `self`.
+func NewClient(baseURL, session string, self *tss.PartyID, peers []*tss.PartyID) *Client {
+	return &Client{
+		base:    strings.TrimRight(baseURL, "/"),
+		session: session,
+		self:    self,
+		peers:   transport.NewPeers(peers...),
+		http:    &http.Client{Timeout: defaultPollWait + 30*time.Second},
+	}
+}
+
+// SetHTTPClient sets the client of the requests, e.g. one with TLS client certificates
+func (c *Client) SetHTTPClient(hc *http.Client) {
+	c.http = hc
+}
+
+// CreateSession creates the session on the coordinator; one party, or an operator, creates it before the parties
+// start
+func (c *Client) CreateSession(ctx context.Context, spec SessionSpec) error {
+	return c.do(ctx, http.MethodPost, "/sessions", spec, nil)
+}
+
+// Status returns the status of the session
+func (c *Client) Status(ctx context.Context) (*SessionStatus, error) {
+	status := new(SessionStatus)
+	if err := c.do(ctx, http.MethodGet, c.sessionPath(""), nil, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Send posts a message of the party to the coordinator
+func (c *Client) Send(ctx context.Context, msg tss.Message) error {
+	env, err := transport.NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	m := Message{From: PartyKey(msg.GetFrom()), Payload: env.Marshal()}
+	for _, to := range msg.GetTo() {
+		m.To = append(m.To, PartyKey(to))
+	}
+	return c.do(ctx, http.MethodPost, c.sessionPath("/messages"), m, nil)
+}
+
+// ReportProgress reports the round the party has started, or that it is done or has failed
+func (c *Client) ReportProgress(ctx context.Context, round int, done bool, failure error) error {
+	p := Progress{Party: PartyKey(c.self), Round: round, Done: done}
+	if failure != nil {
+		p.Error = failure.Error()
+	}
+	return c.do(ctx, http.MethodPost, c.sessionPath("/progress"), p, nil)
+}
+
+// Run sends the messages of `party` from its out channel until the channel is closed or `ctx` is done. A message
+// that cannot be sent yields an error of the party on `errCh`.
+func (c *Client) Run(ctx context.Context, party tss.Party, outCh <-chan tss.Message, errCh chan<- *tss.Error) {
+	for {
+		select {
+		case msg, ok := <-outCh:
+			if !ok {
+				return
+			}
+			if err := c.Send(ctx, msg); err != nil {
+				errCh <- party.WrapError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Receive polls the coordinator for the messages of the party and passes them to `updater`, e.g. a LocalParty,
+// until `ctx` is done. The errors of the deliveries are sent on `errCh`; those of the polls are retried.
+func (c *Client) Receive(ctx context.Context, updater transport.Updater, errCh chan<- *tss.Error) error {
+	for {
+		var msgs []*Message
+		query := url.Values{
+			"party": {PartyKey(c.self)},
+			"after": {strconv.FormatUint(c.after, 10)},
+			"wait":  {defaultPollWait.String()},
+		}
+		err := c.do(ctx, http.MethodGet, c.sessionPath("/messages?"+query.Encode()), nil, &msgs)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			select {
+			case <-time.After(time.Second):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		for _, m := range msgs {
+			c.after = m.Seq
+			env := new(transport.Envelope)
+			if err := env.Unmarshal(m.Payload); err != nil {
+				errCh <- tss.NewError(err, "transport", -1, nil)
+				continue
+			}
+			if err := transport.Deliver(updater, c.peers, env); err != nil {
+				errCh <- err
+			}
+		}
+	}
+}
+
+func (c *Client) sessionPath(suffix string) string {
+	return "/sessions/" + url.PathEscape(c.session) + suffix
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("coordinator: %s %s: %s: %s", method, path, resp.Status, e.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,430 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package coordinator is a REST service that hosts the sessions of parties on separate machines: it keeps the
+// messages of every session, hands each party those addressed to it, and tracks how far each party has got. It never
+// holds key material; the parties run the protocols and only exchange their messages through it.
+//
+//	POST   /sessions                  create a session from a SessionSpec
+//	GET    /sessions                  list the sessions
+//	GET    /sessions/{id}             the SessionStatus of a session
+//	DELETE /sessions/{id}             forget a session
+//	POST   /sessions/{id}/messages    post a Message from a party
+//	GET    /sessions/{id}/messages    the messages for ?party=<key> with a sequence above ?after=, waiting up to
+//	                                  ?wait= (e.g. 30s) for one to arrive
+//	POST   /sessions/{id}/progress    report the Progress of a party
+package coordinator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	ProtocolKeygen    = "keygen"
+	ProtocolSigning   = "signing"
+	ProtocolResharing = "resharing"
+	ProtocolRefresh   = "refresh"
+
+	defaultMaxMessages = 100000
+	maxPollWait        = time.Minute
+	// the largest request body, an envelope of the largest wire bytes with room to spare
+	maxRequestBytes = 24 << 20
+)
+
+type (
+	// PartySpec names a party of a session by its key, in hex, with the identity key that signs its messages, if any
+	PartySpec struct {
+		Key         string `json:"key"`
+		IdentityKey []byte `json:"identity_key,omitempty"`
+	}
+
+	// SessionSpec describes a session to create
+	SessionSpec struct {
+		ID        string      `json:"id"`
+		Protocol  string      `json:"protocol"`
+		Threshold int         `json:"threshold"`
+		Parties   []PartySpec `json:"parties"`
+	}
+
+	// PartyStatus is how far a party of a session has got
+	PartyStatus struct {
+		Key string `json:"key"`
+		// the round the party last reported starting
+		Round int `json:"round"`
+		// the type of the last message the party posted
+		LastMessage string `json:"last_message,omitempty"`
+		Messages    int    `json:"messages"`
+		Done        bool   `json:"done"`
+		Error       string `json:"error,omitempty"`
+	}
+
+	// SessionStatus is the state of a session
+	SessionStatus struct {
+		SessionSpec
+		Created  time.Time     `json:"created"`
+		Messages int           `json:"messages"`
+		Status   []PartyStatus `json:"status"`
+		// whether every party has reported that it is done
+		Done bool `json:"done"`
+	}
+
+	// Message is a message of a party, with the sequence number the coordinator gave it
+	Message struct {
+		Seq  uint64   `json:"seq"`
+		From string   `json:"from"`
+		To   []string `json:"to,omitempty"`
+		// a transport.Envelope encoded by its Marshal method
+		Payload []byte `json:"payload"`
+	}
+
+	// Progress is what a party reports of itself
+	Progress struct {
+		Party string `json:"party"`
+		Round int    `json:"round,omitempty"`
+		Done  bool   `json:"done,omitempty"`
+		Error string `json:"error,omitempty"`
+	}
+
+	// Server is the coordinator service; it is an http.Handler
+	Server struct {
+		mtx         sync.Mutex
+		sessions    map[string]*session
+		maxMessages int
+	}
+
+	session struct {
+		spec     SessionSpec
+		created  time.Time
+		peers    *transport.Peers
+		status   map[string]*PartyStatus
+		messages []*Message
+		// closed and replaced whenever a message arrives, to wake the parties waiting for one
+		arrived chan struct{}
+	}
+)
+
+// NewServer returns a coordinator without sessions
+func NewServer() *Server {
+	return &Server{
+		sessions:    make(map[string]*session),
+		maxMessages: defaultMaxMessages,
+	}
+}
+
+// SetMaxMessages sets the most messages a session may hold, a bound on the memory that a session may take
+func (s *Server) SetMaxMessages(n int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.maxMessages = n
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBytes)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "sessions" || len(parts) > 3 {
+		httpError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		s.createSession(w, r)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.listSessions(w)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		s.withSession(w, parts[1], func(sn *session) { writeJSON(w, http.StatusOK, sn.statusLocked()) })
+	case len(parts) == 2 && r.Method == http.MethodDelete:
+		s.deleteSession(w, parts[1])
+	case len(parts) == 3 && parts[2] == "messages" && r.Method == http.MethodPost:
+		s.postMessage(w, r, parts[1])
+	case len(parts) == 3 && parts[2] == "messages" && r.Method == http.MethodGet:
+		s.getMessages(w, r, parts[1])
+	case len(parts) == 3 && parts[2] == "progress" && r.Method == http.MethodPost:
+		s.postProgress(w, r, parts[1])
+	default:
+		httpError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (s *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	var spec SessionSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	sn, err := newSession(spec)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.sessions[spec.ID]; ok {
+		httpError(w, http.StatusConflict, fmt.Errorf("session %s already exists", spec.ID))
+		return
+	}
+	s.sessions[spec.ID] = sn
+	writeJSON(w, http.StatusCreated, sn.statusLocked())
+}
+
+func (s *Server) listSessions(w http.ResponseWriter) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	statuses := make([]SessionStatus, 0, len(s.sessions))
+	for _, sn := range s.sessions {
+		statuses = append(statuses, sn.statusLocked())
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (s *Server) deleteSession(w http.ResponseWriter, id string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sn, ok := s.sessions[id]
+	if !ok {
+		httpError(w, http.StatusNotFound, fmt.Errorf("no session %s", id))
+		return
+	}
+	close(sn.arrived)
+	delete(s.sessions, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) postMessage(w http.ResponseWriter, r *http.Request, id string) {
+	var msg Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.withSession(w, id, func(sn *session) {
+		if len(sn.messages) >= s.maxMessages {
+			httpError(w, http.StatusInsufficientStorage, errors.New("the session holds too many messages"))
+			return
+		}
+		status, ok := sn.status[msg.From]
+		if !ok {
+			httpError(w, http.StatusForbidden, fmt.Errorf("party %s is not in the session", msg.From))
+			return
+		}
+		for _, to := range msg.To {
+			if _, ok := sn.status[to]; !ok {
+				httpError(w, http.StatusBadRequest, fmt.Errorf("party %s is not in the session", to))
+				return
+			}
+		}
+		// parse the message, as its recipients will, to refuse garbage and to learn its type
+		env := new(transport.Envelope)
+		if err := env.Unmarshal(msg.Payload); err != nil {
+			httpError(w, http.StatusBadRequest, err)
+			return
+		}
+		if hex.EncodeToString(env.From) != msg.From {
+			httpError(w, http.StatusBadRequest, errors.New("the envelope is not from the party that posted it"))
+			return
+		}
+		parsed, err := sn.peers.Parse(env)
+		if err != nil {
+			httpError(w, http.StatusBadRequest, err)
+			return
+		}
+		msg.Seq = uint64(len(sn.messages)) + 1
+		sn.messages = append(sn.messages, &msg)
+		status.LastMessage = parsed.Type()
+		status.Messages++
+		close(sn.arrived)
+		sn.arrived = make(chan struct{})
+		writeJSON(w, http.StatusCreated, struct {
+			Seq uint64 `json:"seq"`
+		}{msg.Seq})
+	})
+}
+
+func (s *Server) getMessages(w http.ResponseWriter, r *http.Request, id string) {
+	query := r.URL.Query()
+	party := query.Get("party")
+	var after uint64
+	var wait time.Duration
+	var err error
+	if v := query.Get("after"); v != "" {
+		if after, err = strconv.ParseUint(v, 10, 64); err != nil {
+			httpError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if v := query.Get("wait"); v != "" {
+		if wait, err = time.ParseDuration(v); err != nil {
+			httpError(w, http.StatusBadRequest, err)
+			return
+		}
+		if wait > maxPollWait {
+			wait = maxPollWait
+		}
+	}
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	for {
+		s.mtx.Lock()
+		sn, ok := s.sessions[id]
+		if !ok {
+			s.mtx.Unlock()
+			httpError(w, http.StatusNotFound, fmt.Errorf("no session %s", id))
+			return
+		}
+		if _, ok := sn.status[party]; !ok {
+			s.mtx.Unlock()
+			httpError(w, http.StatusForbidden, fmt.Errorf("party %s is not in the session", party))
+			return
+		}
+		msgs, arrived := sn.messagesFor(party, after), sn.arrived
+		s.mtx.Unlock()
+		if len(msgs) > 0 || wait <= 0 {
+			writeJSON(w, http.StatusOK, msgs)
+			return
+		}
+		select {
+		case <-arrived:
+		case <-deadline.C:
+			wait = 0
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) postProgress(w http.ResponseWriter, r *http.Request, id string) {
+	var p Progress
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.withSession(w, id, func(sn *session) {
+		status, ok := sn.status[p.Party]
+		if !ok {
+			httpError(w, http.StatusForbidden, fmt.Errorf("party %s is not in the session", p.Party))
+			return
+		}
+		if p.Round > status.Round {
+			status.Round = p.Round
+		}
+		status.Done = status.Done || p.Done
+		if p.Error != "" {
+			status.Error = p.Error
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// withSession calls `fn` with the session locked, or fails the request if there is no such session
+func (s *Server) withSession(w http.ResponseWriter, id string, fn func(*session)) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sn, ok := s.sessions[id]
+	if !ok {
+		httpError(w, http.StatusNotFound, fmt.Errorf("no session %s", id))
+		return
+	}
+	fn(sn)
+}
+
+// ----- //
+
+func newSession(spec SessionSpec) (*session, error) {
+	switch spec.Protocol {
+	case ProtocolKeygen, ProtocolSigning, ProtocolResharing, ProtocolRefresh:
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", spec.Protocol)
+	}
+	if spec.ID == "" || len(spec.Parties) == 0 {
+		return nil, errors.New("a session needs an id and parties")
+	}
+	ids := make([]*tss.PartyID, len(spec.Parties))
+	status := make(map[string]*PartyStatus, len(spec.Parties))
+	for i, p := range spec.Parties {
+		key, err := hex.DecodeString(p.Key)
+		if err != nil || len(key) == 0 || key[0] == 0 {
+			return nil, fmt.Errorf("party key %q is not minimal hex", p.Key)
+		}
+		if _, ok := status[p.Key]; ok {
+			return nil, fmt.Errorf("party %s is listed twice", p.Key)
+		}
+		ids[i] = tss.NewPartyID(p.Key, "", new(big.Int).SetBytes(key))
+		ids[i].IdentityKey = p.IdentityKey
+		status[p.Key] = &PartyStatus{Key: p.Key}
+	}
+	return &session{
+		spec:    spec,
+		created: time.Now(),
+		peers:   transport.NewPeers(ids...),
+		status:  status,
+		arrived: make(chan struct{}),
+	}, nil
+}
+
+// messagesFor returns the messages for a party with a sequence above `after`; its own broadcasts are left out
+func (sn *session) messagesFor(party string, after uint64) []*Message {
+	msgs := make([]*Message, 0)
+	for _, msg := range sn.messages[minUint64(after, uint64(len(sn.messages))):] {
+		if msg.From == party {
+			continue
+		}
+		if len(msg.To) == 0 {
+			msgs = append(msgs, msg)
+			continue
+		}
+		for _, to := range msg.To {
+			if to == party {
+				msgs = append(msgs, msg)
+				break
+			}
+		}
+	}
+	return msgs
+}
+
+func (sn *session) statusLocked() SessionStatus {
+	st := SessionStatus{
+		SessionSpec: sn.spec,
+		Created:     sn.created,
+		Messages:    len(sn.messages),
+		Done:        true,
+	}
+	for _, p := range sn.spec.Parties {
+		status := sn.status[p.Key]
+		st.Status = append(st.Status, *status)
+		st.Done = st.Done && status.Done
+	}
+	return st
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func httpError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
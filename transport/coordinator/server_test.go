@@ -0,0 +1,113 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package coordinator_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	. "github.com/bnb-chain/tss-lib/v2/transport/coordinator"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestKeygenThroughCoordinator(t *testing.T) {
+	server := httptest.NewServer(NewServer())
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	spec := NewSessionSpec("keygen-1", ProtocolKeygen, test.TestThreshold, pIDs)
+
+	clients := make([]*Client, len(pIDs))
+	sessions := make([]*keygen.Session, len(pIDs))
+	for i, pid := range pIDs {
+		clients[i] = NewClient(server.URL, spec.ID, pid, pIDs)
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pid, len(pIDs), test.TestThreshold)
+		sessions[i] = keygen.NewSession(params, len(pIDs))
+		client := clients[i]
+		sessions[i].SetOnRoundStarted(func(round int) {
+			_ = client.ReportProgress(ctx, round, false, nil)
+		})
+	}
+	assert.NoError(t, clients[0].CreateSession(ctx, spec))
+	assert.Error(t, clients[1].CreateSession(ctx, spec), "the session exists")
+
+	for i := range pIDs {
+		go clients[i].Receive(ctx, sessions[i], errCh)
+		go clients[i].Run(ctx, sessions[i], sessions[i].Out, errCh)
+		go func(S *keygen.Session) {
+			if err := S.Start(); err != nil {
+				errCh <- err
+			}
+		}(sessions[i])
+	}
+
+	var pub *crypto.ECPoint
+	for i, S := range sessions {
+		select {
+		case save := <-S.End:
+			if pub == nil {
+				pub = save.EDDSAPub
+			}
+			assert.True(t, pub.Equals(save.EDDSAPub))
+			assert.NoError(t, clients[i].ReportProgress(ctx, 0, true, nil))
+		case err := <-errCh:
+			t.Fatalf("keygen failed: %v", err)
+		case <-time.After(time.Minute):
+			t.Fatal("keygen timed out")
+		}
+	}
+
+	status, err := clients[0].Status(ctx)
+	assert.NoError(t, err)
+	assert.True(t, status.Done)
+	for _, st := range status.Status {
+		assert.Equal(t, 3, st.Round)
+		assert.NotEmpty(t, st.LastMessage)
+		assert.True(t, st.Messages > 0)
+	}
+}
+
+func TestCoordinatorRejects(t *testing.T) {
+	server := httptest.NewServer(NewServer())
+	defer server.Close()
+	ctx := context.Background()
+	pIDs := tss.GenerateTestPartyIDs(3)
+	outsider := tss.GenerateTestPartyIDs(4)[3]
+
+	client := NewClient(server.URL, "s", pIDs[0], pIDs)
+	assert.Error(t, client.CreateSession(ctx, NewSessionSpec("s", "dance", 1, pIDs)), "unknown protocol")
+	assert.NoError(t, client.CreateSession(ctx, NewSessionSpec("s", ProtocolSigning, 1, pIDs)))
+
+	_, err := NewClient(server.URL, "t", pIDs[0], pIDs).Status(ctx)
+	assert.Error(t, err, "unknown session")
+	assert.Error(t, NewClient(server.URL, "s", outsider, pIDs).ReportProgress(ctx, 1, false, nil))
+
+	resp, err := http.Post(server.URL+"/sessions/s/messages", "application/json",
+		strings.NewReader(`{"from":"`+PartyKey(pIDs[1])+`","payload":"AAEC"}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "the payload is not an envelope")
+
+	// a poll without messages returns once its wait is over
+	resp, err = http.Get(server.URL + "/sessions/s/messages?party=" + PartyKey(pIDs[1]) + "&wait=10ms")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,123 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"google.golang.org/grpc"
+
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	defaultSendTimeout = 30 * time.Second
+)
+
+type (
+	// Peer is a party and the address of the Server of its host
+	Peer struct {
+		ID   *tss.PartyID
+		Addr string
+	}
+
+	// Client sends the messages of a local party to the servers of its peers
+	Client struct {
+		peers   *transport.Peers
+		conns   map[string]*grpc.ClientConn
+		timeout time.Duration
+	}
+)
+
+// Dial connects to the servers of the peers. The connections are made lazily by gRPC, so Dial does not wait for the
+// servers to be up; pass grpc.WithTransportCredentials to authenticate them.
+func Dial(peers []Peer, opts ...grpc.DialOption) (*Client, error) {
+	ids := make([]*tss.PartyID, len(peers))
+	c := &Client{
+		conns:   make(map[string]*grpc.ClientConn, len(peers)),
+		timeout: defaultSendTimeout,
+	}
+	for i, peer := range peers {
+		if peer.ID == nil {
+			c.Close()
+			return nil, errors.New("peer has no party ID")
+		}
+		conn, err := grpc.Dial(peer.Addr, opts...)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("dial %s at %s: %w", peer.ID, peer.Addr, err)
+		}
+		c.conns[string(peer.ID.KeyInt().Bytes())] = conn
+		ids[i] = peer.ID
+	}
+	c.peers = transport.NewPeers(ids...)
+	return c, nil
+}
+
+// SetTimeout sets how long the delivery of a message to one peer may take, 30 seconds by default
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// Send sends a message to the peers it is addressed to, or to all peers but its sender when it is addressed to all
+func (c *Client) Send(ctx context.Context, msg tss.Message) error {
+	env, err := transport.NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	var result *multierror.Error
+	for _, to := range c.peers.Recipients(msg) {
+		if err := c.deliver(ctx, to, env); err != nil {
+			result = multierror.Append(result, fmt.Errorf("send %s to %s: %w", msg.Type(), to, err))
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+// Run sends the messages of `party` from its out channel until the channel is closed or `ctx` is done. A message
+// that cannot be sent yields an error of the party on `errCh`.
+func (c *Client) Run(ctx context.Context, party tss.Party, outCh <-chan tss.Message, errCh chan<- *tss.Error) {
+	for {
+		select {
+		case msg, ok := <-outCh:
+			if !ok {
+				return
+			}
+			if err := c.Send(ctx, msg); err != nil {
+				errCh <- party.WrapError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Close closes the connections to the peers
+func (c *Client) Close() error {
+	var result *multierror.Error
+	for _, conn := range c.conns {
+		if err := conn.Close(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+func (c *Client) deliver(ctx context.Context, to *tss.PartyID, env *transport.Envelope) error {
+	conn, ok := c.conns[string(to.KeyInt().Bytes())]
+	if !ok {
+		return errors.New("no connection to the party")
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return conn.Invoke(ctx, DeliverMethod, env, new(Ack), grpc.CallContentSubtype(codecName))
+}
@@ -0,0 +1,83 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	. "github.com/bnb-chain/tss-lib/v2/transport/grpc"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestKeygenOverGRPC(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listeners := make(map[string]*bufconn.Listener, len(pIDs))
+	dialer := grpc.WithContextDialer(func(_ context.Context, addr string) (net.Conn, error) {
+		return listeners[addr].Dial()
+	})
+	peers := make([]Peer, len(pIDs))
+	for i, pid := range pIDs {
+		peers[i] = Peer{ID: pid, Addr: pid.Id}
+		listeners[pid.Id] = bufconn.Listen(1 << 20)
+	}
+
+	sessions := make([]*keygen.Session, len(pIDs))
+	for i, pid := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pid, len(pIDs), test.TestThreshold)
+		sessions[i] = keygen.NewSession(params, len(pIDs))
+
+		gs := grpc.NewServer()
+		NewServer(sessions[i], pIDs, errCh).Register(gs)
+		go gs.Serve(listeners[pid.Id])
+		defer gs.Stop()
+
+		client, err := Dial(peers, dialer, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer client.Close()
+		go client.Run(ctx, sessions[i], sessions[i].Out, errCh)
+	}
+	for _, S := range sessions {
+		go func(S *keygen.Session) {
+			if err := S.Start(); err != nil {
+				errCh <- err
+			}
+		}(S)
+	}
+
+	var pub *crypto.ECPoint
+	for _, S := range sessions {
+		select {
+		case save := <-S.End:
+			if pub == nil {
+				pub = save.EDDSAPub
+			}
+			assert.True(t, pub.Equals(save.EDDSAPub))
+		case err := <-errCh:
+			t.Fatalf("keygen failed: %v", err)
+		case <-time.After(time.Minute):
+			t.Fatal("keygen timed out")
+		}
+	}
+}
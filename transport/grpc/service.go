@@ -0,0 +1,152 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package grpc carries the messages of tss-lib parties over gRPC. Every host runs a Server for its party and a
+// Client that sends the messages of its party to the servers of its peers, point-to-point or to all of them.
+//
+// The service is
+//
+//	service Transport {
+//	    rpc Deliver(transport.Envelope) returns (Ack);
+//	}
+//	message Ack {}
+//
+// with the envelope encoded by transport.Envelope.Marshal, under the content subtype "tss-envelope".
+package grpc
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	ServiceName   = "binance.tsslib.transport.Transport"
+	DeliverMethod = "/" + ServiceName + "/Deliver"
+
+	// the content subtype of the calls, which selects envelopeCodec on both ends
+	codecName = "tss-envelope"
+)
+
+type (
+	// Server delivers the envelopes it receives to a party or a tss.SessionManager. The errors of the deliveries are
+	// returned to the sender, and sent on its error channel, like those of the party itself, when it has room for
+	// them: a Server never blocks a call on the channel, so the channel should be buffered.
+	Server struct {
+		updater transport.Updater
+		peers   *transport.Peers
+		errCh   chan<- *tss.Error
+	}
+
+	// the interface that grpc.Server.RegisterService checks Server against
+	transportServer interface {
+		deliver(ctx context.Context, env *transport.Envelope) (*Ack, error)
+	}
+
+	// Ack is the empty reply of Deliver
+	Ack struct{}
+
+	envelopeCodec struct{}
+)
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*transportServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Deliver",
+			Handler:    deliverHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "transport/grpc/service.go",
+}
+
+func init() {
+	encoding.RegisterCodec(envelopeCodec{})
+}
+
+// NewServer returns a server that delivers the messages of `peers` to `updater`, e.g. a LocalParty. For resharing,
+// `peers` holds the parties of both committees.
+func NewServer(updater transport.Updater, peers []*tss.PartyID, errCh chan<- *tss.Error) *Server {
+	return &Server{
+		updater: updater,
+		peers:   transport.NewPeers(peers...),
+		errCh:   errCh,
+	}
+}
+
+// Register registers the service on a gRPC server; it must be called before the server serves
+func (s *Server) Register(gs *grpc.Server) {
+	gs.RegisterService(&serviceDesc, s)
+}
+
+func (s *Server) deliver(_ context.Context, env *transport.Envelope) (*Ack, error) {
+	if err := transport.Deliver(s.updater, s.peers, env); err != nil {
+		if s.errCh != nil {
+			// the error still reaches the sender if no one is reading the channel
+			select {
+			case s.errCh <- err:
+			default:
+			}
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &Ack{}, nil
+}
+
+func deliverHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	env := new(transport.Envelope)
+	if err := dec(env); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if interceptor == nil {
+		return srv.(transportServer).deliver(ctx, env)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DeliverMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(transportServer).deliver(ctx, req.(*transport.Envelope))
+	}
+	return interceptor(ctx, env, info, handler)
+}
+
+// ----- //
+
+func (envelopeCodec) Name() string {
+	return codecName
+}
+
+func (envelopeCodec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case *transport.Envelope:
+		return m.Marshal(), nil
+	case *Ack:
+		return []byte{}, nil
+	default:
+		return nil, fmt.Errorf("%s codec cannot marshal %T", codecName, v)
+	}
+}
+
+func (envelopeCodec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case *transport.Envelope:
+		return m.Unmarshal(data)
+	case *Ack:
+		return nil
+	default:
+		return fmt.Errorf("%s codec cannot unmarshal %T", codecName, v)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package kafka carries the messages of tss-lib parties over Kafka. Every session has a broadcast topic,
+// "<prefix>.broadcast", for the messages addressed to all parties, and a topic per party, "<prefix>.p2p.<hex key>",
+// for the others. The topics have a single partition, so that each is read in the order it was written.
+//
+// Kafka keeps the topics, so a party that crashed and resumes from a tss.Checkpoint reads its topics again from
+// their first offset: the messages recorded in the checkpoint are skipped (see SkipCheckpointed), and those that
+// arrived after it are delivered.
+//
+//	c, _ := tss.NewCheckpointerFrom(cp)
+//	params.SetEntropySource(c.EntropySource())
+//	party := keygen.NewLocalParty(params, outCh, endCh)
+//	adapter.SkipCheckpointed(cp)
+//	go adapter.Run(ctx, party, outCh, errCh)
+//	_ = c.Resume(party)
+//	go adapter.Receive(ctx, transport.Checkpointed(c, party), errCh)
+package kafka
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	defaultWriteTimeout = 30 * time.Second
+)
+
+// Adapter writes the messages of a party to the topics of a session and delivers those written for it
+type Adapter struct {
+	brokers []string
+	prefix  string
+	self    *tss.PartyID
+	peers   *transport.Peers
+	writer  *kafka.Writer
+
+	mtx sync.Mutex
+	// the IDs of the messages delivered, or recorded in the checkpoint the party resumed from
+	seen map[string]struct{}
+	// the offset after the last message read from each topic
+	offsets map[string]int64
+}
+
+// NewAdapter returns an adapter for `self` in the session whose topics start with `prefix`, e.g. "tss.<session id>",
+// on the Kafka cluster of `brokers`. `peers` are the parties of the session, including `self`; for resharing, those
+// of both committees. The topics are created on first use if the cluster allows it.
+func NewAdapter(brokers []string, prefix string, self *tss.PartyID, peers []*tss.PartyID) *Adapter {
+	return &Adapter{
+		brokers: brokers,
+		prefix:  prefix,
+		self:    self,
+		peers:   transport.NewPeers(peers...),
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			RequiredAcks:           kafka.RequireAll,
+			WriteTimeout:           defaultWriteTimeout,
+			AllowAutoTopicCreation: true,
+		},
+		seen:    make(map[string]struct{}),
+		offsets: make(map[string]int64),
+	}
+}
+
+// BroadcastTopic returns the topic of the messages addressed to all parties
+func (a *Adapter) BroadcastTopic() string {
+	return a.prefix + ".broadcast"
+}
+
+// PartyTopic returns the topic of the messages addressed to a party
+func (a *Adapter) PartyTopic(pid *tss.PartyID) string {
+	return a.prefix + ".p2p." + hex.EncodeToString(pid.KeyInt().Bytes())
+}
+
+// SkipCheckpointed makes the adapter skip the messages recorded in `cp`, which the party replays when it resumes;
+// it must be called before Receive
+func (a *Adapter) SkipCheckpointed(cp *tss.Checkpoint) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	for _, m := range cp.Messages {
+		a.seen[messageID(m.FromKey, m.WireBytes)] = struct{}{}
+	}
+}
+
+// Offsets returns the offset after the last message read from each topic of the party
+func (a *Adapter) Offsets() map[string]int64 {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	offsets := make(map[string]int64, len(a.offsets))
+	for topic, offset := range a.offsets {
+		offsets[topic] = offset
+	}
+	return offsets
+}
+
+// Receive reads the broadcast topic and the topic of the adapter's party from their first offset, and passes the
+// messages to `updater`, e.g. a LocalParty or the transport.Checkpointed party, until `ctx` is done. The errors of
+// the deliveries are sent on `errCh`.
+func (a *Adapter) Receive(ctx context.Context, updater transport.Updater, errCh chan<- *tss.Error) error {
+	topics := []string{a.BroadcastTopic(), a.PartyTopic(a.self)}
+	readErrs := make(chan error, len(topics))
+	var wg sync.WaitGroup
+	for _, topic := range topics {
+		r := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   a.brokers,
+			Topic:     topic,
+			Partition: 0,
+			MaxBytes:  10e6,
+		})
+		if err := r.SetOffset(kafka.FirstOffset); err != nil {
+			_ = r.Close()
+			return fmt.Errorf("read %s: %w", topic, err)
+		}
+		wg.Add(1)
+		go func(topic string, r *kafka.Reader) {
+			defer wg.Done()
+			defer r.Close()
+			for {
+				m, err := r.ReadMessage(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						readErrs <- fmt.Errorf("read %s: %w", topic, err)
+					}
+					return
+				}
+				a.handle(topic, m.Offset, m.Value, updater, errCh)
+			}
+		}(topic, r)
+	}
+	wg.Wait()
+	select {
+	case err := <-readErrs:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// Send writes a message to the broadcast topic when it is addressed to all, and to the topic of each of its
+// recipients otherwise
+func (a *Adapter) Send(ctx context.Context, msg tss.Message) error {
+	env, err := transport.NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	value, key := env.Marshal(), []byte(messageID(env.From, env.WireBytes))
+	if msg.GetTo() == nil {
+		return a.writer.WriteMessages(ctx, kafka.Message{Topic: a.BroadcastTopic(), Key: key, Value: value})
+	}
+	msgs := make([]kafka.Message, 0, len(msg.GetTo()))
+	for _, to := range msg.GetTo() {
+		msgs = append(msgs, kafka.Message{Topic: a.PartyTopic(to), Key: key, Value: value})
+	}
+	if err := a.writer.WriteMessages(ctx, msgs...); err != nil {
+		var writeErrs kafka.WriteErrors
+		if !errors.As(err, &writeErrs) {
+			return err
+		}
+		var result *multierror.Error
+		for i, err := range writeErrs {
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf("send %s to %s: %w", msg.Type(), msg.GetTo()[i], err))
+			}
+		}
+		return result.ErrorOrNil()
+	}
+	return nil
+}
+
+// Run sends the messages of `party` from its out channel until the channel is closed or `ctx` is done. A message
+// that cannot be sent yields an error of the party on `errCh`.
+func (a *Adapter) Run(ctx context.Context, party tss.Party, outCh <-chan tss.Message, errCh chan<- *tss.Error) {
+	for {
+		select {
+		case msg, ok := <-outCh:
+			if !ok {
+				return
+			}
+			if err := a.Send(ctx, msg); err != nil {
+				errCh <- party.WrapError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Close flushes and closes the writer of the adapter
+func (a *Adapter) Close() error {
+	return a.writer.Close()
+}
+
+// handle delivers a message read from a topic once: the party's own broadcasts, the copies of messages written
+// twice and the messages replayed from a checkpoint are skipped
+func (a *Adapter) handle(topic string, offset int64, value []byte, updater transport.Updater, errCh chan<- *tss.Error) {
+	a.mtx.Lock()
+	a.offsets[topic] = offset + 1
+	a.mtx.Unlock()
+
+	env := new(transport.Envelope)
+	if err := env.Unmarshal(value); err != nil {
+		errCh <- tss.NewError(err, "transport", -1, nil)
+		return
+	}
+	if new(big.Int).SetBytes(env.From).Cmp(a.self.KeyInt()) == 0 {
+		return
+	}
+	id := messageID(env.From, env.WireBytes)
+	a.mtx.Lock()
+	_, dup := a.seen[id]
+	a.seen[id] = struct{}{}
+	a.mtx.Unlock()
+	if dup {
+		return
+	}
+	if err := transport.Deliver(updater, a.peers, env); err != nil {
+		errCh <- err
+	}
+}
+
+// messageID identifies a message by its sender and wire bytes, as a checkpoint records it; the session is that of
+// the topics
+func messageID(from, wireBytes []byte) string {
+	h := sha256.New()
+	for _, part := range [][]byte{new(big.Int).SetBytes(from).Bytes(), wireBytes} {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(part)))
+		h.Write(length[:])
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
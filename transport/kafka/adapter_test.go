@@ -0,0 +1,82 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package kafka
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+type countingUpdater struct {
+	updates int
+}
+
+func (u *countingUpdater) Update(tss.ParsedMessage) (bool, *tss.Error) {
+	u.updates++
+	return true, nil
+}
+
+func TestReplayedMessagesAreDeliveredOnce(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	S := keygen.NewSession(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), test.TestThreshold), len(pIDs))
+	assert.Nil(t, S.Start())
+	env, err := transport.NewEnvelope(<-S.Out)
+	assert.NoError(t, err)
+
+	errCh := make(chan *tss.Error, 1)
+	updater := new(countingUpdater)
+	receiver := NewAdapter(nil, "tss.test", pIDs[1], pIDs)
+	topic := receiver.BroadcastTopic()
+	receiver.handle(topic, 0, env.Marshal(), updater, errCh)
+	// read again from the first offset, e.g. after the reader reconnected
+	receiver.handle(topic, 0, env.Marshal(), updater, errCh)
+	assert.Equal(t, 1, updater.updates, "the copy should be dropped")
+	assert.Len(t, errCh, 0)
+	assert.Equal(t, map[string]int64{topic: 1}, receiver.Offsets())
+
+	// the sender does not deliver its own broadcasts to itself
+	sender := NewAdapter(nil, "tss.test", pIDs[0], pIDs)
+	sender.handle(topic, 0, env.Marshal(), updater, errCh)
+	assert.Equal(t, 1, updater.updates)
+
+	assert.Equal(t, "tss.test.broadcast", topic)
+	assert.Equal(t, "tss.test.p2p."+hex.EncodeToString(pIDs[2].KeyInt().Bytes()), receiver.PartyTopic(pIDs[2]))
+}
+
+func TestCheckpointedMessagesAreSkipped(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	S := keygen.NewSession(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), test.TestThreshold), len(pIDs))
+	assert.Nil(t, S.Start())
+	msg := <-S.Out
+	env, err := transport.NewEnvelope(msg)
+	assert.NoError(t, err)
+
+	cp := &tss.Checkpoint{Messages: []tss.CheckpointMessage{{
+		WireBytes:   env.WireBytes,
+		FromID:      pIDs[0].Id,
+		FromMoniker: pIDs[0].Moniker,
+		FromKey:     pIDs[0].Key,
+		FromIndex:   pIDs[0].Index,
+		IsBroadcast: env.IsBroadcast,
+	}}}
+	errCh := make(chan *tss.Error, 1)
+	updater := new(countingUpdater)
+	receiver := NewAdapter(nil, "tss.test", pIDs[1], pIDs)
+	receiver.SkipCheckpointed(cp)
+	receiver.handle(receiver.BroadcastTopic(), 0, env.Marshal(), updater, errCh)
+	assert.Equal(t, 0, updater.updates, "the party replays the message from its checkpoint")
+	assert.Len(t, errCh, 0)
+}
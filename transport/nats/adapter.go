@@ -0,0 +1,240 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package nats carries the messages of tss-lib parties over NATS. Messages addressed to all parties are published on
+// the broadcast subject of a session, "<prefix>.broadcast", and the others on the subject of each recipient,
+// "<prefix>.p2p.<hex key>". With JetStream, delivery is at least once, and the adapter drops the copies of a
+// message that it has already delivered.
+package nats
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/nats-io/nats.go"
+
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	// the number of message IDs remembered to drop redelivered copies
+	defaultDedupWindow = 4096
+)
+
+type (
+	// Adapter publishes the messages of a party on the subjects of a session and delivers those published for it
+	Adapter struct {
+		nc     *nats.Conn
+		js     nats.JetStreamContext
+		prefix string
+		self   *tss.PartyID
+		peers  *transport.Peers
+
+		updater transport.Updater
+		errCh   chan<- *tss.Error
+		subs    []*nats.Subscription
+
+		mtx  sync.Mutex
+		seen *dedup
+	}
+
+	// dedup remembers the IDs of the last `size` messages
+	dedup struct {
+		ids   map[string]struct{}
+		order []string
+		next  int
+	}
+)
+
+// NewAdapter returns an adapter for `self` in the session whose subjects start with `prefix`, e.g. "tss.<session
+// id>". `peers` are the parties of the session, including `self`; for resharing, those of both committees.
+func NewAdapter(nc *nats.Conn, prefix string, self *tss.PartyID, peers []*tss.PartyID) *Adapter {
+	return &Adapter{
+		nc:     nc,
+		prefix: prefix,
+		self:   self,
+		peers:  transport.NewPeers(peers...),
+		seen:   newDedup(defaultDedupWindow),
+	}
+}
+
+// UseJetStream makes the adapter publish and subscribe through JetStream, for at-least-once delivery. The subjects
+// of the session must be bound to a stream, whose duplicate window also drops messages published twice. It must be
+// called before Subscribe.
+func (a *Adapter) UseJetStream(js nats.JetStreamContext) {
+	a.js = js
+}
+
+// SetDedupWindow sets how many message IDs the adapter remembers to drop redelivered copies; it must be called
+// before Subscribe
+func (a *Adapter) SetDedupWindow(size int) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.seen = newDedup(size)
+}
+
+// BroadcastSubject returns the subject of the messages addressed to all parties
+func (a *Adapter) BroadcastSubject() string {
+	return a.prefix + ".broadcast"
+}
+
+// PartySubject returns the subject of the messages addressed to a party
+func (a *Adapter) PartySubject(pid *tss.PartyID) string {
+	return a.prefix + ".p2p." + hex.EncodeToString(pid.KeyInt().Bytes())
+}
+
+// Subscribe subscribes to the broadcast subject and the subject of the adapter's party, and passes the messages
+// received on them to `updater`, e.g. a LocalParty. The errors of the deliveries are sent on `errCh`.
+func (a *Adapter) Subscribe(updater transport.Updater, errCh chan<- *tss.Error) error {
+	a.updater, a.errCh = updater, errCh
+	for _, subject := range []string{a.BroadcastSubject(), a.PartySubject(a.self)} {
+		var sub *nats.Subscription
+		var err error
+		if a.js != nil {
+			sub, err = a.js.Subscribe(subject, a.handle, nats.ManualAck())
+		} else {
+			sub, err = a.nc.Subscribe(subject, a.handle)
+		}
+		if err != nil {
+			_ = a.Unsubscribe()
+			return fmt.Errorf("subscribe to %s: %w", subject, err)
+		}
+		a.subs = append(a.subs, sub)
+	}
+	return nil
+}
+
+// Unsubscribe stops the delivery of messages to the party
+func (a *Adapter) Unsubscribe() error {
+	var result *multierror.Error
+	for _, sub := range a.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	a.subs = nil
+	return result.ErrorOrNil()
+}
+
+// Send publishes a message on the broadcast subject when it is addressed to all, and on the subject of each of its
+// recipients otherwise
+func (a *Adapter) Send(msg tss.Message) error {
+	env, err := transport.NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	data, id := env.Marshal(), messageID(env)
+	if msg.GetTo() == nil {
+		return a.publish(a.BroadcastSubject(), id, data)
+	}
+	var result *multierror.Error
+	for _, to := range msg.GetTo() {
+		if err := a.publish(a.PartySubject(to), id, data); err != nil {
+			result = multierror.Append(result, fmt.Errorf("send %s to %s: %w", msg.Type(), to, err))
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+// Run sends the messages of `party` from its out channel until the channel is closed or `ctx` is done. A message
+// that cannot be sent yields an error of the party on `errCh`.
+func (a *Adapter) Run(ctx context.Context, party tss.Party, outCh <-chan tss.Message, errCh chan<- *tss.Error) {
+	for {
+		select {
+		case msg, ok := <-outCh:
+			if !ok {
+				return
+			}
+			if err := a.Send(msg); err != nil {
+				errCh <- party.WrapError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *Adapter) publish(subject, id string, data []byte) error {
+	if a.js == nil {
+		return a.nc.Publish(subject, data)
+	}
+	// the duplicate window of a stream spans its subjects, and a message sent to several parties is one per subject
+	_, err := a.js.Publish(subject, data, nats.MsgId(id+"/"+subject))
+	return err
+}
+
+// handle delivers a received message once; a message that cannot be delivered is acknowledged all the same, as a
+// redelivery would fail again
+func (a *Adapter) handle(m *nats.Msg) {
+	defer func() {
+		if a.js != nil {
+			_ = m.Ack()
+		}
+	}()
+	env := new(transport.Envelope)
+	if err := env.Unmarshal(m.Data); err != nil {
+		a.errCh <- tss.NewError(err, "transport", -1, nil)
+		return
+	}
+	// the party's own broadcasts come back to it
+	if new(big.Int).SetBytes(env.From).Cmp(a.self.KeyInt()) == 0 {
+		return
+	}
+	a.mtx.Lock()
+	dup := !a.seen.add(messageID(env) + "/" + m.Subject)
+	a.mtx.Unlock()
+	if dup {
+		return
+	}
+	if err := transport.Deliver(a.updater, a.peers, env); err != nil {
+		a.errCh <- err
+	}
+}
+
+// messageID identifies a message across retries: its sender, session and wire bytes
+func messageID(env *transport.Envelope) string {
+	h := sha256.New()
+	for _, part := range [][]byte{env.From, env.SessionID, env.WireBytes} {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(part)))
+		h.Write(length[:])
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ----- //
+
+func newDedup(size int) *dedup {
+	if size <= 0 {
+		size = defaultDedupWindow
+	}
+	return &dedup{
+		ids:   make(map[string]struct{}, size),
+		order: make([]string, size),
+	}
+}
+
+// add records an ID, forgetting the oldest one when the window is full; it returns false if the ID was seen
+func (d *dedup) add(id string) bool {
+	if _, ok := d.ids[id]; ok {
+		return false
+	}
+	if old := d.order[d.next]; old != "" {
+		delete(d.ids, old)
+	}
+	d.order[d.next] = id
+	d.next = (d.next + 1) % len(d.order)
+	d.ids[id] = struct{}{}
+	return true
+}
@@ -0,0 +1,67 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package nats
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+type countingUpdater struct {
+	updates int
+}
+
+func (u *countingUpdater) Update(tss.ParsedMessage) (bool, *tss.Error) {
+	u.updates++
+	return true, nil
+}
+
+func TestRedeliveredMessagesAreDropped(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	S := keygen.NewSession(tss.NewParameters(tss.Edwards(), p2pCtx, pIDs[0], len(pIDs), test.TestThreshold), len(pIDs))
+	assert.Nil(t, S.Start())
+	env, err := transport.NewEnvelope(<-S.Out)
+	assert.NoError(t, err)
+
+	errCh := make(chan *tss.Error, 1)
+	receiver := NewAdapter(nil, "tss.test", pIDs[1], pIDs)
+	updater := new(countingUpdater)
+	receiver.updater, receiver.errCh = updater, errCh
+
+	msg := &nats.Msg{Subject: receiver.BroadcastSubject(), Data: env.Marshal()}
+	receiver.handle(msg)
+	receiver.handle(msg)
+	assert.Equal(t, 1, updater.updates, "the copy should be dropped")
+	assert.Len(t, errCh, 0)
+
+	// the sender does not deliver its own broadcasts to itself
+	sender := NewAdapter(nil, "tss.test", pIDs[0], pIDs)
+	sender.updater, sender.errCh = updater, errCh
+	sender.handle(msg)
+	assert.Equal(t, 1, updater.updates)
+
+	assert.Equal(t, "tss.test.p2p."+hex.EncodeToString(pIDs[2].KeyInt().Bytes()), receiver.PartySubject(pIDs[2]))
+}
+
+func TestDedupWindow(t *testing.T) {
+	d := newDedup(2)
+	assert.True(t, d.add("a"))
+	assert.True(t, d.add("b"))
+	assert.False(t, d.add("a"))
+	assert.True(t, d.add("c"))
+	assert.True(t, d.add("a"), "a has left the window")
+	assert.False(t, d.add("c"))
+}
@@ -0,0 +1,341 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package noise encrypts the messages between two parties with a channel established by the Noise XX handshake
+// (Noise_XX_25519_ChaChaPoly_SHA256). The static key of each party is the X25519 form of its ed25519 identity key,
+// so a party knows that only the peer holding the identity key of its PartyID can read what it sends, e.g. the VSS
+// shares of keygen, even when a relay carries the messages.
+//
+// The party with the lower key initiates. The handshake takes three messages, which the caller carries over its
+// transport like any other: the initiator sends the result of Start, and either side passes what it receives to
+// Handshake and sends its reply, if any, until Established. Sealed messages carry their nonce, so they may be
+// delivered out of order, but never twice.
+package noise
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/flynn/noise"
+	"golang.org/x/crypto/curve25519"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+const (
+	prologueTag = "tss-lib noise channel"
+	nonceSize   = 8
+)
+
+var (
+	cipherSuite = noise.NewCipherSuite(noise.DH25519, noise.CipherChaChaPoly, noise.HashSHA256)
+
+	// the prime of curve25519, 2^255 - 19
+	fieldPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+	// ErrNotEstablished is returned by Seal and Open before the handshake has completed
+	ErrNotEstablished = errors.New("noise: the channel is not established")
+)
+
+type (
+	// Channel is the encrypted channel of a party with one peer
+	Channel struct {
+		mtx       sync.Mutex
+		peer      *tss.PartyID
+		initiator bool
+		hs        *noise.HandshakeState
+		// the handshake messages processed so far
+		step      int
+		send      noise.Cipher
+		recv      noise.Cipher
+		nextNonce uint64
+		// the nonces of the messages opened so far, to refuse replays
+		opened map[uint64]struct{}
+	}
+
+	// Channels holds the channels of a party with each of its peers
+	Channels struct {
+		self     *tss.PartyID
+		channels map[string]*Channel
+	}
+)
+
+// NewChannel returns the channel of the party `self`, whose identity key is `identity`, with `peer`, whose
+// IdentityKey must be set
+func NewChannel(self *tss.PartyID, identity ed25519.PrivateKey, peer *tss.PartyID) (*Channel, error) {
+	if len(identity) != ed25519.PrivateKeySize {
+		return nil, errors.New("noise: invalid identity key")
+	}
+	if len(peer.IdentityKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("noise: party %s has no identity key", peer)
+	}
+	static, err := staticKeypair(identity)
+	if err != nil {
+		return nil, err
+	}
+	initiator := self.KeyInt().Cmp(peer.KeyInt()) < 0
+	hs, err := noise.NewHandshakeState(noise.Config{
+		CipherSuite:   cipherSuite,
+		Random:        rand.Reader,
+		Pattern:       noise.HandshakeXX,
+		Initiator:     initiator,
+		Prologue:      prologue(self, peer),
+		StaticKeypair: static,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Channel{
+		peer:      peer,
+		initiator: initiator,
+		hs:        hs,
+		opened:    make(map[uint64]struct{}),
+	}, nil
+}
+
+// Initiator returns whether this side of the channel starts the handshake
+func (c *Channel) Initiator() bool {
+	return c.initiator
+}
+
+// Start returns the first handshake message, for the initiator to send to the peer
+func (c *Channel) Start() ([]byte, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !c.initiator || c.step != 0 {
+		return nil, errors.New("noise: only the initiator starts the handshake, once")
+	}
+	msg, _, _, err := c.hs.WriteMessage(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.step++
+	return msg, nil
+}
+
+// Handshake processes a handshake message from the peer and returns the reply to send it, or nil when there is none
+func (c *Channel) Handshake(in []byte) ([]byte, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.send != nil {
+		return nil, errors.New("noise: the channel is already established")
+	}
+	// the initiator reads the second message and the responder the first and the third
+	if c.initiator != (c.step == 1) {
+		return nil, errors.New("noise: unexpected handshake message")
+	}
+	_, cs1, cs2, err := c.hs.ReadMessage(nil, in)
+	if err != nil {
+		return nil, fmt.Errorf("noise: handshake with %s: %w", c.peer, err)
+	}
+	c.step++
+	if cs1 != nil {
+		return nil, c.established(cs1, cs2)
+	}
+	out, cs1, cs2, err := c.hs.WriteMessage(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.step++
+	if cs1 != nil {
+		if err := c.established(cs1, cs2); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Established returns whether the handshake has completed
+func (c *Channel) Established() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.send != nil
+}
+
+// Seal encrypts and authenticates bytes for the peer, e.g. the wire bytes of a message
+func (c *Channel) Seal(plaintext []byte) ([]byte, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.send == nil {
+		return nil, ErrNotEstablished
+	}
+	n := c.nextNonce
+	c.nextNonce++
+	out := make([]byte, nonceSize, nonceSize+len(plaintext)+16)
+	binary.BigEndian.PutUint64(out, n)
+	return c.send.Encrypt(out, n, nil, plaintext), nil
+}
+
+// Open decrypts bytes sealed by the peer; bytes that were tampered with, or that were opened before, are refused
+func (c *Channel) Open(sealed []byte) ([]byte, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.recv == nil {
+		return nil, ErrNotEstablished
+	}
+	if len(sealed) < nonceSize {
+		return nil, errors.New("noise: sealed bytes are too short")
+	}
+	n := binary.BigEndian.Uint64(sealed)
+	if _, ok := c.opened[n]; ok {
+		return nil, errors.New("noise: the message was replayed")
+	}
+	plaintext, err := c.recv.Decrypt(nil, n, nil, sealed[nonceSize:])
+	if err != nil {
+		return nil, fmt.Errorf("noise: message from %s: %w", c.peer, err)
+	}
+	c.opened[n] = struct{}{}
+	return plaintext, nil
+}
+
+// established checks that the peer authenticated with its identity key and keeps the ciphers of the transport phase
+func (c *Channel) established(cs1, cs2 *noise.CipherState) error {
+	expected, err := x25519PublicKey(c.peer.IdentityKey)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(c.hs.PeerStatic(), expected) {
+		return fmt.Errorf("noise: the peer did not authenticate with the identity key of %s", c.peer)
+	}
+	if c.initiator {
+		c.send, c.recv = cs1.Cipher(), cs2.Cipher()
+	} else {
+		c.send, c.recv = cs2.Cipher(), cs1.Cipher()
+	}
+	return nil
+}
+
+// ----- //
+
+// NewChannels returns the channels of the party `self` with every other party of `peers`
+func NewChannels(self *tss.PartyID, identity ed25519.PrivateKey, peers []*tss.PartyID) (*Channels, error) {
+	cs := &Channels{
+		self:     self,
+		channels: make(map[string]*Channel, len(peers)),
+	}
+	for _, peer := range peers {
+		if peer.KeyInt().Cmp(self.KeyInt()) == 0 {
+			continue
+		}
+		c, err := NewChannel(self, identity, peer)
+		if err != nil {
+			return nil, err
+		}
+		cs.channels[string(peer.KeyInt().Bytes())] = c
+	}
+	return cs, nil
+}
+
+// Channel returns the channel with a peer, or nil if it is not a peer
+func (cs *Channels) Channel(peer *tss.PartyID) *Channel {
+	return cs.channels[string(peer.KeyInt().Bytes())]
+}
+
+// Start returns the first handshake message of every channel that this party initiates, by peer
+func (cs *Channels) Start() (map[*tss.PartyID][]byte, error) {
+	msgs := make(map[*tss.PartyID][]byte)
+	for _, c := range cs.channels {
+		if !c.Initiator() {
+			continue
+		}
+		msg, err := c.Start()
+		if err != nil {
+			return nil, err
+		}
+		msgs[c.peer] = msg
+	}
+	return msgs, nil
+}
+
+// Established returns whether every channel is established
+func (cs *Channels) Established() bool {
+	for _, c := range cs.channels {
+		if !c.Established() {
+			return false
+		}
+	}
+	return true
+}
+
+// SealWireBytes encrypts the wire bytes of a message for the party it is sent to
+func (cs *Channels) SealWireBytes(to *tss.PartyID, wireBytes []byte) ([]byte, error) {
+	c := cs.Channel(to)
+	if c == nil {
+		return nil, fmt.Errorf("noise: no channel with %s", to)
+	}
+	return c.Seal(wireBytes)
+}
+
+// OpenWireBytes decrypts the wire bytes of a message from a peer, to be passed to ParseWireMessage
+func (cs *Channels) OpenWireBytes(from *tss.PartyID, sealed []byte) ([]byte, error) {
+	c := cs.Channel(from)
+	if c == nil {
+		return nil, fmt.Errorf("noise: no channel with %s", from)
+	}
+	return c.Open(sealed)
+}
+
+// ----- //
+
+// staticKeypair returns the X25519 form of an ed25519 key, whose scalar is the clamped first half of the hash of the seed
+func staticKeypair(identity ed25519.PrivateKey) (noise.DHKey, error) {
+	h := sha512.Sum512(identity.Seed())
+	private := h[:32]
+	public, err := curve25519.X25519(private, curve25519.Basepoint)
+	if err != nil {
+		return noise.DHKey{}, err
+	}
+	return noise.DHKey{Private: private, Public: public}, nil
+}
+
+// x25519PublicKey maps an ed25519 public key to the Montgomery form of the same point, u = (1 + y) / (1 - y)
+func x25519PublicKey(pub ed25519.PublicKey) ([]byte, error) {
+	le := make([]byte, len(pub))
+	for i := range pub {
+		le[len(pub)-1-i] = pub[i]
+	}
+	le[0] &= 0x7f // the sign of x
+	y := new(big.Int).SetBytes(le)
+	if y.Cmp(fieldPrime) >= 0 {
+		return nil, errors.New("noise: invalid ed25519 public key")
+	}
+	den := new(big.Int).Sub(big.NewInt(1), y)
+	den.Mod(den, fieldPrime)
+	if den.Sign() == 0 {
+		return nil, errors.New("noise: invalid ed25519 public key")
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, den.ModInverse(den, fieldPrime))
+	u.Mod(u, fieldPrime)
+	out := make([]byte, 32)
+	u.FillBytes(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, nil
+}
+
+// prologue binds the handshake to the keys of the two parties
+func prologue(self, peer *tss.PartyID) []byte {
+	lo, hi := self.KeyInt(), peer.KeyInt()
+	if lo.Cmp(hi) > 0 {
+		lo, hi = hi, lo
+	}
+	p := []byte(prologueTag)
+	for _, k := range []*big.Int{lo, hi} {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(k.Bytes())))
+		p = append(append(p, length[:]...), k.Bytes()...)
+	}
+	return p
+}
@@ -0,0 +1,155 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package noise_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/transport/noise"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func newIdentities(t *testing.T, n int) ([]ed25519.PrivateKey, tss.SortedPartyIDs) {
+	keys := make([]ed25519.PrivateKey, n)
+	ids := make(tss.UnSortedPartyIDs, n)
+	for i := range keys {
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		keys[i], ids[i] = priv, tss.NewPartyIDFromIdentityKey(pub)
+	}
+	return keys, tss.SortPartyIDs(ids)
+}
+
+// handshake runs the handshake of a pair of channels to completion
+func handshake(t *testing.T, a, b *Channel) error {
+	if !a.Initiator() {
+		a, b = b, a
+	}
+	msg, err := a.Start()
+	assert.NoError(t, err)
+	for from, to := a, b; msg != nil; from, to = to, from {
+		if msg, err = to.Handshake(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestChannel(t *testing.T) {
+	keys, pIDs := newIdentities(t, 2)
+	var priv [2]ed25519.PrivateKey
+	for i, pid := range pIDs {
+		for _, key := range keys {
+			if key.Public().(ed25519.PublicKey).Equal(pid.IdentityKey) {
+				priv[i] = key
+			}
+		}
+	}
+	a, err := NewChannel(pIDs[0], priv[0], pIDs[1])
+	assert.NoError(t, err)
+	b, err := NewChannel(pIDs[1], priv[1], pIDs[0])
+	assert.NoError(t, err)
+	assert.True(t, a.Initiator() != b.Initiator())
+
+	_, err = a.Seal([]byte("early"))
+	assert.ErrorIs(t, err, ErrNotEstablished)
+	assert.NoError(t, handshake(t, a, b))
+	assert.True(t, a.Established())
+	assert.True(t, b.Established())
+
+	// larger than a Noise message, and opened out of order
+	big := make([]byte, 100000)
+	_, _ = rand.Read(big)
+	first, err := a.Seal([]byte("share"))
+	assert.NoError(t, err)
+	second, err := a.Seal(big)
+	assert.NoError(t, err)
+	pt, err := b.Open(second)
+	assert.NoError(t, err)
+	assert.Equal(t, big, pt)
+	pt, err = b.Open(first)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("share"), pt)
+
+	_, err = b.Open(first)
+	assert.Error(t, err, "replayed")
+	reply, err := b.Seal([]byte("reply"))
+	assert.NoError(t, err)
+	reply[len(reply)-1] ^= 1
+	_, err = a.Open(reply)
+	assert.Error(t, err, "tampered")
+}
+
+func TestChannelRejectsOtherIdentity(t *testing.T) {
+	keys, pIDs := newIdentities(t, 2)
+	_, impostor, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	var a, b *Channel
+	for _, key := range keys {
+		if key.Public().(ed25519.PublicKey).Equal(pIDs[0].IdentityKey) {
+			a, err = NewChannel(pIDs[0], key, pIDs[1])
+			assert.NoError(t, err)
+		}
+	}
+	// the peer claims to be pIDs[1] without its identity key
+	b, err = NewChannel(pIDs[1], impostor, pIDs[0])
+	assert.NoError(t, err)
+	assert.Error(t, handshake(t, a, b))
+	assert.False(t, a.Established())
+}
+
+func TestChannels(t *testing.T) {
+	keys, pIDs := newIdentities(t, 3)
+	channels := make(map[*tss.PartyID]*Channels, len(pIDs))
+	for _, key := range keys {
+		self := pIDs[0]
+		for _, pid := range pIDs {
+			if key.Public().(ed25519.PublicKey).Equal(pid.IdentityKey) {
+				self = pid
+			}
+		}
+		cs, err := NewChannels(self, key, pIDs)
+		assert.NoError(t, err)
+		channels[self] = cs
+	}
+	type handshakeMsg struct {
+		from, to *tss.PartyID
+		bz       []byte
+	}
+	var pending []handshakeMsg
+	for self, cs := range channels {
+		msgs, err := cs.Start()
+		assert.NoError(t, err)
+		for to, bz := range msgs {
+			pending = append(pending, handshakeMsg{self, to, bz})
+		}
+	}
+	for len(pending) > 0 {
+		msg := pending[0]
+		pending = pending[1:]
+		reply, err := channels[msg.to].Channel(msg.from).Handshake(msg.bz)
+		assert.NoError(t, err)
+		if reply != nil {
+			pending = append(pending, handshakeMsg{msg.to, msg.from, reply})
+		}
+	}
+	for _, cs := range channels {
+		assert.True(t, cs.Established())
+	}
+
+	sealed, err := channels[pIDs[0]].SealWireBytes(pIDs[2], []byte("wire bytes"))
+	assert.NoError(t, err)
+	_, err = channels[pIDs[1]].OpenWireBytes(pIDs[0], sealed)
+	assert.Error(t, err, "sealed for another party")
+	pt, err := channels[pIDs[2]].OpenWireBytes(pIDs[0], sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("wire bytes"), pt)
+}
@@ -0,0 +1,251 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package transport holds what the network transports of tss-lib share: the envelope a message travels in, and the
+// delivery of a received envelope to a party, so that each transport need only move envelopes between hosts.
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// the fields of an encoded Envelope; the encoding is that of the protobuf message
+//
+//	message Envelope {
+//	    bytes from = 1;
+//	    bool is_broadcast = 2;
+//	    bytes session_id = 3;
+//	    map<string, string> trace_context = 4;
+//	    bytes wire_bytes = 5;
+//	}
+const (
+	fieldFrom         protowire.Number = 1
+	fieldIsBroadcast  protowire.Number = 2
+	fieldSessionID    protowire.Number = 3
+	fieldTraceContext protowire.Number = 4
+	fieldWireBytes    protowire.Number = 5
+
+	fieldMapKey   protowire.Number = 1
+	fieldMapValue protowire.Number = 2
+)
+
+type (
+	// Envelope is a message as it travels between hosts: its wire bytes along with the routing that ParseWireMessage
+	// needs on the other end
+	Envelope struct {
+		// the key of the sending party; the receiver looks the party up among its own peers by it
+		From         []byte
+		IsBroadcast  bool
+		SessionID    []byte
+		TraceContext map[string]string
+		WireBytes    []byte
+	}
+
+	// Updater is what a transport delivers the messages it receives to: a tss.Party or a tss.SessionManager
+	Updater interface {
+		Update(msg tss.ParsedMessage) (ok bool, err *tss.Error)
+	}
+
+	// Peers indexes the parties a host exchanges messages with by their keys. For resharing it holds the parties of
+	// both committees.
+	Peers struct {
+		byKey map[string]*tss.PartyID
+		all   []*tss.PartyID
+	}
+)
+
+// NewEnvelope returns the envelope of a message produced by a local party
+func NewEnvelope(msg tss.Message) (*Envelope, error) {
+	bz, routing, err := msg.WireBytes()
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		From:         msg.GetFrom().KeyInt().Bytes(),
+		IsBroadcast:  msg.IsBroadcast(),
+		SessionID:    routing.SessionID,
+		TraceContext: routing.TraceContext,
+		WireBytes:    bz,
+	}, nil
+}
+
+// Marshal encodes the envelope
+func (env *Envelope) Marshal() []byte {
+	var bz []byte
+	bz = protowire.AppendTag(bz, fieldFrom, protowire.BytesType)
+	bz = protowire.AppendBytes(bz, env.From)
+	if env.IsBroadcast {
+		bz = protowire.AppendTag(bz, fieldIsBroadcast, protowire.VarintType)
+		bz = protowire.AppendVarint(bz, 1)
+	}
+	if len(env.SessionID) > 0 {
+		bz = protowire.AppendTag(bz, fieldSessionID, protowire.BytesType)
+		bz = protowire.AppendBytes(bz, env.SessionID)
+	}
+	for k, v := range env.TraceContext {
+		var entry []byte
+		entry = protowire.AppendTag(entry, fieldMapKey, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, fieldMapValue, protowire.BytesType)
+		entry = protowire.AppendString(entry, v)
+		bz = protowire.AppendTag(bz, fieldTraceContext, protowire.BytesType)
+		bz = protowire.AppendBytes(bz, entry)
+	}
+	bz = protowire.AppendTag(bz, fieldWireBytes, protowire.BytesType)
+	return protowire.AppendBytes(bz, env.WireBytes)
+}
+
+// Unmarshal decodes an envelope encoded by Marshal; unknown fields are skipped
+func (env *Envelope) Unmarshal(bz []byte) error {
+	*env = Envelope{}
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		switch {
+		case num == fieldIsBroadcast && typ == protowire.VarintType:
+			var v uint64
+			if v, n = protowire.ConsumeVarint(bz); n < 0 {
+				return protowire.ParseError(n)
+			}
+			env.IsBroadcast = v != 0
+		case typ == protowire.BytesType:
+			var v []byte
+			if v, n = protowire.ConsumeBytes(bz); n < 0 {
+				return protowire.ParseError(n)
+			}
+			if err := env.setBytesField(num, v); err != nil {
+				return err
+			}
+		default:
+			if n = protowire.ConsumeFieldValue(num, typ, bz); n < 0 {
+				return protowire.ParseError(n)
+			}
+		}
+		bz = bz[n:]
+	}
+	if len(env.From) == 0 {
+		return errors.New("envelope has no sender")
+	}
+	return nil
+}
+
+func (env *Envelope) setBytesField(num protowire.Number, v []byte) error {
+	switch num {
+	case fieldFrom:
+		env.From = append([]byte(nil), v...)
+	case fieldSessionID:
+		env.SessionID = append([]byte(nil), v...)
+	case fieldWireBytes:
+		env.WireBytes = append([]byte(nil), v...)
+	case fieldTraceContext:
+		var k, val string
+		for len(v) > 0 {
+			num, typ, n := protowire.ConsumeTag(v)
+			if n < 0 || typ != protowire.BytesType {
+				return errors.New("envelope has a malformed trace context")
+			}
+			v = v[n:]
+			s, n := protowire.ConsumeString(v)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			v = v[n:]
+			if num == fieldMapKey {
+				k = s
+			} else if num == fieldMapValue {
+				val = s
+			}
+		}
+		if env.TraceContext == nil {
+			env.TraceContext = make(map[string]string)
+		}
+		env.TraceContext[k] = val
+	}
+	return nil
+}
+
+// ----- //
+
+// NewPeers indexes the parties by their keys
+func NewPeers(parties ...*tss.PartyID) *Peers {
+	peers := &Peers{byKey: make(map[string]*tss.PartyID, len(parties))}
+	for _, pid := range parties {
+		peers.byKey[string(pid.KeyInt().Bytes())] = pid
+		peers.all = append(peers.all, pid)
+	}
+	return peers
+}
+
+// FindByKey returns the party with the encoded key, or nil if it is not a peer
+func (peers *Peers) FindByKey(key []byte) *tss.PartyID {
+	return peers.byKey[string(new(big.Int).SetBytes(key).Bytes())]
+}
+
+// Recipients returns the parties a message must be sent to: those it is addressed to, or every other peer when it
+// is addressed to all
+func (peers *Peers) Recipients(msg tss.Message) []*tss.PartyID {
+	if to := msg.GetTo(); to != nil {
+		return to
+	}
+	from := string(msg.GetFrom().KeyInt().Bytes())
+	recipients := make([]*tss.PartyID, 0, len(peers.all))
+	for _, pid := range peers.all {
+		if string(pid.KeyInt().Bytes()) != from {
+			recipients = append(recipients, pid)
+		}
+	}
+	return recipients
+}
+
+// Parse parses the message in a received envelope. The sender is the peer with the key of the envelope, so that its
+// identity key, if any, authenticates the message.
+func (peers *Peers) Parse(env *Envelope) (tss.ParsedMessage, error) {
+	from := peers.FindByKey(env.From)
+	if from == nil {
+		return nil, fmt.Errorf("envelope from unknown party %x", env.From)
+	}
+	msg, err := tss.ParseWireMessageInSession(env.WireBytes, from, env.IsBroadcast, env.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	return tss.WithTraceContext(msg, env.TraceContext), nil
+}
+
+// Checkpointed returns an updater that updates `party` through the checkpointer, so that the messages the party
+// accepts are recorded for it to resume from
+func Checkpointed(c *tss.Checkpointer, party tss.Party) Updater {
+	return checkpointedUpdater{c: c, party: party}
+}
+
+type checkpointedUpdater struct {
+	c     *tss.Checkpointer
+	party tss.Party
+}
+
+func (u checkpointedUpdater) Update(msg tss.ParsedMessage) (bool, *tss.Error) {
+	return u.c.Update(u.party, msg)
+}
+
+// Deliver parses the message in a received envelope and updates the party, or the tss.SessionManager, with it
+func Deliver(updater Updater, peers *Peers, env *Envelope) *tss.Error {
+	msg, err := peers.Parse(env)
+	if err != nil {
+		return tss.NewError(err, "transport", -1, nil)
+	}
+	if _, err := updater.Update(msg); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package transport_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestEnvelopeMarshal(t *testing.T) {
+	env := &Envelope{
+		From:         []byte{1, 2, 3},
+		IsBroadcast:  true,
+		SessionID:    []byte("session"),
+		TraceContext: map[string]string{"traceparent": "00-01", "tracestate": ""},
+		WireBytes:    []byte{0, 2, 1, 42},
+	}
+	var decoded Envelope
+	assert.NoError(t, decoded.Unmarshal(env.Marshal()))
+	assert.Equal(t, *env, decoded)
+
+	env = &Envelope{From: []byte{1}, WireBytes: []byte{}}
+	assert.NoError(t, decoded.Unmarshal(env.Marshal()))
+	assert.False(t, decoded.IsBroadcast)
+	assert.Nil(t, decoded.SessionID)
+	assert.Nil(t, decoded.TraceContext)
+
+	assert.Error(t, decoded.Unmarshal([]byte{0x2a, 0x01, 0x00}), "no sender")
+	assert.Error(t, decoded.Unmarshal([]byte{0x0a, 0x05, 0x01}), "truncated")
+}
+
+func TestPeersRecipients(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(3)
+	peers := NewPeers(pIDs...)
+	assert.Equal(t, pIDs[1], peers.FindByKey(pIDs[1].KeyInt().Bytes()))
+	assert.Nil(t, peers.FindByKey([]byte{0xff}))
+
+	broadcast := tss.NewMessage(tss.MessageRouting{From: pIDs[0], IsBroadcast: true}, nil, nil)
+	assert.Equal(t, []*tss.PartyID{pIDs[1], pIDs[2]}, peers.Recipients(broadcast))
+	p2p := tss.NewMessage(tss.MessageRouting{From: pIDs[0], To: []*tss.PartyID{pIDs[2]}}, nil, nil)
+	assert.Equal(t, []*tss.PartyID{pIDs[2]}, peers.Recipients(p2p))
+}
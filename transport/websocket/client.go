@@ -0,0 +1,130 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package websocket
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/bnb-chain/tss-lib/v2/transport"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// Client is the connection of a party to a relay, in one session. It sends the messages of its party with Run and
+// passes those of its peers to the party with Receive.
+type Client struct {
+	conn  *websocket.Conn
+	peers *transport.Peers
+
+	wmtx sync.Mutex
+}
+
+// Dial connects to the relay at `url`, e.g. "wss://relay.example.com/tss", and joins `session` as `self`. `peers`
+// are the parties of the session, including `self`; for resharing, those of both committees.
+func Dial(ctx context.Context, url, session string, self *tss.PartyID, peers []*tss.PartyID, header http.Header) (*Client, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		conn:  conn,
+		peers: transport.NewPeers(peers...),
+	}
+	if err := c.write(&Frame{Type: FrameJoin, Session: session, From: partyKey(self)}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Send sends a message through the relay: as a broadcast when it is addressed to all, and directly otherwise
+func (c *Client) Send(msg tss.Message) error {
+	env, err := transport.NewEnvelope(msg)
+	if err != nil {
+		return err
+	}
+	frame := &Frame{Type: FrameBroadcast, Payload: env.Marshal()}
+	if to := msg.GetTo(); to != nil {
+		frame.Type = FrameDirect
+		frame.To = make([]string, len(to))
+		for i, pid := range to {
+			frame.To[i] = partyKey(pid)
+		}
+	}
+	return c.write(frame)
+}
+
+// Run sends the messages of `party` from its out channel until the channel is closed or `ctx` is done. A message
+// that cannot be sent yields an error of the party on `errCh`.
+func (c *Client) Run(ctx context.Context, party tss.Party, outCh <-chan tss.Message, errCh chan<- *tss.Error) {
+	for {
+		select {
+		case msg, ok := <-outCh:
+			if !ok {
+				return
+			}
+			if err := c.Send(msg); err != nil {
+				errCh <- party.WrapError(err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Receive passes the messages relayed to this client to `updater`, e.g. a LocalParty, until the connection is
+// closed. The errors of the deliveries and of the relay are sent on `errCh`.
+func (c *Client) Receive(updater transport.Updater, errCh chan<- *tss.Error) error {
+	for {
+		var frame Frame
+		if err := c.conn.ReadJSON(&frame); err != nil {
+			return err
+		}
+		switch frame.Type {
+		case FrameDeliver:
+			env := new(transport.Envelope)
+			if err := env.Unmarshal(frame.Payload); err != nil {
+				errCh <- tss.NewError(err, "transport", -1, nil)
+				continue
+			}
+			// a party may not send envelopes in the name of another
+			if hex.EncodeToString(env.From) != frame.From {
+				errCh <- tss.NewError(errors.New("relayed envelope is not from the party that sent it"), "transport", -1, nil)
+				continue
+			}
+			if err := transport.Deliver(updater, c.peers, env); err != nil {
+				errCh <- err
+			}
+		case FrameError:
+			errCh <- tss.NewError(errors.New("relay: "+frame.Error), "transport", -1, nil)
+		}
+	}
+}
+
+// Close closes the connection to the relay, which ends Receive
+func (c *Client) Close() error {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+	_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return c.conn.Close()
+}
+
+func (c *Client) write(frame *Frame) error {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+	return c.conn.WriteJSON(frame)
+}
+
+// partyKey is how the relay protocol names a party
+func partyKey(pid *tss.PartyID) string {
+	return hex.EncodeToString(pid.KeyInt().Bytes())
+}
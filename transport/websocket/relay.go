@@ -0,0 +1,218 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+// Package websocket carries the messages of tss-lib parties through a relay over WebSocket, for parties that cannot
+// accept inbound connections, e.g. in browsers or on mobile devices. Every party dials the relay and joins a session;
+// the relay forwards what a party broadcasts to the other members of its session and what it sends directly to the
+// members it names.
+//
+// The frames are JSON text messages:
+//
+//	{"type": "join", "session": "...", "from": "<hex key>"}             client to relay, first frame
+//	{"type": "broadcast", "payload": "<base64 envelope>"}                client to relay
+//	{"type": "direct", "to": ["<hex key>", ...], "payload": "..."}     client to relay
+//	{"type": "deliver", "from": "<hex key>", "payload": "..."}          relay to client
+//	{"type": "error", "error": "..."}                                    relay to client
+//
+// The payload is a transport.Envelope encoded by its Marshal method. The relay vouches for nothing: a party is whoever
+// it claims to be when it joins, so parties that do not trust the relay should set identity keys, whose signatures
+// ParseWireMessage checks.
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	FrameJoin      FrameType = "join"
+	FrameBroadcast FrameType = "broadcast"
+	FrameDirect    FrameType = "direct"
+	FrameDeliver   FrameType = "deliver"
+	FrameError     FrameType = "error"
+
+	// the most frames the relay keeps for a session's members that have yet to join
+	defaultMaxBacklog = 4096
+)
+
+type (
+	FrameType string
+
+	// Frame is a message of the relay protocol
+	Frame struct {
+		Type    FrameType `json:"type"`
+		Session string    `json:"session,omitempty"`
+		From    string    `json:"from,omitempty"`
+		To      []string  `json:"to,omitempty"`
+		Payload []byte    `json:"payload,omitempty"`
+		Error   string    `json:"error,omitempty"`
+	}
+
+	// Relay is the reference relay server; it is an http.Handler that upgrades every request to a WebSocket
+	Relay struct {
+		upgrader   websocket.Upgrader
+		maxBacklog int
+
+		mtx      sync.Mutex
+		sessions map[string]*relaySession
+	}
+
+	relaySession struct {
+		members map[string]*relayConn
+		// the broadcasts so far, replayed to members that join late
+		broadcasts []*Frame
+		// the direct frames for members that have yet to join
+		pending map[string][]*Frame
+		backlog int
+	}
+
+	relayConn struct {
+		mtx  sync.Mutex
+		conn *websocket.Conn
+	}
+)
+
+// NewRelay returns a relay; `upgrader` sets e.g. its buffer sizes and the origins it accepts
+func NewRelay(upgrader websocket.Upgrader) *Relay {
+	return &Relay{
+		upgrader:   upgrader,
+		maxBacklog: defaultMaxBacklog,
+		sessions:   make(map[string]*relaySession),
+	}
+}
+
+// SetMaxBacklog sets how many frames the relay keeps for each session for members that have yet to join; a session
+// whose backlog is full drops the frames that would exceed it
+func (r *Relay) SetMaxBacklog(n int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.maxBacklog = n
+}
+
+func (r *Relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	conn, err := r.upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	rc := &relayConn{conn: conn}
+
+	var join Frame
+	if err := conn.ReadJSON(&join); err != nil {
+		return
+	}
+	if join.Type != FrameJoin || join.Session == "" || join.From == "" {
+		_ = rc.write(&Frame{Type: FrameError, Error: "the first frame must join a session"})
+		return
+	}
+	if err := r.join(join.Session, join.From, rc); err != nil {
+		_ = rc.write(&Frame{Type: FrameError, Error: err.Error()})
+		return
+	}
+	defer r.leave(join.Session, join.From)
+
+	for {
+		var frame Frame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return
+		}
+		if err := r.forward(join.Session, join.From, &frame); err != nil {
+			_ = rc.write(&Frame{Type: FrameError, Error: err.Error()})
+		}
+	}
+}
+
+// join adds a member to a session and sends it the frames it missed
+func (r *Relay) join(session, from string, rc *relayConn) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	s, ok := r.sessions[session]
+	if !ok {
+		s = &relaySession{
+			members: make(map[string]*relayConn),
+			pending: make(map[string][]*Frame),
+		}
+		r.sessions[session] = s
+	}
+	if _, ok := s.members[from]; ok {
+		return fmt.Errorf("party %s has already joined session %s", from, session)
+	}
+	s.members[from] = rc
+	for _, frame := range s.broadcasts {
+		if frame.From != from {
+			_ = rc.write(frame)
+		}
+	}
+	for _, frame := range s.pending[from] {
+		_ = rc.write(frame)
+	}
+	s.backlog -= len(s.pending[from])
+	delete(s.pending, from)
+	return nil
+}
+
+// leave removes a member from a session, and the session once it has no members left
+func (r *Relay) leave(session, from string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	s, ok := r.sessions[session]
+	if !ok {
+		return
+	}
+	delete(s.members, from)
+	if len(s.members) == 0 {
+		delete(r.sessions, session)
+	}
+}
+
+// forward delivers a frame of a member to the members it is for, keeping it for those that have yet to join
+func (r *Relay) forward(session, from string, frame *Frame) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	s, ok := r.sessions[session]
+	if !ok {
+		return errors.New("the session has ended")
+	}
+	deliver := &Frame{Type: FrameDeliver, From: from, Payload: frame.Payload}
+	switch frame.Type {
+	case FrameBroadcast:
+		if s.backlog >= r.maxBacklog {
+			return errors.New("the backlog of the session is full")
+		}
+		s.broadcasts = append(s.broadcasts, deliver)
+		s.backlog++
+		for to, rc := range s.members {
+			if to != from {
+				_ = rc.write(deliver)
+			}
+		}
+	case FrameDirect:
+		for _, to := range frame.To {
+			if rc, ok := s.members[to]; ok {
+				_ = rc.write(deliver)
+				continue
+			}
+			if s.backlog >= r.maxBacklog {
+				return errors.New("the backlog of the session is full")
+			}
+			s.pending[to] = append(s.pending[to], deliver)
+			s.backlog++
+		}
+	default:
+		return fmt.Errorf("unexpected frame %q", frame.Type)
+	}
+	return nil
+}
+
+func (rc *relayConn) write(frame *Frame) error {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	return rc.conn.WriteJSON(frame)
+}
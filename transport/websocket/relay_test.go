@@ -0,0 +1,97 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package websocket_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto"
+	"github.com/bnb-chain/tss-lib/v2/eddsa/keygen"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	. "github.com/bnb-chain/tss-lib/v2/transport/websocket"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestKeygenThroughRelay(t *testing.T) {
+	server := httptest.NewServer(NewRelay(gorilla.Upgrader{}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	p2pCtx := tss.NewPeerContext(pIDs)
+	errCh := make(chan *tss.Error, len(pIDs))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sessions := make([]*keygen.Session, len(pIDs))
+	for i, pid := range pIDs {
+		params := tss.NewParameters(tss.Edwards(), p2pCtx, pid, len(pIDs), test.TestThreshold)
+		sessions[i] = keygen.NewSession(params, len(pIDs))
+	}
+	// the parties join one at a time while the others are already sending, so the relay has to keep their messages
+	for i, pid := range pIDs {
+		client, err := Dial(ctx, url, "keygen", pid, pIDs, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer client.Close()
+		go client.Receive(sessions[i], errCh)
+		go client.Run(ctx, sessions[i], sessions[i].Out, errCh)
+		go func(S *keygen.Session) {
+			if err := S.Start(); err != nil {
+				errCh <- err
+			}
+		}(sessions[i])
+	}
+
+	var pub *crypto.ECPoint
+	for _, S := range sessions {
+		select {
+		case save := <-S.End:
+			if pub == nil {
+				pub = save.EDDSAPub
+			}
+			assert.True(t, pub.Equals(save.EDDSAPub))
+		case err := <-errCh:
+			t.Fatalf("keygen failed: %v", err)
+		case <-time.After(time.Minute):
+			t.Fatal("keygen timed out")
+		}
+	}
+}
+
+func TestRelayRejectsDuplicateJoin(t *testing.T) {
+	server := httptest.NewServer(NewRelay(gorilla.Upgrader{}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	pIDs := tss.GenerateTestPartyIDs(2)
+
+	first, err := Dial(context.Background(), url, "s", pIDs[0], pIDs, nil)
+	assert.NoError(t, err)
+	defer first.Close()
+	// the relay handles the joins in order, so wait for the first one before the second
+	time.Sleep(100 * time.Millisecond)
+	second, err := Dial(context.Background(), url, "s", pIDs[0], pIDs, nil)
+	assert.NoError(t, err)
+	defer second.Close()
+
+	errCh := make(chan *tss.Error, 1)
+	go second.Receive(nil, errCh)
+	select {
+	case err := <-errCh:
+		assert.Contains(t, err.Error(), "already joined")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the relay accepted a second join of the same party")
+	}
+}
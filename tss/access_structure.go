@@ -0,0 +1,242 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// the deepest nesting of groups in an access structure
+const maxAccessStructureDepth = 8
+
+// AccessStructure describes which sets of parties may sign with a key: Threshold of its members must take part, and
+// each member is either a party or a nested group with an access structure of its own, e.g. 2 of 3 departments,
+// each with 3 of its 5 members. Keygen with Parameters.SetAccessStructure shares the key along the structure (see
+// vss.CreateHierarchical), and signing resolves it for the parties that take part.
+// Unlike the threshold of the parameters, Threshold is the number of members needed, k in k-of-n.
+type AccessStructure struct {
+	Name      string `json:"name,omitempty"`
+	Threshold int    `json:"threshold"`
+	// the members of the group: either the keys of parties, or nested groups, but not both
+	Parties []*big.Int         `json:"parties,omitempty"`
+	Groups  []*AccessStructure `json:"groups,omitempty"`
+}
+
+// NewAccessStructure returns a group of parties of which `threshold` are needed
+func NewAccessStructure(name string, threshold int, parties ...*PartyID) *AccessStructure {
+	keys := make([]*big.Int, len(parties))
+	for i, p := range parties {
+		keys[i] = p.KeyInt()
+	}
+	return &AccessStructure{Name: name, Threshold: threshold, Parties: keys}
+}
+
+// NewNestedAccessStructure returns a group of groups of which `threshold` are needed
+func NewNestedAccessStructure(name string, threshold int, groups ...*AccessStructure) *AccessStructure {
+	return &AccessStructure{Name: name, Threshold: threshold, Groups: groups}
+}
+
+// Members returns the number of members of the group, n in k-of-n
+func (as *AccessStructure) Members() int {
+	return len(as.Parties) + len(as.Groups)
+}
+
+// ValidateBasic checks the thresholds and the nesting of the structure, and that no party appears twice
+func (as *AccessStructure) ValidateBasic() error {
+	return as.validate(0, make(map[string]struct{}))
+}
+
+func (as *AccessStructure) validate(depth int, seen map[string]struct{}) error {
+	if as == nil {
+		return errors.New("access structure has an empty group")
+	}
+	if depth >= maxAccessStructureDepth {
+		return fmt.Errorf("access structure is nested more than %d levels deep", maxAccessStructureDepth)
+	}
+	if (len(as.Parties) == 0) == (len(as.Groups) == 0) {
+		return fmt.Errorf("group %q of the access structure must hold either parties or groups", as.Name)
+	}
+	if as.Threshold < 1 || as.Members() < as.Threshold {
+		return fmt.Errorf("group %q of the access structure needs %d of %d members", as.Name, as.Threshold, as.Members())
+	}
+	for _, key := range as.Parties {
+		if key == nil || key.Sign() <= 0 {
+			return fmt.Errorf("group %q of the access structure has an invalid party key", as.Name)
+		}
+		if _, ok := seen[key.String()]; ok {
+			return fmt.Errorf("party %s appears twice in the access structure", key)
+		}
+		seen[key.String()] = struct{}{}
+	}
+	for _, group := range as.Groups {
+		if err := group.validate(depth+1, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the structure, and that its parties are exactly `ids`
+func (as *AccessStructure) Validate(ids SortedPartyIDs) error {
+	if err := as.ValidateBasic(); err != nil {
+		return err
+	}
+	keys := as.Keys()
+	if len(keys) != len(ids) {
+		return fmt.Errorf("access structure has %d parties, but the session has %d", len(keys), len(ids))
+	}
+	for _, key := range keys {
+		if ids.FindByKey(key) == nil {
+			return fmt.Errorf("party %s of the access structure is not in the session", key)
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys of the parties of the structure, depth first
+func (as *AccessStructure) Keys() []*big.Int {
+	keys := append([]*big.Int(nil), as.Parties...)
+	for _, group := range as.Groups {
+		keys = append(keys, group.Keys()...)
+	}
+	return keys
+}
+
+// MinSigners returns the size of the smallest set of parties that satisfies the structure
+func (as *AccessStructure) MinSigners() int {
+	if len(as.Parties) > 0 {
+		return as.Threshold
+	}
+	sizes := make([]int, len(as.Groups))
+	for i, group := range as.Groups {
+		sizes[i] = group.MinSigners()
+	}
+	total := 0
+	for k := 0; k < as.Threshold; k++ {
+		min := k
+		for i := k + 1; i < len(sizes); i++ {
+			if sizes[i] < sizes[min] {
+				min = i
+			}
+		}
+		sizes[k], sizes[min] = sizes[min], sizes[k]
+		total += sizes[k]
+	}
+	return total
+}
+
+// IsSatisfiedBy reports whether the parties may sign together
+func (as *AccessStructure) IsSatisfiedBy(parties []*PartyID) bool {
+	return as.satisfied(partyKeySet(parties))
+}
+
+// Resolve chooses among the available parties a set that satisfies the structure with no party to spare: the first
+// Threshold members of each group that can take part. Signing requires exactly such a set.
+func (as *AccessStructure) Resolve(available []*PartyID) (UnSortedPartyIDs, error) {
+	chosen := make(map[string]*big.Int)
+	if !as.resolve(nil, partyKeySet(available), big.NewInt(1), chosen) {
+		return nil, errors.New("the available parties do not satisfy the access structure")
+	}
+	signers := make(UnSortedPartyIDs, 0, len(chosen))
+	for _, p := range available {
+		if _, ok := chosen[p.KeyInt().String()]; ok {
+			signers = append(signers, p)
+		}
+	}
+	return signers, nil
+}
+
+// Coefficients returns the coefficients, in the order of the keys of the signers, that recombine the key from their
+// shares in place of the Lagrange coefficients of a flat threshold. The signers must be a set chosen by Resolve.
+func (as *AccessStructure) Coefficients(ec elliptic.Curve, signers []*big.Int) ([]*big.Int, error) {
+	has := make(map[string]bool, len(signers))
+	for _, key := range signers {
+		has[key.String()] = true
+	}
+	chosen := make(map[string]*big.Int, len(signers))
+	if !as.resolve(ec.Params().N, has, big.NewInt(1), chosen) {
+		return nil, errors.New("the signers do not satisfy the access structure")
+	}
+	coefs := make([]*big.Int, len(signers))
+	for i, key := range signers {
+		if coefs[i] = chosen[key.String()]; coefs[i] == nil {
+			return nil, fmt.Errorf("signer %s is not needed by the access structure; sign with a set chosen by Resolve", key)
+		}
+	}
+	return coefs, nil
+}
+
+func (as *AccessStructure) satisfied(has map[string]bool) bool {
+	return len(as.satisfiedMembers(has)) >= as.Threshold
+}
+
+// satisfiedMembers returns the positions, from 1, of the members that can take part
+func (as *AccessStructure) satisfiedMembers(has map[string]bool) []int64 {
+	var xs []int64
+	for i, key := range as.Parties {
+		if has[key.String()] {
+			xs = append(xs, int64(i+1))
+		}
+	}
+	for i, group := range as.Groups {
+		if group.satisfied(has) {
+			xs = append(xs, int64(i+1))
+		}
+	}
+	return xs
+}
+
+// resolve chooses the first Threshold members that can take part and adds the chosen parties to `chosen` with their
+// coefficients, the product of `coef` and of the Lagrange coefficients at 0 of the chosen members on the way down,
+// modulo q. Without q only the choice is made.
+func (as *AccessStructure) resolve(q *big.Int, has map[string]bool, coef *big.Int, chosen map[string]*big.Int) bool {
+	xs := as.satisfiedMembers(has)
+	if len(xs) < as.Threshold {
+		return false
+	}
+	xs = xs[:as.Threshold]
+	for _, x := range xs {
+		c := coef
+		if q != nil {
+			c = common.ModInt(q).Mul(coef, lagrangeAtZero(q, x, xs))
+		}
+		if len(as.Parties) > 0 {
+			chosen[as.Parties[x-1].String()] = c
+			continue
+		}
+		if !as.Groups[x-1].resolve(q, has, c, chosen) {
+			return false
+		}
+	}
+	return true
+}
+
+// lagrangeAtZero returns the Lagrange coefficient at 0 of `x` among `xs`, modulo q
+func lagrangeAtZero(q *big.Int, x int64, xs []int64) *big.Int {
+	modQ := common.ModInt(q)
+	coef := big.NewInt(1)
+	for _, y := range xs {
+		if y == x {
+			continue
+		}
+		coef = modQ.Mul(coef, modQ.Mul(big.NewInt(y), modQ.ModInverse(big.NewInt(y-x))))
+	}
+	return coef
+}
+
+func partyKeySet(parties []*PartyID) map[string]bool {
+	has := make(map[string]bool, len(parties))
+	for _, p := range parties {
+		has[p.KeyInt().String()] = true
+	}
+	return has
+}
@@ -0,0 +1,314 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// A minimal CBOR (RFC 8949) codec for the values of the wire messages and the save data: unsigned and negative
+// integers, bignums (tags 2 and 3), byte and text strings, arrays, maps, booleans, null and float64. Encoding is
+// deterministic; decoding accepts definite lengths only.
+
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborTag    = 6 << 5
+	cborSimple = 7 << 5
+
+	cborFalse   = cborSimple | 20
+	cborTrue    = cborSimple | 21
+	cborNull    = cborSimple | 22
+	cborFloat64 = cborSimple | 27
+
+	cborTagPosBignum = 2
+	cborTagNegBignum = 3
+
+	// the deepest nesting of arrays and maps that is decoded
+	cborMaxDepth = 32
+)
+
+type (
+	// cborMapEntry is an entry of a decoded CBOR map; keys are uint64 or string
+	cborMapEntry struct {
+		Key, Value interface{}
+	}
+
+	cborDecoder struct {
+		bz    []byte
+		depth int
+	}
+)
+
+func cborAppendHead(bz []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(bz, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(bz, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return cborAppendUint(append(bz, major|25), n, 2)
+	case n <= math.MaxUint32:
+		return cborAppendUint(append(bz, major|26), n, 4)
+	default:
+		return cborAppendUint(append(bz, major|27), n, 8)
+	}
+}
+
+// cborAppendUint appends the `size` low bytes of n in big-endian order
+func cborAppendUint(bz []byte, n uint64, size int) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	return append(bz, buf[8-size:]...)
+}
+
+func cborAppendInt(bz []byte, n int64) []byte {
+	if n < 0 {
+		return cborAppendHead(bz, cborNegInt, uint64(-(n + 1)))
+	}
+	return cborAppendHead(bz, cborUint, uint64(n))
+}
+
+// cborAppendBigInt encodes an integer, as a bignum if it does not fit in 64 bits
+func cborAppendBigInt(bz []byte, n *big.Int) []byte {
+	if n.IsUint64() {
+		return cborAppendHead(bz, cborUint, n.Uint64())
+	}
+	if n.Sign() < 0 {
+		m := new(big.Int).Neg(n)
+		m.Sub(m, big.NewInt(1))
+		if m.IsUint64() {
+			return cborAppendHead(bz, cborNegInt, m.Uint64())
+		}
+		return cborAppendBytes(cborAppendHead(bz, cborTag, cborTagNegBignum), m.Bytes())
+	}
+	return cborAppendBytes(cborAppendHead(bz, cborTag, cborTagPosBignum), n.Bytes())
+}
+
+func cborAppendBytes(bz, b []byte) []byte {
+	return append(cborAppendHead(bz, cborBytes, uint64(len(b))), b...)
+}
+
+func cborAppendText(bz []byte, s string) []byte {
+	return append(cborAppendHead(bz, cborText, uint64(len(s))), s...)
+}
+
+func cborAppendBool(bz []byte, b bool) []byte {
+	if b {
+		return append(bz, cborTrue)
+	}
+	return append(bz, cborFalse)
+}
+
+func cborAppendFloat(bz []byte, f float64) []byte {
+	return cborAppendUint(append(bz, cborFloat64), math.Float64bits(f), 8)
+}
+
+// cborAppendValue encodes a value of the data model produced by cborDecoder.value, with the keys of string-keyed
+// maps sorted so that the encoding is deterministic
+func cborAppendValue(bz []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		return append(bz, cborNull), nil
+	case bool:
+		return cborAppendBool(bz, v), nil
+	case uint64:
+		return cborAppendHead(bz, cborUint, v), nil
+	case int64:
+		return cborAppendInt(bz, v), nil
+	case *big.Int:
+		return cborAppendBigInt(bz, v), nil
+	case float64:
+		return cborAppendFloat(bz, v), nil
+	case []byte:
+		return cborAppendBytes(bz, v), nil
+	case string:
+		return cborAppendText(bz, v), nil
+	case []interface{}:
+		bz = cborAppendHead(bz, cborArray, uint64(len(v)))
+		for _, e := range v {
+			if bz, err = cborAppendValue(bz, e); err != nil {
+				return nil, err
+			}
+		}
+		return bz, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		bz = cborAppendHead(bz, cborMap, uint64(len(v)))
+		for _, k := range keys {
+			if bz, err = cborAppendValue(cborAppendText(bz, k), v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return bz, nil
+	case []cborMapEntry:
+		bz = cborAppendHead(bz, cborMap, uint64(len(v)))
+		for _, e := range v {
+			if bz, err = cborAppendValue(bz, e.Key); err != nil {
+				return nil, err
+			}
+			if bz, err = cborAppendValue(bz, e.Value); err != nil {
+				return nil, err
+			}
+		}
+		return bz, nil
+	default:
+		return nil, fmt.Errorf("cbor: cannot encode a value of type %T", v)
+	}
+}
+
+// decodeCBOR decodes a single CBOR item that spans all of `bz`
+func decodeCBOR(bz []byte) (interface{}, error) {
+	d := &cborDecoder{bz: bz}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if len(d.bz) != 0 {
+		return nil, errors.New("cbor: trailing bytes after the value")
+	}
+	return v, nil
+}
+
+func (d *cborDecoder) head() (major byte, n uint64, err error) {
+	if len(d.bz) == 0 {
+		return 0, 0, errors.New("cbor: unexpected end of input")
+	}
+	major, info := d.bz[0]&0xe0, d.bz[0]&0x1f
+	d.bz = d.bz[1:]
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	default:
+		return 0, 0, fmt.Errorf("cbor: unsupported additional information %d", info)
+	}
+	if len(d.bz) < size {
+		return 0, 0, errors.New("cbor: unexpected end of input")
+	}
+	for _, b := range d.bz[:size] {
+		n = n<<8 | uint64(b)
+	}
+	d.bz = d.bz[size:]
+	return major, n, nil
+}
+
+func (d *cborDecoder) take(n uint64) ([]byte, error) {
+	if uint64(len(d.bz)) < n {
+		return nil, errors.New("cbor: unexpected end of input")
+	}
+	b := d.bz[:n]
+	d.bz = d.bz[n:]
+	return b, nil
+}
+
+// value decodes the next item: integers decode to uint64 or int64, or to *big.Int when they do not fit, and maps to
+// []cborMapEntry in their encoded order
+func (d *cborDecoder) value() (interface{}, error) {
+	start := d.bz
+	major, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return n, nil
+	case cborNegInt:
+		if n <= math.MaxInt64 {
+			return -int64(n) - 1, nil
+		}
+		m := new(big.Int).SetUint64(n)
+		return m.Neg(m).Sub(m, big.NewInt(1)), nil
+	case cborBytes, cborText:
+		b, err := d.take(n)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborText {
+			return string(b), nil
+		}
+		return append([]byte(nil), b...), nil
+	case cborArray, cborMap:
+		if d.depth++; d.depth > cborMaxDepth {
+			return nil, errors.New("cbor: nested too deeply")
+		}
+		defer func() { d.depth-- }()
+		// every item takes at least one byte, which bounds the allocation
+		if uint64(len(d.bz)) < n {
+			return nil, errors.New("cbor: unexpected end of input")
+		}
+		if major == cborArray {
+			arr := make([]interface{}, n)
+			for i := range arr {
+				if arr[i], err = d.value(); err != nil {
+					return nil, err
+				}
+			}
+			return arr, nil
+		}
+		entries := make([]cborMapEntry, n)
+		for i := range entries {
+			if entries[i].Key, err = d.value(); err != nil {
+				return nil, err
+			}
+			switch entries[i].Key.(type) {
+			case uint64, string:
+			default:
+				return nil, errors.New("cbor: map keys must be unsigned integers or text")
+			}
+			if entries[i].Value, err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+		return entries, nil
+	case cborTag:
+		if n != cborTagPosBignum && n != cborTagNegBignum {
+			return nil, fmt.Errorf("cbor: unsupported tag %d", n)
+		}
+		major, size, err := d.head()
+		if err != nil || major != cborBytes {
+			return nil, errors.New("cbor: a bignum must be a byte string")
+		}
+		b, err := d.take(size)
+		if err != nil {
+			return nil, err
+		}
+		m := new(big.Int).SetBytes(b)
+		if n == cborTagNegBignum {
+			m.Neg(m).Sub(m, big.NewInt(1))
+		}
+		return m, nil
+	default:
+		switch start[0] {
+		case cborFalse:
+			return false, nil
+		case cborTrue:
+			return true, nil
+		case cborNull:
+			return nil, nil
+		case cborFloat64:
+			return math.Float64frombits(n), nil
+		}
+		return nil, fmt.Errorf("cbor: unsupported simple value 0x%x", start[0])
+	}
+}
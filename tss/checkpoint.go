@@ -0,0 +1,161 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+const checkpointSeedLen = 32
+
+type (
+	// Checkpoint is what a party needs to resume a session after a crash: the seed of its entropy source and the
+	// messages it had accepted, in order. The seed reproduces every secret of the session, so a checkpoint must be
+	// stored as securely as the key share itself.
+	Checkpoint struct {
+		Seed     []byte
+		Messages []CheckpointMessage
+	}
+
+	// CheckpointMessage is a message accepted by the party, as received from the wire
+	CheckpointMessage struct {
+		WireBytes   []byte
+		FromID      string
+		FromMoniker string
+		FromKey     []byte
+		FromIndex   int
+		// the identity key of the sender, if any, so that the message is verified again when it is replayed
+		FromIdentityKey []byte
+		IsBroadcast     bool
+	}
+
+	// Checkpointer records a session so that it can be resumed. Rather than serializing the temporary data of the
+	// party and its round, it seeds and replays: the party draws all of its randomness from the checkpoint seed (see
+	// EntropySource), so replaying the recorded messages into a new party built from the same parameters and save data
+	// recomputes its state exactly, up to the last message it had accepted. This holds for the pre-parameters that
+	// ECDSA keygen, refresh and resharing generate too, as the safe prime search is sequential on a deterministic source.
+	// The resumed party sends again the messages of the rounds it replays; they are identical to the ones sent
+	// before the crash, so peers ignore them.
+	Checkpointer struct {
+		mtx sync.Mutex
+		cp  Checkpoint
+	}
+)
+
+// NewCheckpointer returns a checkpointer for a new session with a fresh random seed
+func NewCheckpointer() (*Checkpointer, error) {
+	return NewCheckpointerWithRand(rand.Reader)
+}
+
+// NewCheckpointerWithRand returns a checkpointer for a new session with a seed drawn from `rand`, e.g. a
+// common.MixedReader
+func NewCheckpointerWithRand(rand io.Reader) (*Checkpointer, error) {
+	seed := make([]byte, checkpointSeedLen)
+	if _, err := io.ReadFull(rand, seed); err != nil {
+		return nil, err
+	}
+	return &Checkpointer{cp: Checkpoint{Seed: seed}}, nil
+}
+
+// NewCheckpointerFrom returns a checkpointer that resumes the session of `cp`
+func NewCheckpointerFrom(cp *Checkpoint) (*Checkpointer, error) {
+	if cp == nil || len(cp.Seed) < checkpointSeedLen {
+		return nil, errors.New("checkpoint has no seed")
+	}
+	return &Checkpointer{cp: *cp.copy()}, nil
+}
+
+// EntropySource returns a new reader of the checkpoint seed, to be passed to Parameters.SetEntropySource before the
+// party is constructed
+func (c *Checkpointer) EntropySource() io.Reader {
+	return common.NewDeterministicReader(c.cp.Seed)
+}
+
+// Update updates `p` with the message and records it if the party accepted it. A message parsed with
+// ParseWireMessage is recorded as it was received, with the signature of its sender, if any.
+func (c *Checkpointer) Update(p Party, msg ParsedMessage) (bool, *Error) {
+	var wireBytes []byte
+	if impl, ok := msg.(*MessageImpl); ok && impl.receivedBytes != nil {
+		wireBytes = impl.receivedBytes
+	} else {
+		var err error
+		if wireBytes, _, err = msg.WireBytes(); err != nil {
+			return false, p.WrapError(err)
+		}
+	}
+	ok, tssErr := p.Update(msg)
+	if ok && tssErr == nil {
+		c.record(wireBytes, msg.GetFrom(), msg.IsBroadcast())
+	}
+	return ok, tssErr
+}
+
+// UpdateFromBytes updates `p` with the wire message and records it if the party accepted it
+func (c *Checkpointer) UpdateFromBytes(p Party, wireBytes []byte, from *PartyID, isBroadcast bool) (bool, *Error) {
+	ok, err := p.UpdateFromBytes(wireBytes, from, isBroadcast)
+	if ok && err == nil {
+		c.record(wireBytes, from, isBroadcast)
+	}
+	return ok, err
+}
+
+// Checkpoint returns a copy of the record so far, to be persisted
+func (c *Checkpointer) Checkpoint() *Checkpoint {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.cp.copy()
+}
+
+// Resume starts `p`, which must have been constructed with the EntropySource of this checkpointer, and replays the
+// recorded messages into it
+func (c *Checkpointer) Resume(p Party) *Error {
+	if err := p.Start(); err != nil {
+		return err
+	}
+	for _, m := range c.Checkpoint().Messages {
+		from := NewPartyID(m.FromID, m.FromMoniker, new(big.Int).SetBytes(m.FromKey))
+		from.Index = m.FromIndex
+		if m.FromIdentityKey != nil {
+			from.IdentityKey = append(ed25519.PublicKey(nil), m.FromIdentityKey...)
+		}
+		if _, err := p.UpdateFromBytes(m.WireBytes, from, m.IsBroadcast); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Checkpointer) record(wireBytes []byte, from *PartyID, isBroadcast bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	m := CheckpointMessage{
+		WireBytes:   append([]byte(nil), wireBytes...),
+		FromID:      from.Id,
+		FromMoniker: from.Moniker,
+		FromKey:     append([]byte(nil), from.Key...),
+		FromIndex:   from.Index,
+		IsBroadcast: isBroadcast,
+	}
+	if from.IdentityKey != nil {
+		m.FromIdentityKey = append([]byte(nil), from.IdentityKey...)
+	}
+	c.cp.Messages = append(c.cp.Messages, m)
+}
+
+func (cp *Checkpoint) copy() *Checkpoint {
+	return &Checkpoint{
+		Seed:     append([]byte(nil), cp.Seed...),
+		Messages: append([]CheckpointMessage(nil), cp.Messages...),
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"crypto/elliptic"
 	"errors"
 	"reflect"
+	"sync"
 
 	"github.com/bnb-chain/tss-lib/v2/babyjubjub"
 	s256k1 "github.com/btcsuite/btcd/btcec/v2"
@@ -25,8 +26,9 @@ const (
 )
 
 var (
-	ec       elliptic.Curve
-	registry map[CurveName]elliptic.Curve
+	ec          elliptic.Curve
+	registry    map[CurveName]elliptic.Curve
+	registryMtx sync.RWMutex
 )
 
 // Init default curve (secp256k1)
@@ -40,11 +42,15 @@ func init() {
 }
 
 func RegisterCurve(name CurveName, curve elliptic.Curve) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
 	registry[name] = curve
 }
 
 // return curve, exist(bool)
 func GetCurveByName(name CurveName) (elliptic.Curve, bool) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
 	if val, exist := registry[name]; exist {
 		return val, true
 	}
@@ -54,6 +60,16 @@ func GetCurveByName(name CurveName) (elliptic.Curve, bool) {
 
 // return name, exist(bool)
 func GetCurveName(curve elliptic.Curve) (CurveName, bool) {
+	// custom curves share a type, and are told apart by their spec
+	if spec, ok := CurveSpecOf(curve); ok {
+		registered, exist := GetCurveByName(spec.Name)
+		if registered, ok := CurveSpecOf(registered); exist && ok && registered.Equal(spec) {
+			return spec.Name, true
+		}
+		return "", false
+	}
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
 	for name, e := range registry {
 		if reflect.TypeOf(curve) == reflect.TypeOf(e) {
 			return name, true
@@ -0,0 +1,308 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	// MaxCustomCurveBits bounds the size of P and N of a custom curve, above the 521 bits of P-521
+	MaxCustomCurveBits = 1024
+
+	// Weierstrass curves are y^2 = x^3 + A*x + B
+	Weierstrass CurveType = "weierstrass"
+	// TwistedEdwards curves are A*x^2 + y^2 = 1 + D*x^2*y^2
+	TwistedEdwards CurveType = "edwards"
+)
+
+type (
+	CurveType string
+
+	// CurveSpec defines a curve that tss-lib does not ship with, over the prime field of P, with the base point (Gx, Gy)
+	// of prime order N. It marshals to JSON, and the points of such a curve carry it in their JSON so that save data is
+	// only read on a host where the same curve is registered under its name.
+	CurveSpec struct {
+		Name CurveName `json:"name"`
+		Type CurveType `json:"type"`
+		P    *big.Int  `json:"p"`
+		N    *big.Int  `json:"n"`
+		A    *big.Int  `json:"a"`
+		// B of a Weierstrass curve, and D of a twisted Edwards curve
+		B       *big.Int `json:"b,omitempty"`
+		D       *big.Int `json:"d,omitempty"`
+		Gx      *big.Int `json:"gx"`
+		Gy      *big.Int `json:"gy"`
+		BitSize int      `json:"bit_size,omitempty"`
+	}
+
+	// CurveConfig is the format of the file read by LoadCurveConfig
+	CurveConfig struct {
+		Curves []CurveSpec `json:"curves"`
+	}
+
+	// customCurve implements elliptic.Curve for a CurveSpec with affine coordinates. It is not constant time.
+	// The identity is (0, 0) on a Weierstrass curve, like on the curves of crypto/elliptic, and (0, 1) on an Edwards
+	// curve.
+	customCurve struct {
+		spec   CurveSpec
+		params *elliptic.CurveParams
+	}
+)
+
+// NewCustomCurve checks the spec and returns its curve, without registering it
+func NewCustomCurve(spec CurveSpec) (elliptic.Curve, error) {
+	if spec.Name == "" {
+		return nil, errors.New("custom curve has no name")
+	}
+	if spec.P == nil || spec.N == nil || spec.A == nil || spec.Gx == nil || spec.Gy == nil {
+		return nil, fmt.Errorf("custom curve %s is missing parameters", spec.Name)
+	}
+	if MaxCustomCurveBits < spec.P.BitLen() || MaxCustomCurveBits < spec.N.BitLen() {
+		return nil, fmt.Errorf("custom curve %s: P and N must not be over %d bits", spec.Name, MaxCustomCurveBits)
+	}
+	if spec.P.Cmp(big.NewInt(3)) <= 0 || !spec.P.ProbablyPrime(20) || spec.N.Cmp(big.NewInt(1)) <= 0 || !spec.N.ProbablyPrime(20) {
+		return nil, fmt.Errorf("custom curve %s: P and N must be prime", spec.Name)
+	}
+	modP := func(n *big.Int) *big.Int { return new(big.Int).Mod(n, spec.P) }
+	switch spec.Type {
+	case Weierstrass:
+		if spec.B == nil {
+			return nil, fmt.Errorf("custom curve %s is missing B", spec.Name)
+		}
+		// 4a^3 + 27b^2 != 0
+		a3 := new(big.Int).Exp(spec.A, big.NewInt(3), spec.P)
+		b2 := new(big.Int).Exp(spec.B, big.NewInt(2), spec.P)
+		disc := modP(new(big.Int).Add(a3.Mul(a3, big.NewInt(4)), b2.Mul(b2, big.NewInt(27))))
+		if disc.Sign() == 0 {
+			return nil, fmt.Errorf("custom curve %s is singular", spec.Name)
+		}
+	case TwistedEdwards:
+		if spec.D == nil {
+			return nil, fmt.Errorf("custom curve %s is missing D", spec.Name)
+		}
+		a, d := modP(spec.A), modP(spec.D)
+		if a.Sign() == 0 || d.Sign() == 0 || a.Cmp(d) == 0 {
+			return nil, fmt.Errorf("custom curve %s is singular", spec.Name)
+		}
+	default:
+		return nil, fmt.Errorf("custom curve %s has an unknown type %q", spec.Name, spec.Type)
+	}
+	if spec.BitSize == 0 {
+		spec.BitSize = spec.P.BitLen()
+	}
+	curve := &customCurve{
+		spec: spec,
+		params: &elliptic.CurveParams{
+			P:       spec.P,
+			N:       spec.N,
+			B:       spec.B,
+			Gx:      spec.Gx,
+			Gy:      spec.Gy,
+			BitSize: spec.BitSize,
+			Name:    string(spec.Name),
+		},
+	}
+	if !curve.IsOnCurve(spec.Gx, spec.Gy) {
+		return nil, fmt.Errorf("custom curve %s: the base point is not on the curve", spec.Name)
+	}
+	if x, y := curve.ScalarBaseMult(spec.N.Bytes()); !curve.isIdentity(x, y) {
+		return nil, fmt.Errorf("custom curve %s: the base point does not have order N", spec.Name)
+	}
+	return curve, nil
+}
+
+// RegisterCustomCurve checks the spec and registers its curve under its name. Registering the same spec again returns
+// the curve registered first; another curve of the same name is an error.
+func RegisterCustomCurve(spec CurveSpec) (elliptic.Curve, error) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	if curve, ok := registry[spec.Name]; ok {
+		if existing, ok := CurveSpecOf(curve); ok && existing.Equal(&spec) {
+			return curve, nil
+		}
+		return nil, fmt.Errorf("another curve is registered as %s", spec.Name)
+	}
+	curve, err := NewCustomCurve(spec)
+	if err != nil {
+		return nil, err
+	}
+	registry[spec.Name] = curve
+	return curve, nil
+}
+
+// LoadCurveConfig registers the custom curves of a JSON file in the format of CurveConfig
+func LoadCurveConfig(path string) error {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var config CurveConfig
+	if err := json.Unmarshal(bz, &config); err != nil {
+		return fmt.Errorf("curve config %s: %v", path, err)
+	}
+	for _, spec := range config.Curves {
+		if _, err := RegisterCustomCurve(spec); err != nil {
+			return fmt.Errorf("curve config %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// CurveSpecOf returns the spec of a custom curve
+func CurveSpecOf(curve elliptic.Curve) (*CurveSpec, bool) {
+	if cc, ok := curve.(*customCurve); ok {
+		spec := cc.spec
+		return &spec, true
+	}
+	return nil, false
+}
+
+// Equal reports whether both specs define the same curve
+func (spec *CurveSpec) Equal(other *CurveSpec) bool {
+	eq := func(x, y *big.Int) bool { return (x == nil) == (y == nil) && (x == nil || x.Cmp(y) == 0) }
+	return spec.Name == other.Name && spec.Type == other.Type && eq(spec.P, other.P) && eq(spec.N, other.N) &&
+		eq(spec.A, other.A) && eq(spec.B, other.B) && eq(spec.D, other.D) && eq(spec.Gx, other.Gx) && eq(spec.Gy, other.Gy)
+}
+
+// ----- //
+
+func (c *customCurve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+func (c *customCurve) IsOnCurve(x, y *big.Int) bool {
+	p := c.spec.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	x2 := new(big.Int).Mul(x, x)
+	y2 := new(big.Int).Mul(y, y)
+	var left, right *big.Int
+	if c.spec.Type == Weierstrass {
+		left = y2
+		right = new(big.Int).Mul(x2, x)
+		right.Add(right, new(big.Int).Mul(c.spec.A, x))
+		right.Add(right, c.spec.B)
+	} else {
+		left = new(big.Int).Mul(c.spec.A, x2)
+		left.Add(left, y2)
+		right = new(big.Int).Mul(c.spec.D, x2)
+		right.Mul(right, y2)
+		right.Add(right, big.NewInt(1))
+	}
+	return left.Sub(left, right).Mod(left, p).Sign() == 0
+}
+
+func (c *customCurve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if c.spec.Type == TwistedEdwards {
+		return c.edwardsAdd(x1, y1, x2, y2)
+	}
+	if c.isIdentity(x1, y1) {
+		return new(big.Int).Set(x2), new(big.Int).Set(y2)
+	}
+	if c.isIdentity(x2, y2) {
+		return new(big.Int).Set(x1), new(big.Int).Set(y1)
+	}
+	p := c.spec.P
+	if x1.Cmp(x2) == 0 {
+		if y1.Cmp(y2) == 0 {
+			return c.Double(x1, y1)
+		}
+		return new(big.Int), new(big.Int)
+	}
+	// l = (y2 - y1) / (x2 - x1)
+	l := new(big.Int).Sub(x2, x1)
+	l.ModInverse(l.Mod(l, p), p)
+	l.Mul(l, new(big.Int).Sub(y2, y1)).Mod(l, p)
+	return c.weierstrassFinish(l, x1, y1, x2)
+}
+
+func (c *customCurve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	if c.spec.Type == TwistedEdwards {
+		return c.edwardsAdd(x1, y1, x1, y1)
+	}
+	if c.isIdentity(x1, y1) || y1.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	p := c.spec.P
+	// l = (3 x1^2 + a) / 2 y1
+	l := new(big.Int).Mul(x1, x1)
+	l.Mul(l, big.NewInt(3)).Add(l, c.spec.A)
+	den := new(big.Int).Lsh(y1, 1)
+	den.ModInverse(den.Mod(den, p), p)
+	l.Mul(l, den).Mod(l, p)
+	return c.weierstrassFinish(l, x1, y1, x1)
+}
+
+// weierstrassFinish returns x3 = l^2 - x1 - x2 and y3 = l (x1 - x3) - y1
+func (c *customCurve) weierstrassFinish(l, x1, y1, x2 *big.Int) (*big.Int, *big.Int) {
+	p := c.spec.P
+	x3 := new(big.Int).Mul(l, l)
+	x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, p)
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, l).Sub(y3, y1).Mod(y3, p)
+	return x3, y3
+}
+
+// edwardsAdd uses the unified addition law, which also doubles and handles the identity
+func (c *customCurve) edwardsAdd(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := c.spec.P
+	x1y2 := new(big.Int).Mul(x1, y2)
+	y1x2 := new(big.Int).Mul(y1, x2)
+	x1x2 := new(big.Int).Mul(x1, x2)
+	y1y2 := new(big.Int).Mul(y1, y2)
+	dxy := new(big.Int).Mul(c.spec.D, x1x2)
+	dxy.Mul(dxy, y1y2).Mod(dxy, p)
+
+	// x3 = (x1 y2 + y1 x2) / (1 + d x1 x2 y1 y2)
+	den := new(big.Int).Add(big.NewInt(1), dxy)
+	den.ModInverse(den.Mod(den, p), p)
+	x3 := x1y2.Add(x1y2, y1x2)
+	x3.Mul(x3, den).Mod(x3, p)
+
+	// y3 = (y1 y2 - a x1 x2) / (1 - d x1 x2 y1 y2)
+	den = new(big.Int).Sub(big.NewInt(1), dxy)
+	den.ModInverse(den.Mod(den, p), p)
+	y3 := y1y2.Sub(y1y2, x1x2.Mul(x1x2, c.spec.A))
+	y3.Mul(y3, den).Mod(y3, p)
+	return x3, y3
+}
+
+func (c *customCurve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	x, y := c.identity()
+	for _, b := range k {
+		for bit := 7; 0 <= bit; bit-- {
+			x, y = c.Double(x, y)
+			if b>>uint(bit)&1 == 1 {
+				x, y = c.Add(x, y, x1, y1)
+			}
+		}
+	}
+	return x, y
+}
+
+func (c *customCurve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.spec.Gx, c.spec.Gy, k)
+}
+
+func (c *customCurve) identity() (*big.Int, *big.Int) {
+	if c.spec.Type == TwistedEdwards {
+		return new(big.Int), big.NewInt(1)
+	}
+	return new(big.Int), new(big.Int)
+}
+
+func (c *customCurve) isIdentity(x, y *big.Int) bool {
+	ix, iy := c.identity()
+	return x.Cmp(ix) == 0 && y.Cmp(iy) == 0
+}
@@ -0,0 +1,356 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Encoding selects how the wire messages and the save data of a session are serialised
+type Encoding int
+
+const (
+	// EncodingProtobuf encodes messages with protobuf and save data as JSON; this is the default
+	EncodingProtobuf Encoding = iota
+	// EncodingCBOR encodes both with CBOR (RFC 8949): a message is the array [type URL, content], where the content
+	// is a map from the protobuf field numbers to their values, and save data is the map of its JSON form, with
+	// integers as bignums
+	EncodingCBOR
+)
+
+// the oldest protocol version that can read the CBOR wire messages
+const cborProtocolVersion uint32 = 2
+
+func (enc Encoding) String() string {
+	switch enc {
+	case EncodingProtobuf:
+		return "protobuf"
+	case EncodingCBOR:
+		return "cbor"
+	default:
+		return fmt.Sprintf("Encoding(%d)", int(enc))
+	}
+}
+
+// MarshalSaveData serialises the save data of a party, or any other value that marshals to JSON, with `enc`
+func MarshalSaveData(v interface{}, enc Encoding) ([]byte, error) {
+	bz, err := json.Marshal(v)
+	if err != nil || enc == EncodingProtobuf {
+		return bz, err
+	}
+	if enc != EncodingCBOR {
+		return nil, fmt.Errorf("MarshalSaveData: unknown encoding %s", enc)
+	}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	if tree, err = jsonToCBOR(tree); err != nil {
+		return nil, err
+	}
+	return cborAppendValue(nil, tree)
+}
+
+// UnmarshalSaveData parses save data produced by MarshalSaveData with either encoding into `v`
+func UnmarshalSaveData(bz []byte, v interface{}) error {
+	if len(bz) == 0 || bz[0]&0xe0 != cborMap {
+		return json.Unmarshal(bz, v)
+	}
+	tree, err := decodeCBOR(bz)
+	if err != nil {
+		return err
+	}
+	if tree, err = cborToJSON(tree); err != nil {
+		return err
+	}
+	if bz, err = json.Marshal(tree); err != nil {
+		return err
+	}
+	return json.Unmarshal(bz, v)
+}
+
+// jsonToCBOR turns the numbers of a decoded JSON value into integers
+func jsonToCBOR(v interface{}) (interface{}, error) {
+	var err error
+	switch v := v.(type) {
+	case json.Number:
+		if n, ok := new(big.Int).SetString(string(v), 10); ok {
+			return n, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		for i := range v {
+			if v[i], err = jsonToCBOR(v[i]); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		for k := range v {
+			if v[k], err = jsonToCBOR(v[k]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// cborToJSON turns a decoded CBOR value into one that marshals to the JSON it came from
+func cborToJSON(v interface{}) (interface{}, error) {
+	var err error
+	switch v := v.(type) {
+	case uint64, int64, *big.Int:
+		return json.Number(fmt.Sprint(v)), nil
+	case []byte:
+		return nil, errors.New("UnmarshalSaveData: unexpected byte string")
+	case []interface{}:
+		for i := range v {
+			if v[i], err = cborToJSON(v[i]); err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case []cborMapEntry:
+		m := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			k, ok := e.Key.(string)
+			if !ok {
+				return nil, errors.New("UnmarshalSaveData: map keys must be text")
+			}
+			if m[k], err = cborToJSON(e.Value); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	return v, nil
+}
+
+// ----- //
+
+// isCBORMessage reports whether versioned message bytes hold a CBOR message, which starts with an array of two
+func isCBORMessage(wireBytes []byte) bool {
+	return 0 < len(wireBytes) && wireBytes[0] == cborArray|2
+}
+
+// marshalCBORMessage encodes the content of a message as [type URL, content]
+func marshalCBORMessage(any *anypb.Any, content proto.Message) ([]byte, error) {
+	fields, err := protoToCBOR(content.ProtoReflect())
+	if err != nil {
+		return nil, err
+	}
+	return cborAppendValue(nil, []interface{}{any.GetTypeUrl(), fields})
+}
+
+// unmarshalCBORMessage decodes a message encoded by marshalCBORMessage into the Any of its protobuf form
+func unmarshalCBORMessage(wireBytes []byte) (*anypb.Any, error) {
+	v, err := decodeCBOR(wireBytes)
+	if err != nil {
+		return nil, err
+	}
+	arr, _ := v.([]interface{})
+	if len(arr) != 2 {
+		return nil, errors.New("ParseWireMessage: a CBOR message must be an array of its type and content")
+	}
+	typeURL, ok := arr[0].(string)
+	if !ok {
+		return nil, errors.New("ParseWireMessage: the CBOR message has no type")
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return nil, fmt.Errorf("ParseWireMessage: %v", err)
+	}
+	content := mt.New()
+	if err := cborToProto(content, arr[1]); err != nil {
+		return nil, err
+	}
+	return anypb.New(content.Interface())
+}
+
+// protoToCBOR maps the populated fields of a message from their numbers to their values
+func protoToCBOR(m protoreflect.Message) ([]cborMapEntry, error) {
+	var (
+		entries []cborMapEntry
+		err     error
+	)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		var value interface{}
+		switch {
+		case fd.IsMap():
+			err = fmt.Errorf("cbor: map field %s is not supported", fd.FullName())
+			return false
+		case fd.IsList():
+			list := v.List()
+			arr := make([]interface{}, list.Len())
+			for i := range arr {
+				if arr[i], err = protoValueToCBOR(fd, list.Get(i)); err != nil {
+					return false
+				}
+			}
+			value = arr
+		default:
+			if value, err = protoValueToCBOR(fd, v); err != nil {
+				return false
+			}
+		}
+		entries = append(entries, cborMapEntry{Key: uint64(fd.Number()), Value: value})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Range visits the fields in an undefined order
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key.(uint64) < entries[j].Key.(uint64) })
+	return entries, nil
+}
+
+func protoValueToCBOR(fd protoreflect.FieldDescriptor, v protoreflect.Value) (interface{}, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool(), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int(), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint(), nil
+	case protoreflect.EnumKind:
+		return int64(v.Enum()), nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float(), nil
+	case protoreflect.StringKind:
+		return v.String(), nil
+	case protoreflect.BytesKind:
+		return v.Bytes(), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return protoToCBOR(v.Message())
+	default:
+		return nil, fmt.Errorf("cbor: field %s has an unsupported kind %s", fd.FullName(), fd.Kind())
+	}
+}
+
+// cborToProto sets the fields of `m` from a map produced by protoToCBOR
+func cborToProto(m protoreflect.Message, v interface{}) error {
+	entries, ok := v.([]cborMapEntry)
+	if !ok {
+		return errors.New("ParseWireMessage: CBOR message content must be a map")
+	}
+	fields := m.Descriptor().Fields()
+	for _, e := range entries {
+		num, ok := e.Key.(uint64)
+		if !ok || num > uint64(protowire.MaxValidNumber) {
+			return errors.New("ParseWireMessage: CBOR message fields must be keyed by number")
+		}
+		fd := fields.ByNumber(protoreflect.FieldNumber(num))
+		if fd == nil {
+			// like protobuf, skip the fields of a newer schema
+			continue
+		}
+		if fd.IsMap() {
+			return fmt.Errorf("ParseWireMessage: map field %s is not supported", fd.FullName())
+		}
+		if !fd.IsList() {
+			pv, err := cborToProtoValue(m, fd, e.Value)
+			if err != nil {
+				return err
+			}
+			m.Set(fd, pv)
+			continue
+		}
+		arr, ok := e.Value.([]interface{})
+		if !ok {
+			return fmt.Errorf("ParseWireMessage: field %s must be an array", fd.FullName())
+		}
+		list := m.Mutable(fd).List()
+		for _, ev := range arr {
+			pv, err := cborToProtoValue(m, fd, ev)
+			if err != nil {
+				return err
+			}
+			list.Append(pv)
+		}
+	}
+	return nil
+}
+
+func cborToProtoValue(m protoreflect.Message, fd protoreflect.FieldDescriptor, v interface{}) (protoreflect.Value, error) {
+	bad := fmt.Errorf("ParseWireMessage: field %s has a value of the wrong type", fd.FullName())
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, ok := v.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.EnumKind:
+		if n, ok := cborInt(v); ok && int64(int32(n)) == n {
+			if fd.Kind() == protoreflect.EnumKind {
+				return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+			}
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, ok := cborInt(v); ok {
+			return protoreflect.ValueOfInt64(n), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, ok := v.(uint64); ok && uint64(uint32(n)) == n {
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, ok := v.(uint64); ok {
+			return protoreflect.ValueOfUint64(n), nil
+		}
+	case protoreflect.FloatKind:
+		if f, ok := v.(float64); ok {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+	case protoreflect.DoubleKind:
+		if f, ok := v.(float64); ok {
+			return protoreflect.ValueOfFloat64(f), nil
+		}
+	case protoreflect.StringKind:
+		if s, ok := v.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+	case protoreflect.BytesKind:
+		if b, ok := v.([]byte); ok {
+			return protoreflect.ValueOfBytes(b), nil
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		var sub protoreflect.Message
+		if fd.IsList() {
+			sub = m.Mutable(fd).List().NewElement().Message()
+		} else {
+			sub = m.NewField(fd).Message()
+		}
+		if err := cborToProto(sub, v); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(sub), nil
+	}
+	return protoreflect.Value{}, bad
+}
+
+func cborInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case uint64:
+		return int64(n), n <= 1<<63-1
+	}
+	return 0, false
+}
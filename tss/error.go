@@ -17,6 +17,8 @@ type Error struct {
 	round    int
 	victim   *PartyID
 	culprits []*PartyID
+	evidence []*Evidence
+	code     ErrorCode
 }
 
 func NewError(err error, task string, round int, victim *PartyID, culprits ...*PartyID) *Error {
@@ -35,6 +37,33 @@ func (err *Error) Victim() *PartyID { return err.victim }
 
 func (err *Error) Culprits() []*PartyID { return err.culprits }
 
+// WithEvidence attaches evidence against the culprits, skipping nil evidence, and returns the error
+func (err *Error) WithEvidence(evidence ...*Evidence) *Error {
+	for _, ev := range evidence {
+		if ev != nil {
+			err.evidence = append(err.evidence, ev)
+		}
+	}
+	return err
+}
+
+// Evidence returns the evidence against the culprits, when the protocol can provide it
+func (err *Error) Evidence() []*Evidence { return err.evidence }
+
+// WithCode sets the code of the error and returns it
+func (err *Error) WithCode(code ErrorCode) *Error {
+	err.code = code
+	return err
+}
+
+// Code returns the code of the error, or that of its cause when it was not given one
+func (err *Error) Code() ErrorCode {
+	if err.code != CodeUnknown {
+		return err.code
+	}
+	return codeOf(err.cause)
+}
+
 func (err *Error) Error() string {
 	if err == nil || err.cause == nil {
 		return "Error is nil"
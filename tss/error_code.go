@@ -0,0 +1,114 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode classifies an *Error so that an orchestrator can react to it without parsing its message: exclude the
+// culprits and start over when they provably misbehaved (see Blame), or retry with the same parties (see Retryable).
+type ErrorCode int
+
+const (
+	CodeUnknown ErrorCode = iota
+	// the parameters or the save data given to the party are invalid
+	CodeInvalidInput
+	// a message is malformed, or comes from an unknown sender
+	CodeInvalidMessage
+	// a sender sent two different messages of the same type
+	CodeDuplicateMessage
+	// a message belongs to another session
+	CodeWrongSession
+	// a de-commitment does not open its commitment
+	CodeBadCommitment
+	// a secret share does not match the sender's public commitments
+	CodeBadShare
+	// a zero-knowledge proof does not verify
+	CodeBadProof
+	// public data sent by a party, such as its Paillier or ring-Pedersen parameters, is invalid or inconsistent
+	CodeBadPublicData
+	// a final consistency check failed, without identifying the cheater
+	CodeVerificationFailed
+	// a round did not receive all of its messages in time
+	CodeTimeout
+	// the context of the session was cancelled
+	CodeCancelled
+	// the party failed on its own, e.g. in an unexpected state
+	CodeInternal
+	// a peer sent more messages than its RateLimiter allows
+	CodeRateLimited
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	CodeUnknown:            "Unknown",
+	CodeInvalidInput:       "InvalidInput",
+	CodeInvalidMessage:     "InvalidMessage",
+	CodeDuplicateMessage:   "DuplicateMessage",
+	CodeWrongSession:       "WrongSession",
+	CodeBadCommitment:      "BadCommitment",
+	CodeBadShare:           "BadShare",
+	CodeBadProof:           "BadProof",
+	CodeBadPublicData:      "BadPublicData",
+	CodeVerificationFailed: "VerificationFailed",
+	CodeTimeout:            "Timeout",
+	CodeCancelled:          "Cancelled",
+	CodeInternal:           "Internal",
+	CodeRateLimited:        "RateLimited",
+}
+
+func (code ErrorCode) String() string {
+	if name, ok := errorCodeNames[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrorCode(%d)", int(code))
+}
+
+// Blame reports whether the culprits of an error with this code sent provably invalid data, so that they should be
+// excluded from the next attempt
+func (code ErrorCode) Blame() bool {
+	switch code {
+	case CodeInvalidMessage, CodeDuplicateMessage, CodeBadCommitment, CodeBadShare, CodeBadProof, CodeBadPublicData,
+		CodeRateLimited:
+		return true
+	}
+	return false
+}
+
+// Retryable reports whether the session may succeed if it is run again with the same parties
+func (code ErrorCode) Retryable() bool {
+	switch code {
+	case CodeWrongSession, CodeVerificationFailed, CodeTimeout:
+		return true
+	}
+	return false
+}
+
+// codedError is an error that carries its code, for the errors collected before they are wrapped in an *Error
+type codedError struct {
+	code ErrorCode
+	err  error
+}
+
+// CodedError attaches `code` to `err`. An *Error wrapping it, even among other errors, reports the code unless it
+// was given one of its own with WithCode.
+func CodedError(code ErrorCode, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+func (ce *codedError) Error() string { return ce.err.Error() }
+
+func (ce *codedError) Unwrap() error { return ce.err }
+
+func codeOf(err error) ErrorCode {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return CodeUnknown
+}
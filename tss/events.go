@@ -0,0 +1,89 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"time"
+)
+
+// roundHooks are the lifecycle callbacks of a party. They are called by BaseStart and BaseUpdate one at a time and in
+// the order of the events, once the party has been unlocked, so they may safely call back into the party.
+type roundHooks struct {
+	onRoundStarted    func(round int)
+	onRoundFinished   func(round int, elapsed time.Duration)
+	onMessageReceived func(msg ParsedMessage)
+}
+
+// SetOnRoundStarted registers `fn` to be called with the number of each round once the party has started it.
+// It must be set before Start.
+func (p *BaseParty) SetOnRoundStarted(fn func(round int)) {
+	p.hooks.onRoundStarted = fn
+}
+
+// SetOnRoundFinished registers `fn` to be called with the number of each round, and the time since it started, once
+// the party has received all of its messages and moves on. It must be set before Start.
+func (p *BaseParty) SetOnRoundFinished(fn func(round int, elapsed time.Duration)) {
+	p.hooks.onRoundFinished = fn
+}
+
+// SetOnMessageReceived registers `fn` to be called with every valid message given to the party, before it is
+// processed. It must be set before Start.
+func (p *BaseParty) SetOnMessageReceived(fn func(msg ParsedMessage)) {
+	p.hooks.onMessageReceived = fn
+}
+
+// notifyRoundStarted records that the current round has started. It must be called with the party locked.
+func (p *BaseParty) notifyRoundStarted() {
+	p.roundStartedAt = time.Now()
+	if fn, rnd := p.hooks.onRoundStarted, p.rnd; fn != nil && rnd != nil {
+		round := rnd.RoundNumber()
+		p.events = append(p.events, func() { fn(round) })
+	}
+}
+
+// notifyRoundFinished records that the current round is complete. It must be called with the party locked.
+func (p *BaseParty) notifyRoundFinished() {
+	if p.rnd == nil {
+		return
+	}
+	round, elapsed := p.rnd.RoundNumber(), time.Since(p.roundStartedAt)
+	if p.sink != nil {
+		p.sink.RoundFinished(p.task, round, elapsed)
+	}
+	if fn := p.hooks.onRoundFinished; fn != nil {
+		p.events = append(p.events, func() { fn(round, elapsed) })
+	}
+}
+
+// notifyMessageReceived records the arrival of a message. It must be called with the party locked.
+func (p *BaseParty) notifyMessageReceived(msg ParsedMessage) {
+	if fn := p.hooks.onMessageReceived; fn != nil {
+		p.events = append(p.events, func() { fn(msg) })
+	}
+}
+
+// fireEvents calls the hooks of the events recorded so far, one at a time and in order. A hook that calls back into
+// the party leaves its events to the outer call. It must be called with the party unlocked.
+func (p *BaseParty) fireEvents() {
+	p.lock()
+	if p.firing {
+		p.unlock()
+		return
+	}
+	p.firing = true
+	for len(p.events) > 0 {
+		events := p.events
+		p.events = nil
+		p.unlock()
+		for _, event := range events {
+			event()
+		}
+		p.lock()
+	}
+	p.firing = false
+	p.unlock()
+}
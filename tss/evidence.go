@@ -0,0 +1,87 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsignedEvidence is returned by NewEvidence for a message that its sender did not sign with an identity key
+var ErrUnsignedEvidence = errors.New("NewEvidence: a message is not signed by the identity key of its sender")
+
+// Evidence holds the messages that show a culprit misbehaved towards an accuser, so that other parties can verify the blame.
+// It may be serialized with encoding/json. The messages are kept as the accuser received them, signed by the culprit
+// with its identity key (see Parameters.SetIdentityKey), so that the accuser cannot forge them.
+type Evidence struct {
+	Task    string   `json:"task"`
+	Round   int      `json:"round"`
+	Reason  string   `json:"reason"`
+	Culprit *PartyID `json:"culprit"`
+	Accuser *PartyID `json:"accuser"`
+	// the messages involved, in an order defined by the protocol and the reason
+	Messages []*EvidenceMessage `json:"messages"`
+}
+
+// EvidenceMessage is a message of the culprit: its signed wire bytes and how it was delivered
+type EvidenceMessage struct {
+	WireBytes   []byte `json:"wire_bytes"`
+	IsBroadcast bool   `json:"is_broadcast"`
+}
+
+// NewEvidence returns the evidence of the messages, or an error wrapping ErrUnsignedEvidence when one of them was not
+// received with the signature of its sender, as there is then nothing to prove that the culprit sent it
+func NewEvidence(task string, round int, reason string, culprit, accuser *PartyID, msgs ...ParsedMessage) (*Evidence, error) {
+	ev := &Evidence{
+		Task:     task,
+		Round:    round,
+		Reason:   reason,
+		Culprit:  culprit,
+		Accuser:  accuser,
+		Messages: make([]*EvidenceMessage, len(msgs)),
+	}
+	for j, msg := range msgs {
+		impl, ok := msg.(*MessageImpl)
+		if !ok || impl.signedBytes == nil {
+			return nil, ErrUnsignedEvidence
+		}
+		ev.Messages[j] = &EvidenceMessage{
+			WireBytes:   impl.signedBytes,
+			IsBroadcast: impl.IsBroadcast(),
+		}
+	}
+	return ev, nil
+}
+
+// ParseMessages decodes the messages of the evidence, checking that each one was signed by `culprit`, the culprit as
+// the verifier knows it with its identity key, and that it was sent to the accuser
+func (ev *Evidence) ParseMessages(culprit *PartyID) ([]ParsedMessage, error) {
+	if ev.Culprit == nil || ev.Culprit.MessageWrapper_PartyID == nil || ev.Accuser == nil || ev.Accuser.MessageWrapper_PartyID == nil {
+		return nil, errors.New("evidence is missing the culprit or the accuser")
+	}
+	if culprit == nil || culprit.MessageWrapper_PartyID == nil || culprit.KeyInt().Cmp(ev.Culprit.KeyInt()) != 0 {
+		return nil, errors.New("evidence blames another party")
+	}
+	if culprit.IdentityKey == nil {
+		return nil, fmt.Errorf("the culprit %s has no identity key to verify the evidence with", culprit)
+	}
+	msgs := make([]ParsedMessage, len(ev.Messages))
+	for j, m := range ev.Messages {
+		if m == nil {
+			return nil, errors.New("evidence holds an empty message")
+		}
+		msg, err := ParseWireMessage(m.WireBytes, culprit, m.IsBroadcast)
+		if err != nil {
+			return nil, err
+		}
+		if impl, ok := msg.(*MessageImpl); !ok || !impl.addressedTo(ev.Accuser) {
+			return nil, errors.New("evidence holds a message that was not sent to the accuser")
+		}
+		msgs[j] = msg
+	}
+	return msgs, nil
+}
@@ -0,0 +1,41 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+const (
+	keyIDTag = "tss-lib key id"
+	// the bytes of the hash kept in a key ID
+	keyIDLen = 16
+)
+
+// KeyID returns the fingerprint of the public key (x, y) on the curve `ec`: the first 16 bytes of SHA-512/256 of the
+// curve name and the coordinates, in hex. It depends on the public key only, so every party computes the same one,
+// and it stays the same through resharing and refresh. It is empty when the key is missing.
+func KeyID(ec elliptic.Curve, x, y *big.Int) string {
+	if ec == nil || x == nil || y == nil {
+		return ""
+	}
+	name, ok := GetCurveName(ec)
+	if !ok {
+		name = CurveName(ec.Params().Name)
+	}
+	byteLen := (ec.Params().P.BitLen() + 7) / 8
+	sum := common.SHA512_256(
+		[]byte(keyIDTag),
+		[]byte(name),
+		x.FillBytes(make([]byte, byteLen)),
+		y.FillBytes(make([]byte, byteLen)))
+	return hex.EncodeToString(sum[:keyIDLen])
+}
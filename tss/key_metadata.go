@@ -0,0 +1,64 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"crypto/elliptic"
+	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// KeyMetadata describes the key of save data, so that tooling can inventory keys without bookkeeping of its own.
+// Keygen, and resharing for the new committee, set it in the save data they produce; refresh and repair update the
+// threshold and the party count when they change them, and keep the KeyID. Save data from before metadata was introduced has none.
+type KeyMetadata struct {
+	// when the party got its share of the key, by keygen or resharing
+	CreatedAt time.Time
+	Curve     CurveName
+	// threshold+1 parties sign with the key, unless it was shared along an access structure
+	Threshold  int
+	PartyCount int
+	HashScheme common.HashScheme
+	// the ProtocolVersion of the release that produced the save data
+	ProtocolVersion uint32
+	// the fingerprint of the public key, by which coordinators may refer to the key (see KeyID)
+	KeyID string `json:",omitempty"`
+}
+
+// NewKeyMetadata returns the metadata of a key produced now on the curve `ec`
+func NewKeyMetadata(ec elliptic.Curve, threshold, partyCount int, scheme common.HashScheme) *KeyMetadata {
+	name, _ := GetCurveName(ec)
+	return &KeyMetadata{
+		CreatedAt:       time.Now().UTC(),
+		Curve:           name,
+		Threshold:       threshold,
+		PartyCount:      partyCount,
+		HashScheme:      scheme,
+		ProtocolVersion: ProtocolVersion,
+	}
+}
+
+// WithParties returns a copy of the metadata with another threshold and party count, or nil for no metadata
+func (meta *KeyMetadata) WithParties(threshold, partyCount int) *KeyMetadata {
+	if meta == nil {
+		return nil
+	}
+	updated := *meta
+	updated.Threshold, updated.PartyCount = threshold, partyCount
+	return &updated
+}
+
+// WithKeyID returns a copy of the metadata with another key ID, or nil for no metadata
+func (meta *KeyMetadata) WithKeyID(keyID string) *KeyMetadata {
+	if meta == nil {
+		return nil
+	}
+	updated := *meta
+	updated.KeyID = keyID
+	return &updated
+}
@@ -0,0 +1,142 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	defaultMaxMessageBytes = 16 << 20
+	defaultMaxRepeatedLen  = 1024
+	// 32768 bits, well above the Paillier and NTilde values of the largest proofs
+	defaultMaxFieldBytes = 4096
+
+	// the deepest nesting of messages within the content of a message
+	maxWireMessageDepth = 32
+)
+
+type (
+	// WireLimits bound the messages that ParseWireMessage accepts, so that a malicious peer cannot exhaust the memory
+	// of a party with a crafted message before it is validated. Zero fields take the defaults of DefaultWireLimits.
+	WireLimits struct {
+		// the largest wire bytes, and the largest that compressed wire bytes may expand to
+		MaxMessageBytes int
+		// the most elements of a repeated field, e.g. of the flattened points or proof values of a message
+		MaxRepeatedLen int
+		// the longest bytes field of the content, which bounds the size of its big.Ints
+		MaxFieldBytes int
+	}
+)
+
+// ErrWireLimit is wrapped by the error of ParseWireMessage for a message that exceeds the wire limits
+var ErrWireLimit = errors.New("message exceeds the wire limits")
+
+var wireLimits atomic.Value // WireLimits
+
+// DefaultWireLimits returns the limits that apply unless SetWireLimits is called
+func DefaultWireLimits() WireLimits {
+	return WireLimits{
+		MaxMessageBytes: defaultMaxMessageBytes,
+		MaxRepeatedLen:  defaultMaxRepeatedLen,
+		MaxFieldBytes:   defaultMaxFieldBytes,
+	}
+}
+
+// SetWireLimits sets the limits of every message parsed from then on by this process
+func SetWireLimits(limits WireLimits) {
+	defaults := DefaultWireLimits()
+	if limits.MaxMessageBytes <= 0 {
+		limits.MaxMessageBytes = defaults.MaxMessageBytes
+	}
+	if limits.MaxRepeatedLen <= 0 {
+		limits.MaxRepeatedLen = defaults.MaxRepeatedLen
+	}
+	if limits.MaxFieldBytes <= 0 {
+		limits.MaxFieldBytes = defaults.MaxFieldBytes
+	}
+	wireLimits.Store(limits)
+}
+
+// GetWireLimits returns the limits in effect
+func GetWireLimits() WireLimits {
+	if limits, ok := wireLimits.Load().(WireLimits); ok {
+		return limits
+	}
+	return DefaultWireLimits()
+}
+
+// checkWireSize rejects wire bytes, before or after decompression, that are larger than the limit
+func checkWireSize(wireBytes []byte, limits WireLimits) error {
+	if len(wireBytes) > limits.MaxMessageBytes {
+		return fmt.Errorf("ParseWireMessage: %w: the message has %d bytes, more than %d", ErrWireLimit,
+			len(wireBytes), limits.MaxMessageBytes)
+	}
+	return nil
+}
+
+// checkContentLimits walks the encoded content of a message along the fields of its type without decoding it, and
+// rejects repeated fields with too many elements and bytes fields that are too long. Content of an unknown type is
+// left to UnmarshalNew to reject.
+func checkContentLimits(typeURL string, content []byte, limits WireLimits) error {
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return nil
+	}
+	if err := checkFieldLimits(mt.Descriptor(), content, limits, 0); err != nil {
+		return fmt.Errorf("ParseWireMessage: %w: %v", ErrWireLimit, err)
+	}
+	return nil
+}
+
+func checkFieldLimits(md protoreflect.MessageDescriptor, bz []byte, limits WireLimits, depth int) error {
+	if depth >= maxWireMessageDepth {
+		return errors.New("the content is nested too deeply")
+	}
+	counts := make(map[protowire.Number]int)
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		fd := md.Fields().ByNumber(num)
+		if fd != nil && fd.IsList() {
+			if counts[num]++; counts[num] > limits.MaxRepeatedLen {
+				return fmt.Errorf("field %s has more than %d elements", fd.FullName(), limits.MaxRepeatedLen)
+			}
+		}
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, bz); n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if fd != nil && fd.Kind() == protoreflect.MessageKind {
+			if err := checkFieldLimits(fd.Message(), v, limits, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(v) > limits.MaxFieldBytes {
+			return fmt.Errorf("field %d has %d bytes, more than %d", num, len(v), limits.MaxFieldBytes)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+// stubContent borrows the fields of MessageWrapper as a payload: a bytes field and a repeated one
+type stubContent struct {
+	*tss.MessageWrapper
+}
+
+func (stubContent) ValidateBasic() bool {
+	return true
+}
+
+func TestWireLimits(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	meta := tss.MessageRouting{From: pIDs[0], IsBroadcast: true}
+	wireBytes := func(content *tss.MessageWrapper) []byte {
+		stub := stubContent{content}
+		bz, _, err := tss.NewMessage(meta, stub, tss.NewMessageWrapper(meta, stub)).WireBytes()
+		assert.NoError(t, err)
+		return bz
+	}
+	// the stub is not a protocol message, so it is only ever refused as unknown content past the limits
+	_, err := tss.ParseWireMessage(wireBytes(&tss.MessageWrapper{SessionId: make([]byte, 32)}), pIDs[0], true)
+	assert.NotErrorIs(t, err, tss.ErrWireLimit)
+
+	// a big.Int of a million bits is refused before it is decoded
+	_, err = tss.ParseWireMessage(wireBytes(&tss.MessageWrapper{SessionId: make([]byte, 1<<17)}), pIDs[0], true)
+	assert.ErrorIs(t, err, tss.ErrWireLimit)
+	// and so is a repeated field with too many elements
+	_, err = tss.ParseWireMessage(wireBytes(&tss.MessageWrapper{To: make([]*tss.MessageWrapper_PartyID, 2000)}), pIDs[0], true)
+	assert.ErrorIs(t, err, tss.ErrWireLimit)
+	_, err = tss.ParseWireMessage(make([]byte, 17<<20), pIDs[0], true)
+	assert.ErrorIs(t, err, tss.ErrWireLimit)
+
+	defer tss.SetWireLimits(tss.DefaultWireLimits())
+	tss.SetWireLimits(tss.WireLimits{MaxFieldBytes: 16})
+	assert.Equal(t, tss.DefaultWireLimits().MaxMessageBytes, tss.GetWireLimits().MaxMessageBytes)
+	_, err = tss.ParseWireMessage(wireBytes(&tss.MessageWrapper{SessionId: make([]byte, 32)}), pIDs[0], true)
+	assert.ErrorIs(t, err, tss.ErrWireLimit)
+}
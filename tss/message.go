@@ -7,6 +7,8 @@
 package tss
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"fmt"
 
 	"google.golang.org/protobuf/proto"
@@ -61,6 +63,12 @@ type (
 		IsToOldCommittee bool
 		// whether the message should be sent to both old and new committee participants
 		IsToOldAndNewCommittees bool
+		// the session the message belongs to, set by a SessionManager; the transport must deliver it with the message
+		SessionID []byte
+		// the trace context of the sender when it has a Tracer; the transport should deliver it with the message
+		TraceContext map[string]string
+		// whether the wire bytes are compressed; informational, as ParseWireMessage detects compressed bytes itself
+		Compressed bool
 	}
 
 	// Implements ParsedMessage; this is a concrete implementation of what messages produced by a LocalParty look like
@@ -68,6 +76,16 @@ type (
 		MessageRouting
 		content MessageContent
 		wire    *MessageWrapper
+		// the identity key of the sender, if its wire bytes must be signed
+		signer ed25519.PrivateKey
+		// the encoding of its wire bytes
+		encoding Encoding
+		// the keys of the recipients that the sender signed, if any; see addressedTo
+		recipients [][]byte
+		// the wire bytes as received, if the message was parsed from them; see Checkpointer
+		receivedBytes []byte
+		// the received bytes, if they carry the signature of the sender; see Evidence
+		signedBytes []byte
 	}
 )
 
@@ -96,6 +114,10 @@ func NewMessageWrapper(routing MessageRouting, content MessageContent) *MessageW
 		IsToOldAndNewCommittees: routing.IsToOldAndNewCommittees,
 		From:                    routing.From.MessageWrapper_PartyID,
 		To:                      to,
+		SessionId:               routing.SessionID,
+		Compressed:              routing.Compressed,
+		ProtocolVersion:         ProtocolVersion,
+		MinProtocolVersion:      MinProtocolVersion,
 		Message:                 any,
 	}
 }
@@ -137,13 +159,60 @@ func (mm *MessageImpl) IsToOldAndNewCommittees() bool {
 }
 
 func (mm *MessageImpl) WireBytes() ([]byte, *MessageRouting, error) {
-	bz, err := proto.Marshal(mm.wire.Message)
-	if err != nil {
-		return nil, nil, err
+	var bz []byte
+	var err error
+	if mm.encoding == EncodingCBOR {
+		if bz, err = marshalCBORMessage(mm.wire.Message, mm.content); err != nil {
+			return nil, nil, err
+		}
+		bz = prependWireVersion(bz, cborProtocolVersion)
+	} else {
+		if bz, err = proto.Marshal(mm.wire.Message); err != nil {
+			return nil, nil, err
+		}
+		bz = prependWireVersion(bz, MinProtocolVersion)
+	}
+	if mm.Compressed {
+		if bz, err = compressWireBytes(bz); err != nil {
+			return nil, nil, err
+		}
+	}
+	if mm.signer != nil {
+		bz = signWireBytes(mm.signer, mm.From, mm.wire.IsBroadcast, newWireEnvelope(mm.To, mm.SessionID), bz)
 	}
 	return bz, &mm.MessageRouting, nil
 }
 
+// addressedTo reports whether the message may be handed to the party `pID`: its sender signed no recipients, i.e. it
+// is unsigned or a broadcast to all parties, or `pID` is one of them
+func (mm *MessageImpl) addressedTo(pID *PartyID) bool {
+	if len(mm.recipients) == 0 {
+		return true
+	}
+	for _, key := range mm.recipients {
+		if bytes.Equal(key, pID.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// setSessionID tags the message with the session it belongs to, in both its routing and its wrapper
+func (mm *MessageImpl) setSessionID(sessionID []byte) {
+	mm.SessionID = sessionID
+	if mm.wire != nil {
+		mm.wire.SessionId = sessionID
+	}
+}
+
+// setCompressed has the wire bytes of the message compressed
+func (mm *MessageImpl) setCompressed() {
+	mm.Compressed = true
+	if mm.wire != nil {
+		mm.wire.Compressed = true
+	}
+}
+
 func (mm *MessageImpl) WireMsg() *MessageWrapper {
 	return mm.wire
 }
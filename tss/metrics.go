@@ -0,0 +1,66 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"time"
+)
+
+// Metrics receives measurements of the parties, e.g. to export them to Prometheus as counters of the messages in and
+// out, a histogram of the round durations and a gauge of the active sessions. Set it with Parameters.SetMetrics.
+// Its methods are called by every party that shares it, concurrently and with the party locked, so they must be safe
+// for concurrent use and must not block.
+type Metrics interface {
+	// MessageSent counts a message sent by a party, by its type, e.g. "binance.tsslib.ecdsa.signing.SignRound1Message1"
+	MessageSent(msgType string)
+	// MessageReceived counts a message received by a party, by its type
+	MessageReceived(msgType string)
+	// RoundFinished observes the time a round of `task` took, from its start until it had all of its messages
+	RoundFinished(task string, round int, elapsed time.Duration)
+	// SessionStarted is called when a party of `task` starts
+	SessionStarted(task string)
+	// SessionEnded is called once for every started session: when the party finishes, with a nil error, or when it
+	// fails in a round or is aborted, with the error
+	SessionEnded(task string, err *Error)
+}
+
+// startSession records that the party has started `task`. It must be called with the party locked.
+func (p *BaseParty) startSession(task string) {
+	p.task = task
+	if p.rnd != nil {
+		p.sink = p.rnd.Params().Metrics()
+	}
+	if p.sink != nil && !p.active {
+		p.active = true
+		p.sink.SessionStarted(task)
+	}
+}
+
+// endSession records that the session has ended, with `err` if it failed, and wipes the secrets of the party. It
+// must be called with the party locked.
+func (p *BaseParty) endSession(err *Error) {
+	p.endRoundSpan(err)
+	if p.sink != nil && p.active {
+		p.active = false
+		p.sink.SessionEnded(p.task, err)
+	}
+	if p.wiper != nil {
+		p.wiper.Wipe()
+		p.wiper = nil
+	}
+}
+
+func (p *BaseParty) setWiper(w Wiper) {
+	p.wiper = w
+}
+
+// countReceived records the arrival of a message. It must be called with the party locked.
+func (p *BaseParty) countReceived(msg ParsedMessage) {
+	if p.sink != nil {
+		p.sink.MessageReceived(msg.Type())
+	}
+}
@@ -7,11 +7,18 @@
 package tss
 
 import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
+	"fmt"
 	"io"
+	"math/big"
 	"runtime"
 	"time"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
 )
 
 type (
@@ -28,8 +35,32 @@ type (
 		// for keygen
 		noProofMod bool
 		noProofFac bool
+		// for ecdsa signing
+		noLowS bool
 		// random sources
 		partialKeyRand, rand io.Reader
+		// hash used for session identifiers
+		hashScheme common.HashScheme
+		// cancels the session and bounds its long computations
+		ctx context.Context
+		// mixed into the SSIDs and carried by the messages of the session
+		sessionID []byte
+		// signs the outbound wire messages
+		identityKey ed25519.PrivateKey
+		// deadline of each round
+		roundTimeout *RoundTimeout
+		// receives the measurements of the party
+		metrics Metrics
+		// creates the spans of the party
+		tracer Tracer
+		// compresses the outbound wire messages
+		compress bool
+		// serialises the outbound wire messages
+		encoding Encoding
+		// for keygen: who may sign with the key, in place of the threshold
+		accessStructure *AccessStructure
+		// for signing: unwraps the wrapped share of the party
+		shareWrapper ShareWrapper
 	}
 
 	ReSharingParameters struct {
@@ -56,9 +87,18 @@ func NewParameters(ec elliptic.Curve, ctx *PeerContext, partyID *PartyID, partyC
 		safePrimeGenTimeout: defaultSafePrimeGenTimeout,
 		partialKeyRand:      rand.Reader,
 		rand:                rand.Reader,
+		hashScheme:          defaultHashScheme(ec),
 	}
 }
 
+// BabyJubJub keys are meant for circuits over its base field, where Poseidon is the native hash
+func defaultHashScheme(ec elliptic.Curve) common.HashScheme {
+	if SameCurve(ec, BabyJubJub()) {
+		return common.HashPoseidon
+	}
+	return common.HashSHA512_256
+}
+
 func (params *Parameters) EC() elliptic.Curve {
 	return params.ec
 }
@@ -83,6 +123,96 @@ func (params *Parameters) Concurrency() int {
 	return params.concurrency
 }
 
+// Context returns the context of the session, context.Background() unless SetContext was called
+func (params *Parameters) Context() context.Context {
+	if params.ctx == nil {
+		return context.Background()
+	}
+	return params.ctx
+}
+
+// SetContext binds the session to `ctx`. Once it is done, the party refuses to start, to process messages or to
+// begin another round, returning the context error; safe prime generation also stops at its deadline.
+// Callers waiting on the out and end channels of the party should also select on ctx.Done().
+func (params *Parameters) SetContext(ctx context.Context) {
+	params.ctx = ctx
+}
+
+func (params *Parameters) SessionID() []byte {
+	return params.sessionID
+}
+
+// SetSessionID gives the session an explicit ID, agreed on by all of its parties and never reused. It is mixed into
+// the SSID of every protocol, so that proofs cannot be replayed across sessions, and set on every outbound message;
+// messages that carry another session ID are rejected. Messages parsed without a session ID are still accepted.
+func (params *Parameters) SetSessionID(sessionID []byte) {
+	params.sessionID = append([]byte(nil), sessionID...)
+}
+
+func (params *Parameters) IdentityKey() ed25519.PrivateKey {
+	return params.identityKey
+}
+
+// SetIdentityKey sets the long-term key that signs the wire bytes of every outbound message. Its public key must be
+// registered in the IdentityKey of this party's PartyID on the other parties, whose ParseWireMessage then rejects
+// any message from this party that it did not sign.
+func (params *Parameters) SetIdentityKey(key ed25519.PrivateKey) {
+	params.identityKey = key
+}
+
+// SessionIDInts returns the ints to append to the SSID of the session: none without a session ID, so that the
+// SSIDs of sessions without one are unchanged
+func (params *Parameters) SessionIDInts() []*big.Int {
+	return SessionIDInts(params.sessionID)
+}
+
+// SessionIDInts returns the length and the value of the session ID, or nothing for an empty ID
+func SessionIDInts(sessionID []byte) []*big.Int {
+	if len(sessionID) == 0 {
+		return nil
+	}
+	return []*big.Int{big.NewInt(int64(len(sessionID))), new(big.Int).SetBytes(sessionID)}
+}
+
+// Outbound prepares an outbound message of the party: it sets the session ID of the parameters, if any, has the
+// message encoded, compressed and signed with the identity key, if any, when its wire bytes are taken, counts it in
+// the metrics and attaches the trace context
+func (params *Parameters) Outbound(msg ParsedMessage) ParsedMessage {
+	impl, ok := msg.(*MessageImpl)
+	if !ok {
+		return msg
+	}
+	if len(params.sessionID) > 0 {
+		impl.setSessionID(params.sessionID)
+	}
+	if params.identityKey != nil {
+		impl.signer = params.identityKey
+	}
+	if params.metrics != nil {
+		params.metrics.MessageSent(msg.Type())
+	}
+	if params.tracer != nil {
+		impl.TraceContext = params.tracer.Inject(params.Context())
+	}
+	if params.compress {
+		impl.setCompressed()
+	}
+	impl.encoding = params.encoding
+	return msg
+}
+
+// ValidateSessionID checks that a message carrying a session ID belongs to this session
+func (params *Parameters) ValidateSessionID(msg ParsedMessage) error {
+	impl, ok := msg.(*MessageImpl)
+	if !ok || len(impl.SessionID) == 0 || len(params.sessionID) == 0 {
+		return nil
+	}
+	if !bytes.Equal(impl.SessionID, params.sessionID) {
+		return fmt.Errorf("received msg of unknown or expired session %x", impl.SessionID)
+	}
+	return nil
+}
+
 func (params *Parameters) SafePrimeGenTimeout() time.Duration {
 	return params.safePrimeGenTimeout
 }
@@ -96,6 +226,78 @@ func (params *Parameters) SetSafePrimeGenTimeout(timeout time.Duration) {
 	params.safePrimeGenTimeout = timeout
 }
 
+func (params *Parameters) RoundTimeout() *RoundTimeout {
+	return params.roundTimeout
+}
+
+// SetRoundTimeout makes the party re-request, and eventually abort, a round whose messages do not all arrive in time.
+// By default a party waits for its messages for as long as its context allows.
+func (params *Parameters) SetRoundTimeout(rt *RoundTimeout) {
+	params.roundTimeout = rt
+}
+
+func (params *Parameters) Metrics() Metrics {
+	return params.metrics
+}
+
+// SetMetrics makes the party report its messages, rounds and session to `metrics`
+func (params *Parameters) SetMetrics(metrics Metrics) {
+	params.metrics = metrics
+}
+
+func (params *Parameters) Tracer() Tracer {
+	return params.tracer
+}
+
+// SetTracer makes the party trace its rounds and the messages it receives with `tracer`, as children of the span in
+// the context of the parameters
+func (params *Parameters) SetTracer(tracer Tracer) {
+	params.tracer = tracer
+}
+
+func (params *Parameters) Compression() bool {
+	return params.compress
+}
+
+// SetCompression has the wire bytes of the party's messages gzip-compressed, which shrinks the large Paillier and
+// ring-Pedersen proofs of ECDSA keygen several times over. Receivers detect compressed messages by themselves, so it
+// only needs to be enabled on the senders.
+func (params *Parameters) SetCompression(enabled bool) {
+	params.compress = enabled
+}
+
+func (params *Parameters) Encoding() Encoding {
+	return params.encoding
+}
+
+// SetEncoding selects the encoding of the wire bytes of the party's messages. Receivers detect the encoding of each
+// message by themselves; CBOR messages can only be read by parties of protocol version 2 or later. The save data
+// of the session may be serialised with the same encoding by MarshalSaveData.
+func (params *Parameters) SetEncoding(enc Encoding) {
+	params.encoding = enc
+}
+
+func (params *Parameters) AccessStructure() *AccessStructure {
+	return params.accessStructure
+}
+
+// SetAccessStructure makes keygen share the key along a nested access structure over the parties of the session,
+// rather than with the threshold of the parameters, which must then be MinSigners()-1: keygen fails otherwise. Every
+// party must set the same structure. Keys with an access structure cannot be refreshed, reshared or repaired.
+func (params *Parameters) SetAccessStructure(as *AccessStructure) {
+	params.accessStructure = as
+}
+
+func (params *Parameters) ShareWrapper() ShareWrapper {
+	return params.shareWrapper
+}
+
+// SetShareWrapper sets the wrapper that signing unwraps the share of the party with, when the save data holds it
+// wrapped rather than in Xi
+func (params *Parameters) SetShareWrapper(w ShareWrapper) {
+	params.shareWrapper = w
+}
+
 func (params *Parameters) NoProofMod() bool {
 	return params.noProofMod
 }
@@ -112,6 +314,16 @@ func (params *Parameters) SetNoProofFac() {
 	params.noProofFac = true
 }
 
+// NoLowS reports whether ECDSA signing may output a high S. By default S is normalised to the lower half
+// of the curve order, as most chains reject the malleable high-S form.
+func (params *Parameters) NoLowS() bool {
+	return params.noLowS
+}
+
+func (params *Parameters) SetNoLowS() {
+	params.noLowS = true
+}
+
 func (params *Parameters) PartialKeyRand() io.Reader {
 	return params.partialKeyRand
 }
@@ -128,6 +340,35 @@ func (params *Parameters) SetRand(rand io.Reader) {
 	params.rand = rand
 }
 
+// SetEntropySource makes `rand` the single source of randomness of the party, for both Rand and PartialKeyRand.
+// Pass a common.DeterministicReader to reproduce a run byte-for-byte in tests and audits, or a common.MixedReader to
+// draw from a hardware RNG mixed with the RNG of the OS.
+func (params *Parameters) SetEntropySource(rand io.Reader) {
+	params.rand = rand
+	params.partialKeyRand = common.ForkRandom(rand, "partial key")
+}
+
+func (params *Parameters) HashScheme() common.HashScheme {
+	return params.hashScheme
+}
+
+// SetHashScheme selects the hash used to derive the session identifiers and hash commitments of keygen, resharing
+// and signing, and with common.HashPoseidon or common.HashRescuePrime the Fiat-Shamir challenges of the ECDSA signing
+// proofs as well (see common.HashScheme.ChallengeHash).
+// Schemes beyond the built-in ones may be added with common.RegisterHasher. Every party of the session must use the
+// same scheme; the default is common.HashPoseidon on BabyJubJub and common.HashSHA512_256 on every other curve.
+func (params *Parameters) SetHashScheme(scheme common.HashScheme) {
+	params.hashScheme = scheme
+}
+
+// ValidateHashScheme checks that `keyScheme`, recorded in the save data of a key, is the hash scheme of this session
+func (params *Parameters) ValidateHashScheme(keyScheme common.HashScheme) error {
+	if keyScheme != params.hashScheme {
+		return fmt.Errorf("the key was generated with the %s hash scheme, but this session uses %s", keyScheme, params.hashScheme)
+	}
+	return nil
+}
+
 // ----- //
 
 // Exported, used in `tss` client
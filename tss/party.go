@@ -7,9 +7,14 @@
 package tss
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 )
@@ -34,14 +39,78 @@ type Party interface {
 	setRound(Round) *Error
 	round() Round
 	advance()
+	started() bool
+	enqueue(ParsedMessage) bool
+	dequeueAll() []ParsedMessage
+	startRoundTimer()
+	abortError() *Error
+	checkDuplicate(ParsedMessage) (bool, *Error)
+	rateLimit(ParsedMessage) *Error
+	strike(ParsedMessage)
+	startSession(task string)
+	endSession(*Error)
+	setWiper(Wiper)
+	countReceived(ParsedMessage)
+	startRoundSpan(task string)
+	endRoundSpan(*Error)
+	startMessageSpan(task string, msg ParsedMessage) Span
+	notifyRoundStarted()
+	notifyRoundFinished()
+	notifyMessageReceived(ParsedMessage)
+	fireEvents()
 	lock()
 	unlock()
 }
 
+// Wiper is implemented by the parties that keep secrets in their temporary data, such as the polynomial of keygen or
+// the nonce of signing. Wipe overwrites them on a best-effort basis (see common.ZeroInt), and is called when the
+// session of the party ends, whether it finished, failed or was aborted. The save data and pre-signatures a party
+// hands over are the caller's, and are left for the caller to wipe.
+type Wiper interface {
+	Wipe()
+}
+
+// MaxQueuedMessages bounds the messages a party holds before Start, as a party that is never started would otherwise
+// keep every message sent to it. A message beyond it is dropped with an error wrapping ErrQueueFull.
+const MaxQueuedMessages = 1024
+
+// ErrQueueFull is wrapped by the error of a party for a message dropped because MaxQueuedMessages were held before Start
+var ErrQueueFull = errors.New("too many messages received before Start")
+
 type BaseParty struct {
 	mtx        sync.Mutex
 	rnd        Round
 	FirstRound Round
+
+	// messages received before Start(), replayed once the first round has started
+	begun  bool
+	queued []ParsedMessage
+
+	// digests of the messages received so far, by sender and type
+	received map[string][]byte
+
+	// the deadline of the current round and the error of a round aborted by it (see RoundTimeout)
+	timer    *time.Timer
+	timerGen int
+	resends  int
+	aborted  *Error
+
+	// lifecycle callbacks and the events waiting to be passed to them
+	hooks          roundHooks
+	roundStartedAt time.Time
+	events         []func()
+	firing         bool
+
+	// the task of the party and its metrics, if any, while its session is active
+	task   string
+	sink   Metrics
+	active bool
+	// the span of the current round, if the party has a Tracer
+	roundSpan Span
+	// throttles the messages of each peer, if set (see SetRateLimiter)
+	limiter *RateLimiter
+	// wipes the secrets of the party when its session ends, if it has any
+	wiper Wiper
 }
 
 func (p *BaseParty) Running() bool {
@@ -67,17 +136,30 @@ func (p *BaseParty) WrapError(err error, culprits ...*PartyID) *Error {
 // an implementation of ValidateMessage that is shared across the different types of parties (keygen, signing, dynamic groups)
 func (p *BaseParty) ValidateMessage(msg ParsedMessage) (bool, *Error) {
 	if msg == nil || msg.Content() == nil {
-		return false, p.WrapError(fmt.Errorf("received nil msg: %s", msg))
+		return false, p.WrapError(fmt.Errorf("received nil msg: %s", msg)).WithCode(CodeInvalidMessage)
 	}
 	if msg.GetFrom() == nil || !msg.GetFrom().ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg))
+		return false, p.WrapError(fmt.Errorf("received msg with an invalid sender: %s", msg)).WithCode(CodeInvalidMessage)
 	}
 	if !msg.ValidateBasic() {
-		return false, p.WrapError(fmt.Errorf("message failed ValidateBasic: %s", msg), msg.GetFrom())
+		return false, p.WrapError(fmt.Errorf("message failed ValidateBasic: %s", msg), msg.GetFrom()).WithCode(CodeInvalidMessage)
 	}
 	return true, nil
 }
 
+// RoundNumber returns the number of the round the party is in: 1 before Start, and -1 once every round has finished
+func (p *BaseParty) RoundNumber() int {
+	p.lock()
+	defer p.unlock()
+	if p.rnd != nil {
+		return p.rnd.RoundNumber()
+	}
+	if p.begun {
+		return -1
+	}
+	return 1
+}
+
 func (p *BaseParty) String() string {
 	if rnd := p.round(); rnd != nil {
 		return fmt.Sprintf("round: %d", rnd.RoundNumber())
@@ -91,9 +173,10 @@ func (p *BaseParty) String() string {
 
 func (p *BaseParty) setRound(round Round) *Error {
 	if p.rnd != nil {
-		return p.WrapError(errors.New("a round is already set on this party"))
+		return p.WrapError(errors.New("a round is already set on this party")).WithCode(CodeInternal)
 	}
 	p.rnd = round
+	p.begun = true
 	return nil
 }
 
@@ -105,6 +188,47 @@ func (p *BaseParty) advance() {
 	p.rnd = p.rnd.NextRound()
 }
 
+func (p *BaseParty) started() bool {
+	return p.begun
+}
+
+// checkDuplicate records the message, and reports whether the sender had already sent it. A different message of the
+// same type from the same sender is an equivocation, and an error.
+func (p *BaseParty) checkDuplicate(msg ParsedMessage) (bool, *Error) {
+	bz, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg.Content())
+	if err != nil {
+		return false, p.WrapError(err, msg.GetFrom()).WithCode(CodeInvalidMessage)
+	}
+	digest := sha256.Sum256(bz)
+	key := string(msg.GetFrom().Key) + "/" + msg.Type()
+	if prev, ok := p.received[key]; ok {
+		if bytes.Equal(prev, digest[:]) {
+			return true, nil
+		}
+		return false, p.WrapError(fmt.Errorf("received two different messages %s", msg.Type()), msg.GetFrom()).WithCode(CodeDuplicateMessage)
+	}
+	if p.received == nil {
+		p.received = make(map[string][]byte)
+	}
+	p.received[key] = digest[:]
+	return false, nil
+}
+
+// enqueue holds a message received before Start, or returns false when MaxQueuedMessages are already held
+func (p *BaseParty) enqueue(msg ParsedMessage) bool {
+	if len(p.queued) >= MaxQueuedMessages {
+		return false
+	}
+	p.queued = append(p.queued, msg)
+	return true
+}
+
+func (p *BaseParty) dequeueAll() []ParsedMessage {
+	queued := p.queued
+	p.queued = nil
+	return queued
+}
+
 func (p *BaseParty) lock() {
 	p.mtx.Lock()
 }
@@ -115,71 +239,180 @@ func (p *BaseParty) unlock() {
 
 // ----- //
 
+// BaseStart starts the first round and then replays any messages that were received before the party was started,
+// so callers need not hold back or redeliver messages that arrive early.
 func BaseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
+	err := baseStart(p, task, prepare...)
+	p.fireEvents()
+	if err != nil {
+		return err
+	}
+	p.lock()
+	queued := p.dequeueAll()
+	p.unlock()
+	for _, msg := range queued {
+		// the arrival of a queued message has already been reported
+		_, err := baseUpdate(p, msg, task, false, false)
+		p.fireEvents()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func baseStart(p Party, task string, prepare ...func(Round) *Error) *Error {
 	p.lock()
 	defer p.unlock()
 	if p.PartyID() == nil || !p.PartyID().ValidateBasic() {
-		return p.WrapError(fmt.Errorf("could not start. this party has an invalid PartyID: %+v", p.PartyID()))
+		return p.WrapError(fmt.Errorf("could not start. this party has an invalid PartyID: %+v", p.PartyID())).WithCode(CodeInvalidInput)
 	}
 	if p.round() != nil {
-		return p.WrapError(errors.New("could not start. this party is in an unexpected state. use the constructor and Start()"))
+		return p.WrapError(errors.New("could not start. this party is in an unexpected state. use the constructor and Start()")).WithCode(CodeInternal)
 	}
 	round := p.FirstRound()
 	if err := p.setRound(round); err != nil {
 		return err
 	}
+	if w, ok := p.(Wiper); ok {
+		p.setWiper(w)
+	}
 	if 1 < len(prepare) {
-		return p.WrapError(errors.New("too many prepare functions given to Start(); 1 allowed"))
+		return p.WrapError(errors.New("too many prepare functions given to Start(); 1 allowed")).WithCode(CodeInvalidInput)
 	}
 	if len(prepare) == 1 {
 		if err := prepare[0](round); err != nil {
 			return err
 		}
 	}
+	if err := round.Params().Context().Err(); err != nil {
+		return p.WrapError(err).WithCode(CodeCancelled)
+	}
 	common.Logger.Infof("party %s: %s round %d starting", p.round().Params().PartyID(), task, 1)
 	defer func() {
 		common.Logger.Debugf("party %s: %s round %d finished", p.round().Params().PartyID(), task, 1)
 	}()
-	return p.round().Start()
+	p.startRoundSpan(task)
+	if err := p.round().Start(); err != nil {
+		p.endSession(err)
+		return err
+	}
+	p.startSession(task)
+	p.startRoundTimer()
+	p.notifyRoundStarted()
+	return nil
 }
 
 // an implementation of Update that is shared across the different types of parties (keygen, signing, dynamic groups)
+// Messages may be given in any order: those received before Start are queued until the first round has started, and
+// those of a later round are stored by StoreMessage and picked up once that round can accept them, so callers need
+// no retry loop.
 func BaseUpdate(p Party, msg ParsedMessage, task string) (ok bool, err *Error) {
+	ok, err = baseUpdate(p, msg, task, true, false)
+	p.fireEvents()
+	return ok, err
+}
+
+// `arrival` is false when a queued message is replayed, and `rerun` when a message is handed again to the next round
+func baseUpdate(p Party, msg ParsedMessage, task string, arrival, rerun bool) (ok bool, err *Error) {
+	// drop the messages of a peer that sends too many before spending any time on them
+	if arrival {
+		if err := p.rateLimit(msg); err != nil {
+			return false, err
+		}
+	}
 	// fast-fail on an invalid message; do not lock the mutex yet
 	if _, err := p.ValidateMessage(msg); err != nil {
+		p.strike(msg)
 		return false, err
 	}
+	// a signed message that its sender did not address to this party was redirected on the way, so its sender is not blamed
+	if impl, ok := msg.(*MessageImpl); ok && !impl.addressedTo(p.PartyID()) {
+		return false, p.WrapError(fmt.Errorf("received msg from %s addressed to other parties", msg.GetFrom())).
+			WithCode(CodeInvalidMessage)
+	}
 	// lock the mutex. need this mtx unlock hook; L108 is recursive so cannot use defer
+	var span Span // of the processing of this message, if the party has a Tracer
 	r := func(ok bool, err *Error) (bool, *Error) {
+		if span != nil {
+			span.End(spanError(err))
+		}
 		p.unlock()
 		return ok, err
 	}
 	p.lock() // data is written to P state below
 	common.Logger.Debugf("party %s received message: %s", p.PartyID(), msg.String())
+	if arrival {
+		p.notifyMessageReceived(msg)
+	}
+	if err := p.abortError(); err != nil {
+		return r(false, err)
+	}
+	if !p.started() {
+		// too early: hold the message until the first round has started. messages for later rounds are kept by
+		// StoreMessage and picked up by those rounds' Update once they begin.
+		if !p.enqueue(msg) {
+			return r(false, p.WrapError(fmt.Errorf("dropped a message from %s: %w", msg.GetFrom(), ErrQueueFull)).
+				WithCode(CodeRateLimited))
+		}
+		return r(true, nil)
+	}
 	if p.round() != nil {
+		if err := p.round().Params().Context().Err(); err != nil {
+			tssErr := p.WrapError(err).WithCode(CodeCancelled)
+			p.endSession(tssErr)
+			return r(false, tssErr)
+		}
+		if err := p.round().Params().ValidateSessionID(msg); err != nil {
+			p.strike(msg)
+			return r(false, p.WrapError(err, msg.GetFrom()).WithCode(CodeWrongSession))
+		}
 		common.Logger.Debugf("party %s round %d update: %s", p.PartyID(), p.round().RoundNumber(), msg.String())
 	}
+	if !rerun {
+		p.countReceived(msg)
+		if seen, err := p.checkDuplicate(msg); err != nil {
+			p.strike(msg)
+			return r(false, err)
+		} else if seen {
+			// an identical copy, e.g. resent after a ResendRequest, has nothing new but is no misbehaviour
+			return r(true, nil)
+		}
+		span = p.startMessageSpan(task, msg)
+	}
 	if ok, err := p.StoreMessage(msg); err != nil || !ok {
 		return r(false, err)
 	}
 	if p.round() != nil {
 		common.Logger.Debugf("party %s: %s round %d update", p.round().Params().PartyID(), task, p.round().RoundNumber())
 		if _, err := p.round().Update(); err != nil {
+			p.endSession(err)
 			return r(false, err)
 		}
 		if p.round().CanProceed() {
-			if p.advance(); p.round() != nil {
+			p.notifyRoundFinished()
+			p.endRoundSpan(nil)
+			p.advance()
+			p.startRoundTimer()
+			if p.round() != nil {
+				p.startRoundSpan(task)
 				if err := p.round().Start(); err != nil {
+					p.endSession(err)
 					return r(false, err)
 				}
+				p.notifyRoundStarted()
 				rndNum := p.round().RoundNumber()
 				common.Logger.Infof("party %s: %s round %d started", p.round().Params().PartyID(), task, rndNum)
 			} else {
 				// finished! the round implementation will have sent the data through the `end` channel.
 				common.Logger.Infof("party %s: %s finished!", p.PartyID(), task)
+				p.endSession(nil)
+			}
+			if span != nil {
+				span.End(nil)
 			}
-			p.unlock()                      // recursive so can't defer after return
-			return BaseUpdate(p, msg, task) // re-run round update or finish)
+			p.unlock()                                   // recursive so can't defer after return
+			return baseUpdate(p, msg, task, false, true) // re-run round update or finish)
 		}
 		return r(true, nil)
 	}
@@ -7,14 +7,19 @@
 package tss
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"math/big"
 	"sort"
 
 	"github.com/bnb-chain/tss-lib/v2/common"
 )
 
+const partyKeyDerivationTag = "tss-lib party key"
+
 type (
 	// PartyID represents a participant in the TSS protocol rounds.
 	// Note: The `id` and `moniker` are provided for convenience to allow you to track participants easier.
@@ -22,6 +27,9 @@ type (
 	PartyID struct {
 		*MessageWrapper_PartyID
 		Index int `json:"index"`
+		// The long-term public key that the party signs its wire messages with, if any; see Parameters.SetIdentityKey.
+		// When it is set, ParseWireMessage only accepts messages from this party that carry its signature.
+		IdentityKey ed25519.PublicKey `json:"identity_key,omitempty"`
 	}
 
 	UnSortedPartyIDs []*PartyID
@@ -53,6 +61,56 @@ func NewPartyID(id, moniker string, key *big.Int) *PartyID {
 	}
 }
 
+// Clone returns a deep copy of the PartyID, with its identity key, so that re-indexing the copy leaves `pid` as is
+func (pid *PartyID) Clone() *PartyID {
+	clone := NewPartyID(pid.Id, pid.Moniker, pid.KeyInt())
+	clone.Index = pid.Index
+	if pid.IdentityKey != nil {
+		clone.IdentityKey = append(ed25519.PublicKey(nil), pid.IdentityKey...)
+	}
+	return clone
+}
+
+// NewPartyIDFromPublicKey derives a PartyID from the long-term public key of a party, in any encoding, so that
+// coordinators that only share the public keys of a committee assign every party the same key, and thus once sorted
+// the same index. The key is a hash of `pub` short enough to be a valid index on every supported curve, the ID is
+// `pub` in hex and the moniker its first 8 hex digits.
+func NewPartyIDFromPublicKey(pub []byte) *PartyID {
+	id := hex.EncodeToString(pub)
+	moniker := id
+	if len(moniker) > 8 {
+		moniker = moniker[:8]
+	}
+	return NewPartyID(id, moniker, partyKeyFromPublicKey(pub))
+}
+
+// NewPartyIDFromIdentityKey is NewPartyIDFromPublicKey for the ed25519 key that the party signs its wire messages
+// with (see Parameters.SetIdentityKey); the key is also set as its IdentityKey.
+func NewPartyIDFromIdentityKey(pub ed25519.PublicKey) *PartyID {
+	pid := NewPartyIDFromPublicKey(pub)
+	pid.IdentityKey = pub
+	return pid
+}
+
+// SortedPartyIDsFromPublicKeys derives the party IDs of a committee with NewPartyIDFromPublicKey, and sorts them
+func SortedPartyIDsFromPublicKeys(pubs ...[]byte) SortedPartyIDs {
+	ids := make(UnSortedPartyIDs, len(pubs))
+	for i, pub := range pubs {
+		ids[i] = NewPartyIDFromPublicKey(pub)
+	}
+	return SortPartyIDs(ids)
+}
+
+// partyKeyFromPublicKey hashes the public key to 248 bits, below the order of every supported curve, and never to 0
+func partyKeyFromPublicKey(pub []byte) *big.Int {
+	for ctr := byte(0); ; ctr++ {
+		digest := common.SHA512_256([]byte(partyKeyDerivationTag), pub, []byte{ctr})
+		if key := new(big.Int).SetBytes(digest[:31]); key.Sign() != 0 {
+			return key
+		}
+	}
+}
+
 func (pid PartyID) String() string {
 	return fmt.Sprintf("{%d,%s}", pid.Index, pid.Moniker)
 }
@@ -80,8 +138,13 @@ func SortPartyIDs(ids UnSortedPartyIDs, startAt ...int) SortedPartyIDs {
 
 // GenerateTestPartyIDs generates a list of mock PartyIDs for tests
 func GenerateTestPartyIDs(count int, startAt ...int) SortedPartyIDs {
+	return GenerateTestPartyIDsWithRand(rand.Reader, count, startAt...)
+}
+
+// GenerateTestPartyIDsWithRand generates a list of mock PartyIDs for tests, drawing their keys from `rand`
+func GenerateTestPartyIDsWithRand(rand io.Reader, count int, startAt ...int) SortedPartyIDs {
 	ids := make(UnSortedPartyIDs, 0, count)
-	key := common.MustGetRandomInt(rand.Reader, 256)
+	key := common.MustGetRandomInt(rand, 256)
 	frm := 0
 	i := 0 // default `i`
 	if len(startAt) > 0 {
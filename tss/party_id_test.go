@@ -0,0 +1,46 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/crypto/vss"
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestPartyIDsFromPublicKeys(t *testing.T) {
+	pubs := make([][]byte, test.TestParticipants)
+	for i := range pubs {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		pubs[i] = pub
+	}
+	reversed := make([][]byte, len(pubs))
+	for i, pub := range pubs {
+		reversed[len(pubs)-1-i] = pub
+	}
+
+	// two coordinators that list the committee in different orders agree on the keys and the indexes
+	pIDs, other := tss.SortedPartyIDsFromPublicKeys(pubs...), tss.SortedPartyIDsFromPublicKeys(reversed...)
+	for i := range pIDs {
+		assert.Equal(t, pIDs[i].Key, other[i].Key)
+		assert.Equal(t, pIDs[i].Id, other[i].Id)
+		assert.Equal(t, i, other[i].Index)
+	}
+	_, err := vss.CheckIndexes(tss.Edwards(), pIDs.Keys())
+	assert.NoError(t, err)
+
+	pid := tss.NewPartyIDFromIdentityKey(pubs[0])
+	assert.Equal(t, ed25519.PublicKey(pubs[0]), pid.IdentityKey)
+	assert.NotNil(t, pIDs.FindByKey(pid.KeyInt()))
+}
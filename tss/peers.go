@@ -6,6 +6,10 @@
 
 package tss
 
+import (
+	"fmt"
+)
+
 type (
 	PeerContext struct {
 		partyIDs SortedPartyIDs
@@ -23,3 +27,120 @@ func (p2pCtx *PeerContext) IDs() SortedPartyIDs {
 func (p2pCtx *PeerContext) SetIDs(ids SortedPartyIDs) {
 	p2pCtx.partyIDs = ids
 }
+
+// PeerContextProblem is what ValidatePeerContext found wrong with a peer context
+type PeerContextProblem int
+
+const (
+	// the context has no parties
+	PeerContextEmpty PeerContextProblem = iota
+	// a party ID is nil
+	PeerContextNilParty
+	// a party has an empty or zero key
+	PeerContextZeroKey
+	// two parties have the same key
+	PeerContextDuplicateKey
+	// two parties have the same ID
+	PeerContextDuplicateID
+	// the parties are not sorted by key, e.g. they were not given to SortPartyIDs
+	PeerContextUnsorted
+	// the indexes of the parties are not consecutive from that of the first one
+	PeerContextBadIndex
+	// this party is not in the context
+	PeerContextSelfMissing
+	// this party has the key of a party of the context, but another index or ID
+	PeerContextSelfMismatch
+)
+
+var peerContextProblems = map[PeerContextProblem]string{
+	PeerContextEmpty:        "the peer context has no parties",
+	PeerContextNilParty:     "nil party ID",
+	PeerContextZeroKey:      "empty or zero key",
+	PeerContextDuplicateKey: "duplicate key",
+	PeerContextDuplicateID:  "duplicate ID",
+	PeerContextUnsorted:     "the parties are not sorted by key",
+	PeerContextBadIndex:     "the party indexes are not consecutive",
+	PeerContextSelfMissing:  "this party is not in the peer context",
+	PeerContextSelfMismatch: "this party differs from the party of the peer context with its key",
+}
+
+func (problem PeerContextProblem) String() string {
+	if s, ok := peerContextProblems[problem]; ok {
+		return s
+	}
+	return fmt.Sprintf("PeerContextProblem(%d)", int(problem))
+}
+
+// PeerContextError is the error of ValidatePeerContext: the problem, the position in the context of the party that
+// has it, or -1, and that party, if any
+type PeerContextError struct {
+	Problem  PeerContextProblem
+	Position int
+	Party    *PartyID
+}
+
+func (err *PeerContextError) Error() string {
+	msg := fmt.Sprintf("invalid peer context: %s", err.Problem)
+	if 0 <= err.Position {
+		msg += fmt.Sprintf(" at position %d", err.Position)
+	}
+	if err.Party != nil {
+		msg += fmt.Sprintf(": %s", err.Party)
+	}
+	return msg
+}
+
+// ValidatePeerContext checks a peer context before it is given to NewParameters, so that an inconsistent committee
+// fails at once rather than in the middle of a round: every party must have a non-zero key and a unique key and ID,
+// the parties must be sorted with consecutive indexes, and `self`, unless it is nil, must be one of them.
+// The error is a *PeerContextError.
+func ValidatePeerContext(p2pCtx *PeerContext, self *PartyID) error {
+	if p2pCtx == nil || len(p2pCtx.IDs()) == 0 {
+		return &PeerContextError{Problem: PeerContextEmpty, Position: -1}
+	}
+	ids := p2pCtx.IDs()
+	keys := make(map[string]struct{}, len(ids))
+	names := make(map[string]struct{}, len(ids))
+	for i, pid := range ids {
+		if pid == nil || pid.MessageWrapper_PartyID == nil {
+			return &PeerContextError{Problem: PeerContextNilParty, Position: i}
+		}
+		fail := func(problem PeerContextProblem) error {
+			return &PeerContextError{Problem: problem, Position: i, Party: pid}
+		}
+		key := pid.KeyInt()
+		if key.Sign() == 0 {
+			return fail(PeerContextZeroKey)
+		}
+		if _, ok := keys[key.String()]; ok {
+			return fail(PeerContextDuplicateKey)
+		}
+		keys[key.String()] = struct{}{}
+		if pid.Id != "" {
+			if _, ok := names[pid.Id]; ok {
+				return fail(PeerContextDuplicateID)
+			}
+			names[pid.Id] = struct{}{}
+		}
+		if 0 < i && ids[i-1].KeyInt().Cmp(key) > 0 {
+			return fail(PeerContextUnsorted)
+		}
+		if pid.Index < 0 || pid.Index != ids[0].Index+i {
+			return fail(PeerContextBadIndex)
+		}
+	}
+	if self == nil {
+		return nil
+	}
+	if self.MessageWrapper_PartyID == nil {
+		return &PeerContextError{Problem: PeerContextSelfMissing, Position: -1}
+	}
+	member := ids.FindByKey(self.KeyInt())
+	if member == nil {
+		return &PeerContextError{Problem: PeerContextSelfMissing, Position: -1, Party: self}
+	}
+	if member.Index != self.Index || member.Id != self.Id {
+		return &PeerContextError{Problem: PeerContextSelfMismatch, Position: member.Index - ids[0].Index, Party: self}
+	}
+	return nil
+}
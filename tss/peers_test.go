@@ -0,0 +1,57 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bnb-chain/tss-lib/v2/test"
+	"github.com/bnb-chain/tss-lib/v2/tss"
+)
+
+func TestValidatePeerContext(t *testing.T) {
+	pIDs := tss.GenerateTestPartyIDs(test.TestParticipants)
+	assert.NoError(t, tss.ValidatePeerContext(tss.NewPeerContext(pIDs), pIDs[2]))
+	assert.NoError(t, tss.ValidatePeerContext(tss.NewPeerContext(tss.GenerateTestPartyIDs(3, 4)), nil))
+
+	zero := pIDs[1].Clone()
+	zero.Key = []byte{0}
+	dupKey := pIDs[1].Clone()
+	dupKey.Id, dupKey.Index = "other", 2
+	dupID := pIDs[2].Clone()
+	dupID.Id = pIDs[1].Id
+	gapped := pIDs[2].Clone()
+	gapped.Index = 3
+	stranger := tss.GenerateTestPartyIDs(1)[0]
+	impostor := pIDs[1].Clone()
+	impostor.Index = 0
+
+	for _, tc := range []struct {
+		ids     tss.SortedPartyIDs
+		self    *tss.PartyID
+		problem tss.PeerContextProblem
+	}{
+		{nil, nil, tss.PeerContextEmpty},
+		{tss.SortedPartyIDs{pIDs[0], nil, pIDs[2]}, nil, tss.PeerContextNilParty},
+		{tss.SortedPartyIDs{pIDs[0], zero, pIDs[2]}, nil, tss.PeerContextZeroKey},
+		{tss.SortedPartyIDs{pIDs[0], pIDs[1], dupKey}, nil, tss.PeerContextDuplicateKey},
+		{tss.SortedPartyIDs{pIDs[0], pIDs[1], dupID}, nil, tss.PeerContextDuplicateID},
+		{tss.SortedPartyIDs{pIDs[1], pIDs[0], pIDs[2]}, nil, tss.PeerContextUnsorted},
+		{tss.SortedPartyIDs{pIDs[0], pIDs[1], gapped}, nil, tss.PeerContextBadIndex},
+		{pIDs, stranger, tss.PeerContextSelfMissing},
+		{pIDs, impostor, tss.PeerContextSelfMismatch},
+	} {
+		err := tss.ValidatePeerContext(tss.NewPeerContext(tc.ids), tc.self)
+		var pcErr *tss.PeerContextError
+		if assert.True(t, errors.As(err, &pcErr), "expected %s", tc.problem) {
+			assert.Equal(t, tc.problem, pcErr.Problem, err.Error())
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"sync"
+)
+
+// RoundProgress reports the rounds completed by a party to the caller's callbacks, once per round and in order.
+// Report is called by a LocalParty after each Start and Update, once the party has been unlocked, so the callbacks
+// may safely call back into the party.
+type RoundProgress struct {
+	mtx      sync.Mutex
+	total    int
+	reported int
+
+	// OnRoundComplete is called with the number of each round once the party has completed it
+	OnRoundComplete func(round int)
+	// OnProgress is called with the percentage of the rounds completed so far, after OnRoundComplete
+	OnProgress func(percent float64)
+}
+
+func NewRoundProgress(totalRounds int) *RoundProgress {
+	return &RoundProgress{total: totalRounds}
+}
+
+// Report calls the callbacks for every round that `p` completed since the last report
+func (rp *RoundProgress) Report(p *BaseParty) {
+	rp.mtx.Lock()
+	defer rp.mtx.Unlock()
+	completed := rp.total
+	if rnd := p.RoundNumber(); 0 <= rnd {
+		completed = rnd - 1
+	}
+	for ; rp.reported < completed; rp.reported++ {
+		if rp.OnRoundComplete != nil {
+			rp.OnRoundComplete(rp.reported + 1)
+		}
+		if rp.OnProgress != nil {
+			rp.OnProgress(float64(rp.reported+1) * 100 / float64(rp.total))
+		}
+	}
+}
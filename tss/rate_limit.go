@@ -0,0 +1,144 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type (
+	// RateLimiter throttles the messages of each peer with a token bucket, so that a peer spamming a party cannot make
+	// it spend its CPU validating them. A peer that is throttled, or that sends conflicting or invalid messages, earns
+	// a strike, and is reported as a culprit once it has more strikes than allowed. One limiter may be shared by the
+	// parties of several sessions, so that a peer is limited across all of them.
+	RateLimiter struct {
+		mtx        sync.Mutex
+		rate       float64
+		burst      float64
+		maxStrikes int
+		peers      map[string]*peerBucket
+	}
+
+	peerBucket struct {
+		tokens  float64
+		last    time.Time
+		strikes int
+	}
+)
+
+// ErrRateLimited is wrapped by the error of a party for a message that its RateLimiter refused
+var ErrRateLimited = errors.New("peer exceeded its message rate")
+
+// NewRateLimiter returns a limiter that lets each peer send `burst` messages at once and `rate` messages per second
+// on average, and reports it after `maxStrikes` strikes. Identical messages resent on a ResendRequest take tokens
+// but earn no strike.
+func NewRateLimiter(rate float64, burst, maxStrikes int) *RateLimiter {
+	return &RateLimiter{
+		rate:       rate,
+		burst:      float64(burst),
+		maxStrikes: maxStrikes,
+		peers:      make(map[string]*peerBucket),
+	}
+}
+
+// Strikes returns the number of strikes of the peer so far
+func (l *RateLimiter) Strikes(from *PartyID) int {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if b, ok := l.peers[string(from.Key)]; ok {
+		return b.strikes
+	}
+	return 0
+}
+
+// Forgive clears the strikes of the peer and refills its bucket, e.g. once it has been restarted
+func (l *RateLimiter) Forgive(from *PartyID) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	delete(l.peers, string(from.Key))
+}
+
+// take takes a token for a message from the peer. It returns ErrRateLimited when there is none, which earns a
+// strike, and a wrapping error when the peer has too many strikes.
+func (l *RateLimiter) take(from *PartyID) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	b := l.bucket(from)
+	if b.strikes > l.maxStrikes {
+		return l.reported(b)
+	}
+	now := time.Now()
+	if b.tokens += now.Sub(b.last).Seconds() * l.rate; b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		if b.strikes++; b.strikes > l.maxStrikes {
+			return l.reported(b)
+		}
+		return ErrRateLimited
+	}
+	b.tokens--
+	return nil
+}
+
+// strike records a conflicting or invalid message from the peer
+func (l *RateLimiter) strike(from *PartyID) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.bucket(from).strikes++
+}
+
+// reported returns the error of take for a peer with too many strikes
+func (l *RateLimiter) reported(b *peerBucket) error {
+	return fmt.Errorf("%w: the peer has %d strikes, more than %d", ErrRateLimited, b.strikes, l.maxStrikes)
+}
+
+func (l *RateLimiter) bucket(from *PartyID) *peerBucket {
+	b, ok := l.peers[string(from.Key)]
+	if !ok {
+		b = &peerBucket{tokens: l.burst, last: time.Now()}
+		l.peers[string(from.Key)] = b
+	}
+	return b
+}
+
+// ----- //
+
+// SetRateLimiter makes the party refuse the messages of a peer that exceed the rate of `l`, and report the peer as a
+// culprit once it has too many strikes. It must be set before Start.
+func (p *BaseParty) SetRateLimiter(l *RateLimiter) {
+	p.limiter = l
+}
+
+// rateLimit takes a token for a message that has just arrived. A refused message yields an error with code
+// CodeRateLimited, which names the sender as a culprit once it has too many strikes.
+func (p *BaseParty) rateLimit(msg ParsedMessage) *Error {
+	if p.limiter == nil || msg == nil || msg.GetFrom() == nil || !msg.GetFrom().ValidateBasic() {
+		return nil
+	}
+	err := p.limiter.take(msg.GetFrom())
+	switch {
+	case err == nil:
+		return nil
+	case err == ErrRateLimited:
+		return p.WrapError(fmt.Errorf("dropped a message from %s: %w", msg.GetFrom(), err)).WithCode(CodeRateLimited)
+	default:
+		return p.WrapError(err, msg.GetFrom()).WithCode(CodeRateLimited)
+	}
+}
+
+// strike records that the sender of a message sent a conflicting or an invalid message
+func (p *BaseParty) strike(msg ParsedMessage) {
+	if p.limiter == nil || msg == nil || msg.GetFrom() == nil || !msg.GetFrom().ValidateBasic() {
+		return
+	}
+	p.limiter.strike(msg.GetFrom())
+}
@@ -0,0 +1,203 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// PartyJoined is the event of a party joining the committee. The party gets a share when the members admit it
+	// (see repair.NewLocalPartyToAdd) or reshare the key from the previous committee to the new one.
+	PartyJoined CommitteeEventType = iota
+	// PartyLeft is the event of a party leaving the committee. The remaining parties should revoke its share, by
+	// refreshing without it (see refresh.NewLocalPartyToRevoke) or by resharing to the new committee.
+	PartyLeft
+)
+
+type (
+	CommitteeEventType int
+
+	// CommitteeEvent is a change of the committee of a PartyRegistry
+	CommitteeEvent struct {
+		Type CommitteeEventType
+		// the party that joined or left, with its index in Committee or Previous
+		Party *PartyID
+		// counts the changes of the committee; it may be used to derive the session IDs of the sessions that follow
+		Epoch uint64
+		// the committee before and after the change, sorted and indexed independently
+		Previous, Committee SortedPartyIDs
+	}
+
+	// PartyRegistry tracks the live committee: the parties that hold, or are to hold, a share of a key. It rejects
+	// parties whose key or ID is already taken, and calls its subscribers with every change, so that resharing or
+	// refresh sessions can be started automatically as parties come and go.
+	PartyRegistry struct {
+		mtx         sync.Mutex
+		members     SortedPartyIDs
+		epoch       uint64
+		subscribers []func(CommitteeEvent)
+		events      []CommitteeEvent
+		firing      bool
+	}
+)
+
+func (t CommitteeEventType) String() string {
+	switch t {
+	case PartyJoined:
+		return "joined"
+	case PartyLeft:
+		return "left"
+	default:
+		return fmt.Sprintf("CommitteeEventType(%d)", int(t))
+	}
+}
+
+// PeerContexts returns the contexts of the previous and the new committee, e.g. for NewReSharingParameters
+func (ev CommitteeEvent) PeerContexts() (oldCtx, newCtx *PeerContext) {
+	return NewPeerContext(ev.Previous), NewPeerContext(ev.Committee)
+}
+
+// ValidatePartyIDs checks that every party ID is valid and that no two parties share a key or an ID
+func ValidatePartyIDs(ids []*PartyID) error {
+	keys := make(map[string]struct{}, len(ids))
+	names := make(map[string]struct{}, len(ids))
+	for _, pid := range ids {
+		if pid == nil || pid.MessageWrapper_PartyID == nil || len(pid.Key) == 0 || pid.KeyInt().Sign() == 0 {
+			return errors.New("party ID is nil or has an empty key")
+		}
+		key := pid.KeyInt().String()
+		if _, ok := keys[key]; ok {
+			return fmt.Errorf("party %s has the same key as another party", pid)
+		}
+		keys[key] = struct{}{}
+		if pid.Id == "" {
+			continue
+		}
+		if _, ok := names[pid.Id]; ok {
+			return fmt.Errorf("party %s has the same ID %q as another party", pid, pid.Id)
+		}
+		names[pid.Id] = struct{}{}
+	}
+	return nil
+}
+
+// NewPartyRegistry returns a registry whose committee is `initial`, e.g. the parties of keygen
+func NewPartyRegistry(initial ...*PartyID) (*PartyRegistry, error) {
+	if err := ValidatePartyIDs(initial); err != nil {
+		return nil, err
+	}
+	return &PartyRegistry{members: copyPartyIDs(initial)}, nil
+}
+
+// Subscribe registers `fn` to be called with every change of the committee. The subscribers are called one at a time,
+// in the order of the changes and outside of the lock of the registry, so they may call back into it.
+func (r *PartyRegistry) Subscribe(fn func(CommitteeEvent)) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Committee returns the current committee, sorted
+func (r *PartyRegistry) Committee() SortedPartyIDs {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return copyPartyIDs(r.members)
+}
+
+// Epoch returns the number of changes of the committee so far
+func (r *PartyRegistry) Epoch() uint64 {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.epoch
+}
+
+// Join adds a party to the committee; its key and ID must not be taken by a member
+func (r *PartyRegistry) Join(pid *PartyID) error {
+	r.mtx.Lock()
+	members := append(UnSortedPartyIDs{pid}, r.members...)
+	if err := ValidatePartyIDs(members); err != nil {
+		r.mtx.Unlock()
+		return err
+	}
+	r.change(PartyJoined, pid, members)
+	r.mtx.Unlock()
+	r.fire()
+	return nil
+}
+
+// Leave removes the party with the key of `pid` from the committee
+func (r *PartyRegistry) Leave(pid *PartyID) error {
+	if pid == nil || pid.MessageWrapper_PartyID == nil {
+		return errors.New("party ID is nil")
+	}
+	r.mtx.Lock()
+	member := r.members.FindByKey(pid.KeyInt())
+	if member == nil {
+		r.mtx.Unlock()
+		return fmt.Errorf("party %s is not in the committee", pid)
+	}
+	r.change(PartyLeft, member, UnSortedPartyIDs(r.members.Exclude(member)))
+	r.mtx.Unlock()
+	r.fire()
+	return nil
+}
+
+// change replaces the committee with `members` and records the event. It must be called with the registry locked.
+func (r *PartyRegistry) change(typ CommitteeEventType, pid *PartyID, members UnSortedPartyIDs) {
+	previous := r.members
+	r.members = copyPartyIDs(members)
+	r.epoch++
+	ev := CommitteeEvent{
+		Type:      typ,
+		Epoch:     r.epoch,
+		Previous:  copyPartyIDs(previous),
+		Committee: copyPartyIDs(r.members),
+	}
+	if typ == PartyJoined {
+		ev.Party = ev.Committee.FindByKey(pid.KeyInt())
+	} else {
+		ev.Party = ev.Previous.FindByKey(pid.KeyInt())
+	}
+	r.events = append(r.events, ev)
+}
+
+// fire calls the subscribers with the events recorded so far; a subscriber that changes the committee leaves its
+// events to the outer call. It must be called with the registry unlocked.
+func (r *PartyRegistry) fire() {
+	r.mtx.Lock()
+	if r.firing {
+		r.mtx.Unlock()
+		return
+	}
+	r.firing = true
+	for len(r.events) > 0 {
+		events, subscribers := r.events, r.subscribers
+		r.events = nil
+		r.mtx.Unlock()
+		for _, ev := range events {
+			for _, fn := range subscribers {
+				fn(ev)
+			}
+		}
+		r.mtx.Lock()
+	}
+	r.firing = false
+	r.mtx.Unlock()
+}
+
+// copyPartyIDs returns sorted copies of the party IDs, so that sorting them does not change the indexes of the originals
+func copyPartyIDs(ids []*PartyID) SortedPartyIDs {
+	copies := make(UnSortedPartyIDs, len(ids))
+	for i, pid := range ids {
+		cp := *pid
+		copies[i] = &cp
+	}
+	return SortPartyIDs(copies)
+}
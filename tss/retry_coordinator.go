@@ -0,0 +1,117 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+	// SigningAttemptFunc runs one complete signing session over `committee` and returns nil once it has produced a
+	// signature. The committee is freshly sorted and indexed for the attempt, so it can be passed to NewPeerContext as is.
+	// A returned *Error with culprits makes the coordinator exclude those parties from later attempts.
+	SigningAttemptFunc func(attempt int, committee SortedPartyIDs) error
+
+	// SigningAttempt records the outcome of one attempt of a RetryCoordinator
+	SigningAttempt struct {
+		Committee SortedPartyIDs
+		Culprits  []*PartyID
+		Err       error
+	}
+
+	// RetryCoordinator drives a signing session to completion despite misbehaving parties: whenever an attempt aborts
+	// with culprits, they are excluded, a new committee of threshold+1 parties is selected from the remaining ones and
+	// signing is restarted, up to a maximum number of attempts.
+	RetryCoordinator struct {
+		available   SortedPartyIDs
+		threshold   int
+		maxAttempts int
+		attempt     SigningAttemptFunc
+
+		excluded map[string]bool
+		history  []SigningAttempt
+	}
+)
+
+// NewRetryCoordinator returns a coordinator that signs with threshold+1 of the `available` parties
+func NewRetryCoordinator(available SortedPartyIDs, threshold, maxAttempts int, attempt SigningAttemptFunc) *RetryCoordinator {
+	return &RetryCoordinator{
+		available:   available,
+		threshold:   threshold,
+		maxAttempts: maxAttempts,
+		attempt:     attempt,
+		excluded:    make(map[string]bool),
+	}
+}
+
+// Run signs until an attempt succeeds, returning the committee that produced the signature.
+// It fails once maxAttempts have been made or fewer than threshold+1 parties remain.
+func (rc *RetryCoordinator) Run() (SortedPartyIDs, error) {
+	if rc.attempt == nil {
+		return nil, errors.New("retry coordinator has no attempt func")
+	}
+	for i := 0; i < rc.maxAttempts; i++ {
+		committee, err := rc.nextCommittee()
+		if err != nil {
+			return nil, err
+		}
+		err = rc.attempt(i, committee)
+		record := SigningAttempt{Committee: committee, Err: err}
+		if err == nil {
+			rc.history = append(rc.history, record)
+			return committee, nil
+		}
+		var tssErr *Error
+		if errors.As(err, &tssErr) {
+			for _, culprit := range tssErr.Culprits() {
+				if culprit == nil || culprit.Key == nil {
+					continue
+				}
+				record.Culprits = append(record.Culprits, culprit)
+				rc.excluded[culprit.KeyInt().String()] = true
+			}
+		}
+		rc.history = append(rc.history, record)
+	}
+	return nil, fmt.Errorf("signing did not succeed after %d attempts", rc.maxAttempts)
+}
+
+// History returns the attempts made so far, in order
+func (rc *RetryCoordinator) History() []SigningAttempt {
+	return append([]SigningAttempt(nil), rc.history...)
+}
+
+// Excluded returns the parties that have been blamed in earlier attempts
+func (rc *RetryCoordinator) Excluded() SortedPartyIDs {
+	excluded := make(SortedPartyIDs, 0, len(rc.excluded))
+	for _, pid := range rc.available {
+		if rc.excluded[pid.KeyInt().String()] {
+			excluded = append(excluded, pid)
+		}
+	}
+	return excluded
+}
+
+// nextCommittee selects the first threshold+1 parties that have not been excluded, as copies that keep their
+// identity keys, so that sorting them does not disturb the indices of the available set
+func (rc *RetryCoordinator) nextCommittee() (SortedPartyIDs, error) {
+	selected := make(UnSortedPartyIDs, 0, rc.threshold+1)
+	for _, pid := range rc.available {
+		if len(selected) == rc.threshold+1 {
+			break
+		}
+		if rc.excluded[pid.KeyInt().String()] {
+			continue
+		}
+		selected = append(selected, pid.Clone())
+	}
+	if len(selected) < rc.threshold+1 {
+		return nil, fmt.Errorf("only %d parties remain after excluding culprits, need %d", len(selected), rc.threshold+1)
+	}
+	return SortPartyIDs(selected), nil
+}
@@ -0,0 +1,143 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type (
+	// SessionManager runs many parties side by side, e.g. one signing session per message.
+	// Outbound messages are tagged with their session ID in MessageRouting.SessionID, and inbound messages are
+	// routed to the party of the session they carry, so callers need not maintain their own session maps.
+	SessionManager struct {
+		mtx      sync.Mutex
+		out      chan<- Message
+		sessions map[string]*managedSession
+	}
+
+	managedSession struct {
+		party Party
+		done  chan struct{}
+	}
+)
+
+// NewSessionManager returns a manager that sends the tagged messages of all of its sessions to `out`
+func NewSessionManager(out chan<- Message) *SessionManager {
+	return &SessionManager{
+		out:      out,
+		sessions: make(map[string]*managedSession),
+	}
+}
+
+// Add registers a new session. `newParty` must construct the session's party with the given outbound channel,
+// e.g. func(out chan<- tss.Message) tss.Party { return signing.NewLocalParty(msg, params, key, out, end) }.
+// The returned party has not been started. The session holds a goroutine forwarding the messages of its party until
+// it is removed, so every session added must eventually be passed to Remove, or the manager closed with Close.
+func (sm *SessionManager) Add(sessionID []byte, newParty func(out chan<- Message) Party) (Party, error) {
+	if len(sessionID) == 0 {
+		return nil, errors.New("session id must not be empty")
+	}
+	sm.mtx.Lock()
+	defer sm.mtx.Unlock()
+	if _, ok := sm.sessions[string(sessionID)]; ok {
+		return nil, fmt.Errorf("session %x already exists", sessionID)
+	}
+	out := make(chan Message)
+	session := &managedSession{
+		party: newParty(out),
+		done:  make(chan struct{}),
+	}
+	sm.sessions[string(sessionID)] = session
+	go sm.forward(append([]byte(nil), sessionID...), out, session.done)
+	return session.party, nil
+}
+
+// Remove forgets a session once it has ended or failed and stops forwarding the messages of its party, which must not
+// send any more
+func (sm *SessionManager) Remove(sessionID []byte) {
+	sm.mtx.Lock()
+	defer sm.mtx.Unlock()
+	if session, ok := sm.sessions[string(sessionID)]; ok {
+		close(session.done)
+		delete(sm.sessions, string(sessionID))
+	}
+}
+
+// Close removes all of the sessions, e.g. when the manager is no longer used
+func (sm *SessionManager) Close() {
+	sm.mtx.Lock()
+	defer sm.mtx.Unlock()
+	for id, session := range sm.sessions {
+		close(session.done)
+		delete(sm.sessions, id)
+	}
+}
+
+// Party returns the party running the session, or nil if there is no such session
+func (sm *SessionManager) Party(sessionID []byte) Party {
+	sm.mtx.Lock()
+	defer sm.mtx.Unlock()
+	if session, ok := sm.sessions[string(sessionID)]; ok {
+		return session.party
+	}
+	return nil
+}
+
+// Len returns the number of sessions currently registered
+func (sm *SessionManager) Len() int {
+	sm.mtx.Lock()
+	defer sm.mtx.Unlock()
+	return len(sm.sessions)
+}
+
+// UpdateFromBytes routes a message received from the wire to the party of session `sessionID`
+func (sm *SessionManager) UpdateFromBytes(sessionID []byte, wireBytes []byte, from *PartyID, isBroadcast bool) (bool, *Error) {
+	party := sm.Party(sessionID)
+	if party == nil {
+		return false, NewError(fmt.Errorf("unknown session %x", sessionID), "", -1, nil, from)
+	}
+	msg, err := ParseWireMessageInSession(wireBytes, from, isBroadcast, sessionID)
+	if err != nil {
+		return false, party.WrapError(err)
+	}
+	return party.Update(msg)
+}
+
+// Update routes a parsed message to the party of the session in its routing; used when running locally or in tests
+func (sm *SessionManager) Update(msg ParsedMessage) (bool, *Error) {
+	impl, ok := msg.(*MessageImpl)
+	if !ok || len(impl.SessionID) == 0 {
+		return false, NewError(errors.New("message carries no session id"), "", -1, nil, msg.GetFrom())
+	}
+	party := sm.Party(impl.SessionID)
+	if party == nil {
+		return false, NewError(fmt.Errorf("unknown session %x", impl.SessionID), "", -1, nil, msg.GetFrom())
+	}
+	return party.Update(msg)
+}
+
+// forward tags and sends the messages of a session's party to the manager's channel until the session is removed
+func (sm *SessionManager) forward(sessionID []byte, in <-chan Message, done <-chan struct{}) {
+	for {
+		select {
+		case msg := <-in:
+			if impl, ok := msg.(*MessageImpl); ok {
+				impl.setSessionID(sessionID)
+			}
+			select {
+			case sm.out <- msg:
+			case <-done:
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"math/big"
+)
+
+// ShareWrapper encrypts the secret share of a party, Xi, under a key that never leaves its holder, e.g. a key of an
+// HSM (see package storage/hsm). A share wrapped with the WrapShare method of the save data is only unwrapped into
+// memory by signing, when its first round starts, and is zeroed right after its use; see Parameters.SetShareWrapper.
+type ShareWrapper interface {
+	WrapShare(xi *big.Int) ([]byte, error)
+	UnwrapShare(wrapped []byte) (*big.Int, error)
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+// DefaultStepBufferSize is the number of outbound messages a Stepper can hold after a single step when it is given
+// no buffer size; it is ample for the protocols of this library with up to a few hundred parties
+const DefaultStepBufferSize = 1024
+
+// Stepper drives a party synchronously, without any goroutine of the caller: each call feeds the party and returns
+// the messages that it sent in response, to be delivered by the caller. This suits environments where a party
+// cannot have a goroutine draining its out channel, such as WASM, mobile bindings and deterministic simulations.
+// The party sends its save data or signature to its `end` channel, which must therefore be buffered.
+// The party may still use goroutines of its own, e.g. for safe prime generation; give ECDSA keygen its pre-params to
+// avoid them.
+type Stepper struct {
+	party Party
+	out   chan Message
+}
+
+// NewStepper constructs a party with `newParty`, which must pass the given outbound channel to the party's
+// constructor, e.g. func(out chan<- tss.Message) tss.Party { return keygen.NewLocalParty(params, out, end) }.
+// A step that sends more than `bufferSize` messages blocks forever; pass 0 for DefaultStepBufferSize.
+func NewStepper(bufferSize int, newParty func(out chan<- Message) Party) *Stepper {
+	if bufferSize <= 0 {
+		bufferSize = DefaultStepBufferSize
+	}
+	out := make(chan Message, bufferSize)
+	return &Stepper{party: newParty(out), out: out}
+}
+
+// Party returns the party driven by the stepper
+func (s *Stepper) Party() Party {
+	return s.party
+}
+
+// Start starts the party and returns the messages of its first round
+func (s *Stepper) Start() ([]Message, *Error) {
+	err := s.party.Start()
+	return s.drain(), err
+}
+
+// Step updates the party with a message and returns the messages it sent in response, if any. They are returned
+// even with an error, as the party may have sent some before it failed.
+func (s *Stepper) Step(msg ParsedMessage) ([]Message, *Error) {
+	_, err := s.party.Update(msg)
+	return s.drain(), err
+}
+
+// StepFromBytes updates the party with a message received from the wire, like Step
+func (s *Stepper) StepFromBytes(wireBytes []byte, from *PartyID, isBroadcast bool) ([]Message, *Error) {
+	_, err := s.party.UpdateFromBytes(wireBytes, from, isBroadcast)
+	return s.drain(), err
+}
+
+func (s *Stepper) drain() []Message {
+	var msgs []Message
+	for {
+		select {
+		case msg := <-s.out:
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"fmt"
+	"time"
+)
+
+type (
+	// RoundTimeout bounds the time a party waits for the messages of each round, so that a dropped message does not
+	// stall the session forever. When a round has not completed by its Deadline, a ResendRequest is emitted for the
+	// parties it is still waiting for, and the deadline is re-armed; once MaxResends requests have gone unanswered the
+	// round is aborted. Set it with Parameters.SetRoundTimeout.
+	RoundTimeout struct {
+		// Deadline of each round, counted from its start and again from every ResendRequest
+		Deadline time.Duration
+		// MaxResends is the number of ResendRequests emitted for a round before it is aborted; 0 aborts at the first
+		// deadline
+		MaxResends int
+
+		// OnResend is called with a request that the transport should deliver to the parties in its To
+		OnResend func(req *ResendRequest)
+		// OnAbort is called with the error of an aborted round, which blames the parties that never sent their
+		// messages. Every later update of the party fails with the same error.
+		OnAbort func(err *Error)
+	}
+
+	// ResendRequest asks the parties in To to send again the messages of Round they sent to From. It is a control
+	// message for the transport: a party that receives it should redeliver its messages of that round to From, as
+	// they were sent before.
+	ResendRequest struct {
+		From      *PartyID
+		To        []*PartyID
+		Round     int
+		SessionID []byte
+	}
+)
+
+// startRoundTimer arms the deadline of the current round, replacing the one of the previous round.
+// It must be called with the party locked.
+func (p *BaseParty) startRoundTimer() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.timerGen++
+	p.resends = 0
+	if p.rnd == nil {
+		return
+	}
+	rt := p.rnd.Params().RoundTimeout()
+	if rt == nil || rt.Deadline <= 0 {
+		return
+	}
+	gen := p.timerGen
+	p.timer = time.AfterFunc(rt.Deadline, func() { p.roundTimedOut(gen, rt) })
+}
+
+func (p *BaseParty) roundTimedOut(gen int, rt *RoundTimeout) {
+	p.lock()
+	if gen != p.timerGen || p.rnd == nil || p.aborted != nil {
+		p.unlock()
+		return
+	}
+	rnd := p.rnd
+	waitingFor := waitingForOthers(rnd)
+	if len(waitingFor) == 0 {
+		// the round has all of its messages and is still computing
+		p.timer = time.AfterFunc(rt.Deadline, func() { p.roundTimedOut(gen, rt) })
+		p.unlock()
+		return
+	}
+	if p.resends < rt.MaxResends {
+		p.resends++
+		p.timer = time.AfterFunc(rt.Deadline, func() { p.roundTimedOut(gen, rt) })
+		req := &ResendRequest{
+			From:      rnd.Params().PartyID(),
+			To:        waitingFor,
+			Round:     rnd.RoundNumber(),
+			SessionID: rnd.Params().SessionID(),
+		}
+		p.unlock()
+		if rt.OnResend != nil {
+			rt.OnResend(req)
+		}
+		return
+	}
+	p.timer = nil
+	p.aborted = rnd.WrapError(fmt.Errorf("round %d timed out waiting for %d parties", rnd.RoundNumber(), len(waitingFor)), waitingFor...).WithCode(CodeTimeout)
+	err := p.aborted
+	p.endSession(err)
+	p.unlock()
+	if rt.OnAbort != nil {
+		rt.OnAbort(err)
+	}
+}
+
+// waitingForOthers returns the parties that the round waits for but this one, whose own message is only taken in by
+// the round once the message of another party arrives
+func waitingForOthers(rnd Round) []*PartyID {
+	self, all := rnd.Params().PartyID(), rnd.WaitingFor()
+	waitingFor := make([]*PartyID, 0, len(all))
+	for _, pID := range all {
+		if pID.KeyInt().Cmp(self.KeyInt()) != 0 {
+			waitingFor = append(waitingFor, pID)
+		}
+	}
+	return waitingFor
+}
+
+func (p *BaseParty) abortError() *Error {
+	return p.aborted
+}
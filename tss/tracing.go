@@ -0,0 +1,85 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// Tracer creates the spans of a party, so that a session run across several services can be followed end-to-end,
+	// e.g. with OpenTelemetry in Jaeger or Tempo. Set it with Parameters.SetTracer.
+	// The party opens a span for each round, from its start until it has all of its messages, and a span for the
+	// processing of each received message. Spans are children of the context of the parameters; the span of a
+	// message is linked to its sender through the trace context that the sender attached to it (see WithTraceContext).
+	Tracer interface {
+		// Start starts a span named `name` as a child of the span in `ctx`
+		Start(ctx context.Context, name string) (context.Context, Span)
+		// Inject returns the trace context of `ctx` to attach to an outbound message, e.g. its W3C traceparent
+		Inject(ctx context.Context) map[string]string
+		// Extract returns `ctx` with the remote span of a trace context received with a message
+		Extract(ctx context.Context, carrier map[string]string) context.Context
+	}
+
+	// Span is a span started by a Tracer
+	Span interface {
+		// End ends the span, recording `err` if the round or the message failed
+		End(err error)
+	}
+)
+
+// WithTraceContext sets on a message received from the wire the trace context that the transport delivered with it,
+// from the TraceContext of the sender's MessageRouting
+func WithTraceContext(msg ParsedMessage, carrier map[string]string) ParsedMessage {
+	if impl, ok := msg.(*MessageImpl); ok && len(carrier) > 0 {
+		impl.TraceContext = carrier
+	}
+	return msg
+}
+
+// startRoundSpan starts the span of the current round. It must be called with the party locked.
+func (p *BaseParty) startRoundSpan(task string) {
+	if p.rnd == nil {
+		return
+	}
+	params := p.rnd.Params()
+	if params.Tracer() == nil {
+		return
+	}
+	_, p.roundSpan = params.Tracer().Start(params.Context(), fmt.Sprintf("tss-lib %s round %d", task, p.rnd.RoundNumber()))
+}
+
+// endRoundSpan ends the span of the current round, if any. It must be called with the party locked.
+func (p *BaseParty) endRoundSpan(err *Error) {
+	if p.roundSpan == nil {
+		return
+	}
+	p.roundSpan.End(spanError(err))
+	p.roundSpan = nil
+}
+
+// startMessageSpan starts the span of the processing of a received message. It must be called with the party locked.
+func (p *BaseParty) startMessageSpan(task string, msg ParsedMessage) Span {
+	if p.rnd == nil || p.rnd.Params().Tracer() == nil {
+		return nil
+	}
+	params := p.rnd.Params()
+	ctx := params.Context()
+	if impl, ok := msg.(*MessageImpl); ok && len(impl.TraceContext) > 0 {
+		ctx = params.Tracer().Extract(ctx, impl.TraceContext)
+	}
+	_, span := params.Tracer().Start(ctx, fmt.Sprintf("tss-lib %s message %s", task, msg.Type()))
+	return span
+}
+
+func spanError(err *Error) error {
+	if err == nil {
+		return nil
+	}
+	return err
+}
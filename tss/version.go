@@ -0,0 +1,74 @@
+// Copyright © 2019 Binance
+//
+// This file is part of Binance. The full Binance copyright notice, including
+// terms governing use, modification, and redistribution, is contained in the
+// file LICENSE at the root of the source code distribution tree.
+
+package tss
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// ProtocolVersion is the version of the messages sent by this release. It is raised whenever a change to the
+	// message contents or to the way they are processed would make parties of different releases disagree.
+	ProtocolVersion uint32 = 2
+	// MinProtocolVersion is the oldest version whose parties can read the messages of this release, and whose
+	// messages this release can read
+	MinProtocolVersion uint32 = 1
+
+	// the first byte of versioned wire bytes; no marshalled Any, CBOR message or gzip stream starts with it
+	wireVersionMarker = 0x00
+)
+
+// ErrIncompatibleProtocol is wrapped by the error of ParseWireMessage for a message from a party whose release of
+// tss-lib cannot take part in the same session
+var ErrIncompatibleProtocol = errors.New("incompatible protocol version")
+
+// prependWireVersion tags the message bytes with the protocol version of this release and the oldest one that can
+// read them
+func prependWireVersion(wireBytes []byte, minVersion uint32) []byte {
+	header := make([]byte, 1, 1+2*binary.MaxVarintLen32+len(wireBytes))
+	header[0] = wireVersionMarker
+	header = appendUvarint(header, uint64(ProtocolVersion))
+	header = appendUvarint(header, uint64(minVersion))
+	return append(header, wireBytes...)
+}
+
+// stripWireVersion returns the protocol versions that tag the message bytes, and the bytes without them. Messages
+// of the releases from before versioning are untagged and have version 0.
+func stripWireVersion(wireBytes []byte) (version, minVersion uint32, rest []byte, err error) {
+	if len(wireBytes) == 0 || wireBytes[0] != wireVersionMarker {
+		return 0, 0, wireBytes, nil
+	}
+	rest = wireBytes[1:]
+	for _, v := range []*uint32{&version, &minVersion} {
+		n, size := binary.Uvarint(rest)
+		if size <= 0 || n > uint64(^uint32(0)) {
+			return 0, 0, nil, errors.New("ParseWireMessage: the message has a malformed protocol version")
+		}
+		*v, rest = uint32(n), rest[size:]
+	}
+	return version, minVersion, rest, nil
+}
+
+// checkProtocolVersion checks that the versions announced by the sender of a message overlap those of this release
+func checkProtocolVersion(version, minVersion uint32) error {
+	if version == 0 {
+		return fmt.Errorf("ParseWireMessage: %w: the message carries no protocol version, so its sender runs a release "+
+			"from before version %d", ErrIncompatibleProtocol, MinProtocolVersion)
+	}
+	if minVersion > version || version < MinProtocolVersion || ProtocolVersion < minVersion {
+		return fmt.Errorf("ParseWireMessage: %w: the sender speaks versions %d to %d, and this party %d to %d",
+			ErrIncompatibleProtocol, minVersion, version, MinProtocolVersion, ProtocolVersion)
+	}
+	return nil
+}
+
+func appendUvarint(bz []byte, n uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(bz, buf[:binary.PutUvarint(buf[:], n)]...)
+}
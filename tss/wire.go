@@ -7,24 +7,106 @@
 package tss
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
 	"errors"
+	"fmt"
+	"io"
+
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
+const (
+	wireSignatureTag = "tss-lib wire message"
+)
+
 // Used externally to update a LocalParty with a valid ParsedMessage
+// When `from` has an IdentityKey, the wire bytes must carry a valid signature by it, as produced by a party with
+// Parameters.SetIdentityKey, so that a message cannot be attributed to another party by an untrusted transport.
+// A message from a release of tss-lib whose protocol versions do not overlap those of this one is rejected with an
+// error wrapping ErrIncompatibleProtocol, and a message beyond the limits of SetWireLimits with one wrapping ErrWireLimit.
 func ParseWireMessage(wireBytes []byte, from *PartyID, isBroadcast bool) (ParsedMessage, error) {
+	limits := GetWireLimits()
+	if err := checkWireSize(wireBytes, limits); err != nil {
+		return nil, err
+	}
+	received := append([]byte(nil), wireBytes...)
+	var signed *wireEnvelope
+	var signedBytes []byte
+	if from != nil && from.IdentityKey != nil {
+		signedBytes = received
+		var err error
+		if signed, wireBytes, err = verifyWireBytes(from, isBroadcast, wireBytes); err != nil {
+			return nil, err
+		}
+	}
+	compressed := isCompressed(wireBytes)
+	if compressed {
+		var err error
+		if wireBytes, err = decompressWireBytes(wireBytes, limits); err != nil {
+			return nil, err
+		}
+	}
+	version, minVersion, wireBytes, err := stripWireVersion(wireBytes)
+	if err != nil {
+		return nil, err
+	}
 	wire := new(MessageWrapper)
 	wire.Message = new(anypb.Any)
 	wire.From = from.MessageWrapper_PartyID
 	wire.IsBroadcast = isBroadcast
-	if err := proto.Unmarshal(wireBytes, wire.Message); err != nil {
+	wire.Compressed = compressed
+	wire.ProtocolVersion, wire.MinProtocolVersion = version, minVersion
+	if isCBORMessage(wireBytes) {
+		if wire.Message, err = unmarshalCBORMessage(wireBytes); err != nil {
+			return nil, err
+		}
+	} else if err := proto.Unmarshal(wireBytes, wire.Message); err != nil {
 		return nil, err
 	}
-	return parseWrappedMessage(wire, from)
+	if signed != nil {
+		wire.SessionId = signed.sessionID
+	}
+	msg, err := parseWrappedMessage(wire, from)
+	if err != nil {
+		return nil, err
+	}
+	if impl, ok := msg.(*MessageImpl); ok {
+		impl.receivedBytes = received
+		if signed != nil {
+			impl.recipients, impl.signedBytes = signed.recipients, signedBytes
+		}
+	}
+	return msg, nil
+}
+
+// ParseWireMessageInSession parses a message that the transport delivered with the session ID of its routing
+func ParseWireMessageInSession(wireBytes []byte, from *PartyID, isBroadcast bool, sessionID []byte) (ParsedMessage, error) {
+	msg, err := ParseWireMessage(wireBytes, from, isBroadcast)
+	if err != nil {
+		return nil, err
+	}
+	if impl, ok := msg.(*MessageImpl); ok && len(sessionID) > 0 {
+		// the session ID signed by the sender, if any, is the one the message belongs to
+		if len(impl.SessionID) > 0 && !bytes.Equal(impl.SessionID, sessionID) {
+			return nil, fmt.Errorf("ParseWireMessage: the message from %s was signed for session %x", from, impl.SessionID)
+		}
+		impl.setSessionID(append([]byte(nil), sessionID...))
+	}
+	return msg, nil
 }
 
 func parseWrappedMessage(wire *MessageWrapper, from *PartyID) (ParsedMessage, error) {
+	// fail before decoding content whose fields may mean something else to the sender
+	if err := checkProtocolVersion(wire.ProtocolVersion, wire.MinProtocolVersion); err != nil {
+		return nil, err
+	}
+	// and before allocating the big.Ints and slices of content that a malicious sender may have inflated
+	if err := checkContentLimits(wire.Message.GetTypeUrl(), wire.Message.GetValue(), GetWireLimits()); err != nil {
+		return nil, err
+	}
 	m, err := wire.Message.UnmarshalNew()
 	if err != nil {
 		return nil, err
@@ -32,9 +114,155 @@ func parseWrappedMessage(wire *MessageWrapper, from *PartyID) (ParsedMessage, er
 	meta := MessageRouting{
 		From:        from,
 		IsBroadcast: wire.IsBroadcast,
+		SessionID:   wire.SessionId,
+		Compressed:  wire.Compressed,
 	}
 	if content, ok := m.(MessageContent); ok {
 		return NewMessage(meta, content, wire), nil
 	}
 	return nil, errors.New("ParseWireMessage: the message contained unknown content")
 }
+
+// wireEnvelope is what the sender signs along with the wire bytes: the recipients and the session of the message, so
+// that a relay can neither redirect it to another party nor replay it in another session
+type wireEnvelope struct {
+	// the keys of the recipients, or none for a broadcast to all parties
+	recipients [][]byte
+	sessionID  []byte
+}
+
+// signWireBytes prepends to the wire bytes the signature of the sender and the envelope that it covers along with
+// the wire bytes, its key and the broadcast flag
+func signWireBytes(key ed25519.PrivateKey, from *PartyID, isBroadcast bool, env *wireEnvelope, wireBytes []byte) []byte {
+	header := env.marshal()
+	sig := ed25519.Sign(key, wireSignaturePayload(from, isBroadcast, header, wireBytes))
+	signed := make([]byte, 0, len(sig)+len(header)+len(wireBytes))
+	signed = append(signed, sig...)
+	signed = append(signed, header...)
+	return append(signed, wireBytes...)
+}
+
+// verifyWireBytes checks the signature of signed wire bytes and returns the envelope and the message bytes that it covers
+func verifyWireBytes(from *PartyID, isBroadcast bool, signed []byte) (*wireEnvelope, []byte, error) {
+	if len(from.IdentityKey) != ed25519.PublicKeySize {
+		return nil, nil, fmt.Errorf("ParseWireMessage: invalid identity key of party %s", from)
+	}
+	if len(signed) < ed25519.SignatureSize {
+		return nil, nil, fmt.Errorf("ParseWireMessage: the message from %s is not signed", from)
+	}
+	sig, rest := signed[:ed25519.SignatureSize], signed[ed25519.SignatureSize:]
+	env, wireBytes, err := unmarshalWireEnvelope(rest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ParseWireMessage: the message from %s has %v", from, err)
+	}
+	header := rest[:len(rest)-len(wireBytes)]
+	if !ed25519.Verify(from.IdentityKey, wireSignaturePayload(from, isBroadcast, header, wireBytes), sig) {
+		return nil, nil, fmt.Errorf("ParseWireMessage: invalid signature on the message from %s", from)
+	}
+	return env, wireBytes, nil
+}
+
+func wireSignaturePayload(from *PartyID, isBroadcast bool, header, wireBytes []byte) []byte {
+	payload := make([]byte, 0, len(wireSignatureTag)+len(from.Key)+len(header)+len(wireBytes)+3)
+	payload = append(payload, wireSignatureTag...)
+	payload = appendWireField(payload, from.Key)
+	if isBroadcast {
+		payload = append(payload, 1)
+	} else {
+		payload = append(payload, 0)
+	}
+	payload = append(payload, header...)
+	return append(payload, wireBytes...)
+}
+
+func newWireEnvelope(to []*PartyID, sessionID []byte) *wireEnvelope {
+	env := &wireEnvelope{sessionID: sessionID}
+	for _, pID := range to {
+		env.recipients = append(env.recipients, pID.Key)
+	}
+	return env
+}
+
+// marshal encodes the number of recipients, then each recipient key and the session ID with their lengths
+func (env *wireEnvelope) marshal() []byte {
+	bz := []byte{byte(len(env.recipients) >> 8), byte(len(env.recipients))}
+	for _, key := range env.recipients {
+		bz = appendWireField(bz, key)
+	}
+	return appendWireField(bz, env.sessionID)
+}
+
+// unmarshalWireEnvelope decodes the envelope at the start of `bz` and returns the bytes that follow it
+func unmarshalWireEnvelope(bz []byte) (*wireEnvelope, []byte, error) {
+	if len(bz) < 2 {
+		return nil, nil, errors.New("a truncated envelope")
+	}
+	count := int(bz[0])<<8 | int(bz[1])
+	bz = bz[2:]
+	env := new(wireEnvelope)
+	for i := 0; i < count; i++ {
+		var key []byte
+		var err error
+		if key, bz, err = readWireField(bz); err != nil {
+			return nil, nil, err
+		}
+		env.recipients = append(env.recipients, key)
+	}
+	var err error
+	if env.sessionID, bz, err = readWireField(bz); err != nil {
+		return nil, nil, err
+	}
+	if len(env.sessionID) == 0 {
+		env.sessionID = nil
+	}
+	return env, bz, nil
+}
+
+func appendWireField(bz, field []byte) []byte {
+	bz = append(bz, byte(len(field)>>8), byte(len(field)))
+	return append(bz, field...)
+}
+
+func readWireField(bz []byte) ([]byte, []byte, error) {
+	if len(bz) < 2 {
+		return nil, nil, errors.New("a truncated envelope")
+	}
+	n := int(bz[0])<<8 | int(bz[1])
+	if len(bz)-2 < n {
+		return nil, nil, errors.New("a truncated envelope")
+	}
+	return bz[2 : 2+n], bz[2+n:], nil
+}
+
+// isCompressed reports whether the wire bytes start with the gzip magic, which cannot start the versioned message bytes
+func isCompressed(wireBytes []byte) bool {
+	return 2 <= len(wireBytes) && wireBytes[0] == 0x1f && wireBytes[1] == 0x8b
+}
+
+func compressWireBytes(wireBytes []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(wireBytes); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressWireBytes(compressed []byte, limits WireLimits) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("ParseWireMessage: %v", err)
+	}
+	defer zr.Close()
+	wireBytes, err := io.ReadAll(io.LimitReader(zr, int64(limits.MaxMessageBytes)+1))
+	if err != nil {
+		return nil, fmt.Errorf("ParseWireMessage: %v", err)
+	}
+	if err := checkWireSize(wireBytes, limits); err != nil {
+		return nil, err
+	}
+	return wireBytes, nil
+}